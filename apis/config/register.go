@@ -45,7 +45,10 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&NetworkOverheadArgs{},
 		&SySchedArgs{},
 		&PeaksArgs{},
+		&EnergyCostArgs{},
+		&LoadThresholdArgs{},
 		&NodeMetadataArgs{},
+		&DiskIOArgs{},
 	)
 	return nil
 }
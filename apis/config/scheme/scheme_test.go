@@ -34,6 +34,7 @@ import (
 	"sigs.k8s.io/scheduler-plugins/apis/config"
 	v1 "sigs.k8s.io/scheduler-plugins/apis/config/v1"
 	"sigs.k8s.io/scheduler-plugins/pkg/coscheduling"
+	"sigs.k8s.io/scheduler-plugins/pkg/diskio"
 	"sigs.k8s.io/scheduler-plugins/pkg/networkaware/networkoverhead"
 	"sigs.k8s.io/scheduler-plugins/pkg/networkaware/topologicalsort"
 	"sigs.k8s.io/scheduler-plugins/pkg/noderesources"
@@ -77,6 +78,7 @@ profiles:
 - schedulerName: scheduler-plugins
   pluginConfig:
   - name: Coscheduling # Test argument defaulting logic
+  - name: DiskIO # Test argument defaulting logic
   - name: TopologicalSort
     args:
       namespaces:
@@ -99,6 +101,12 @@ profiles:
 								PermitWaitingTimeSeconds: 60,
 							},
 						},
+						{
+							Name: diskio.Name,
+							Args: &config.DiskIOArgs{
+								ScoringStrategy: config.DiskIOLeastAllocated,
+							},
+						},
 						{
 							Name: topologicalsort.Name,
 							Args: &config.TopologicalSortArgs{
@@ -111,6 +119,7 @@ profiles:
 								Namespaces:          []string{"networkAware"},
 								WeightsName:         "netCosts",
 								NetworkTopologyName: "net-topology-v1",
+								TopologyKeys:        []string{"topology.kubernetes.io/zone", "topology.kubernetes.io/region"},
 							},
 						},
 						{
@@ -186,6 +195,7 @@ profiles:
 								Namespaces:          []string{"default"},
 								WeightsName:         "UserDefined",
 								NetworkTopologyName: "nt-default",
+								TopologyKeys:        []string{"topology.kubernetes.io/zone", "topology.kubernetes.io/region"},
 							},
 						},
 						{
@@ -367,7 +377,10 @@ func TestCodecsEncodePluginConfig(t *testing.T) {
 											Type:    config.Prometheus,
 											Address: "http://prometheus-k8s.monitoring.svc.cluster.local:9090",
 										},
-										WatcherAddress: "http://deadbeef:2020"},
+										WatcherAddress:                "http://deadbeef:2020",
+										MetricsRefreshIntervalSeconds: v1.DefaultMetricsRefreshIntervalSeconds,
+										MetricsRetentionSeconds:       v1.DefaultMetricsRetentionSeconds,
+									},
 									TargetUtilization: 60,
 									DefaultRequests: corev1.ResourceList{
 										corev1.ResourceCPU: testCPUQuantity,
@@ -384,7 +397,10 @@ func TestCodecsEncodePluginConfig(t *testing.T) {
 											Address:            "http://prometheus-k8s.monitoring.svc.cluster.local:9090",
 											InsecureSkipVerify: false,
 										},
-										WatcherAddress: "http://deadbeef:2020"},
+										WatcherAddress:                "http://deadbeef:2020",
+										MetricsRefreshIntervalSeconds: v1.DefaultMetricsRefreshIntervalSeconds,
+										MetricsRetentionSeconds:       v1.DefaultMetricsRetentionSeconds,
+									},
 									SafeVarianceMargin:      v1.DefaultSafeVarianceMargin,
 									SafeVarianceSensitivity: v1.DefaultSafeVarianceSensitivity,
 								},
@@ -398,7 +414,10 @@ func TestCodecsEncodePluginConfig(t *testing.T) {
 											Address:            "http://prometheus-k8s.monitoring.svc.cluster.local:9090",
 											InsecureSkipVerify: false,
 										},
-										WatcherAddress: "http://deadbeef:2020"},
+										WatcherAddress:                "http://deadbeef:2020",
+										MetricsRefreshIntervalSeconds: v1.DefaultMetricsRefreshIntervalSeconds,
+										MetricsRetentionSeconds:       v1.DefaultMetricsRetentionSeconds,
+									},
 									SmoothingWindowSize: v1.DefaultSmoothingWindowSize,
 									RiskLimitWeights: map[corev1.ResourceName]float64{
 										corev1.ResourceCPU:    v1.DefaultRiskLimitWeight,
@@ -418,6 +437,7 @@ func TestCodecsEncodePluginConfig(t *testing.T) {
 									Namespaces:          []string{"default"},
 									WeightsName:         "netCosts",
 									NetworkTopologyName: "net-topology-v1",
+									TopologyKeys:        []string{"topology.kubernetes.io/zone", "topology.kubernetes.io/region"},
 								},
 							},
 							{
@@ -483,6 +503,8 @@ profiles:
         insecureSkipVerify: false
         token: ""
         type: Prometheus
+      metricsRefreshIntervalSeconds: 30
+      metricsRetentionSeconds: 900
       targetUtilization: 60
       watcherAddress: http://deadbeef:2020
     name: TargetLoadPacking
@@ -494,6 +516,8 @@ profiles:
         insecureSkipVerify: false
         token: ""
         type: Prometheus
+      metricsRefreshIntervalSeconds: 30
+      metricsRetentionSeconds: 900
       safeVarianceMargin: 1
       safeVarianceSensitivity: 1
       watcherAddress: http://deadbeef:2020
@@ -506,6 +530,8 @@ profiles:
         insecureSkipVerify: false
         token: ""
         type: Prometheus
+      metricsRefreshIntervalSeconds: 30
+      metricsRetentionSeconds: 900
       riskLimitWeights:
         cpu: 0.5
         memory: 0.5
@@ -524,6 +550,9 @@ profiles:
       namespaces:
       - default
       networkTopologyName: net-topology-v1
+      topologyKeys:
+      - topology.kubernetes.io/zone
+      - topology.kubernetes.io/region
       weightsName: netCosts
     name: NetworkOverhead
   - args:
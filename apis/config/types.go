@@ -80,6 +80,22 @@ type MetricProviderSpec struct {
 	Token string
 	// Whether to enable the InsureSkipVerify options for https requests on Metric Providers.
 	InsecureSkipVerify bool
+	// PromQL queries of the metrics of the nodes. If set for the Prometheus metric provider,
+	// the queries are issued by Trimaran directly instead of the fixed queries of load watcher.
+	Queries []MetricQuery
+}
+
+// MetricQuery is a PromQL query of a metric of the nodes
+type MetricQuery struct {
+	// Type of the metric, e.g. CPU or Memory
+	Type string
+	// Operator of the metric, e.g. AVG or STD
+	Operator string
+	// PromQL query returning an instant vector with one sample per node
+	Query string
+	// Label of the samples holding the name of the node, e.g. node, rather than instance which holds the
+	// host:port of the scrape target
+	NodeLabel string
 }
 
 // TrimaranSpec holds common parameters for trimaran plugins
@@ -88,6 +104,10 @@ type TrimaranSpec struct {
 	MetricProvider MetricProviderSpec
 	// Address of load watcher service
 	WatcherAddress string
+	// Interval in seconds between two refreshes of the metrics from load watcher
+	MetricsRefreshIntervalSeconds int64
+	// Duration in seconds for which the history of the metrics of each node is retained
+	MetricsRetentionSeconds int64
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -104,6 +124,17 @@ type TargetLoadPackingArgs struct {
 	DefaultRequestsMultiplier string
 	// Node target CPU Utilization for bin packing
 	TargetUtilization int64
+	// Node target utilization and score weight of each resource for bin packing.
+	// If empty, only CPU is considered, with TargetUtilization as target.
+	ResourceTargets map[v1.ResourceName]ResourceTarget
+}
+
+// ResourceTarget is the target utilization of a resource for bin packing, and the weight of its score
+type ResourceTarget struct {
+	// Node target utilization percent of the resource
+	TargetUtilization int64
+	// Weight of the score of the resource
+	Weight int64
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -146,6 +177,9 @@ const (
 	LeastAllocated ScoringStrategyType = "LeastAllocated"
 	// LeastNUMANodes strategy favors nodes which requires least amount of NUMA nodes to satisfy resource requests for given pod
 	LeastNUMANodes ScoringStrategyType = "LeastNUMANodes"
+	// DeviceNUMAAffinity strategy favors nodes like LeastNUMANodes, and which can place the devices requested by the given pod
+	// on the same NUMA nodes as its CPUs and memory, or on the closest ones
+	DeviceNUMAAffinity ScoringStrategyType = "DeviceNUMAAffinity"
 )
 
 // ScoringStrategy define ScoringStrategyType for node resource topology plugin
@@ -192,6 +226,14 @@ const (
 	CacheResyncScopeOnlyResources CacheResyncScope = "OnlyResources"
 )
 
+// CacheReservationBackend is a "string" type
+type CacheReservationBackend string
+
+const (
+	CacheReservationBackendLocal  CacheReservationBackend = "Local"
+	CacheReservationBackendShared CacheReservationBackend = "Shared"
+)
+
 // NodeResourceTopologyCache define configuration details for the NodeResourceTopology cache.
 type NodeResourceTopologyCache struct {
 	// ForeignPodsDetect sets how foreign pods should be handled.
@@ -219,6 +261,17 @@ type NodeResourceTopologyCache struct {
 	// "All" to make the code react to node config changes avoiding reboots.
 	// Use "OnlyResources" to restore the previous behavior.
 	ResyncScope *CacheResyncScope
+	// ReservationBackend controls where the cache tracks the resources reserved for the pods
+	// being scheduled. "Local" keeps them in the memory of the scheduler instance. "Shared" also
+	// publishes them on a ledger of ConfigMaps, one per node, so all the scheduler instances
+	// cooperating on the same nodes account the in-flight reservations of each other, and the
+	// pods they schedule are not handled as foreign pods.
+	// Has no effect if caching is disabled (CacheResyncPeriod is zero) or if DiscardReservedNodes
+	// is enabled. If unspecified, default is "Local".
+	ReservationBackend *CacheReservationBackend
+	// ReservationNamespace is the namespace holding the ledger ConfigMaps when ReservationBackend
+	// is "Shared". If unspecified, default is "kube-system".
+	ReservationNamespace *string
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -266,6 +319,10 @@ type NetworkOverheadArgs struct {
 
 	// The NetworkTopology CRD name
 	NetworkTopologyName string
+
+	// Topology keys of the nodes ordered from the finest to the coarsest, e.g. hostname, rack, zone, region.
+	// Each topology key has its own costs in the NetworkTopology CRD. (Default: zone, region)
+	TopologyKeys []string
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -278,6 +335,17 @@ type SySchedArgs struct {
 
 	// CR name of the default profile for all system calls
 	DefaultProfileName string
+
+	// Label marking the sensitive pods, which the Filter isolates from risky co-tenants.
+	// A pod is sensitive if the label is set to "true".
+	SensitivePodLabel string
+
+	// System calls the co-tenants of sensitive pods must not use
+	DeniedSyscalls []string
+
+	// Maximum count of extraneous system calls, used by the co-tenants but not by
+	// a sensitive pod, the pod can be exposed to. No limit if unset.
+	MaxExtraneousSyscalls *int64
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -299,6 +367,87 @@ type PowerModel struct {
 	// Idle power of node will be K0 + K1
 }
 
+// IntensitySourceType is a "string" type.
+type IntensitySourceType string
+
+const (
+	// ConfigMapIntensitySource reads a schedule of the intensity of each zone, by hour of the day, from a ConfigMap
+	ConfigMapIntensitySource IntensitySourceType = "ConfigMap"
+	// HTTPIntensitySource polls the current intensity of each zone from an HTTP endpoint
+	HTTPIntensitySource IntensitySourceType = "HTTP"
+)
+
+// IntensitySourceSpec denotes the source of the intensity signal of the zones
+type IntensitySourceSpec struct {
+	// Type of the source
+	Type IntensitySourceType
+	// Namespace of the ConfigMap, for the ConfigMap source
+	ConfigMapNamespace string
+	// Name of the ConfigMap, for the ConfigMap source
+	ConfigMapName string
+	// Address of the endpoint, for the HTTP source
+	Address string
+	// Interval in seconds between two polls of the endpoint, for the HTTP source
+	RefreshIntervalSeconds int64
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// EnergyCostArgs holds arguments used to configure the EnergyCost plugin
+type EnergyCostArgs struct {
+	metav1.TypeMeta
+
+	// Common parameters for trimaran plugins
+	TrimaranSpec
+	// Power models keyed by node name, resolved as for the Peaks plugin
+	NodePowerModel map[string]PowerModel
+	// Label of the nodes holding the zone the intensity signal is keyed by
+	ZoneLabel string
+	// Source of the intensity signal, such as the carbon intensity of the grid or the price of electricity
+	IntensitySource IntensitySourceSpec
+}
+
+// UtilizationSourceType is a "string" type.
+type UtilizationSourceType string
+
+const (
+	// ObservedUtilization checks the utilization of the nodes reported by their metrics
+	ObservedUtilization UtilizationSourceType = "Observed"
+	// PredictedUtilization checks the utilization of the nodes reported by their metrics, plus the requests
+	// of the pod and of the pods assigned to the nodes since their metrics
+	PredictedUtilization UtilizationSourceType = "Predicted"
+)
+
+// MetricsGracePolicyType is a "string" type.
+type MetricsGracePolicyType string
+
+const (
+	// AllowMetricsGracePolicy lets the nodes with missing or stale metrics pass the filter
+	AllowMetricsGracePolicy MetricsGracePolicyType = "Allow"
+	// RejectMetricsGracePolicy rejects the nodes with missing or stale metrics
+	RejectMetricsGracePolicy MetricsGracePolicyType = "Reject"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// LoadThresholdArgs holds arguments used to configure the LoadThreshold plugin
+type LoadThresholdArgs struct {
+	metav1.TypeMeta
+
+	// Common parameters for trimaran plugins
+	TrimaranSpec
+	// Node CPU utilization percent above which nodes are rejected, 0 to not check the CPU utilization
+	CPUThreshold int64
+	// Node memory utilization percent above which nodes are rejected, 0 to not check the memory utilization
+	MemoryThreshold int64
+	// Utilization checked against the thresholds
+	Utilization UtilizationSourceType
+	// Policy for the nodes with missing or stale metrics
+	MetricsGracePolicy MetricsGracePolicyType
+	// Age in seconds beyond which the metrics of a node are stale
+	MaxMetricsAgeSeconds int64
+}
+
 // MetadataSourceType defines where to look for metadata
 type MetadataSourceType string
 
@@ -364,3 +513,23 @@ type NodeMetadataArgs struct {
 	//   - Custom: "2006-01-02 15:04:05"
 	TimestampFormat string `json:"timestampFormat,omitempty"`
 }
+
+// DiskIOScoringStrategy defines how nodes are scored by their disk IO utilization.
+type DiskIOScoringStrategy string
+
+const (
+	// DiskIOLeastAllocated favors nodes with the least disk IO bandwidth allocated, balancing the IO load.
+	DiskIOLeastAllocated DiskIOScoringStrategy = "LeastAllocated"
+	// DiskIOMostAllocated favors nodes with the most disk IO bandwidth allocated, packing the IO load.
+	DiskIOMostAllocated DiskIOScoringStrategy = "MostAllocated"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// DiskIOArgs holds arguments used to configure the DiskIO plugin.
+type DiskIOArgs struct {
+	metav1.TypeMeta
+
+	// ScoringStrategy selects how nodes are scored by their disk IO utilization.
+	ScoringStrategy DiskIOScoringStrategy
+}
@@ -52,6 +52,8 @@ var (
 	DefaultRequestsMultiplier = "1.5"
 	// DefaultTargetUtilizationPercent Recommended to keep -10 than desired limit.
 	DefaultTargetUtilizationPercent int64 = 40
+	// DefaultResourceTargetWeight is the weight of the score of a resource with a target utilization
+	DefaultResourceTargetWeight int64 = 1
 
 	// Defaults for LoadVariationRiskBalancing plugin
 
@@ -82,6 +84,16 @@ var (
 	DefaultMetricProviderType = KubernetesMetricsServer
 	// DefaultInsecureSkipVerify is whether to skip the certificate verification
 	DefaultInsecureSkipVerify = true
+	// DefaultMetricsRefreshIntervalSeconds is the interval between two refreshes of the metrics from load watcher
+	DefaultMetricsRefreshIntervalSeconds int64 = 30
+	// DefaultMetricsRetentionSeconds is the duration for which the history of the metrics is retained
+	DefaultMetricsRetentionSeconds int64 = 900
+	// DefaultMetricQueryOperator is the operator of the metric of a PromQL query, i.e. the average
+	DefaultMetricQueryOperator = "AVG"
+	// DefaultMetricQueryNodeLabel is the label holding the name of the node in the results of a PromQL query,
+	// i.e. the one the kube-prometheus recording rules relabel the node-exporter samples with. The instance label
+	// holds the host:port of the scrape target, not the node name.
+	DefaultMetricQueryNodeLabel = "node"
 
 	defaultResourceSpec = []schedulerconfigv1.ResourceSpec{
 		{Name: string(v1.ResourceCPU), Weight: 1},
@@ -94,17 +106,51 @@ var (
 
 	defaultInformerMode = CacheInformerDedicated
 
+	defaultReservationBackend = CacheReservationBackendLocal
+
 	// Defaults for NetworkOverhead
 	// DefaultWeightsName contains the default costs to be used by networkAware plugins
 	DefaultWeightsName = "UserDefined"
 	// DefaultNetworkTopologyName contains the networkTopology CR name to be used by networkAware plugins
 	DefaultNetworkTopologyName = "nt-default"
+	// DefaultTopologyKeys contains the topology keys of the nodes, from the finest to the coarsest, to be used by networkAware plugins
+	DefaultTopologyKeys = []string{v1.LabelTopologyZone, v1.LabelTopologyRegion}
 
 	// Defaults for SySched
 	// DefaultSySchedProfileNamespace is the namesapce of the default syscall profile CR for SySched plugin
 	DefaultSySchedProfileNamespace = "default"
 	// DefaultSySchedProfileName is the name of the default syscall profile CR for SySched plugin
 	DefaultSySchedProfileName = "all-syscalls"
+	// DefaultSySchedSensitivePodLabel is the label marking the sensitive pods for SySched plugin
+	DefaultSySchedSensitivePodLabel = "sysched.scheduling.x-k8s.io/sensitive"
+
+	// Defaults for EnergyCost plugin
+	// DefaultEnergyCostZoneLabel is the label of the nodes holding the zone the intensity signal is keyed by
+	DefaultEnergyCostZoneLabel = v1.LabelTopologyZone
+	// DefaultIntensitySourceType reads the intensity signal from a ConfigMap
+	DefaultIntensitySourceType = ConfigMapIntensitySource
+	// DefaultIntensityConfigMapNamespace is the namespace of the ConfigMap holding the intensity signal
+	DefaultIntensityConfigMapNamespace = metav1.NamespaceSystem
+	// DefaultIntensityConfigMapName is the name of the ConfigMap holding the intensity signal
+	DefaultIntensityConfigMapName = "energy-cost-intensity"
+	// DefaultIntensityRefreshIntervalSeconds is the interval between two polls of the HTTP intensity source
+	DefaultIntensityRefreshIntervalSeconds int64 = 300
+
+	// Defaults for LoadThreshold plugin
+	// DefaultLoadThresholdPercent is the utilization percent above which nodes are rejected
+	DefaultLoadThresholdPercent int64 = 90
+	// DefaultUtilizationSourceType checks the utilization predicted once the pod runs on the nodes
+	DefaultUtilizationSourceType = PredictedUtilization
+	// DefaultMetricsGracePolicyType lets the nodes with missing or stale metrics pass, so an outage
+	// of the metric source does not make the whole cluster unschedulable
+	DefaultMetricsGracePolicyType = AllowMetricsGracePolicy
+	// DefaultMaxMetricsAgeSeconds is the age beyond which the metrics of a node are stale
+	DefaultMaxMetricsAgeSeconds int64 = 300
+
+	// Defaults for DiskIO plugin
+
+	// DefaultDiskIOScoringStrategy spreads the disk IO load across nodes.
+	DefaultDiskIOScoringStrategy = DiskIOLeastAllocated
 )
 
 // SetDefaults_CoschedulingArgs sets the default parameters for Coscheduling plugin.
@@ -136,6 +182,21 @@ func SetDefaultTrimaranSpec(args *TrimaranSpec) {
 	if args.MetricProvider.Type == Prometheus && args.MetricProvider.InsecureSkipVerify == nil {
 		args.MetricProvider.InsecureSkipVerify = &DefaultInsecureSkipVerify
 	}
+	for i := range args.MetricProvider.Queries {
+		query := &args.MetricProvider.Queries[i]
+		if query.Operator == "" {
+			query.Operator = DefaultMetricQueryOperator
+		}
+		if query.NodeLabel == "" {
+			query.NodeLabel = DefaultMetricQueryNodeLabel
+		}
+	}
+	if args.MetricsRefreshIntervalSeconds == nil {
+		args.MetricsRefreshIntervalSeconds = &DefaultMetricsRefreshIntervalSeconds
+	}
+	if args.MetricsRetentionSeconds == nil {
+		args.MetricsRetentionSeconds = &DefaultMetricsRetentionSeconds
+	}
 }
 
 // SetDefaults_TargetLoadPackingArgs sets the default parameters for TargetLoadPacking plugin
@@ -151,6 +212,15 @@ func SetDefaults_TargetLoadPackingArgs(args *TargetLoadPackingArgs) {
 	if args.TargetUtilization == nil || *args.TargetUtilization <= 0 {
 		args.TargetUtilization = &DefaultTargetUtilizationPercent
 	}
+	for r, target := range args.ResourceTargets {
+		if target.TargetUtilization <= 0 {
+			target.TargetUtilization = DefaultTargetUtilizationPercent
+		}
+		if target.Weight <= 0 {
+			target.Weight = DefaultResourceTargetWeight
+		}
+		args.ResourceTargets[r] = target
+	}
 }
 
 // SetDefaults_LoadVariationRiskBalancingArgs sets the default parameters for LoadVariationRiskBalancing plugin
@@ -214,6 +284,9 @@ func SetDefaults_NodeResourceTopologyMatchArgs(obj *NodeResourceTopologyMatchArg
 	if obj.Cache.InformerMode == nil {
 		obj.Cache.InformerMode = &defaultInformerMode
 	}
+	if obj.Cache.ReservationBackend == nil {
+		obj.Cache.ReservationBackend = &defaultReservationBackend
+	}
 }
 
 // SetDefaults_PreemptionTolerationArgs reuses SetDefaults_DefaultPreemptionArgs
@@ -241,6 +314,10 @@ func SetDefaults_NetworkOverheadArgs(obj *NetworkOverheadArgs) {
 	if obj.NetworkTopologyName == nil {
 		obj.NetworkTopologyName = &DefaultNetworkTopologyName
 	}
+
+	if len(obj.TopologyKeys) == 0 {
+		obj.TopologyKeys = append([]string(nil), DefaultTopologyKeys...)
+	}
 }
 
 // SetDefaults_SySchedArgs sets the default parameters for SySchedArgs plugin.
@@ -252,6 +329,10 @@ func SetDefaults_SySchedArgs(obj *SySchedArgs) {
 	if obj.DefaultProfileName == nil {
 		obj.DefaultProfileName = &DefaultSySchedProfileName
 	}
+
+	if obj.SensitivePodLabel == nil {
+		obj.SensitivePodLabel = &DefaultSySchedSensitivePodLabel
+	}
 }
 
 // SetDefaults_NodeMetadataArgs sets the default parameters for NodeMetadataArgs plugin.
@@ -260,3 +341,55 @@ func SetDefaults_NodeMetadataArgs(obj *NodeMetadataArgs) {
 		obj.TimestampFormat = ptr.To(time.RFC3339)
 	}
 }
+
+// SetDefaults_EnergyCostArgs sets the default parameters for EnergyCost plugin
+func SetDefaults_EnergyCostArgs(args *EnergyCostArgs) {
+	SetDefaultTrimaranSpec(&args.TrimaranSpec)
+	if args.ZoneLabel == nil || *args.ZoneLabel == "" {
+		args.ZoneLabel = &DefaultEnergyCostZoneLabel
+	}
+	source := &args.IntensitySource
+	if source.Type == "" {
+		source.Type = DefaultIntensitySourceType
+	}
+	if source.Type == ConfigMapIntensitySource {
+		if source.ConfigMapNamespace == nil || *source.ConfigMapNamespace == "" {
+			source.ConfigMapNamespace = &DefaultIntensityConfigMapNamespace
+		}
+		if source.ConfigMapName == nil || *source.ConfigMapName == "" {
+			source.ConfigMapName = &DefaultIntensityConfigMapName
+		}
+	}
+	if source.Type == HTTPIntensitySource {
+		if source.RefreshIntervalSeconds == nil || *source.RefreshIntervalSeconds <= 0 {
+			source.RefreshIntervalSeconds = &DefaultIntensityRefreshIntervalSeconds
+		}
+	}
+}
+
+// SetDefaults_LoadThresholdArgs sets the default parameters for LoadThreshold plugin
+func SetDefaults_LoadThresholdArgs(args *LoadThresholdArgs) {
+	SetDefaultTrimaranSpec(&args.TrimaranSpec)
+	if args.CPUThreshold == nil {
+		args.CPUThreshold = &DefaultLoadThresholdPercent
+	}
+	if args.MemoryThreshold == nil {
+		args.MemoryThreshold = &DefaultLoadThresholdPercent
+	}
+	if args.Utilization == "" {
+		args.Utilization = DefaultUtilizationSourceType
+	}
+	if args.MetricsGracePolicy == "" {
+		args.MetricsGracePolicy = DefaultMetricsGracePolicyType
+	}
+	if args.MaxMetricsAgeSeconds == nil || *args.MaxMetricsAgeSeconds <= 0 {
+		args.MaxMetricsAgeSeconds = &DefaultMaxMetricsAgeSeconds
+	}
+}
+
+// SetDefaults_DiskIOArgs sets the default parameters for DiskIO plugin.
+func SetDefaults_DiskIOArgs(obj *DiskIOArgs) {
+	if obj.ScoringStrategy == "" {
+		obj.ScoringStrategy = DefaultDiskIOScoringStrategy
+	}
+}
@@ -86,7 +86,10 @@ func TestSchedulingDefaults(t *testing.T) {
 				TrimaranSpec: TrimaranSpec{
 					MetricProvider: MetricProviderSpec{
 						Type: "KubernetesMetricsServer",
-					}},
+					},
+					MetricsRefreshIntervalSeconds: pointer.Int64Ptr(30),
+					MetricsRetentionSeconds:       pointer.Int64Ptr(900),
+				},
 				DefaultRequests: v1.ResourceList{v1.ResourceCPU: resource.MustParse(
 					strconv.FormatInt(DefaultRequestsMilliCores, 10) + "m")},
 				DefaultRequestsMultiplier: pointer.StringPtr("1.5"),
@@ -101,13 +104,24 @@ func TestSchedulingDefaults(t *testing.T) {
 				DefaultRequests:           v1.ResourceList{v1.ResourceCPU: resource.MustParse("100m")},
 				DefaultRequestsMultiplier: pointer.StringPtr("2.5"),
 				TargetUtilization:         pointer.Int64Ptr(50),
+				ResourceTargets: map[v1.ResourceName]ResourceTarget{
+					v1.ResourceCPU:    {TargetUtilization: 60},
+					v1.ResourceMemory: {Weight: 2},
+				},
 			},
 			expect: &TargetLoadPackingArgs{
 				TrimaranSpec: TrimaranSpec{
-					WatcherAddress: pointer.StringPtr("http://localhost:2020")},
+					WatcherAddress:                pointer.StringPtr("http://localhost:2020"),
+					MetricsRefreshIntervalSeconds: pointer.Int64Ptr(30),
+					MetricsRetentionSeconds:       pointer.Int64Ptr(900),
+				},
 				DefaultRequests:           v1.ResourceList{v1.ResourceCPU: resource.MustParse("100m")},
 				DefaultRequestsMultiplier: pointer.StringPtr("2.5"),
 				TargetUtilization:         pointer.Int64Ptr(50),
+				ResourceTargets: map[v1.ResourceName]ResourceTarget{
+					v1.ResourceCPU:    {TargetUtilization: 60, Weight: 1},
+					v1.ResourceMemory: {TargetUtilization: 40, Weight: 2},
+				},
 			},
 		},
 		{
@@ -117,7 +131,10 @@ func TestSchedulingDefaults(t *testing.T) {
 				TrimaranSpec: TrimaranSpec{
 					MetricProvider: MetricProviderSpec{
 						Type: "KubernetesMetricsServer",
-					}},
+					},
+					MetricsRefreshIntervalSeconds: pointer.Int64Ptr(30),
+					MetricsRetentionSeconds:       pointer.Int64Ptr(900),
+				},
 				SafeVarianceMargin:      pointer.Float64Ptr(1.0),
 				SafeVarianceSensitivity: pointer.Float64Ptr(1.0),
 			},
@@ -132,11 +149,43 @@ func TestSchedulingDefaults(t *testing.T) {
 				TrimaranSpec: TrimaranSpec{
 					MetricProvider: MetricProviderSpec{
 						Type: "KubernetesMetricsServer",
-					}},
+					},
+					MetricsRefreshIntervalSeconds: pointer.Int64Ptr(30),
+					MetricsRetentionSeconds:       pointer.Int64Ptr(900),
+				},
 				SafeVarianceMargin:      pointer.Float64Ptr(2.0),
 				SafeVarianceSensitivity: pointer.Float64Ptr(2.0),
 			},
 		},
+		{
+			name: "set metric queries LoadVariationRiskBalancingArgs",
+			config: &LoadVariationRiskBalancingArgs{
+				TrimaranSpec: TrimaranSpec{
+					MetricProvider: MetricProviderSpec{
+						Type: "Prometheus",
+						Queries: []MetricQuery{
+							{Type: "CPU", Query: "node:cpu_utilisation:avg5m * 100"},
+							{Type: "Memory", Operator: "STD", Query: "node:memory_utilisation:stddev5m * 100", NodeLabel: "instance"},
+						},
+					}},
+			},
+			expect: &LoadVariationRiskBalancingArgs{
+				TrimaranSpec: TrimaranSpec{
+					MetricProvider: MetricProviderSpec{
+						Type:               "Prometheus",
+						InsecureSkipVerify: pointer.BoolPtr(true),
+						Queries: []MetricQuery{
+							{Type: "CPU", Operator: "AVG", Query: "node:cpu_utilisation:avg5m * 100", NodeLabel: "node"},
+							{Type: "Memory", Operator: "STD", Query: "node:memory_utilisation:stddev5m * 100", NodeLabel: "instance"},
+						},
+					},
+					MetricsRefreshIntervalSeconds: pointer.Int64Ptr(30),
+					MetricsRetentionSeconds:       pointer.Int64Ptr(900),
+				},
+				SafeVarianceMargin:      pointer.Float64Ptr(1.0),
+				SafeVarianceSensitivity: pointer.Float64Ptr(1.0),
+			},
+		},
 		{
 			name:   "empty config LowRiskOverCommitmentArgs",
 			config: &LowRiskOverCommitmentArgs{},
@@ -144,7 +193,10 @@ func TestSchedulingDefaults(t *testing.T) {
 				TrimaranSpec: TrimaranSpec{
 					MetricProvider: MetricProviderSpec{
 						Type: "KubernetesMetricsServer",
-					}},
+					},
+					MetricsRefreshIntervalSeconds: pointer.Int64Ptr(30),
+					MetricsRetentionSeconds:       pointer.Int64Ptr(900),
+				},
 				SmoothingWindowSize: pointer.Int64Ptr(5),
 				RiskLimitWeights: map[v1.ResourceName]float64{
 					v1.ResourceCPU:    0.5,
@@ -165,7 +217,10 @@ func TestSchedulingDefaults(t *testing.T) {
 				TrimaranSpec: TrimaranSpec{
 					MetricProvider: MetricProviderSpec{
 						Type: "KubernetesMetricsServer",
-					}},
+					},
+					MetricsRefreshIntervalSeconds: pointer.Int64Ptr(30),
+					MetricsRetentionSeconds:       pointer.Int64Ptr(900),
+				},
 				SmoothingWindowSize: pointer.Int64Ptr(10),
 				RiskLimitWeights: map[v1.ResourceName]float64{
 					v1.ResourceCPU:    0.2,
@@ -186,7 +241,10 @@ func TestSchedulingDefaults(t *testing.T) {
 				TrimaranSpec: TrimaranSpec{
 					MetricProvider: MetricProviderSpec{
 						Type: "KubernetesMetricsServer",
-					}},
+					},
+					MetricsRefreshIntervalSeconds: pointer.Int64Ptr(30),
+					MetricsRetentionSeconds:       pointer.Int64Ptr(900),
+				},
 				SmoothingWindowSize: pointer.Int64Ptr(10),
 				RiskLimitWeights: map[v1.ResourceName]float64{
 					v1.ResourceCPU:    0.5,
@@ -203,9 +261,10 @@ func TestSchedulingDefaults(t *testing.T) {
 					Resources: defaultResourceSpec,
 				},
 				Cache: &NodeResourceTopologyCache{
-					ForeignPodsDetect: &defaultForeignPodsDetect,
-					ResyncMethod:      &defaultResyncMethod,
-					InformerMode:      &defaultInformerMode,
+					ForeignPodsDetect:  &defaultForeignPodsDetect,
+					ResyncMethod:       &defaultResyncMethod,
+					InformerMode:       &defaultInformerMode,
+					ReservationBackend: &defaultReservationBackend,
 				},
 			},
 		},
@@ -240,6 +299,7 @@ func TestSchedulingDefaults(t *testing.T) {
 				Namespaces:          []string{"default"},
 				WeightsName:         pointer.StringPtr("UserDefined"),
 				NetworkTopologyName: pointer.StringPtr("nt-default"),
+				TopologyKeys:        []string{"topology.kubernetes.io/zone", "topology.kubernetes.io/region"},
 			},
 		},
 		{
@@ -248,11 +308,13 @@ func TestSchedulingDefaults(t *testing.T) {
 				Namespaces:          []string{"n2"},
 				WeightsName:         pointer.StringPtr("latency"),
 				NetworkTopologyName: pointer.StringPtr("nt-latency-costs"),
+				TopologyKeys:        []string{"kubernetes.io/hostname", "example.com/rack", "topology.kubernetes.io/zone"},
 			},
 			expect: &NetworkOverheadArgs{
 				Namespaces:          []string{"n2"},
 				WeightsName:         pointer.StringPtr("latency"),
 				NetworkTopologyName: pointer.StringPtr("nt-latency-costs"),
+				TopologyKeys:        []string{"kubernetes.io/hostname", "example.com/rack", "topology.kubernetes.io/zone"},
 			},
 		},
 		{
@@ -261,6 +323,7 @@ func TestSchedulingDefaults(t *testing.T) {
 			expect: &SySchedArgs{
 				DefaultProfileNamespace: pointer.StringPtr("default"),
 				DefaultProfileName:      pointer.StringPtr("all-syscalls"),
+				SensitivePodLabel:       pointer.StringPtr("sysched.scheduling.x-k8s.io/sensitive"),
 			},
 		},
 		{
@@ -268,10 +331,120 @@ func TestSchedulingDefaults(t *testing.T) {
 			config: &SySchedArgs{
 				DefaultProfileNamespace: pointer.StringPtr("default"),
 				DefaultProfileName:      pointer.StringPtr("all-syscalls"),
+				SensitivePodLabel:       pointer.StringPtr("example.com/isolated"),
+				DeniedSyscalls:          []string{"ptrace"},
+				MaxExtraneousSyscalls:   pointer.Int64Ptr(10),
 			},
 			expect: &SySchedArgs{
 				DefaultProfileNamespace: pointer.StringPtr("default"),
 				DefaultProfileName:      pointer.StringPtr("all-syscalls"),
+				SensitivePodLabel:       pointer.StringPtr("example.com/isolated"),
+				DeniedSyscalls:          []string{"ptrace"},
+				MaxExtraneousSyscalls:   pointer.Int64Ptr(10),
+			},
+		},
+		{
+			name:   "empty config DiskIOArgs",
+			config: &DiskIOArgs{},
+			expect: &DiskIOArgs{
+				ScoringStrategy: DiskIOLeastAllocated,
+			},
+		},
+		{
+			name: "set non default DiskIOArgs",
+			config: &DiskIOArgs{
+				ScoringStrategy: DiskIOMostAllocated,
+			},
+			expect: &DiskIOArgs{
+				ScoringStrategy: DiskIOMostAllocated,
+			},
+		},
+		{
+			name:   "empty config EnergyCostArgs",
+			config: &EnergyCostArgs{},
+			expect: &EnergyCostArgs{
+				TrimaranSpec: TrimaranSpec{
+					MetricProvider: MetricProviderSpec{
+						Type: "KubernetesMetricsServer",
+					},
+					MetricsRefreshIntervalSeconds: pointer.Int64Ptr(30),
+					MetricsRetentionSeconds:       pointer.Int64Ptr(900),
+				},
+				ZoneLabel: pointer.StringPtr("topology.kubernetes.io/zone"),
+				IntensitySource: IntensitySourceSpec{
+					Type:               ConfigMapIntensitySource,
+					ConfigMapNamespace: pointer.StringPtr("kube-system"),
+					ConfigMapName:      pointer.StringPtr("energy-cost-intensity"),
+				},
+			},
+		},
+		{
+			name: "set non default EnergyCostArgs",
+			config: &EnergyCostArgs{
+				TrimaranSpec: TrimaranSpec{
+					WatcherAddress: pointer.StringPtr("http://localhost:2020"),
+				},
+				ZoneLabel: pointer.StringPtr("topology.kubernetes.io/region"),
+				IntensitySource: IntensitySourceSpec{
+					Type:    HTTPIntensitySource,
+					Address: pointer.StringPtr("http://intensity:8080"),
+				},
+			},
+			expect: &EnergyCostArgs{
+				TrimaranSpec: TrimaranSpec{
+					WatcherAddress:                pointer.StringPtr("http://localhost:2020"),
+					MetricsRefreshIntervalSeconds: pointer.Int64Ptr(30),
+					MetricsRetentionSeconds:       pointer.Int64Ptr(900),
+				},
+				ZoneLabel: pointer.StringPtr("topology.kubernetes.io/region"),
+				IntensitySource: IntensitySourceSpec{
+					Type:                   HTTPIntensitySource,
+					Address:                pointer.StringPtr("http://intensity:8080"),
+					RefreshIntervalSeconds: pointer.Int64Ptr(300),
+				},
+			},
+		},
+		{
+			name:   "empty config LoadThresholdArgs",
+			config: &LoadThresholdArgs{},
+			expect: &LoadThresholdArgs{
+				TrimaranSpec: TrimaranSpec{
+					MetricProvider: MetricProviderSpec{
+						Type: "KubernetesMetricsServer",
+					},
+					MetricsRefreshIntervalSeconds: pointer.Int64Ptr(30),
+					MetricsRetentionSeconds:       pointer.Int64Ptr(900),
+				},
+				CPUThreshold:         pointer.Int64Ptr(90),
+				MemoryThreshold:      pointer.Int64Ptr(90),
+				Utilization:          PredictedUtilization,
+				MetricsGracePolicy:   AllowMetricsGracePolicy,
+				MaxMetricsAgeSeconds: pointer.Int64Ptr(300),
+			},
+		},
+		{
+			name: "set non default LoadThresholdArgs",
+			config: &LoadThresholdArgs{
+				TrimaranSpec: TrimaranSpec{
+					WatcherAddress: pointer.StringPtr("http://localhost:2020"),
+				},
+				CPUThreshold:         pointer.Int64Ptr(80),
+				MemoryThreshold:      pointer.Int64Ptr(0),
+				Utilization:          ObservedUtilization,
+				MetricsGracePolicy:   RejectMetricsGracePolicy,
+				MaxMetricsAgeSeconds: pointer.Int64Ptr(120),
+			},
+			expect: &LoadThresholdArgs{
+				TrimaranSpec: TrimaranSpec{
+					WatcherAddress:                pointer.StringPtr("http://localhost:2020"),
+					MetricsRefreshIntervalSeconds: pointer.Int64Ptr(30),
+					MetricsRetentionSeconds:       pointer.Int64Ptr(900),
+				},
+				CPUThreshold:         pointer.Int64Ptr(80),
+				MemoryThreshold:      pointer.Int64Ptr(0),
+				Utilization:          ObservedUtilization,
+				MetricsGracePolicy:   RejectMetricsGracePolicy,
+				MaxMetricsAgeSeconds: pointer.Int64Ptr(120),
 			},
 		},
 	}
@@ -50,6 +50,46 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*DiskIOArgs)(nil), (*config.DiskIOArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_DiskIOArgs_To_config_DiskIOArgs(a.(*DiskIOArgs), b.(*config.DiskIOArgs), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.DiskIOArgs)(nil), (*DiskIOArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_DiskIOArgs_To_v1_DiskIOArgs(a.(*config.DiskIOArgs), b.(*DiskIOArgs), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*EnergyCostArgs)(nil), (*config.EnergyCostArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_EnergyCostArgs_To_config_EnergyCostArgs(a.(*EnergyCostArgs), b.(*config.EnergyCostArgs), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.EnergyCostArgs)(nil), (*EnergyCostArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_EnergyCostArgs_To_v1_EnergyCostArgs(a.(*config.EnergyCostArgs), b.(*EnergyCostArgs), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*IntensitySourceSpec)(nil), (*config.IntensitySourceSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_IntensitySourceSpec_To_config_IntensitySourceSpec(a.(*IntensitySourceSpec), b.(*config.IntensitySourceSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.IntensitySourceSpec)(nil), (*IntensitySourceSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_IntensitySourceSpec_To_v1_IntensitySourceSpec(a.(*config.IntensitySourceSpec), b.(*IntensitySourceSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*LoadThresholdArgs)(nil), (*config.LoadThresholdArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_LoadThresholdArgs_To_config_LoadThresholdArgs(a.(*LoadThresholdArgs), b.(*config.LoadThresholdArgs), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.LoadThresholdArgs)(nil), (*LoadThresholdArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_LoadThresholdArgs_To_v1_LoadThresholdArgs(a.(*config.LoadThresholdArgs), b.(*LoadThresholdArgs), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*LoadVariationRiskBalancingArgs)(nil), (*config.LoadVariationRiskBalancingArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1_LoadVariationRiskBalancingArgs_To_config_LoadVariationRiskBalancingArgs(a.(*LoadVariationRiskBalancingArgs), b.(*config.LoadVariationRiskBalancingArgs), scope)
 	}); err != nil {
@@ -80,6 +120,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*MetricQuery)(nil), (*config.MetricQuery)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_MetricQuery_To_config_MetricQuery(a.(*MetricQuery), b.(*config.MetricQuery), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.MetricQuery)(nil), (*MetricQuery)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_MetricQuery_To_v1_MetricQuery(a.(*config.MetricQuery), b.(*MetricQuery), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*NetworkOverheadArgs)(nil), (*config.NetworkOverheadArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1_NetworkOverheadArgs_To_config_NetworkOverheadArgs(a.(*NetworkOverheadArgs), b.(*config.NetworkOverheadArgs), scope)
 	}); err != nil {
@@ -140,6 +190,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*ResourceTarget)(nil), (*config.ResourceTarget)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_ResourceTarget_To_config_ResourceTarget(a.(*ResourceTarget), b.(*config.ResourceTarget), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.ResourceTarget)(nil), (*ResourceTarget)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_ResourceTarget_To_v1_ResourceTarget(a.(*config.ResourceTarget), b.(*ResourceTarget), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*ScoringStrategy)(nil), (*config.ScoringStrategy)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1_ScoringStrategy_To_config_ScoringStrategy(a.(*ScoringStrategy), b.(*config.ScoringStrategy), scope)
 	}); err != nil {
@@ -243,6 +303,154 @@ func Convert_config_CoschedulingArgs_To_v1_CoschedulingArgs(in *config.Coschedul
 	return autoConvert_config_CoschedulingArgs_To_v1_CoschedulingArgs(in, out, s)
 }
 
+func autoConvert_v1_DiskIOArgs_To_config_DiskIOArgs(in *DiskIOArgs, out *config.DiskIOArgs, s conversion.Scope) error {
+	out.ScoringStrategy = config.DiskIOScoringStrategy(in.ScoringStrategy)
+	return nil
+}
+
+// Convert_v1_DiskIOArgs_To_config_DiskIOArgs is an autogenerated conversion function.
+func Convert_v1_DiskIOArgs_To_config_DiskIOArgs(in *DiskIOArgs, out *config.DiskIOArgs, s conversion.Scope) error {
+	return autoConvert_v1_DiskIOArgs_To_config_DiskIOArgs(in, out, s)
+}
+
+func autoConvert_config_DiskIOArgs_To_v1_DiskIOArgs(in *config.DiskIOArgs, out *DiskIOArgs, s conversion.Scope) error {
+	out.ScoringStrategy = DiskIOScoringStrategy(in.ScoringStrategy)
+	return nil
+}
+
+// Convert_config_DiskIOArgs_To_v1_DiskIOArgs is an autogenerated conversion function.
+func Convert_config_DiskIOArgs_To_v1_DiskIOArgs(in *config.DiskIOArgs, out *DiskIOArgs, s conversion.Scope) error {
+	return autoConvert_config_DiskIOArgs_To_v1_DiskIOArgs(in, out, s)
+}
+
+func autoConvert_v1_EnergyCostArgs_To_config_EnergyCostArgs(in *EnergyCostArgs, out *config.EnergyCostArgs, s conversion.Scope) error {
+	if err := Convert_v1_TrimaranSpec_To_config_TrimaranSpec(&in.TrimaranSpec, &out.TrimaranSpec, s); err != nil {
+		return err
+	}
+	out.NodePowerModel = *(*map[string]config.PowerModel)(unsafe.Pointer(&in.NodePowerModel))
+	if err := metav1.Convert_Pointer_string_To_string(&in.ZoneLabel, &out.ZoneLabel, s); err != nil {
+		return err
+	}
+	if err := Convert_v1_IntensitySourceSpec_To_config_IntensitySourceSpec(&in.IntensitySource, &out.IntensitySource, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1_EnergyCostArgs_To_config_EnergyCostArgs is an autogenerated conversion function.
+func Convert_v1_EnergyCostArgs_To_config_EnergyCostArgs(in *EnergyCostArgs, out *config.EnergyCostArgs, s conversion.Scope) error {
+	return autoConvert_v1_EnergyCostArgs_To_config_EnergyCostArgs(in, out, s)
+}
+
+func autoConvert_config_EnergyCostArgs_To_v1_EnergyCostArgs(in *config.EnergyCostArgs, out *EnergyCostArgs, s conversion.Scope) error {
+	if err := Convert_config_TrimaranSpec_To_v1_TrimaranSpec(&in.TrimaranSpec, &out.TrimaranSpec, s); err != nil {
+		return err
+	}
+	out.NodePowerModel = *(*map[string]PowerModel)(unsafe.Pointer(&in.NodePowerModel))
+	if err := metav1.Convert_string_To_Pointer_string(&in.ZoneLabel, &out.ZoneLabel, s); err != nil {
+		return err
+	}
+	if err := Convert_config_IntensitySourceSpec_To_v1_IntensitySourceSpec(&in.IntensitySource, &out.IntensitySource, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_config_EnergyCostArgs_To_v1_EnergyCostArgs is an autogenerated conversion function.
+func Convert_config_EnergyCostArgs_To_v1_EnergyCostArgs(in *config.EnergyCostArgs, out *EnergyCostArgs, s conversion.Scope) error {
+	return autoConvert_config_EnergyCostArgs_To_v1_EnergyCostArgs(in, out, s)
+}
+
+func autoConvert_v1_IntensitySourceSpec_To_config_IntensitySourceSpec(in *IntensitySourceSpec, out *config.IntensitySourceSpec, s conversion.Scope) error {
+	out.Type = config.IntensitySourceType(in.Type)
+	if err := metav1.Convert_Pointer_string_To_string(&in.ConfigMapNamespace, &out.ConfigMapNamespace, s); err != nil {
+		return err
+	}
+	if err := metav1.Convert_Pointer_string_To_string(&in.ConfigMapName, &out.ConfigMapName, s); err != nil {
+		return err
+	}
+	if err := metav1.Convert_Pointer_string_To_string(&in.Address, &out.Address, s); err != nil {
+		return err
+	}
+	if err := metav1.Convert_Pointer_int64_To_int64(&in.RefreshIntervalSeconds, &out.RefreshIntervalSeconds, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1_IntensitySourceSpec_To_config_IntensitySourceSpec is an autogenerated conversion function.
+func Convert_v1_IntensitySourceSpec_To_config_IntensitySourceSpec(in *IntensitySourceSpec, out *config.IntensitySourceSpec, s conversion.Scope) error {
+	return autoConvert_v1_IntensitySourceSpec_To_config_IntensitySourceSpec(in, out, s)
+}
+
+func autoConvert_config_IntensitySourceSpec_To_v1_IntensitySourceSpec(in *config.IntensitySourceSpec, out *IntensitySourceSpec, s conversion.Scope) error {
+	out.Type = IntensitySourceType(in.Type)
+	if err := metav1.Convert_string_To_Pointer_string(&in.ConfigMapNamespace, &out.ConfigMapNamespace, s); err != nil {
+		return err
+	}
+	if err := metav1.Convert_string_To_Pointer_string(&in.ConfigMapName, &out.ConfigMapName, s); err != nil {
+		return err
+	}
+	if err := metav1.Convert_string_To_Pointer_string(&in.Address, &out.Address, s); err != nil {
+		return err
+	}
+	if err := metav1.Convert_int64_To_Pointer_int64(&in.RefreshIntervalSeconds, &out.RefreshIntervalSeconds, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_config_IntensitySourceSpec_To_v1_IntensitySourceSpec is an autogenerated conversion function.
+func Convert_config_IntensitySourceSpec_To_v1_IntensitySourceSpec(in *config.IntensitySourceSpec, out *IntensitySourceSpec, s conversion.Scope) error {
+	return autoConvert_config_IntensitySourceSpec_To_v1_IntensitySourceSpec(in, out, s)
+}
+
+func autoConvert_v1_LoadThresholdArgs_To_config_LoadThresholdArgs(in *LoadThresholdArgs, out *config.LoadThresholdArgs, s conversion.Scope) error {
+	if err := Convert_v1_TrimaranSpec_To_config_TrimaranSpec(&in.TrimaranSpec, &out.TrimaranSpec, s); err != nil {
+		return err
+	}
+	if err := metav1.Convert_Pointer_int64_To_int64(&in.CPUThreshold, &out.CPUThreshold, s); err != nil {
+		return err
+	}
+	if err := metav1.Convert_Pointer_int64_To_int64(&in.MemoryThreshold, &out.MemoryThreshold, s); err != nil {
+		return err
+	}
+	out.Utilization = config.UtilizationSourceType(in.Utilization)
+	out.MetricsGracePolicy = config.MetricsGracePolicyType(in.MetricsGracePolicy)
+	if err := metav1.Convert_Pointer_int64_To_int64(&in.MaxMetricsAgeSeconds, &out.MaxMetricsAgeSeconds, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1_LoadThresholdArgs_To_config_LoadThresholdArgs is an autogenerated conversion function.
+func Convert_v1_LoadThresholdArgs_To_config_LoadThresholdArgs(in *LoadThresholdArgs, out *config.LoadThresholdArgs, s conversion.Scope) error {
+	return autoConvert_v1_LoadThresholdArgs_To_config_LoadThresholdArgs(in, out, s)
+}
+
+func autoConvert_config_LoadThresholdArgs_To_v1_LoadThresholdArgs(in *config.LoadThresholdArgs, out *LoadThresholdArgs, s conversion.Scope) error {
+	if err := Convert_config_TrimaranSpec_To_v1_TrimaranSpec(&in.TrimaranSpec, &out.TrimaranSpec, s); err != nil {
+		return err
+	}
+	if err := metav1.Convert_int64_To_Pointer_int64(&in.CPUThreshold, &out.CPUThreshold, s); err != nil {
+		return err
+	}
+	if err := metav1.Convert_int64_To_Pointer_int64(&in.MemoryThreshold, &out.MemoryThreshold, s); err != nil {
+		return err
+	}
+	out.Utilization = UtilizationSourceType(in.Utilization)
+	out.MetricsGracePolicy = MetricsGracePolicyType(in.MetricsGracePolicy)
+	if err := metav1.Convert_int64_To_Pointer_int64(&in.MaxMetricsAgeSeconds, &out.MaxMetricsAgeSeconds, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_config_LoadThresholdArgs_To_v1_LoadThresholdArgs is an autogenerated conversion function.
+func Convert_config_LoadThresholdArgs_To_v1_LoadThresholdArgs(in *config.LoadThresholdArgs, out *LoadThresholdArgs, s conversion.Scope) error {
+	return autoConvert_config_LoadThresholdArgs_To_v1_LoadThresholdArgs(in, out, s)
+}
+
 func autoConvert_v1_LoadVariationRiskBalancingArgs_To_config_LoadVariationRiskBalancingArgs(in *LoadVariationRiskBalancingArgs, out *config.LoadVariationRiskBalancingArgs, s conversion.Scope) error {
 	if err := Convert_v1_TrimaranSpec_To_config_TrimaranSpec(&in.TrimaranSpec, &out.TrimaranSpec, s); err != nil {
 		return err
@@ -322,6 +530,7 @@ func autoConvert_v1_MetricProviderSpec_To_config_MetricProviderSpec(in *MetricPr
 	if err := metav1.Convert_Pointer_bool_To_bool(&in.InsecureSkipVerify, &out.InsecureSkipVerify, s); err != nil {
 		return err
 	}
+	out.Queries = *(*[]config.MetricQuery)(unsafe.Pointer(&in.Queries))
 	return nil
 }
 
@@ -341,6 +550,7 @@ func autoConvert_config_MetricProviderSpec_To_v1_MetricProviderSpec(in *config.M
 	if err := metav1.Convert_bool_To_Pointer_bool(&in.InsecureSkipVerify, &out.InsecureSkipVerify, s); err != nil {
 		return err
 	}
+	out.Queries = *(*[]MetricQuery)(unsafe.Pointer(&in.Queries))
 	return nil
 }
 
@@ -349,6 +559,32 @@ func Convert_config_MetricProviderSpec_To_v1_MetricProviderSpec(in *config.Metri
 	return autoConvert_config_MetricProviderSpec_To_v1_MetricProviderSpec(in, out, s)
 }
 
+func autoConvert_v1_MetricQuery_To_config_MetricQuery(in *MetricQuery, out *config.MetricQuery, s conversion.Scope) error {
+	out.Type = in.Type
+	out.Operator = in.Operator
+	out.Query = in.Query
+	out.NodeLabel = in.NodeLabel
+	return nil
+}
+
+// Convert_v1_MetricQuery_To_config_MetricQuery is an autogenerated conversion function.
+func Convert_v1_MetricQuery_To_config_MetricQuery(in *MetricQuery, out *config.MetricQuery, s conversion.Scope) error {
+	return autoConvert_v1_MetricQuery_To_config_MetricQuery(in, out, s)
+}
+
+func autoConvert_config_MetricQuery_To_v1_MetricQuery(in *config.MetricQuery, out *MetricQuery, s conversion.Scope) error {
+	out.Type = in.Type
+	out.Operator = in.Operator
+	out.Query = in.Query
+	out.NodeLabel = in.NodeLabel
+	return nil
+}
+
+// Convert_config_MetricQuery_To_v1_MetricQuery is an autogenerated conversion function.
+func Convert_config_MetricQuery_To_v1_MetricQuery(in *config.MetricQuery, out *MetricQuery, s conversion.Scope) error {
+	return autoConvert_config_MetricQuery_To_v1_MetricQuery(in, out, s)
+}
+
 func autoConvert_v1_NetworkOverheadArgs_To_config_NetworkOverheadArgs(in *NetworkOverheadArgs, out *config.NetworkOverheadArgs, s conversion.Scope) error {
 	out.Namespaces = *(*[]string)(unsafe.Pointer(&in.Namespaces))
 	if err := metav1.Convert_Pointer_string_To_string(&in.WeightsName, &out.WeightsName, s); err != nil {
@@ -357,6 +593,7 @@ func autoConvert_v1_NetworkOverheadArgs_To_config_NetworkOverheadArgs(in *Networ
 	if err := metav1.Convert_Pointer_string_To_string(&in.NetworkTopologyName, &out.NetworkTopologyName, s); err != nil {
 		return err
 	}
+	out.TopologyKeys = *(*[]string)(unsafe.Pointer(&in.TopologyKeys))
 	return nil
 }
 
@@ -373,6 +610,7 @@ func autoConvert_config_NetworkOverheadArgs_To_v1_NetworkOverheadArgs(in *config
 	if err := metav1.Convert_string_To_Pointer_string(&in.NetworkTopologyName, &out.NetworkTopologyName, s); err != nil {
 		return err
 	}
+	out.TopologyKeys = *(*[]string)(unsafe.Pointer(&in.TopologyKeys))
 	return nil
 }
 
@@ -412,6 +650,8 @@ func autoConvert_v1_NodeResourceTopologyCache_To_config_NodeResourceTopologyCach
 	out.ResyncMethod = (*config.CacheResyncMethod)(unsafe.Pointer(in.ResyncMethod))
 	out.InformerMode = (*config.CacheInformerMode)(unsafe.Pointer(in.InformerMode))
 	out.ResyncScope = (*config.CacheResyncScope)(unsafe.Pointer(in.ResyncScope))
+	out.ReservationBackend = (*config.CacheReservationBackend)(unsafe.Pointer(in.ReservationBackend))
+	out.ReservationNamespace = (*string)(unsafe.Pointer(in.ReservationNamespace))
 	return nil
 }
 
@@ -425,6 +665,8 @@ func autoConvert_config_NodeResourceTopologyCache_To_v1_NodeResourceTopologyCach
 	out.ResyncMethod = (*CacheResyncMethod)(unsafe.Pointer(in.ResyncMethod))
 	out.InformerMode = (*CacheInformerMode)(unsafe.Pointer(in.InformerMode))
 	out.ResyncScope = (*CacheResyncScope)(unsafe.Pointer(in.ResyncScope))
+	out.ReservationBackend = (*CacheReservationBackend)(unsafe.Pointer(in.ReservationBackend))
+	out.ReservationNamespace = (*string)(unsafe.Pointer(in.ReservationNamespace))
 	return nil
 }
 
@@ -551,6 +793,28 @@ func Convert_config_PreemptionTolerationArgs_To_v1_PreemptionTolerationArgs(in *
 	return autoConvert_config_PreemptionTolerationArgs_To_v1_PreemptionTolerationArgs(in, out, s)
 }
 
+func autoConvert_v1_ResourceTarget_To_config_ResourceTarget(in *ResourceTarget, out *config.ResourceTarget, s conversion.Scope) error {
+	out.TargetUtilization = in.TargetUtilization
+	out.Weight = in.Weight
+	return nil
+}
+
+// Convert_v1_ResourceTarget_To_config_ResourceTarget is an autogenerated conversion function.
+func Convert_v1_ResourceTarget_To_config_ResourceTarget(in *ResourceTarget, out *config.ResourceTarget, s conversion.Scope) error {
+	return autoConvert_v1_ResourceTarget_To_config_ResourceTarget(in, out, s)
+}
+
+func autoConvert_config_ResourceTarget_To_v1_ResourceTarget(in *config.ResourceTarget, out *ResourceTarget, s conversion.Scope) error {
+	out.TargetUtilization = in.TargetUtilization
+	out.Weight = in.Weight
+	return nil
+}
+
+// Convert_config_ResourceTarget_To_v1_ResourceTarget is an autogenerated conversion function.
+func Convert_config_ResourceTarget_To_v1_ResourceTarget(in *config.ResourceTarget, out *ResourceTarget, s conversion.Scope) error {
+	return autoConvert_config_ResourceTarget_To_v1_ResourceTarget(in, out, s)
+}
+
 func autoConvert_v1_ScoringStrategy_To_config_ScoringStrategy(in *ScoringStrategy, out *config.ScoringStrategy, s conversion.Scope) error {
 	out.Type = config.ScoringStrategyType(in.Type)
 	out.Resources = *(*[]apisconfig.ResourceSpec)(unsafe.Pointer(&in.Resources))
@@ -580,6 +844,11 @@ func autoConvert_v1_SySchedArgs_To_config_SySchedArgs(in *SySchedArgs, out *conf
 	if err := metav1.Convert_Pointer_string_To_string(&in.DefaultProfileName, &out.DefaultProfileName, s); err != nil {
 		return err
 	}
+	if err := metav1.Convert_Pointer_string_To_string(&in.SensitivePodLabel, &out.SensitivePodLabel, s); err != nil {
+		return err
+	}
+	out.DeniedSyscalls = *(*[]string)(unsafe.Pointer(&in.DeniedSyscalls))
+	out.MaxExtraneousSyscalls = (*int64)(unsafe.Pointer(in.MaxExtraneousSyscalls))
 	return nil
 }
 
@@ -595,6 +864,11 @@ func autoConvert_config_SySchedArgs_To_v1_SySchedArgs(in *config.SySchedArgs, ou
 	if err := metav1.Convert_string_To_Pointer_string(&in.DefaultProfileName, &out.DefaultProfileName, s); err != nil {
 		return err
 	}
+	if err := metav1.Convert_string_To_Pointer_string(&in.SensitivePodLabel, &out.SensitivePodLabel, s); err != nil {
+		return err
+	}
+	out.DeniedSyscalls = *(*[]string)(unsafe.Pointer(&in.DeniedSyscalls))
+	out.MaxExtraneousSyscalls = (*int64)(unsafe.Pointer(in.MaxExtraneousSyscalls))
 	return nil
 }
 
@@ -614,6 +888,7 @@ func autoConvert_v1_TargetLoadPackingArgs_To_config_TargetLoadPackingArgs(in *Ta
 	if err := metav1.Convert_Pointer_int64_To_int64(&in.TargetUtilization, &out.TargetUtilization, s); err != nil {
 		return err
 	}
+	out.ResourceTargets = *(*map[corev1.ResourceName]config.ResourceTarget)(unsafe.Pointer(&in.ResourceTargets))
 	return nil
 }
 
@@ -633,6 +908,7 @@ func autoConvert_config_TargetLoadPackingArgs_To_v1_TargetLoadPackingArgs(in *co
 	if err := metav1.Convert_int64_To_Pointer_int64(&in.TargetUtilization, &out.TargetUtilization, s); err != nil {
 		return err
 	}
+	out.ResourceTargets = *(*map[corev1.ResourceName]ResourceTarget)(unsafe.Pointer(&in.ResourceTargets))
 	return nil
 }
 
@@ -668,6 +944,12 @@ func autoConvert_v1_TrimaranSpec_To_config_TrimaranSpec(in *TrimaranSpec, out *c
 	if err := metav1.Convert_Pointer_string_To_string(&in.WatcherAddress, &out.WatcherAddress, s); err != nil {
 		return err
 	}
+	if err := metav1.Convert_Pointer_int64_To_int64(&in.MetricsRefreshIntervalSeconds, &out.MetricsRefreshIntervalSeconds, s); err != nil {
+		return err
+	}
+	if err := metav1.Convert_Pointer_int64_To_int64(&in.MetricsRetentionSeconds, &out.MetricsRetentionSeconds, s); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -683,6 +965,12 @@ func autoConvert_config_TrimaranSpec_To_v1_TrimaranSpec(in *config.TrimaranSpec,
 	if err := metav1.Convert_string_To_Pointer_string(&in.WatcherAddress, &out.WatcherAddress, s); err != nil {
 		return err
 	}
+	if err := metav1.Convert_int64_To_Pointer_int64(&in.MetricsRefreshIntervalSeconds, &out.MetricsRefreshIntervalSeconds, s); err != nil {
+		return err
+	}
+	if err := metav1.Convert_int64_To_Pointer_int64(&in.MetricsRetentionSeconds, &out.MetricsRetentionSeconds, s); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -62,6 +62,147 @@ func (in *CoschedulingArgs) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DiskIOArgs) DeepCopyInto(out *DiskIOArgs) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiskIOArgs.
+func (in *DiskIOArgs) DeepCopy() *DiskIOArgs {
+	if in == nil {
+		return nil
+	}
+	out := new(DiskIOArgs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DiskIOArgs) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EnergyCostArgs) DeepCopyInto(out *EnergyCostArgs) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.TrimaranSpec.DeepCopyInto(&out.TrimaranSpec)
+	if in.NodePowerModel != nil {
+		in, out := &in.NodePowerModel, &out.NodePowerModel
+		*out = make(map[string]PowerModel, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ZoneLabel != nil {
+		in, out := &in.ZoneLabel, &out.ZoneLabel
+		*out = new(string)
+		**out = **in
+	}
+	in.IntensitySource.DeepCopyInto(&out.IntensitySource)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnergyCostArgs.
+func (in *EnergyCostArgs) DeepCopy() *EnergyCostArgs {
+	if in == nil {
+		return nil
+	}
+	out := new(EnergyCostArgs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *EnergyCostArgs) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IntensitySourceSpec) DeepCopyInto(out *IntensitySourceSpec) {
+	*out = *in
+	if in.ConfigMapNamespace != nil {
+		in, out := &in.ConfigMapNamespace, &out.ConfigMapNamespace
+		*out = new(string)
+		**out = **in
+	}
+	if in.ConfigMapName != nil {
+		in, out := &in.ConfigMapName, &out.ConfigMapName
+		*out = new(string)
+		**out = **in
+	}
+	if in.Address != nil {
+		in, out := &in.Address, &out.Address
+		*out = new(string)
+		**out = **in
+	}
+	if in.RefreshIntervalSeconds != nil {
+		in, out := &in.RefreshIntervalSeconds, &out.RefreshIntervalSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IntensitySourceSpec.
+func (in *IntensitySourceSpec) DeepCopy() *IntensitySourceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IntensitySourceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoadThresholdArgs) DeepCopyInto(out *LoadThresholdArgs) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.TrimaranSpec.DeepCopyInto(&out.TrimaranSpec)
+	if in.CPUThreshold != nil {
+		in, out := &in.CPUThreshold, &out.CPUThreshold
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MemoryThreshold != nil {
+		in, out := &in.MemoryThreshold, &out.MemoryThreshold
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MaxMetricsAgeSeconds != nil {
+		in, out := &in.MaxMetricsAgeSeconds, &out.MaxMetricsAgeSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadThresholdArgs.
+func (in *LoadThresholdArgs) DeepCopy() *LoadThresholdArgs {
+	if in == nil {
+		return nil
+	}
+	out := new(LoadThresholdArgs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *LoadThresholdArgs) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LoadVariationRiskBalancingArgs) DeepCopyInto(out *LoadVariationRiskBalancingArgs) {
 	*out = *in
@@ -154,6 +295,11 @@ func (in *MetricProviderSpec) DeepCopyInto(out *MetricProviderSpec) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.Queries != nil {
+		in, out := &in.Queries, &out.Queries
+		*out = make([]MetricQuery, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -167,6 +313,22 @@ func (in *MetricProviderSpec) DeepCopy() *MetricProviderSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetricQuery) DeepCopyInto(out *MetricQuery) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetricQuery.
+func (in *MetricQuery) DeepCopy() *MetricQuery {
+	if in == nil {
+		return nil
+	}
+	out := new(MetricQuery)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NetworkOverheadArgs) DeepCopyInto(out *NetworkOverheadArgs) {
 	*out = *in
@@ -186,6 +348,11 @@ func (in *NetworkOverheadArgs) DeepCopyInto(out *NetworkOverheadArgs) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.TopologyKeys != nil {
+		in, out := &in.TopologyKeys, &out.TopologyKeys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -280,6 +447,16 @@ func (in *NodeResourceTopologyCache) DeepCopyInto(out *NodeResourceTopologyCache
 		*out = new(CacheResyncScope)
 		**out = **in
 	}
+	if in.ReservationBackend != nil {
+		in, out := &in.ReservationBackend, &out.ReservationBackend
+		*out = new(CacheReservationBackend)
+		**out = **in
+	}
+	if in.ReservationNamespace != nil {
+		in, out := &in.ReservationNamespace, &out.ReservationNamespace
+		*out = new(string)
+		**out = **in
+	}
 	return
 }
 
@@ -446,6 +623,22 @@ func (in *PreemptionTolerationArgs) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceTarget) DeepCopyInto(out *ResourceTarget) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceTarget.
+func (in *ResourceTarget) DeepCopy() *ResourceTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ScoringStrategy) DeepCopyInto(out *ScoringStrategy) {
 	*out = *in
@@ -481,6 +674,21 @@ func (in *SySchedArgs) DeepCopyInto(out *SySchedArgs) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.SensitivePodLabel != nil {
+		in, out := &in.SensitivePodLabel, &out.SensitivePodLabel
+		*out = new(string)
+		**out = **in
+	}
+	if in.DeniedSyscalls != nil {
+		in, out := &in.DeniedSyscalls, &out.DeniedSyscalls
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.MaxExtraneousSyscalls != nil {
+		in, out := &in.MaxExtraneousSyscalls, &out.MaxExtraneousSyscalls
+		*out = new(int64)
+		**out = **in
+	}
 	return
 }
 
@@ -524,6 +732,13 @@ func (in *TargetLoadPackingArgs) DeepCopyInto(out *TargetLoadPackingArgs) {
 		*out = new(int64)
 		**out = **in
 	}
+	if in.ResourceTargets != nil {
+		in, out := &in.ResourceTargets, &out.ResourceTargets
+		*out = make(map[corev1.ResourceName]ResourceTarget, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	return
 }
 
@@ -584,6 +799,16 @@ func (in *TrimaranSpec) DeepCopyInto(out *TrimaranSpec) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.MetricsRefreshIntervalSeconds != nil {
+		in, out := &in.MetricsRefreshIntervalSeconds, &out.MetricsRefreshIntervalSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MetricsRetentionSeconds != nil {
+		in, out := &in.MetricsRetentionSeconds, &out.MetricsRetentionSeconds
+		*out = new(int64)
+		**out = **in
+	}
 	return
 }
 
@@ -30,6 +30,9 @@ import (
 // All generated defaulters are covering - they call all nested defaulters.
 func RegisterDefaults(scheme *runtime.Scheme) error {
 	scheme.AddTypeDefaultingFunc(&CoschedulingArgs{}, func(obj interface{}) { SetObjectDefaults_CoschedulingArgs(obj.(*CoschedulingArgs)) })
+	scheme.AddTypeDefaultingFunc(&DiskIOArgs{}, func(obj interface{}) { SetObjectDefaults_DiskIOArgs(obj.(*DiskIOArgs)) })
+	scheme.AddTypeDefaultingFunc(&EnergyCostArgs{}, func(obj interface{}) { SetObjectDefaults_EnergyCostArgs(obj.(*EnergyCostArgs)) })
+	scheme.AddTypeDefaultingFunc(&LoadThresholdArgs{}, func(obj interface{}) { SetObjectDefaults_LoadThresholdArgs(obj.(*LoadThresholdArgs)) })
 	scheme.AddTypeDefaultingFunc(&LoadVariationRiskBalancingArgs{}, func(obj interface{}) {
 		SetObjectDefaults_LoadVariationRiskBalancingArgs(obj.(*LoadVariationRiskBalancingArgs))
 	})
@@ -53,6 +56,18 @@ func SetObjectDefaults_CoschedulingArgs(in *CoschedulingArgs) {
 	SetDefaults_CoschedulingArgs(in)
 }
 
+func SetObjectDefaults_DiskIOArgs(in *DiskIOArgs) {
+	SetDefaults_DiskIOArgs(in)
+}
+
+func SetObjectDefaults_EnergyCostArgs(in *EnergyCostArgs) {
+	SetDefaults_EnergyCostArgs(in)
+}
+
+func SetObjectDefaults_LoadThresholdArgs(in *LoadThresholdArgs) {
+	SetDefaults_LoadThresholdArgs(in)
+}
+
 func SetObjectDefaults_LoadVariationRiskBalancingArgs(in *LoadVariationRiskBalancingArgs) {
 	SetDefaults_LoadVariationRiskBalancingArgs(in)
 }
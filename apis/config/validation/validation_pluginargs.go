@@ -19,6 +19,7 @@ package validation
 import (
 	"fmt"
 
+	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	schedconfig "k8s.io/kubernetes/pkg/scheduler/apis/config"
@@ -42,6 +43,7 @@ func init() {
 		string(config.BalancedAllocation),
 		string(config.LeastAllocated),
 		string(config.LeastNUMANodes),
+		string(config.DeviceNUMAAffinity),
 	)
 }
 
@@ -163,3 +165,152 @@ func ValidateNodeMetadataArgs(args *config.NodeMetadataArgs, path *field.Path) e
 	}
 	return allErrs.ToAggregate()
 }
+
+func ValidateNetworkOverheadArgs(args *config.NetworkOverheadArgs, path *field.Path) error {
+	var allErrs field.ErrorList
+	seen := sets.New[string]()
+	for i, key := range args.TopologyKeys {
+		if key == "" {
+			allErrs = append(allErrs, field.Invalid(path.Child("topologyKeys").Index(i), key, "topology key cannot be empty"))
+		} else if seen.Has(key) {
+			allErrs = append(allErrs, field.Duplicate(path.Child("topologyKeys").Index(i), key))
+		}
+		seen.Insert(key)
+	}
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return allErrs.ToAggregate()
+}
+
+func ValidateDiskIOArgs(args *config.DiskIOArgs, path *field.Path) error {
+	var allErrs field.ErrorList
+	if args.ScoringStrategy != config.DiskIOLeastAllocated && args.ScoringStrategy != config.DiskIOMostAllocated {
+		allErrs = append(allErrs, field.Invalid(path.Child("scoringStrategy"),
+			args.ScoringStrategy, "scoringStrategy must be either \"LeastAllocated\" or \"MostAllocated\""))
+	}
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return allErrs.ToAggregate()
+}
+
+func ValidateSySchedArgs(args *config.SySchedArgs, path *field.Path) error {
+	var allErrs field.ErrorList
+	if args.SensitivePodLabel != "" {
+		allErrs = append(allErrs, metav1validation.ValidateLabelName(args.SensitivePodLabel, path.Child("sensitivePodLabel"))...)
+	}
+	for i, syscall := range args.DeniedSyscalls {
+		if syscall == "" {
+			allErrs = append(allErrs, field.Invalid(path.Child("deniedSyscalls").Index(i), syscall, "syscall cannot be empty"))
+		}
+	}
+	if args.MaxExtraneousSyscalls != nil && *args.MaxExtraneousSyscalls < 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("maxExtraneousSyscalls"),
+			*args.MaxExtraneousSyscalls, "maxExtraneousSyscalls should be a non-negative value"))
+	}
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return allErrs.ToAggregate()
+}
+
+func ValidateTrimaranSpec(spec *config.TrimaranSpec, path *field.Path) error {
+	var allErrs field.ErrorList
+	if spec.MetricsRefreshIntervalSeconds < 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("metricsRefreshIntervalSeconds"),
+			spec.MetricsRefreshIntervalSeconds, "metricsRefreshIntervalSeconds should be a non-negative value"))
+	}
+	if spec.MetricsRetentionSeconds < 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("metricsRetentionSeconds"),
+			spec.MetricsRetentionSeconds, "metricsRetentionSeconds should be a non-negative value"))
+	} else if spec.MetricsRetentionSeconds > 0 && spec.MetricsRetentionSeconds < spec.MetricsRefreshIntervalSeconds {
+		allErrs = append(allErrs, field.Invalid(path.Child("metricsRetentionSeconds"),
+			spec.MetricsRetentionSeconds, "metricsRetentionSeconds should not be less than metricsRefreshIntervalSeconds"))
+	}
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return allErrs.ToAggregate()
+}
+
+func ValidateTargetLoadPackingArgs(args *config.TargetLoadPackingArgs, path *field.Path) error {
+	var allErrs field.ErrorList
+	for resourceName, target := range args.ResourceTargets {
+		p := path.Child("resourceTargets").Key(string(resourceName))
+		if target.TargetUtilization <= 0 || target.TargetUtilization > 100 {
+			allErrs = append(allErrs, field.Invalid(p.Child("targetUtilization"),
+				target.TargetUtilization, "targetUtilization should be between 1 and 100"))
+		}
+		if target.Weight <= 0 {
+			allErrs = append(allErrs, field.Invalid(p.Child("weight"), target.Weight, "weight should be a positive value"))
+		}
+	}
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return allErrs.ToAggregate()
+}
+
+func ValidateEnergyCostArgs(args *config.EnergyCostArgs, path *field.Path) error {
+	var allErrs field.ErrorList
+	allErrs = append(allErrs, metav1validation.ValidateLabelName(args.ZoneLabel, path.Child("zoneLabel"))...)
+	source := args.IntensitySource
+	sourcePath := path.Child("intensitySource")
+	switch source.Type {
+	case config.ConfigMapIntensitySource:
+		if source.ConfigMapNamespace == "" {
+			allErrs = append(allErrs, field.Required(sourcePath.Child("configMapNamespace"), "configMapNamespace is required for the ConfigMap source"))
+		}
+		if source.ConfigMapName == "" {
+			allErrs = append(allErrs, field.Required(sourcePath.Child("configMapName"), "configMapName is required for the ConfigMap source"))
+		}
+	case config.HTTPIntensitySource:
+		if source.Address == "" {
+			allErrs = append(allErrs, field.Required(sourcePath.Child("address"), "address is required for the HTTP source"))
+		}
+		if source.RefreshIntervalSeconds <= 0 {
+			allErrs = append(allErrs, field.Invalid(sourcePath.Child("refreshIntervalSeconds"),
+				source.RefreshIntervalSeconds, "refreshIntervalSeconds should be a positive value"))
+		}
+	default:
+		allErrs = append(allErrs, field.NotSupported(sourcePath.Child("type"), source.Type,
+			[]string{string(config.ConfigMapIntensitySource), string(config.HTTPIntensitySource)}))
+	}
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return allErrs.ToAggregate()
+}
+
+func ValidateLoadThresholdArgs(args *config.LoadThresholdArgs, path *field.Path) error {
+	var allErrs field.ErrorList
+	if args.CPUThreshold < 0 || args.CPUThreshold > 100 {
+		allErrs = append(allErrs, field.Invalid(path.Child("cpuThreshold"),
+			args.CPUThreshold, "cpuThreshold should be between 0 and 100"))
+	}
+	if args.MemoryThreshold < 0 || args.MemoryThreshold > 100 {
+		allErrs = append(allErrs, field.Invalid(path.Child("memoryThreshold"),
+			args.MemoryThreshold, "memoryThreshold should be between 0 and 100"))
+	}
+	switch args.Utilization {
+	case config.ObservedUtilization, config.PredictedUtilization:
+	default:
+		allErrs = append(allErrs, field.NotSupported(path.Child("utilization"), args.Utilization,
+			[]string{string(config.ObservedUtilization), string(config.PredictedUtilization)}))
+	}
+	switch args.MetricsGracePolicy {
+	case config.AllowMetricsGracePolicy, config.RejectMetricsGracePolicy:
+	default:
+		allErrs = append(allErrs, field.NotSupported(path.Child("metricsGracePolicy"), args.MetricsGracePolicy,
+			[]string{string(config.AllowMetricsGracePolicy), string(config.RejectMetricsGracePolicy)}))
+	}
+	if args.MaxMetricsAgeSeconds <= 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("maxMetricsAgeSeconds"),
+			args.MaxMetricsAgeSeconds, "maxMetricsAgeSeconds should be a positive value"))
+	}
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return allErrs.ToAggregate()
+}
@@ -23,7 +23,10 @@ import (
 
 	gocmp "github.com/google/go-cmp/cmp"
 
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	schedconfig "k8s.io/kubernetes/pkg/scheduler/apis/config"
+	"k8s.io/utils/ptr"
 
 	"sigs.k8s.io/scheduler-plugins/apis/config"
 )
@@ -42,6 +45,14 @@ func TestValidateNodeResourceTopologyMatchArgs(t *testing.T) {
 				},
 			},
 		},
+		{
+			description: "correct config, DeviceNUMAAffinity ScoringStrategy type",
+			args: &config.NodeResourceTopologyMatchArgs{
+				ScoringStrategy: config.ScoringStrategy{
+					Type: config.DeviceNUMAAffinity,
+				},
+			},
+		},
 		{
 			description: "incorrect config, wrong ScoringStrategy type",
 			args: &config.NodeResourceTopologyMatchArgs{
@@ -319,3 +330,477 @@ func TestValidateNodeMetadataArgs(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateDiskIOArgs(t *testing.T) {
+	testCases := []struct {
+		args        *config.DiskIOArgs
+		expectedErr error
+		description string
+	}{
+		{
+			description: "correct config with LeastAllocated",
+			args: &config.DiskIOArgs{
+				ScoringStrategy: config.DiskIOLeastAllocated,
+			},
+		},
+		{
+			description: "correct config with MostAllocated",
+			args: &config.DiskIOArgs{
+				ScoringStrategy: config.DiskIOMostAllocated,
+			},
+		},
+		{
+			description: "invalid ScoringStrategy",
+			args: &config.DiskIOArgs{
+				ScoringStrategy: "Balanced",
+			},
+			expectedErr: fmt.Errorf("scoringStrategy must be either \"LeastAllocated\" or \"MostAllocated\""),
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			err := ValidateDiskIOArgs(testCase.args, nil)
+			if testCase.expectedErr != nil {
+				if err == nil {
+					t.Fatalf("expected err to equal %v not nil", testCase.expectedErr)
+				}
+				if !strings.Contains(err.Error(), testCase.expectedErr.Error()) {
+					t.Fatalf("expected err to contain %s in error message: %s", testCase.expectedErr.Error(), err.Error())
+				}
+			}
+			if testCase.expectedErr == nil && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestValidateNetworkOverheadArgs(t *testing.T) {
+	testCases := []struct {
+		args        *config.NetworkOverheadArgs
+		expectedErr error
+		description string
+	}{
+		{
+			description: "correct config",
+			args: &config.NetworkOverheadArgs{
+				TopologyKeys: []string{"kubernetes.io/hostname", "example.com/rack", "topology.kubernetes.io/zone"},
+			},
+		},
+		{
+			description: "empty topology key",
+			args: &config.NetworkOverheadArgs{
+				TopologyKeys: []string{"topology.kubernetes.io/zone", ""},
+			},
+			expectedErr: fmt.Errorf("topology key cannot be empty"),
+		},
+		{
+			description: "duplicate topology key",
+			args: &config.NetworkOverheadArgs{
+				TopologyKeys: []string{"topology.kubernetes.io/zone", "topology.kubernetes.io/zone"},
+			},
+			expectedErr: fmt.Errorf("Duplicate value: \"topology.kubernetes.io/zone\""),
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			err := ValidateNetworkOverheadArgs(testCase.args, field.NewPath("args"))
+			if testCase.expectedErr != nil {
+				if err == nil {
+					t.Fatalf("expected err to equal %v not nil", testCase.expectedErr)
+				}
+				if !strings.Contains(err.Error(), testCase.expectedErr.Error()) {
+					t.Fatalf("expected err to contain %s in error message: %s", testCase.expectedErr.Error(), err.Error())
+				}
+			}
+			if testCase.expectedErr == nil && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestValidateSySchedArgs(t *testing.T) {
+	testCases := []struct {
+		args        *config.SySchedArgs
+		expectedErr error
+		description string
+	}{
+		{
+			description: "default config",
+			args: &config.SySchedArgs{
+				SensitivePodLabel: "sysched.scheduling.x-k8s.io/sensitive",
+			},
+		},
+		{
+			description: "correct filter config",
+			args: &config.SySchedArgs{
+				SensitivePodLabel:     "sysched.scheduling.x-k8s.io/sensitive",
+				DeniedSyscalls:        []string{"ptrace", "mount"},
+				MaxExtraneousSyscalls: ptr.To[int64](0),
+			},
+		},
+		{
+			description: "invalid sensitive pod label",
+			args: &config.SySchedArgs{
+				SensitivePodLabel: "not a label",
+			},
+			expectedErr: fmt.Errorf("sensitivePodLabel"),
+		},
+		{
+			description: "empty denied syscall",
+			args: &config.SySchedArgs{
+				DeniedSyscalls: []string{"ptrace", ""},
+			},
+			expectedErr: fmt.Errorf("syscall cannot be empty"),
+		},
+		{
+			description: "negative max extraneous syscalls",
+			args: &config.SySchedArgs{
+				MaxExtraneousSyscalls: ptr.To[int64](-1),
+			},
+			expectedErr: fmt.Errorf("maxExtraneousSyscalls should be a non-negative value"),
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			err := ValidateSySchedArgs(testCase.args, nil)
+			if testCase.expectedErr != nil {
+				if err == nil {
+					t.Fatalf("expected err to equal %v not nil", testCase.expectedErr)
+				}
+				if !strings.Contains(err.Error(), testCase.expectedErr.Error()) {
+					t.Fatalf("expected err to contain %s in error message: %s", testCase.expectedErr.Error(), err.Error())
+				}
+			}
+			if testCase.expectedErr == nil && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestValidateTrimaranSpec(t *testing.T) {
+	testCases := []struct {
+		spec        *config.TrimaranSpec
+		expectedErr error
+		description string
+	}{
+		{
+			description: "default config",
+			spec: &config.TrimaranSpec{
+				MetricsRefreshIntervalSeconds: 30,
+				MetricsRetentionSeconds:       900,
+			},
+		},
+		{
+			description: "unset intervals",
+			spec:        &config.TrimaranSpec{},
+		},
+		{
+			description: "negative refresh interval",
+			spec: &config.TrimaranSpec{
+				MetricsRefreshIntervalSeconds: -30,
+				MetricsRetentionSeconds:       900,
+			},
+			expectedErr: fmt.Errorf("metricsRefreshIntervalSeconds should be a non-negative value"),
+		},
+		{
+			description: "negative retention",
+			spec: &config.TrimaranSpec{
+				MetricsRefreshIntervalSeconds: 30,
+				MetricsRetentionSeconds:       -1,
+			},
+			expectedErr: fmt.Errorf("metricsRetentionSeconds should be a non-negative value"),
+		},
+		{
+			description: "retention shorter than the refresh interval",
+			spec: &config.TrimaranSpec{
+				MetricsRefreshIntervalSeconds: 60,
+				MetricsRetentionSeconds:       30,
+			},
+			expectedErr: fmt.Errorf("metricsRetentionSeconds should not be less than metricsRefreshIntervalSeconds"),
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			err := ValidateTrimaranSpec(testCase.spec, nil)
+			if testCase.expectedErr != nil {
+				if err == nil {
+					t.Fatalf("expected err to equal %v not nil", testCase.expectedErr)
+				}
+				if !strings.Contains(err.Error(), testCase.expectedErr.Error()) {
+					t.Fatalf("expected err to contain %s in error message: %s", testCase.expectedErr.Error(), err.Error())
+				}
+			}
+			if testCase.expectedErr == nil && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestValidateTargetLoadPackingArgs(t *testing.T) {
+	testCases := []struct {
+		args        *config.TargetLoadPackingArgs
+		expectedErr error
+		description string
+	}{
+		{
+			description: "default config",
+			args: &config.TargetLoadPackingArgs{
+				TargetUtilization: 40,
+			},
+		},
+		{
+			description: "correct resource targets",
+			args: &config.TargetLoadPackingArgs{
+				ResourceTargets: map[v1.ResourceName]config.ResourceTarget{
+					v1.ResourceCPU:    {TargetUtilization: 40, Weight: 1},
+					v1.ResourceMemory: {TargetUtilization: 100, Weight: 2},
+				},
+			},
+		},
+		{
+			description: "target utilization above 100",
+			args: &config.TargetLoadPackingArgs{
+				ResourceTargets: map[v1.ResourceName]config.ResourceTarget{
+					v1.ResourceMemory: {TargetUtilization: 120, Weight: 1},
+				},
+			},
+			expectedErr: fmt.Errorf("resourceTargets[memory].targetUtilization"),
+		},
+		{
+			description: "zero weight",
+			args: &config.TargetLoadPackingArgs{
+				ResourceTargets: map[v1.ResourceName]config.ResourceTarget{
+					v1.ResourceCPU: {TargetUtilization: 40},
+				},
+			},
+			expectedErr: fmt.Errorf("weight should be a positive value"),
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			err := ValidateTargetLoadPackingArgs(testCase.args, nil)
+			if testCase.expectedErr != nil {
+				if err == nil {
+					t.Fatalf("expected err to equal %v not nil", testCase.expectedErr)
+				}
+				if !strings.Contains(err.Error(), testCase.expectedErr.Error()) {
+					t.Fatalf("expected err to contain %s in error message: %s", testCase.expectedErr.Error(), err.Error())
+				}
+			}
+			if testCase.expectedErr == nil && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestValidateEnergyCostArgs(t *testing.T) {
+	testCases := []struct {
+		args        *config.EnergyCostArgs
+		expectedErr error
+		description string
+	}{
+		{
+			description: "default config",
+			args: &config.EnergyCostArgs{
+				ZoneLabel: v1.LabelTopologyZone,
+				IntensitySource: config.IntensitySourceSpec{
+					Type:               config.ConfigMapIntensitySource,
+					ConfigMapNamespace: "kube-system",
+					ConfigMapName:      "energy-cost-intensity",
+				},
+			},
+		},
+		{
+			description: "correct HTTP source",
+			args: &config.EnergyCostArgs{
+				ZoneLabel: v1.LabelTopologyRegion,
+				IntensitySource: config.IntensitySourceSpec{
+					Type:                   config.HTTPIntensitySource,
+					Address:                "http://intensity:8080",
+					RefreshIntervalSeconds: 60,
+				},
+			},
+		},
+		{
+			description: "invalid zone label",
+			args: &config.EnergyCostArgs{
+				ZoneLabel: "invalid label",
+				IntensitySource: config.IntensitySourceSpec{
+					Type:               config.ConfigMapIntensitySource,
+					ConfigMapNamespace: "kube-system",
+					ConfigMapName:      "energy-cost-intensity",
+				},
+			},
+			expectedErr: fmt.Errorf("zoneLabel"),
+		},
+		{
+			description: "ConfigMap source without name",
+			args: &config.EnergyCostArgs{
+				ZoneLabel: v1.LabelTopologyZone,
+				IntensitySource: config.IntensitySourceSpec{
+					Type:               config.ConfigMapIntensitySource,
+					ConfigMapNamespace: "kube-system",
+				},
+			},
+			expectedErr: fmt.Errorf("intensitySource.configMapName"),
+		},
+		{
+			description: "HTTP source without address",
+			args: &config.EnergyCostArgs{
+				ZoneLabel: v1.LabelTopologyZone,
+				IntensitySource: config.IntensitySourceSpec{
+					Type:                   config.HTTPIntensitySource,
+					RefreshIntervalSeconds: 60,
+				},
+			},
+			expectedErr: fmt.Errorf("intensitySource.address"),
+		},
+		{
+			description: "HTTP source without refresh interval",
+			args: &config.EnergyCostArgs{
+				ZoneLabel: v1.LabelTopologyZone,
+				IntensitySource: config.IntensitySourceSpec{
+					Type:    config.HTTPIntensitySource,
+					Address: "http://intensity:8080",
+				},
+			},
+			expectedErr: fmt.Errorf("refreshIntervalSeconds should be a positive value"),
+		},
+		{
+			description: "unknown source type",
+			args: &config.EnergyCostArgs{
+				ZoneLabel: v1.LabelTopologyZone,
+				IntensitySource: config.IntensitySourceSpec{
+					Type: "File",
+				},
+			},
+			expectedErr: fmt.Errorf("intensitySource.type"),
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			err := ValidateEnergyCostArgs(testCase.args, nil)
+			if testCase.expectedErr != nil {
+				if err == nil {
+					t.Fatalf("expected err to equal %v not nil", testCase.expectedErr)
+				}
+				if !strings.Contains(err.Error(), testCase.expectedErr.Error()) {
+					t.Fatalf("expected err to contain %s in error message: %s", testCase.expectedErr.Error(), err.Error())
+				}
+			}
+			if testCase.expectedErr == nil && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestValidateLoadThresholdArgs(t *testing.T) {
+	testCases := []struct {
+		args        *config.LoadThresholdArgs
+		expectedErr error
+		description string
+	}{
+		{
+			description: "default config",
+			args: &config.LoadThresholdArgs{
+				CPUThreshold:         90,
+				MemoryThreshold:      90,
+				Utilization:          config.PredictedUtilization,
+				MetricsGracePolicy:   config.AllowMetricsGracePolicy,
+				MaxMetricsAgeSeconds: 300,
+			},
+		},
+		{
+			description: "memory not checked",
+			args: &config.LoadThresholdArgs{
+				CPUThreshold:         80,
+				Utilization:          config.ObservedUtilization,
+				MetricsGracePolicy:   config.RejectMetricsGracePolicy,
+				MaxMetricsAgeSeconds: 60,
+			},
+		},
+		{
+			description: "CPU threshold above 100",
+			args: &config.LoadThresholdArgs{
+				CPUThreshold:         101,
+				MemoryThreshold:      90,
+				Utilization:          config.PredictedUtilization,
+				MetricsGracePolicy:   config.AllowMetricsGracePolicy,
+				MaxMetricsAgeSeconds: 300,
+			},
+			expectedErr: fmt.Errorf("cpuThreshold should be between 0 and 100"),
+		},
+		{
+			description: "negative memory threshold",
+			args: &config.LoadThresholdArgs{
+				CPUThreshold:         90,
+				MemoryThreshold:      -1,
+				Utilization:          config.PredictedUtilization,
+				MetricsGracePolicy:   config.AllowMetricsGracePolicy,
+				MaxMetricsAgeSeconds: 300,
+			},
+			expectedErr: fmt.Errorf("memoryThreshold should be between 0 and 100"),
+		},
+		{
+			description: "unknown utilization",
+			args: &config.LoadThresholdArgs{
+				CPUThreshold:         90,
+				MemoryThreshold:      90,
+				Utilization:          "Peak",
+				MetricsGracePolicy:   config.AllowMetricsGracePolicy,
+				MaxMetricsAgeSeconds: 300,
+			},
+			expectedErr: fmt.Errorf("utilization"),
+		},
+		{
+			description: "unknown grace policy",
+			args: &config.LoadThresholdArgs{
+				CPUThreshold:         90,
+				MemoryThreshold:      90,
+				Utilization:          config.PredictedUtilization,
+				MetricsGracePolicy:   "MinimumScore",
+				MaxMetricsAgeSeconds: 300,
+			},
+			expectedErr: fmt.Errorf("metricsGracePolicy"),
+		},
+		{
+			description: "non-positive max metrics age",
+			args: &config.LoadThresholdArgs{
+				CPUThreshold:       90,
+				MemoryThreshold:    90,
+				Utilization:        config.PredictedUtilization,
+				MetricsGracePolicy: config.AllowMetricsGracePolicy,
+			},
+			expectedErr: fmt.Errorf("maxMetricsAgeSeconds should be a positive value"),
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			err := ValidateLoadThresholdArgs(testCase.args, nil)
+			if testCase.expectedErr != nil {
+				if err == nil {
+					t.Fatalf("expected err to equal %v not nil", testCase.expectedErr)
+				}
+				if !strings.Contains(err.Error(), testCase.expectedErr.Error()) {
+					t.Fatalf("expected err to contain %s in error message: %s", testCase.expectedErr.Error(), err.Error())
+				}
+			}
+			if testCase.expectedErr == nil && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
@@ -52,11 +52,112 @@ func (in *CoschedulingArgs) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DiskIOArgs) DeepCopyInto(out *DiskIOArgs) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiskIOArgs.
+func (in *DiskIOArgs) DeepCopy() *DiskIOArgs {
+	if in == nil {
+		return nil
+	}
+	out := new(DiskIOArgs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DiskIOArgs) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EnergyCostArgs) DeepCopyInto(out *EnergyCostArgs) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.TrimaranSpec.DeepCopyInto(&out.TrimaranSpec)
+	if in.NodePowerModel != nil {
+		in, out := &in.NodePowerModel, &out.NodePowerModel
+		*out = make(map[string]PowerModel, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	out.IntensitySource = in.IntensitySource
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnergyCostArgs.
+func (in *EnergyCostArgs) DeepCopy() *EnergyCostArgs {
+	if in == nil {
+		return nil
+	}
+	out := new(EnergyCostArgs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *EnergyCostArgs) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IntensitySourceSpec) DeepCopyInto(out *IntensitySourceSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IntensitySourceSpec.
+func (in *IntensitySourceSpec) DeepCopy() *IntensitySourceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IntensitySourceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoadThresholdArgs) DeepCopyInto(out *LoadThresholdArgs) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.TrimaranSpec.DeepCopyInto(&out.TrimaranSpec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadThresholdArgs.
+func (in *LoadThresholdArgs) DeepCopy() *LoadThresholdArgs {
+	if in == nil {
+		return nil
+	}
+	out := new(LoadThresholdArgs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *LoadThresholdArgs) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LoadVariationRiskBalancingArgs) DeepCopyInto(out *LoadVariationRiskBalancingArgs) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
-	out.TrimaranSpec = in.TrimaranSpec
+	in.TrimaranSpec.DeepCopyInto(&out.TrimaranSpec)
 	return
 }
 
@@ -82,7 +183,7 @@ func (in *LoadVariationRiskBalancingArgs) DeepCopyObject() runtime.Object {
 func (in *LowRiskOverCommitmentArgs) DeepCopyInto(out *LowRiskOverCommitmentArgs) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
-	out.TrimaranSpec = in.TrimaranSpec
+	in.TrimaranSpec.DeepCopyInto(&out.TrimaranSpec)
 	if in.RiskLimitWeights != nil {
 		in, out := &in.RiskLimitWeights, &out.RiskLimitWeights
 		*out = make(map[v1.ResourceName]float64, len(*in))
@@ -114,6 +215,11 @@ func (in *LowRiskOverCommitmentArgs) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MetricProviderSpec) DeepCopyInto(out *MetricProviderSpec) {
 	*out = *in
+	if in.Queries != nil {
+		in, out := &in.Queries, &out.Queries
+		*out = make([]MetricQuery, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -127,6 +233,22 @@ func (in *MetricProviderSpec) DeepCopy() *MetricProviderSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetricQuery) DeepCopyInto(out *MetricQuery) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetricQuery.
+func (in *MetricQuery) DeepCopy() *MetricQuery {
+	if in == nil {
+		return nil
+	}
+	out := new(MetricQuery)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NetworkOverheadArgs) DeepCopyInto(out *NetworkOverheadArgs) {
 	*out = *in
@@ -136,6 +258,11 @@ func (in *NetworkOverheadArgs) DeepCopyInto(out *NetworkOverheadArgs) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.TopologyKeys != nil {
+		in, out := &in.TopologyKeys, &out.TopologyKeys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -205,6 +332,16 @@ func (in *NodeResourceTopologyCache) DeepCopyInto(out *NodeResourceTopologyCache
 		*out = new(CacheResyncScope)
 		**out = **in
 	}
+	if in.ReservationBackend != nil {
+		in, out := &in.ReservationBackend, &out.ReservationBackend
+		*out = new(CacheReservationBackend)
+		**out = **in
+	}
+	if in.ReservationNamespace != nil {
+		in, out := &in.ReservationNamespace, &out.ReservationNamespace
+		*out = new(string)
+		**out = **in
+	}
 	return
 }
 
@@ -352,6 +489,22 @@ func (in *PreemptionTolerationArgs) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceTarget) DeepCopyInto(out *ResourceTarget) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceTarget.
+func (in *ResourceTarget) DeepCopy() *ResourceTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ScoringStrategy) DeepCopyInto(out *ScoringStrategy) {
 	*out = *in
@@ -377,6 +530,16 @@ func (in *ScoringStrategy) DeepCopy() *ScoringStrategy {
 func (in *SySchedArgs) DeepCopyInto(out *SySchedArgs) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
+	if in.DeniedSyscalls != nil {
+		in, out := &in.DeniedSyscalls, &out.DeniedSyscalls
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.MaxExtraneousSyscalls != nil {
+		in, out := &in.MaxExtraneousSyscalls, &out.MaxExtraneousSyscalls
+		*out = new(int64)
+		**out = **in
+	}
 	return
 }
 
@@ -402,7 +565,7 @@ func (in *SySchedArgs) DeepCopyObject() runtime.Object {
 func (in *TargetLoadPackingArgs) DeepCopyInto(out *TargetLoadPackingArgs) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
-	out.TrimaranSpec = in.TrimaranSpec
+	in.TrimaranSpec.DeepCopyInto(&out.TrimaranSpec)
 	if in.DefaultRequests != nil {
 		in, out := &in.DefaultRequests, &out.DefaultRequests
 		*out = make(v1.ResourceList, len(*in))
@@ -410,6 +573,13 @@ func (in *TargetLoadPackingArgs) DeepCopyInto(out *TargetLoadPackingArgs) {
 			(*out)[key] = val.DeepCopy()
 		}
 	}
+	if in.ResourceTargets != nil {
+		in, out := &in.ResourceTargets, &out.ResourceTargets
+		*out = make(map[v1.ResourceName]ResourceTarget, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	return
 }
 
@@ -464,7 +634,7 @@ func (in *TopologicalSortArgs) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TrimaranSpec) DeepCopyInto(out *TrimaranSpec) {
 	*out = *in
-	out.MetricProvider = in.MetricProvider
+	in.MetricProvider.DeepCopyInto(&out.MetricProvider)
 	return
 }
 
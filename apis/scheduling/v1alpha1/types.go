@@ -58,11 +58,25 @@ type ElasticQuotaSpec struct {
 	// successfully scheduled pods.
 	// +optional
 	Max v1.ResourceList `json:"max,omitempty" protobuf:"bytes,2,rep,name=max, casttype=ResourceList,castkey=ResourceName"`
+
+	// Parent is the namespace of the ElasticQuota this quota is nested under. A nested quota
+	// borrows from the guarantee of its parent rather than from the whole cluster, and the usage
+	// of its namespace counts against the Max of every ancestor. Quotas without a parent are top-level.
+	// +optional
+	Parent string `json:"parent,omitempty" protobuf:"bytes,3,opt,name=parent"`
+
+	// NamespaceSelector selects namespaces whose pods are subject to this quota in addition to the
+	// namespace of the ElasticQuota itself, so Min, Max and Status.Used are aggregated across all of them.
+	// A namespace that has its own ElasticQuota is always subject to that one. If the selectors of several
+	// ElasticQuotas match a namespace, the first ElasticQuota ordered by namespace and name applies.
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty" protobuf:"bytes,4,opt,name=namespaceSelector"`
 }
 
 // ElasticQuotaStatus defines the observed use.
 type ElasticQuotaStatus struct {
-	// Used is the current observed total usage of the resource in the namespace.
+	// Used is the current observed total usage of the resource in the namespace, including the namespaces
+	// selected by NamespaceSelector.
 	// +optional
 	Used v1.ResourceList `json:"used,omitempty" protobuf:"bytes,1,rep,name=used,casttype=ResourceList,castkey=ResourceName"`
 }
@@ -110,6 +124,16 @@ const (
 
 	// PodGroupLabel is the default label of coscheduling
 	PodGroupLabel = scheduling.GroupName + "/pod-group"
+
+	// PodGroupRoleLabel is the label of a pod identifying its role in the pod group.
+	PodGroupRoleLabel = scheduling.GroupName + "/pod-group-role"
+)
+
+// These are the valid condition types of podGroups.
+const (
+	// PodGroupNUMAAligned means the `spec.minMember` pods of the pod group can be aligned on the NUMA nodes
+	// of the cluster, as simulated by the NodeResourceTopologyMatch plugin before scheduling the pod group.
+	PodGroupNUMAAligned = "NUMAAligned"
 )
 
 // PodGroup is a collection of Pod; used for batch workload.
@@ -150,6 +174,14 @@ type PodGroupSpec struct {
 	// +kubebuilder:validation:Minimum=1
 	MinMember int32 `json:"minMember,omitempty"`
 
+	// MaxMember defines the maximal number of members/tasks of an elastic pod group;
+	// once MinMember members are running, the members beyond MinMember are scheduled
+	// one by one, in the queue order of the pod group, up to MaxMember, and are the first to be preempted.
+	// If not set, the pod group is not elastic. A value less than MinMember is treated as MinMember.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	MaxMember *int32 `json:"maxMember,omitempty"`
+
 	// MinResources defines the minimal resource of members/tasks to run the pod group;
 	// if there's not enough resources to start all tasks, the scheduler
 	// will not start any.
@@ -157,6 +189,62 @@ type PodGroupSpec struct {
 
 	// ScheduleTimeoutSeconds defines the maximal time of members/tasks to wait before run the pod group;
 	ScheduleTimeoutSeconds *int32 `json:"scheduleTimeoutSeconds,omitempty"`
+
+	// TopologyPolicy defines the topology domain the members of the pod group are placed in;
+	// if not set, the members can be placed on any node.
+	// +optional
+	TopologyPolicy *TopologyPolicy `json:"topologyPolicy,omitempty"`
+
+	// Roles defines the sub-groups of the pod group, e.g. launcher, parameter servers and workers;
+	// the pod group is only satisfied if both MinMember and the quorum of every role are reached.
+	// The role of a pod is the value of its `scheduling.x-k8s.io/pod-group-role` label.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Roles []PodGroupRole `json:"roles,omitempty"`
+}
+
+// PodGroupRole represents a sub-group of a pod group.
+type PodGroupRole struct {
+	// Name of the role, matching the `scheduling.x-k8s.io/pod-group-role` label of its pods.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// MinMember defines the minimal number of pods of the role to run the pod group.
+	// +kubebuilder:validation:Minimum=1
+	MinMember int32 `json:"minMember"`
+
+	// MinResources defines the minimal resource of the pods of the role to run the pod group;
+	// it is only accounted if MinResources of the pod group is not set.
+	// +optional
+	MinResources v1.ResourceList `json:"minResources,omitempty"`
+}
+
+// TopologyMode is the mode of a topology policy.
+type TopologyMode string
+
+const (
+	// TopologyModeRequired means all members of the pod group must be placed in the same topology domain.
+	TopologyModeRequired TopologyMode = "Required"
+
+	// TopologyModePreferred means the members of the pod group are preferably placed in the same topology domain,
+	// but can spread over other domains if that domain is full.
+	TopologyModePreferred TopologyMode = "Preferred"
+)
+
+// TopologyPolicy defines how the members of a pod group are placed across topology domains.
+type TopologyPolicy struct {
+	// TopologyKey is the key of the node label defining the topology domains, e.g. topology.kubernetes.io/zone.
+	// Nodes with the same value of this label belong to the same domain.
+	// +kubebuilder:validation:MinLength=1
+	TopologyKey string `json:"topologyKey"`
+
+	// Mode defines whether placing all members in a single domain is required or preferred.
+	// Defaults to Required.
+	// +kubebuilder:validation:Enum=Required;Preferred
+	// +kubebuilder:default=Required
+	// +optional
+	Mode TopologyMode `json:"mode,omitempty"`
 }
 
 // PodGroupStatus represents the current state of a pod group.
@@ -182,6 +270,21 @@ type PodGroupStatus struct {
 
 	// ScheduleStartTime of the group
 	ScheduleStartTime metav1.Time `json:"scheduleStartTime,omitempty"`
+
+	// The desired scale of the group, i.e. the number of its pods, capped at MaxMember.
+	// +optional
+	Desired int32 `json:"desired,omitempty"`
+
+	// The achieved scale of the group, i.e. the number of its pods bound to a node.
+	// +optional
+	Scheduled int32 `json:"scheduled,omitempty"`
+
+	// Conditions represent the latest observations of the pod group, e.g. whether its pods can be
+	// aligned on NUMA nodes.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -196,3 +299,63 @@ type PodGroupList struct {
 	// Items is the list of PodGroup
 	Items []PodGroup `json:"items"`
 }
+
+// PowerModel is the power curve of the nodes it matches, used by the Peaks plugin.
+// The power drawn by a node at a CPU utilisation of x percent is K0 + K1 * e^(K2 * x).
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster,shortName={pm,pms}
+// +kubebuilder:printcolumn:name="K0",JSONPath=".spec.k0",type=string,description="K0 is the constant term of the power curve."
+// +kubebuilder:printcolumn:name="K1",JSONPath=".spec.k1",type=string,description="K1 is the scale of the exponential term of the power curve."
+// +kubebuilder:printcolumn:name="K2",JSONPath=".spec.k2",type=string,description="K2 is the rate of the exponential term of the power curve."
+// +kubebuilder:printcolumn:name="Age",JSONPath=".metadata.creationTimestamp",type=date,description="Age is the time PowerModel was created."
+type PowerModel struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// Standard object's metadata.
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// PowerModelSpec defines the power curve and the nodes it applies to.
+	// +optional
+	Spec PowerModelSpec `json:"spec,omitempty"`
+}
+
+// PowerModelSpec defines the power curve and the nodes it applies to.
+// The coefficients are decimal strings, as floating point numbers are not portable across API clients.
+type PowerModelSpec struct {
+	// InstanceTypes are the values of the node.kubernetes.io/instance-type label of the nodes
+	// the power model applies to.
+	// +optional
+	InstanceTypes []string `json:"instanceTypes,omitempty"`
+
+	// NodeSelector selects the nodes the power model applies to, in addition to the ones of InstanceTypes.
+	// A model matching the instance type of a node takes precedence over one matching through its selector.
+	// +optional
+	NodeSelector *metav1.LabelSelector `json:"nodeSelector,omitempty"`
+
+	// K0 is the constant term of the power curve, in watts.
+	// +kubebuilder:validation:Pattern=`^[-+]?([0-9]+\.?[0-9]*|\.[0-9]+)([eE][-+]?[0-9]+)?$`
+	K0 string `json:"k0"`
+
+	// K1 is the scale of the exponential term of the power curve, in watts.
+	// +kubebuilder:validation:Pattern=`^[-+]?([0-9]+\.?[0-9]*|\.[0-9]+)([eE][-+]?[0-9]+)?$`
+	K1 string `json:"k1"`
+
+	// K2 is the rate of the exponential term of the power curve, per percent of CPU utilisation.
+	// +kubebuilder:validation:Pattern=`^[-+]?([0-9]+\.?[0-9]*|\.[0-9]+)([eE][-+]?[0-9]+)?$`
+	K2 string `json:"k2"`
+}
+
+// +kubebuilder:object:root=true
+
+// PowerModelList is a collection of power models.
+type PowerModelList struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard list metadata
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	// Items is the list of PowerModel
+	Items []PowerModel `json:"items"`
+}
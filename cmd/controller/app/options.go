@@ -17,7 +17,11 @@ limitations under the License.
 package app
 
 import (
+	"time"
+
 	"github.com/spf13/pflag"
+
+	"sigs.k8s.io/scheduler-plugins/pkg/controllers"
 )
 
 type ServerRunOptions struct {
@@ -27,6 +31,13 @@ type ServerRunOptions struct {
 	ApiServerBurst       int
 	Workers              int
 	EnableLeaderElection bool
+	// EnableAppGroup enables the AppGroup controller, which requires the AppGroup CRD.
+	EnableAppGroup bool
+	// EnableNetworkTopology enables the NetworkTopology controller, which requires the NetworkTopology CRD.
+	EnableNetworkTopology        bool
+	NetworkTopologyPeriod        time.Duration
+	NetworkTopologySource        string
+	NetworkTopologySourceAddress string
 }
 
 func NewServerRunOptions() *ServerRunOptions {
@@ -42,4 +53,9 @@ func (s *ServerRunOptions) addAllFlags() {
 	pflag.IntVar(&s.ApiServerBurst, "burst", 10, "burst of query apiserver.")
 	pflag.IntVar(&s.Workers, "workers", 1, "workers of scheduler-plugin-controllers.")
 	pflag.BoolVar(&s.EnableLeaderElection, "enableLeaderElection", s.EnableLeaderElection, "If EnableLeaderElection for controller.")
+	pflag.BoolVar(&s.EnableAppGroup, "enableAppGroup", s.EnableAppGroup, "If enable the AppGroup controller computing the topology order of AppGroups.")
+	pflag.BoolVar(&s.EnableNetworkTopology, "enableNetworkTopology", s.EnableNetworkTopology, "If enable the NetworkTopology controller computing the network costs of NetworkTopologies.")
+	pflag.DurationVar(&s.NetworkTopologyPeriod, "networkTopologyPeriod", controllers.DefaultNetworkTopologyPeriod, "period at which the network costs of NetworkTopologies are recomputed.")
+	pflag.StringVar(&s.NetworkTopologySource, "networkTopologySource", controllers.ConfigMapCostSourceName, "source of the network measurements of NetworkTopologies, ConfigMap or Prometheus.")
+	pflag.StringVar(&s.NetworkTopologySourceAddress, "networkTopologySourceAddress", "", "address of the Prometheus-style endpoint exposing the network measurements.")
 }
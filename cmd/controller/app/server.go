@@ -17,6 +17,8 @@ limitations under the License.
 package app
 
 import (
+	agv1alpha1 "github.com/diktyo-io/appgroup-api/pkg/apis/appgroup/v1alpha1"
+	ntv1alpha1 "github.com/diktyo-io/networktopology-api/pkg/apis/networktopology/v1alpha1"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
@@ -38,6 +40,8 @@ func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 
 	utilruntime.Must(schedulingv1a1.AddToScheme(scheme))
+	utilruntime.Must(agv1alpha1.AddToScheme(scheme))
+	utilruntime.Must(ntv1alpha1.AddToScheme(scheme))
 }
 
 func Run(s *ServerRunOptions) error {
@@ -80,6 +84,35 @@ func Run(s *ServerRunOptions) error {
 		return err
 	}
 
+	if s.EnableAppGroup {
+		if err = (&controllers.AppGroupReconciler{
+			Client:  mgr.GetClient(),
+			Scheme:  mgr.GetScheme(),
+			Workers: s.Workers,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "AppGroup")
+			return err
+		}
+	}
+
+	if s.EnableNetworkTopology {
+		source, err := controllers.NewNetworkCostSource(s.NetworkTopologySource, s.NetworkTopologySourceAddress, mgr.GetAPIReader())
+		if err != nil {
+			setupLog.Error(err, "unable to create network cost source")
+			return err
+		}
+		if err = (&controllers.NetworkTopologyReconciler{
+			Client:  mgr.GetClient(),
+			Scheme:  mgr.GetScheme(),
+			Workers: s.Workers,
+			Source:  source,
+			Period:  s.NetworkTopologyPeriod,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "NetworkTopology")
+			return err
+		}
+	}
+
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up health check")
 		return err
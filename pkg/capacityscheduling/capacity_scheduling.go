@@ -19,6 +19,7 @@ package capacityscheduling
 import (
 	"context"
 	"fmt"
+	"io"
 	"sort"
 	"sync"
 
@@ -27,7 +28,9 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/informers"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	corelisters "k8s.io/client-go/listers/core/v1"
@@ -61,11 +64,21 @@ type CapacityScheduling struct {
 	logger            klog.Logger
 	fh                framework.Handle
 	podLister         corelisters.PodLister
+	nsLister          corelisters.NamespaceLister
 	pdbLister         policylisters.PodDisruptionBudgetLister
 	client            client.Client
 	elasticQuotaInfos ElasticQuotaInfos
+	// refs counts the plugins sharing this instance, guarded by the lock of instances.
+	refs int
 }
 
+// instances are shared by the CapacityScheduling and FairShareSort plugins of a profile, i.e. of a framework
+// handle, so that the queue sort sees the usage of the ElasticQuotas reserved by CapacityScheduling.
+var instances = struct {
+	sync.Mutex
+	byHandle map[framework.Handle]*CapacityScheduling
+}{byHandle: make(map[framework.Handle]*CapacityScheduling)}
+
 // PreFilterState computed at PreFilter and used at PostFilter or Reserve.
 type PreFilterState struct {
 	podReq framework.Resource
@@ -102,6 +115,7 @@ var _ framework.PreFilterPlugin = &CapacityScheduling{}
 var _ framework.PostFilterPlugin = &CapacityScheduling{}
 var _ framework.ReservePlugin = &CapacityScheduling{}
 var _ framework.EnqueueExtensions = &CapacityScheduling{}
+var _ io.Closer = &CapacityScheduling{}
 var _ preemption.Interface = &preemptor{}
 
 const (
@@ -118,14 +132,48 @@ func (c *CapacityScheduling) Name() string {
 	return Name
 }
 
-// New initializes a new plugin and returns it.
+// New initializes a new plugin and returns it. The plugin is shared with the FairShareSort plugin of the same profile.
 func New(ctx context.Context, obj runtime.Object, handle framework.Handle) (framework.Plugin, error) {
+	return acquire(ctx, handle)
+}
+
+// acquire returns the CapacityScheduling of the framework handle, creating it on first use.
+// Each caller must Close it once done with it.
+func acquire(ctx context.Context, handle framework.Handle) (*CapacityScheduling, error) {
+	instances.Lock()
+	defer instances.Unlock()
+	if c, ok := instances.byHandle[handle]; ok {
+		c.refs++
+		return c, nil
+	}
+	c, err := newCapacityScheduling(ctx, handle)
+	if err != nil {
+		return nil, err
+	}
+	c.refs = 1
+	instances.byHandle[handle] = c
+	return c, nil
+}
+
+// Close releases the plugin, shared with the FairShareSort plugin of the same profile.
+func (c *CapacityScheduling) Close() error {
+	instances.Lock()
+	defer instances.Unlock()
+	c.refs--
+	if c.refs <= 0 && instances.byHandle[c.fh] == c {
+		delete(instances.byHandle, c.fh)
+	}
+	return nil
+}
+
+func newCapacityScheduling(ctx context.Context, handle framework.Handle) (*CapacityScheduling, error) {
 	lh := klog.FromContext(ctx).WithValues("plugin", Name)
 	c := &CapacityScheduling{
 		logger:            lh,
 		fh:                handle,
 		elasticQuotaInfos: NewElasticQuotaInfos(),
 		podLister:         handle.SharedInformerFactory().Core().V1().Pods().Lister(),
+		nsLister:          handle.SharedInformerFactory().Core().V1().Namespaces().Lister(),
 		pdbLister:         getPDBLister(handle.SharedInformerFactory()),
 	}
 	logger := klog.FromContext(ctx)
@@ -164,6 +212,11 @@ func New(ctx context.Context, obj runtime.Object, handle framework.Handle) (fram
 		},
 	})
 
+	// The PodGroups are read by the FairShareSort plugin, from the cache of the client.
+	if _, err := ccache.GetInformer(ctx, &v1alpha1.PodGroup{}); err != nil {
+		return nil, err
+	}
+
 	podInformer := handle.SharedInformerFactory().Core().V1().Pods().Informer()
 	podInformer.AddEventHandler(
 		cache.FilteringResourceEventHandler{
@@ -187,6 +240,13 @@ func New(ctx context.Context, obj runtime.Object, handle framework.Handle) (fram
 			},
 		},
 	)
+
+	nsInformer := handle.SharedInformerFactory().Core().V1().Namespaces().Informer()
+	nsInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    c.addNamespace,
+		UpdateFunc: c.updateNamespace,
+		DeleteFunc: c.deleteNamespace,
+	})
 	logger.Info("CapacityScheduling start")
 	return c, nil
 }
@@ -204,7 +264,8 @@ func (c *CapacityScheduling) EventsToRegister(_ context.Context) ([]fwk.ClusterE
 
 // PreFilter performs the following validations.
 // 1. Check if the (pod.request + eq.allocated) is less than eq.max.
-// 2. Check if the sum(eq's usage) > sum(eq's min).
+// 2. Check if the usage of the subtree of each ancestor of eq is more than its min once pod.request is added.
+// 3. Check if the sum(eq's usage) > sum(eq's min).
 func (c *CapacityScheduling) PreFilter(ctx context.Context, state fwk.CycleState, pod *v1.Pod, nodes []fwk.NodeInfo) (*framework.PreFilterResult, *fwk.Status) {
 	// TODO improve the efficiency of taking snapshot
 	// e.g. use a two-pointer data structure to only copy the updated EQs when necessary.
@@ -214,7 +275,7 @@ func (c *CapacityScheduling) PreFilter(ctx context.Context, state fwk.CycleState
 	state.Write(ElasticQuotaSnapshotKey, snapshotElasticQuota)
 
 	elasticQuotaInfos := snapshotElasticQuota.elasticQuotaInfos
-	eq := snapshotElasticQuota.elasticQuotaInfos[pod.Namespace]
+	eq := elasticQuotaInfos.quotaFor(pod.Namespace)
 	if eq == nil {
 		preFilterState := &PreFilterState{
 			podReq: *podReq,
@@ -243,18 +304,17 @@ func (c *CapacityScheduling) PreFilter(ctx context.Context, state fwk.CycleState
 			if p.GetPod().UID == pod.UID {
 				continue
 			}
-			ns := p.GetPod().Namespace
-			info := c.elasticQuotaInfos[ns]
+			info := elasticQuotaInfos.quotaFor(p.GetPod().Namespace)
 			if info != nil {
 				pResourceRequest := util.ResourceList(computePodResourceRequest(p.GetPod()))
 				// If they are subject to the same quota(namespace) and p is more important than pod,
 				// p will be added to the nominatedResource and totalNominatedResource.
 				// If they aren't subject to the same quota(namespace) and the usage of quota(p's namespace) does not exceed min,
 				// p will be added to the totalNominatedResource.
-				if ns == pod.Namespace && corev1helpers.PodPriority(p.GetPod()) >= corev1helpers.PodPriority(pod) {
+				if info == eq && corev1helpers.PodPriority(p.GetPod()) >= corev1helpers.PodPriority(pod) {
 					nominatedPodsReqInEQWithPodReq.Add(pResourceRequest)
 					nominatedPodsReqWithPodReq.Add(pResourceRequest)
-				} else if ns != pod.Namespace && !info.usedOverMin() {
+				} else if info != eq && !elasticQuotaInfos.usedOverMin(info) {
 					nominatedPodsReqWithPodReq.Add(pResourceRequest)
 				}
 			}
@@ -270,10 +330,14 @@ func (c *CapacityScheduling) PreFilter(ctx context.Context, state fwk.CycleState
 	}
 	state.Write(preFilterStateKey, preFilterState)
 
-	if eq.usedOverMaxWith(nominatedPodsReqInEQWithPodReq) {
+	if elasticQuotaInfos.usedOverMaxWith(eq, nominatedPodsReqInEQWithPodReq) {
 		return nil, fwk.NewStatus(fwk.Unschedulable, fmt.Sprintf("Pod %v/%v is rejected in PreFilter because ElasticQuota %v is more than Max", pod.Namespace, pod.Name, eq.Namespace))
 	}
 
+	if elasticQuotaInfos.ancestorsUsedOverMinWith(eq, nominatedPodsReqInEQWithPodReq) {
+		return nil, fwk.NewStatus(fwk.Unschedulable, fmt.Sprintf("Pod %v/%v is rejected in PreFilter because an ancestor of ElasticQuota %v is more than min", pod.Namespace, pod.Name, eq.Namespace))
+	}
+
 	if elasticQuotaInfos.aggregatedUsedOverMinWith(*nominatedPodsReqWithPodReq) {
 		return nil, fwk.NewStatus(fwk.Unschedulable, fmt.Sprintf("Pod %v/%v is rejected in PreFilter because total ElasticQuota used is more than min", pod.Namespace, pod.Name))
 	}
@@ -296,7 +360,7 @@ func (c *CapacityScheduling) AddPod(ctx context.Context, cycleState fwk.CycleSta
 		return fwk.NewStatus(fwk.Error, err.Error())
 	}
 
-	elasticQuotaInfo := elasticQuotaSnapshotState.elasticQuotaInfos[podToAdd.GetPod().Namespace]
+	elasticQuotaInfo := elasticQuotaSnapshotState.elasticQuotaInfos.quotaFor(podToAdd.GetPod().Namespace)
 	if elasticQuotaInfo != nil {
 		err := elasticQuotaInfo.addPodIfNotPresent(podToAdd.GetPod())
 		if err != nil {
@@ -317,7 +381,7 @@ func (c *CapacityScheduling) RemovePod(ctx context.Context, cycleState fwk.Cycle
 		return fwk.NewStatus(fwk.Error, err.Error())
 	}
 
-	elasticQuotaInfo := elasticQuotaSnapshotState.elasticQuotaInfos[podToRemove.GetPod().Namespace]
+	elasticQuotaInfo := elasticQuotaSnapshotState.elasticQuotaInfos.quotaFor(podToRemove.GetPod().Namespace)
 	if elasticQuotaInfo != nil {
 		err = elasticQuotaInfo.deletePodIfPresent(podToRemove.GetPod())
 		if err != nil {
@@ -352,7 +416,7 @@ func (c *CapacityScheduling) Reserve(ctx context.Context, state fwk.CycleState,
 	defer c.Unlock()
 	logger := klog.FromContext(klog.NewContext(ctx, c.logger)).WithValues("ExtensionPoint", "Reserve")
 
-	elasticQuotaInfo := c.elasticQuotaInfos[pod.Namespace]
+	elasticQuotaInfo := c.elasticQuotaInfos.quotaFor(pod.Namespace)
 	if elasticQuotaInfo != nil {
 		err := elasticQuotaInfo.addPodIfNotPresent(pod)
 		if err != nil {
@@ -369,7 +433,7 @@ func (c *CapacityScheduling) Unreserve(ctx context.Context, state fwk.CycleState
 
 	logger := klog.FromContext(ctx)
 
-	elasticQuotaInfo := c.elasticQuotaInfos[pod.Namespace]
+	elasticQuotaInfo := c.elasticQuotaInfos.quotaFor(pod.Namespace)
 	if elasticQuotaInfo != nil {
 		err := elasticQuotaInfo.deletePodIfPresent(pod)
 		if err != nil {
@@ -441,23 +505,24 @@ func (p *preemptor) PodEligibleToPreemptOthers(ctx context.Context, pod *v1.Pod,
 		}
 
 		podPriority := corev1helpers.PodPriority(pod)
-		preemptorEQInfo, preemptorWithEQ := elasticQuotaSnapshotState.elasticQuotaInfos[pod.Namespace]
-		if preemptorWithEQ {
-			moreThanMinWithPreemptor := preemptorEQInfo.usedOverMinWith(&preFilterState.nominatedPodsReqInEQWithPodReq)
+		elasticQuotaInfos := elasticQuotaSnapshotState.elasticQuotaInfos
+		preemptorEQInfo := elasticQuotaInfos.quotaFor(pod.Namespace)
+		if preemptorEQInfo != nil {
+			moreThanMinWithPreemptor := elasticQuotaInfos.usedOverMinWith(preemptorEQInfo, &preFilterState.nominatedPodsReqInEQWithPodReq)
 			for _, p := range nodeInfo.GetPods() {
 				// Checking terminating pods
 				if p.GetPod().DeletionTimestamp != nil {
-					eqInfo, withEQ := elasticQuotaSnapshotState.elasticQuotaInfos[p.GetPod().Namespace]
-					if !withEQ {
+					eqInfo := elasticQuotaInfos.quotaFor(p.GetPod().Namespace)
+					if eqInfo == nil {
 						continue
 					}
-					if p.GetPod().Namespace == pod.Namespace && corev1helpers.PodPriority(p.GetPod()) < podPriority {
+					if eqInfo == preemptorEQInfo && corev1helpers.PodPriority(p.GetPod()) < podPriority {
 						// There is a terminating pod on the nominated node.
 						// If the terminating pod is in the same namespace with preemptor
 						// and it is less important than preemptor,
 						// return false to avoid preempting more pods.
 						return false, "not eligible due to a terminating pod on the nominated node."
-					} else if p.GetPod().Namespace != pod.Namespace && !moreThanMinWithPreemptor && eqInfo.usedOverMin() {
+					} else if eqInfo != preemptorEQInfo && !moreThanMinWithPreemptor && elasticQuotaInfos.borrowing(preemptorEQInfo, eqInfo) {
 						// There is a terminating pod on the nominated node.
 						// The terminating pod isn't in the same namespace with preemptor.
 						// If moreThanMinWithPreemptor is false, it indicates that preemptor can preempt the pods in other EQs whose used is over min.
@@ -469,8 +534,7 @@ func (p *preemptor) PodEligibleToPreemptOthers(ctx context.Context, pod *v1.Pod,
 			}
 		} else {
 			for _, p := range nodeInfo.GetPods() {
-				_, withEQ := elasticQuotaSnapshotState.elasticQuotaInfos[p.GetPod().Namespace]
-				if withEQ {
+				if elasticQuotaInfos.quotaFor(p.GetPod().Namespace) != nil {
 					continue
 				}
 				if p.GetPod().DeletionTimestamp != nil && corev1helpers.PodPriority(p.GetPod()) < podPriority {
@@ -531,7 +595,8 @@ func (p *preemptor) SelectVictimsOnNode(
 
 	elasticQuotaInfos := elasticQuotaSnapshotState.elasticQuotaInfos
 	podPriority := corev1helpers.PodPriority(pod)
-	preemptorElasticQuotaInfo, preemptorWithElasticQuota := elasticQuotaInfos[pod.Namespace]
+	preemptorElasticQuotaInfo := elasticQuotaInfos.quotaFor(pod.Namespace)
+	preemptorWithElasticQuota := preemptorElasticQuotaInfo != nil
 
 	// sort the pods in node by the priority class
 	sort.Slice(nodeInfo.GetPods(), func(i, j int) bool {
@@ -542,10 +607,10 @@ func (p *preemptor) SelectVictimsOnNode(
 	if preemptorWithElasticQuota {
 		nominatedPodsReqInEQWithPodReq = preFilterState.nominatedPodsReqInEQWithPodReq
 		nominatedPodsReqWithPodReq = preFilterState.nominatedPodsReqWithPodReq
-		moreThanMinWithPreemptor := preemptorElasticQuotaInfo.usedOverMinWith(&nominatedPodsReqInEQWithPodReq)
+		moreThanMinWithPreemptor := elasticQuotaInfos.usedOverMinWith(preemptorElasticQuotaInfo, &nominatedPodsReqInEQWithPodReq)
 		for _, p := range nodeInfo.GetPods() {
-			eqInfo, withEQ := elasticQuotaInfos[p.GetPod().Namespace]
-			if !withEQ {
+			eqInfo := elasticQuotaInfos.quotaFor(p.GetPod().Namespace)
+			if eqInfo == nil {
 				continue
 			}
 
@@ -555,7 +620,7 @@ func (p *preemptor) SelectVictimsOnNode(
 				// quotas. So that we will select the pods which subject to the
 				// same quota(namespace) with the lower priority than the
 				// preemptor's priority as potential victims in a node.
-				if p.GetPod().Namespace == pod.Namespace && corev1helpers.PodPriority(p.GetPod()) < podPriority {
+				if eqInfo == preemptorElasticQuotaInfo && corev1helpers.PodPriority(p.GetPod()) < podPriority {
 					potentialVictims = append(potentialVictims, p)
 					if err := removePod(p); err != nil {
 						return nil, 0, fwk.AsStatus(err)
@@ -568,8 +633,9 @@ func (p *preemptor) SelectVictimsOnNode(
 				// `borrowed` by other Quota. Potential victims in a node
 				// will be chosen from Quotas that allocates more resources
 				// than its min, i.e., borrowing resources from other
-				// Quotas.
-				if p.GetPod().Namespace != pod.Namespace && eqInfo.usedOverMin() {
+				// Quotas. For nested quotas, a victim is also borrowing if
+				// any of its ancestors not shared with the preemptor is.
+				if eqInfo != preemptorElasticQuotaInfo && elasticQuotaInfos.borrowing(preemptorElasticQuotaInfo, eqInfo) {
 					potentialVictims = append(potentialVictims, p)
 					if err := removePod(p); err != nil {
 						return nil, 0, fwk.AsStatus(err)
@@ -579,8 +645,7 @@ func (p *preemptor) SelectVictimsOnNode(
 		}
 	} else {
 		for _, p := range nodeInfo.GetPods() {
-			_, withEQ := elasticQuotaInfos[p.GetPod().Namespace]
-			if withEQ {
+			if elasticQuotaInfos.quotaFor(p.GetPod().Namespace) != nil {
 				continue
 			}
 			if corev1helpers.PodPriority(p.GetPod()) < podPriority {
@@ -612,7 +677,8 @@ func (p *preemptor) SelectVictimsOnNode(
 	// after removing all the lower priority pods,
 	// we are almost done and this node is not suitable for preemption.
 	if preemptorWithElasticQuota {
-		if preemptorElasticQuotaInfo.usedOverMaxWith(&podReq) ||
+		if elasticQuotaInfos.usedOverMaxWith(preemptorElasticQuotaInfo, &podReq) ||
+			elasticQuotaInfos.ancestorsUsedOverMinWith(preemptorElasticQuotaInfo, &podReq) ||
 			elasticQuotaInfos.aggregatedUsedOverMinWith(podReq) {
 			return nil, 0, fwk.NewStatus(fwk.Unschedulable, "global quota max exceeded")
 		}
@@ -620,9 +686,18 @@ func (p *preemptor) SelectVictimsOnNode(
 
 	var victims []*v1.Pod
 	numViolatingVictim := 0
-	// Sort potentialVictims by pod priority from high to low, which ensures to
-	// reprieve higher priority pods first.
-	sort.Slice(potentialVictims, func(i, j int) bool {
+	// Sort potentialVictims by the distance of their quota from the preemptor's quota
+	// and then by pod priority from high to low, which ensures to reprieve pods in the
+	// closest subtree and with higher priority first, so borrowed resources are reclaimed
+	// from the most distant subtree first.
+	sort.SliceStable(potentialVictims, func(i, j int) bool {
+		if preemptorWithElasticQuota {
+			di := elasticQuotaInfos.distance(preemptorElasticQuotaInfo, elasticQuotaInfos.quotaFor(potentialVictims[i].GetPod().Namespace))
+			dj := elasticQuotaInfos.distance(preemptorElasticQuotaInfo, elasticQuotaInfos.quotaFor(potentialVictims[j].GetPod().Namespace))
+			if di != dj {
+				return di < dj
+			}
+		}
 		return schedutil.MoreImportantPod(potentialVictims[i].GetPod(), potentialVictims[j].GetPod())
 	})
 	// Try to reprieve as many pods as possible. We first try to reprieve the PDB
@@ -643,7 +718,9 @@ func (p *preemptor) SelectVictimsOnNode(
 			logger.V(5).Info("Found a potential preemption victim on node", "pod", klog.KObj(pi.GetPod()), "node", klog.KObj(nodeInfo.Node()))
 		}
 
-		if preemptorWithElasticQuota && (preemptorElasticQuotaInfo.usedOverMaxWith(&nominatedPodsReqInEQWithPodReq) || elasticQuotaInfos.aggregatedUsedOverMinWith(nominatedPodsReqWithPodReq)) {
+		if preemptorWithElasticQuota && (elasticQuotaInfos.usedOverMaxWith(preemptorElasticQuotaInfo, &nominatedPodsReqInEQWithPodReq) ||
+			elasticQuotaInfos.ancestorsUsedOverMinWith(preemptorElasticQuotaInfo, &nominatedPodsReqInEQWithPodReq) ||
+			elasticQuotaInfos.aggregatedUsedOverMinWith(nominatedPodsReqWithPodReq)) {
 			if err := removePod(pi); err != nil {
 				return false, err
 			}
@@ -683,34 +760,181 @@ func (c *CapacityScheduling) addElasticQuota(obj interface{}) {
 		return
 	}
 
-	elasticQuotaInfo := newElasticQuotaInfo(eq.Namespace, eq.Spec.Min, eq.Spec.Max, nil)
+	elasticQuotaInfo := newElasticQuotaInfoForEQ(eq)
 
 	c.Lock()
-	defer c.Unlock()
 	c.elasticQuotaInfos[eq.Namespace] = elasticQuotaInfo
+	selectsNamespaces := c.elasticQuotaInfos.selectsNamespaces()
+	c.Unlock()
+
+	if selectsNamespaces {
+		c.syncElasticQuotaNamespaces()
+	}
 }
 
 func (c *CapacityScheduling) updateElasticQuota(oldObj, newObj interface{}) {
 	oldEQ := oldObj.(*v1alpha1.ElasticQuota)
 	newEQ := newObj.(*v1alpha1.ElasticQuota)
-	newEQInfo := newElasticQuotaInfo(newEQ.Namespace, newEQ.Spec.Min, newEQ.Spec.Max, nil)
+	newEQInfo := newElasticQuotaInfoForEQ(newEQ)
 
 	c.Lock()
-	defer c.Unlock()
-
 	oldEQInfo := c.elasticQuotaInfos[oldEQ.Namespace]
 	if oldEQInfo != nil {
 		newEQInfo.pods = oldEQInfo.pods
 		newEQInfo.Used = oldEQInfo.Used
+		// Keep the previously selected namespaces, so that the usage of the namespaces
+		// that are no longer selected is moved away by syncElasticQuotaNamespaces.
+		if oldEQInfo.namespaces != nil {
+			newEQInfo.namespaces = oldEQInfo.namespaces
+		}
 	}
 	c.elasticQuotaInfos[newEQ.Namespace] = newEQInfo
+	selectsNamespaces := c.elasticQuotaInfos.selectsNamespaces()
+	c.Unlock()
+
+	if selectsNamespaces {
+		c.syncElasticQuotaNamespaces()
+	}
 }
 
 func (c *CapacityScheduling) deleteElasticQuota(obj interface{}) {
 	elasticQuota := obj.(*v1alpha1.ElasticQuota)
 	c.Lock()
-	defer c.Unlock()
 	delete(c.elasticQuotaInfos, elasticQuota.Namespace)
+	selectsNamespaces := c.elasticQuotaInfos.selectsNamespaces()
+	c.Unlock()
+
+	if elasticQuota.Spec.NamespaceSelector != nil || selectsNamespaces {
+		c.syncElasticQuotaNamespaces()
+	}
+}
+
+func (c *CapacityScheduling) addNamespace(obj interface{}) {
+	if ns, ok := obj.(*v1.Namespace); ok {
+		c.syncNamespace(ns.Name, ns.Labels, true)
+	}
+}
+
+func (c *CapacityScheduling) updateNamespace(oldObj, newObj interface{}) {
+	oldNs := oldObj.(*v1.Namespace)
+	newNs := newObj.(*v1.Namespace)
+	if labels.Equals(oldNs.Labels, newNs.Labels) {
+		return
+	}
+	c.syncNamespace(newNs.Name, newNs.Labels, true)
+}
+
+func (c *CapacityScheduling) deleteNamespace(obj interface{}) {
+	if t, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = t.Obj
+	}
+	if ns, ok := obj.(*v1.Namespace); ok {
+		c.syncNamespace(ns.Name, nil, false)
+	}
+}
+
+// syncNamespace moves the usage of the assigned pods of the namespace to the ElasticQuota that now governs
+// it, if it changed. A deleted namespace is no longer governed by any ElasticQuota selecting it.
+func (c *CapacityScheduling) syncNamespace(namespace string, nsLabels map[string]string, exists bool) {
+	c.Lock()
+	defer c.Unlock()
+	if !c.elasticQuotaInfos.selectsNamespaces() {
+		return
+	}
+	var desired *ElasticQuotaInfo
+	if exists {
+		desired = c.elasticQuotaInfos.selectingQuota(namespace, nsLabels)
+	}
+	c.moveNamespace(namespace, c.elasticQuotaInfos.governing(namespace), desired)
+}
+
+// syncElasticQuotaNamespaces recomputes the namespaces selected by the NamespaceSelector of each
+// ElasticQuota, and moves the usage of the assigned pods of every namespace which is now subject to
+// a different quota.
+func (c *CapacityScheduling) syncElasticQuotaNamespaces() {
+	namespaces, err := c.nsLister.List(labels.Everything())
+	if err != nil {
+		c.logger.Error(err, "Failed to list namespaces")
+		return
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	current := make(map[string]*ElasticQuotaInfo)
+	for _, elasticQuotaInfo := range c.elasticQuotaInfos {
+		for ns := range elasticQuotaInfo.namespaces {
+			current[ns] = elasticQuotaInfo
+		}
+	}
+	desired := make(map[string]*ElasticQuotaInfo)
+	for _, ns := range namespaces {
+		if elasticQuotaInfo := c.elasticQuotaInfos.selectingQuota(ns.Name, ns.Labels); elasticQuotaInfo != nil {
+			desired[ns.Name] = elasticQuotaInfo
+		}
+	}
+
+	for ns := range sets.KeySet(current).Union(sets.KeySet(desired)) {
+		c.moveNamespace(ns, current[ns], desired[ns])
+	}
+	// The usage of the namespaces selected by a NamespaceSelector that was removed is now moved away.
+	for _, elasticQuotaInfo := range c.elasticQuotaInfos {
+		if elasticQuotaInfo.selector == nil {
+			elasticQuotaInfo.namespaces = nil
+		}
+	}
+}
+
+// moveNamespace moves the usage of the assigned pods of the namespace from the ElasticQuota whose
+// NamespaceSelector governed it, or its own ElasticQuota, to the one whose NamespaceSelector now selects it,
+// or its own ElasticQuota. The caller must hold the lock.
+func (c *CapacityScheduling) moveNamespace(ns string, oldInfo, newInfo *ElasticQuotaInfo) {
+	if oldInfo == newInfo {
+		return
+	}
+	if oldInfo != nil {
+		oldInfo.namespaces.Delete(ns)
+	} else {
+		oldInfo = c.elasticQuotaInfos[ns]
+	}
+	if newInfo != nil {
+		if newInfo.namespaces == nil {
+			newInfo.namespaces = sets.New[string]()
+		}
+		newInfo.namespaces.Insert(ns)
+	} else {
+		newInfo = c.elasticQuotaInfos[ns]
+	}
+
+	pods, err := c.podLister.Pods(ns).List(labels.Everything())
+	if err != nil {
+		c.logger.Error(err, "Failed to list pods", "namespace", ns)
+		return
+	}
+	for _, pod := range pods {
+		if !assignedPod(pod) || pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed {
+			continue
+		}
+		if oldInfo != nil {
+			if err := oldInfo.deletePodIfPresent(pod); err != nil {
+				c.logger.Error(err, "Failed to delete Pod from its associated elasticQuota", "pod", klog.KObj(pod))
+			}
+		}
+		if newInfo != nil {
+			if err := newInfo.addPodIfNotPresent(pod); err != nil {
+				c.logger.Error(err, "Failed to add Pod to its associated elasticQuota", "pod", klog.KObj(pod))
+			}
+		}
+	}
+}
+
+// getPodGroup returns the PodGroup from the cache of the client.
+func (c *CapacityScheduling) getPodGroup(namespace, name string) (*v1alpha1.PodGroup, error) {
+	var pg v1alpha1.PodGroup
+	if err := c.client.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: name}, &pg); err != nil {
+		return nil, err
+	}
+	return &pg, nil
 }
 
 func (c *CapacityScheduling) addPod(obj interface{}) {
@@ -722,7 +946,7 @@ func (c *CapacityScheduling) addPod(obj interface{}) {
 	c.Lock()
 	defer c.Unlock()
 
-	elasticQuotaInfo := c.elasticQuotaInfos[pod.Namespace]
+	elasticQuotaInfo := c.elasticQuotaInfos.quotaFor(pod.Namespace)
 	// If elasticQuotaInfo is nil, try to list ElasticQuotas through elasticQuotaLister
 	if elasticQuotaInfo == nil {
 		var eqList v1alpha1.ElasticQuotaList
@@ -740,7 +964,7 @@ func (c *CapacityScheduling) addPod(obj interface{}) {
 		if len(eqs) > 0 {
 			// only one elasticquota is supported in each namespace
 			eq := eqs[0]
-			elasticQuotaInfo = newElasticQuotaInfo(eq.Namespace, eq.Spec.Min, eq.Spec.Max, nil)
+			elasticQuotaInfo = newElasticQuotaInfoForEQ(&eq)
 			c.elasticQuotaInfos[eq.Namespace] = elasticQuotaInfo
 		}
 	}
@@ -765,7 +989,7 @@ func (c *CapacityScheduling) updatePod(oldObj, newObj interface{}) {
 		c.Lock()
 		defer c.Unlock()
 
-		elasticQuotaInfo := c.elasticQuotaInfos[newPod.Namespace]
+		elasticQuotaInfo := c.elasticQuotaInfos.quotaFor(newPod.Namespace)
 		if elasticQuotaInfo != nil {
 			err := elasticQuotaInfo.deletePodIfPresent(newPod)
 			if err != nil {
@@ -782,7 +1006,7 @@ func (c *CapacityScheduling) deletePod(obj interface{}) {
 	c.Lock()
 	defer c.Unlock()
 
-	elasticQuotaInfo := c.elasticQuotaInfos[pod.Namespace]
+	elasticQuotaInfo := c.elasticQuotaInfos.quotaFor(pod.Namespace)
 	if elasticQuotaInfo != nil {
 		err := elasticQuotaInfo.deletePodIfPresent(pod)
 		if err != nil {
@@ -802,6 +1026,22 @@ func (c *CapacityScheduling) snapshotElasticQuota() *ElasticQuotaSnapshotState {
 	}
 }
 
+// newElasticQuotaInfoForEQ returns the ElasticQuotaInfo for the given ElasticQuota.
+func newElasticQuotaInfoForEQ(eq *v1alpha1.ElasticQuota) *ElasticQuotaInfo {
+	elasticQuotaInfo := newElasticQuotaInfo(eq.Namespace, eq.Spec.Min, eq.Spec.Max, nil)
+	elasticQuotaInfo.Parent = eq.Spec.Parent
+	if eq.Spec.NamespaceSelector != nil {
+		elasticQuotaInfo.namespaces = sets.New[string]()
+		selector, err := metav1.LabelSelectorAsSelector(eq.Spec.NamespaceSelector)
+		if err != nil {
+			// An invalid NamespaceSelector selects no other namespace, like in util.ElasticQuotaSelectsNamespace.
+			selector = labels.Nothing()
+		}
+		elasticQuotaInfo.selector = selector
+	}
+	return elasticQuotaInfo
+}
+
 func getPreFilterState(cycleState fwk.CycleState) (*PreFilterState, error) {
 	c, err := cycleState.Read(preFilterStateKey)
 	if err != nil {
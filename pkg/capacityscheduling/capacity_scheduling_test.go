@@ -29,6 +29,7 @@ import (
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	apiruntime "k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/client-go/informers"
 	clientsetfake "k8s.io/client-go/kubernetes/fake"
 	"k8s.io/client-go/tools/events"
@@ -47,6 +48,7 @@ import (
 	imageutils "k8s.io/kubernetes/test/utils/image"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrlfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/scheduler-plugins/apis/scheduling/v1alpha1"
 	testutil "sigs.k8s.io/scheduler-plugins/test/util"
 )
@@ -54,7 +56,7 @@ import (
 const ResourceGPU v1.ResourceName = "nvidia.com/gpu"
 
 var (
-	midPriority, highPriority = int32(100), int32(1000)
+	lowPriority, midPriority, highPriority = int32(10), int32(100), int32(1000)
 )
 
 func TestPreFilter(t *testing.T) {
@@ -130,6 +132,45 @@ func TestPreFilter(t *testing.T) {
 				fwk.Unschedulable,
 			},
 		},
+		{
+			name: "siblings under a parent exceed the min of the parent",
+			podInfos: []podInfo{
+				{podName: "team-a-p1", podNamespace: "team-a", memReq: 100},
+				{podName: "team-a-p2", podNamespace: "team-a", memReq: 300},
+			},
+			elasticQuotas: map[string]*ElasticQuotaInfo{
+				"dept": {
+					Namespace: "dept",
+					Min:       &framework.Resource{Memory: 1000},
+					Max:       &framework.Resource{Memory: 5000},
+					Used:      &framework.Resource{},
+				},
+				"team-a": {
+					Namespace: "team-a",
+					Parent:    "dept",
+					Min:       &framework.Resource{Memory: 500},
+					Max:       &framework.Resource{Memory: 3000},
+					Used:      &framework.Resource{Memory: 400},
+				},
+				"team-b": {
+					Namespace: "team-b",
+					Parent:    "dept",
+					Min:       &framework.Resource{Memory: 500},
+					Max:       &framework.Resource{Memory: 3000},
+					Used:      &framework.Resource{Memory: 500},
+				},
+				"other": {
+					Namespace: "other",
+					Min:       &framework.Resource{Memory: 5000},
+					Max:       &framework.Resource{Memory: 5000},
+					Used:      &framework.Resource{},
+				},
+			},
+			expected: []fwk.Code{
+				fwk.Success,
+				fwk.Unschedulable,
+			},
+		},
 		{
 			name: "without elasticQuotaInfo",
 			podInfos: []podInfo{
@@ -799,6 +840,102 @@ func TestDryRunPreemption(t *testing.T) {
 	}
 }
 
+func TestSelectVictimsOnNodeNestedQuotas(t *testing.T) {
+	// Initialize scheduler metrics
+	metrics.Register()
+
+	res := map[v1.ResourceName]string{v1.ResourceMemory: "150"}
+	// team-b is nested with the preemptor's quota team-a under dept, other is in another subtree.
+	// Both team-b and other borrow, so all their pods are potential victims: the pods of the
+	// farthest quota are preempted first, even though the pod of team-b has a lower priority.
+	pod := makePod("t1-p", "team-a", 50, 0, 0, highPriority, "t1-p", "")
+	pods := []*v1.Pod{
+		makePod("t1-p1", "team-b", 50, 0, 0, lowPriority, "t1-p1", "node-a"),
+		makePod("t1-p2", "other", 50, 0, 0, highPriority, "t1-p2", "node-a"),
+		makePod("t1-p3", "other", 50, 0, 0, midPriority, "t1-p3", "node-a"),
+	}
+	nodes := []*v1.Node{
+		st.MakeNode().Name("node-a").Capacity(res).Obj(),
+	}
+	elasticQuotas := ElasticQuotaInfos{
+		"dept": {
+			Namespace: "dept",
+			Min:       &framework.Resource{Memory: 200},
+			Max:       &framework.Resource{Memory: 300},
+			Used:      &framework.Resource{},
+		},
+		"team-a": {
+			Namespace: "team-a",
+			Parent:    "dept",
+			Min:       &framework.Resource{Memory: 50},
+			Max:       &framework.Resource{Memory: 200},
+			Used:      &framework.Resource{},
+		},
+		"team-b": {
+			Namespace: "team-b",
+			Parent:    "dept",
+			Min:       &framework.Resource{},
+			Max:       &framework.Resource{Memory: 200},
+			Used:      &framework.Resource{Memory: 50},
+		},
+		"other": {
+			Namespace: "other",
+			Min:       &framework.Resource{Memory: 50},
+			Max:       &framework.Resource{Memory: 200},
+			Used:      &framework.Resource{Memory: 100},
+		},
+	}
+
+	cs := clientsetfake.NewSimpleClientset()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	fwk, err := tf.NewFramework(
+		ctx,
+		makeRegisteredPlugin(),
+		"default-scheduler",
+		frameworkruntime.WithClientSet(cs),
+		frameworkruntime.WithEventRecorder(&events.FakeRecorder{}),
+		frameworkruntime.WithPodNominator(testutil.NewPodNominator(nil)),
+		frameworkruntime.WithSnapshotSharedLister(testutil.NewFakeSharedLister(pods, nodes)),
+		frameworkruntime.WithInformerFactory(informers.NewSharedInformerFactory(cs, 0)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	state := framework.NewCycleState()
+	if _, status, _ := fwk.RunPreFilterPlugins(ctx, state, pod); !status.IsSuccess() {
+		t.Errorf("Unexpected preFilterStatus: %v", status)
+	}
+	podReq := computePodResourceRequest(pod)
+	state.Write(preFilterStateKey, &PreFilterState{
+		podReq:                         *podReq,
+		nominatedPodsReqWithPodReq:     *podReq,
+		nominatedPodsReqInEQWithPodReq: *podReq,
+	})
+	state.Write(ElasticQuotaSnapshotKey, &ElasticQuotaSnapshotState{elasticQuotaInfos: elasticQuotas})
+
+	nodeInfo, err := fwk.SnapshotSharedLister().NodeInfos().Get("node-a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &preemptor{fh: fwk, state: state}
+	victims, numViolatingVictim, status := p.SelectVictimsOnNode(ctx, state, pod, nodeInfo.Snapshot(), nil)
+	if !status.IsSuccess() {
+		t.Fatalf("Unexpected status: %v", status)
+	}
+	if numViolatingVictim != 0 {
+		t.Errorf("Unexpected number of PDB violating victims: %v", numViolatingVictim)
+	}
+	var got []string
+	for _, victim := range victims {
+		got = append(got, victim.Name)
+	}
+	if diff := gocmp.Diff([]string{"t1-p3"}, got); diff != "" {
+		t.Errorf("Unexpected victims (-want, +got): %s", diff)
+	}
+}
+
 func TestPodEligibleToPreemptOthers(t *testing.T) {
 	res := map[v1.ResourceName]string{v1.ResourceMemory: "150"}
 	tests := []struct {
@@ -1598,6 +1735,101 @@ func TestDeletePod(t *testing.T) {
 	}
 }
 
+func TestSyncElasticQuotaNamespaces(t *testing.T) {
+	s := apiruntime.NewScheme()
+	utilruntime.Must(v1alpha1.AddToScheme(s))
+
+	tenant := makeEQ("tenant", "eq", makeResourceList(1000, 10000), makeResourceList(100, 1000))
+	tenant.Spec.NamespaceSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"tenant": "a"}}
+	own := makeEQ("own", "eq", makeResourceList(1000, 10000), makeResourceList(100, 1000))
+
+	makeNamespace := func(name string, labels map[string]string) *v1.Namespace {
+		return &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+	}
+	dev := makeNamespace("dev", map[string]string{"tenant": "a"})
+	ownNs := makeNamespace("own", map[string]string{"tenant": "a"})
+	other := makeNamespace("other", nil)
+
+	pods := []*v1.Pod{
+		makePod("p1", "dev", 100, 10, 0, 0, "p1", "node-a"),
+		makePod("p2", "own", 100, 10, 0, 0, "p2", "node-a"),
+		makePod("p3", "other", 100, 10, 0, 0, "p3", "node-a"),
+		// Pods that aren't assigned yet are accounted to the quota when they are reserved.
+		makePod("p4", "dev", 100, 10, 0, 0, "p4", ""),
+	}
+
+	cs := clientsetfake.NewSimpleClientset()
+	informerFactory := informers.NewSharedInformerFactory(cs, 0)
+	podInformer := informerFactory.Core().V1().Pods().Informer()
+	for _, pod := range pods {
+		podInformer.GetStore().Add(pod)
+	}
+	nsInformer := informerFactory.Core().V1().Namespaces().Informer()
+	for _, ns := range []*v1.Namespace{dev, ownNs, other} {
+		nsInformer.GetStore().Add(ns)
+	}
+
+	c := &CapacityScheduling{
+		elasticQuotaInfos: NewElasticQuotaInfos(),
+		podLister:         informerFactory.Core().V1().Pods().Lister(),
+		nsLister:          informerFactory.Core().V1().Namespaces().Lister(),
+		client:            ctrlfake.NewClientBuilder().WithScheme(s).WithObjects(tenant, own).Build(),
+	}
+	c.addElasticQuota(tenant)
+	c.addElasticQuota(own)
+	c.addPod(pods[1])
+
+	tenantInfo := c.elasticQuotaInfos["tenant"]
+	if got := c.elasticQuotaInfos.quotaFor("dev"); got != tenantInfo {
+		t.Errorf("expected namespace dev to be subject to the tenant quota, got %v", got)
+	}
+	if got := c.elasticQuotaInfos.quotaFor("own"); got != c.elasticQuotaInfos["own"] {
+		t.Errorf("expected namespace own to be subject to its own quota, got %v", got)
+	}
+	if got := c.elasticQuotaInfos.quotaFor("other"); got != nil {
+		t.Errorf("expected namespace other not to be subject to any quota, got %v", got)
+	}
+	want := &framework.Resource{MilliCPU: 10, Memory: 100, ScalarResources: map[v1.ResourceName]int64{ResourceGPU: 0}}
+	if !reflect.DeepEqual(tenantInfo.Used, want) {
+		t.Errorf("expected tenant usage %v, got %v", want, tenantInfo.Used)
+	}
+
+	// Relabeling a namespace moves the usage of its pods to the quota that now governs it.
+	relabeled := dev.DeepCopy()
+	relabeled.Labels = nil
+	nsInformer.GetStore().Update(relabeled)
+	c.updateNamespace(dev, relabeled)
+
+	if got := c.elasticQuotaInfos.quotaFor("dev"); got != nil {
+		t.Errorf("expected namespace dev not to be subject to any quota, got %v", got)
+	}
+	want = &framework.Resource{ScalarResources: map[v1.ResourceName]int64{ResourceGPU: 0}}
+	if !reflect.DeepEqual(tenantInfo.Used, want) {
+		t.Errorf("expected tenant usage %v, got %v", want, tenantInfo.Used)
+	}
+
+	// A namespace event only syncs the namespace of the event.
+	nsInformer.GetStore().Update(dev)
+	c.updateNamespace(relabeled, dev)
+	if got := c.elasticQuotaInfos.quotaFor("dev"); got != tenantInfo {
+		t.Errorf("expected namespace dev to be subject to the tenant quota again, got %v", got)
+	}
+
+	// Removing the NamespaceSelector moves the usage away, and namespace events are ignored afterwards.
+	unselecting := tenant.DeepCopy()
+	unselecting.Spec.NamespaceSelector = nil
+	c.updateElasticQuota(tenant, unselecting)
+	if got := c.elasticQuotaInfos.quotaFor("dev"); got != nil {
+		t.Errorf("expected namespace dev not to be subject to any quota, got %v", got)
+	}
+	if got := c.elasticQuotaInfos["tenant"].Used; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected tenant usage %v, got %v", want, got)
+	}
+	if c.elasticQuotaInfos.selectsNamespaces() {
+		t.Errorf("expected no ElasticQuota to select namespaces anymore")
+	}
+}
+
 func makeUnschedulableNodeStatusReader() *framework.NodeToStatus {
 	nodeStatusReader := framework.NewDefaultNodeToStatus()
 	nodeStatusReader.Set("node-a", fwk.NewStatus(fwk.Unschedulable))
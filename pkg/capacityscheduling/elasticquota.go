@@ -21,6 +21,7 @@ import (
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/kubernetes/pkg/scheduler/framework"
 	"sigs.k8s.io/scheduler-plugins/pkg/util"
@@ -37,20 +38,88 @@ func NewElasticQuotaInfos() ElasticQuotaInfos {
 	return make(ElasticQuotaInfos)
 }
 
+// clone returns a snapshot of the quotas, caching the usage of their subtrees.
 func (e ElasticQuotaInfos) clone() ElasticQuotaInfos {
 	elasticQuotas := make(ElasticQuotaInfos)
+	usage := &subtreeUsage{infos: elasticQuotas}
 	for key, elasticQuotaInfo := range e {
 		elasticQuotas[key] = elasticQuotaInfo.clone()
+		elasticQuotas[key].usage = usage
 	}
 	return elasticQuotas
 }
 
+// subtreeUsage caches the usage, and the min guaranteed to the children, of the subtree of each quota of
+// a snapshot. The usage is kept up to date as pods are added to or removed from the quotas of the snapshot,
+// e.g. while selecting the victims of a preemption.
+type subtreeUsage struct {
+	infos ElasticQuotaInfos
+	// used and childrenMin are keyed by namespace, and nil until computed.
+	used        map[string]*framework.Resource
+	childrenMin map[string]*framework.Resource
+}
+
+func (s *subtreeUsage) compute() {
+	if s.used != nil {
+		return
+	}
+	s.used = make(map[string]*framework.Resource, len(s.infos))
+	s.childrenMin = make(map[string]*framework.Resource, len(s.infos))
+	for _, elasticQuotaInfo := range s.infos {
+		if parent := s.infos.parentOf(elasticQuotaInfo); parent != nil && elasticQuotaInfo.Min != nil {
+			addResource(s.childrenMin, parent.Namespace, elasticQuotaInfo.Min, 1)
+		}
+		if elasticQuotaInfo.Used == nil {
+			continue
+		}
+		for _, ancestor := range s.infos.lineage(elasticQuotaInfo) {
+			addResource(s.used, ancestor.Namespace, elasticQuotaInfo.Used, 1)
+		}
+	}
+}
+
+// update adds sign times request to the usage of the subtrees info belongs to, if already computed.
+func (s *subtreeUsage) update(info *ElasticQuotaInfo, request framework.Resource, sign int64) {
+	if s.used == nil {
+		return
+	}
+	for _, ancestor := range s.infos.lineage(info) {
+		addResource(s.used, ancestor.Namespace, &request, sign)
+	}
+}
+
+func addResource(resources map[string]*framework.Resource, namespace string, request *framework.Resource, sign int64) {
+	r, ok := resources[namespace]
+	if !ok {
+		r = framework.NewResource(nil)
+		resources[namespace] = r
+	}
+	r.MilliCPU += sign * request.MilliCPU
+	r.Memory += sign * request.Memory
+	r.EphemeralStorage += sign * request.EphemeralStorage
+	r.AllowedPodNumber += int(sign) * request.AllowedPodNumber
+	for name, value := range request.ScalarResources {
+		r.SetScalar(name, r.ScalarResources[name]+sign*value)
+	}
+}
+
+// cached checks whether the usage of the subtree of info is cached, i.e. info belongs to a snapshot.
+func (e ElasticQuotaInfos) cached(info *ElasticQuotaInfo) bool {
+	return info.usage != nil && e[info.Namespace] == info && info.usage.infos[info.Namespace] == info
+}
+
+// aggregatedUsedOverMinWith checks whether the sum of the top-level quotas' usage, including
+// the usage of every quota nested under them, exceeds the sum of their min once podRequest is added.
+// Borrowing inside a subtree is bounded by the Max of each level, see usedOverMaxWith.
 func (e ElasticQuotaInfos) aggregatedUsedOverMinWith(podRequest framework.Resource) bool {
 	used := framework.NewResource(nil)
 	min := framework.NewResource(nil)
 
 	for _, elasticQuotaInfo := range e {
-		used.Add(util.ResourceList(elasticQuotaInfo.Used))
+		if e.parentOf(elasticQuotaInfo) != nil {
+			continue
+		}
+		used.Add(util.ResourceList(e.subtreeUsed(elasticQuotaInfo)))
 		min.Add(util.ResourceList(elasticQuotaInfo.Min))
 	}
 
@@ -58,14 +127,234 @@ func (e ElasticQuotaInfos) aggregatedUsedOverMinWith(podRequest framework.Resour
 	return cmp(used, min, LowerBoundOfMin)
 }
 
+// quotaFor returns the ElasticQuotaInfo the pods of the given namespace are subject to, or nil if there is none.
+func (e ElasticQuotaInfos) quotaFor(namespace string) *ElasticQuotaInfo {
+	if elasticQuotaInfo, ok := e[namespace]; ok {
+		return elasticQuotaInfo
+	}
+	for _, elasticQuotaInfo := range e {
+		if elasticQuotaInfo.namespaces.Has(namespace) {
+			return elasticQuotaInfo
+		}
+	}
+	return nil
+}
+
+// selectsNamespaces checks whether any ElasticQuota has a NamespaceSelector, or still governs other namespaces.
+func (e ElasticQuotaInfos) selectsNamespaces() bool {
+	for _, elasticQuotaInfo := range e {
+		if elasticQuotaInfo.selector != nil || elasticQuotaInfo.namespaces.Len() > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// selectingQuota returns the ElasticQuotaInfo whose NamespaceSelector selects the given namespace, the one
+// of the first namespace if several do, or nil if the namespace has its own ElasticQuota or none selects it.
+func (e ElasticQuotaInfos) selectingQuota(namespace string, nsLabels map[string]string) *ElasticQuotaInfo {
+	if _, ok := e[namespace]; ok {
+		return nil
+	}
+	var selected *ElasticQuotaInfo
+	for _, elasticQuotaInfo := range e {
+		if elasticQuotaInfo.selector == nil || !elasticQuotaInfo.selector.Matches(labels.Set(nsLabels)) {
+			continue
+		}
+		if selected == nil || elasticQuotaInfo.Namespace < selected.Namespace {
+			selected = elasticQuotaInfo
+		}
+	}
+	return selected
+}
+
+// governing returns the ElasticQuotaInfo whose NamespaceSelector currently governs the given namespace, if any.
+func (e ElasticQuotaInfos) governing(namespace string) *ElasticQuotaInfo {
+	for _, elasticQuotaInfo := range e {
+		if elasticQuotaInfo.namespaces.Has(namespace) {
+			return elasticQuotaInfo
+		}
+	}
+	return nil
+}
+
+// parentOf returns the ElasticQuotaInfo that info is nested under, or nil if info is a
+// top-level quota or its parent is unknown.
+func (e ElasticQuotaInfos) parentOf(info *ElasticQuotaInfo) *ElasticQuotaInfo {
+	if len(info.Parent) == 0 || info.Parent == info.Namespace {
+		return nil
+	}
+	return e[info.Parent]
+}
+
+// lineage returns info followed by its ancestors, ordered from info up to its top-level quota.
+// A misconfigured parent cycle is cut at the first quota that is visited twice.
+func (e ElasticQuotaInfos) lineage(info *ElasticQuotaInfo) []*ElasticQuotaInfo {
+	var lineage []*ElasticQuotaInfo
+	visited := sets.New[string]()
+	for cur := info; cur != nil && !visited.Has(cur.Namespace); cur = e.parentOf(cur) {
+		visited.Insert(cur.Namespace)
+		lineage = append(lineage, cur)
+	}
+	return lineage
+}
+
+// subtreeUsed returns the usage of info together with the usage of every quota nested under it.
+func (e ElasticQuotaInfos) subtreeUsed(info *ElasticQuotaInfo) *framework.Resource {
+	if e.cached(info) {
+		info.usage.compute()
+		if used, ok := info.usage.used[info.Namespace]; ok {
+			return used.Clone()
+		}
+		return framework.NewResource(nil)
+	}
+	used := framework.NewResource(nil)
+	for _, elasticQuotaInfo := range e {
+		if elasticQuotaInfo.Used == nil {
+			continue
+		}
+		for _, ancestor := range e.lineage(elasticQuotaInfo) {
+			if ancestor == info {
+				used.Add(util.ResourceList(elasticQuotaInfo.Used))
+				break
+			}
+		}
+	}
+	return used
+}
+
+// usedOverMinWith checks whether the usage of info's subtree exceeds info's min once podRequest is added.
+func (e ElasticQuotaInfos) usedOverMinWith(info *ElasticQuotaInfo, podRequest *framework.Resource) bool {
+	// "ElasticQuotaInfo doesn't have Min" means used values exceeded min(0)
+	if info.Min == nil {
+		return true
+	}
+	return cmp2(podRequest, e.subtreeUsed(info), info.Min, LowerBoundOfMin)
+}
+
+// usedOverMin checks whether the usage of info's subtree exceeds info's min.
+func (e ElasticQuotaInfos) usedOverMin(info *ElasticQuotaInfo) bool {
+	// "ElasticQuotaInfo doesn't have Min" means used values exceeded min(0)
+	if info.Min == nil {
+		return true
+	}
+	return cmp(e.subtreeUsed(info), info.Min, LowerBoundOfMin)
+}
+
+// guaranteedMin returns the min of info, raised to the sum of the min of the quotas nested directly under it,
+// so that the min guaranteed to its children is always covered.
+func (e ElasticQuotaInfos) guaranteedMin(info *ElasticQuotaInfo) *framework.Resource {
+	min := framework.NewResource(nil)
+	if info.Min != nil {
+		min = info.Min.Clone()
+	}
+	var childrenMin *framework.Resource
+	if e.cached(info) {
+		info.usage.compute()
+		childrenMin = info.usage.childrenMin[info.Namespace]
+	} else {
+		childrenMin = framework.NewResource(nil)
+		for _, elasticQuotaInfo := range e {
+			if elasticQuotaInfo.Min != nil && e.parentOf(elasticQuotaInfo) == info {
+				childrenMin.Add(util.ResourceList(elasticQuotaInfo.Min))
+			}
+		}
+	}
+	if childrenMin == nil {
+		return min
+	}
+	min.MilliCPU = max(min.MilliCPU, childrenMin.MilliCPU)
+	min.Memory = max(min.Memory, childrenMin.Memory)
+	min.EphemeralStorage = max(min.EphemeralStorage, childrenMin.EphemeralStorage)
+	min.AllowedPodNumber = max(min.AllowedPodNumber, childrenMin.AllowedPodNumber)
+	for name, value := range childrenMin.ScalarResources {
+		if value > min.ScalarResources[name] {
+			min.SetScalar(name, value)
+		}
+	}
+	return min
+}
+
+// ancestorsUsedOverMinWith checks whether the usage of the subtree of any ancestor of info exceeds the min
+// guaranteed to it once podRequest is added, i.e. whether info would borrow beyond what the min of an
+// ancestor leaves free among its descendants. The top-level quotas borrow from each other as long as
+// their aggregated usage doesn't exceed their aggregated min, see aggregatedUsedOverMinWith.
+func (e ElasticQuotaInfos) ancestorsUsedOverMinWith(info *ElasticQuotaInfo, podRequest *framework.Resource) bool {
+	lineage := e.lineage(info)
+	for _, ancestor := range lineage[1:] {
+		if cmp2(podRequest, e.subtreeUsed(ancestor), e.guaranteedMin(ancestor), LowerBoundOfMin) {
+			return true
+		}
+	}
+	return false
+}
+
+// usedOverMaxWith checks whether adding podRequest to info exceeds the max of info or of any of its ancestors.
+func (e ElasticQuotaInfos) usedOverMaxWith(info *ElasticQuotaInfo, podRequest *framework.Resource) bool {
+	for _, elasticQuotaInfo := range e.lineage(info) {
+		// "ElasticQuotaInfo doesn't have Max" means there are no limitations(infinite)
+		if elasticQuotaInfo.Max == nil {
+			continue
+		}
+		if cmp2(podRequest, e.subtreeUsed(elasticQuotaInfo), elasticQuotaInfo.Max, UpperBoundOfMax) {
+			return true
+		}
+	}
+	return false
+}
+
+// distance returns the number of levels between preemptor and the closest quota it shares
+// with victim. Quotas in different trees are one level further apart than the height of
+// preemptor's lineage, so that pods in unrelated trees are considered the most distant.
+func (e ElasticQuotaInfos) distance(preemptor, victim *ElasticQuotaInfo) int {
+	victimLineage := sets.New[string]()
+	for _, ancestor := range e.lineage(victim) {
+		victimLineage.Insert(ancestor.Namespace)
+	}
+	preemptorLineage := e.lineage(preemptor)
+	for i, ancestor := range preemptorLineage {
+		if victimLineage.Has(ancestor.Namespace) {
+			return i
+		}
+	}
+	return len(preemptorLineage)
+}
+
+// borrowing checks whether victim, or any of its ancestors that isn't shared with preemptor,
+// uses more than its min, i.e. victim's subtree holds resources borrowed from elsewhere.
+func (e ElasticQuotaInfos) borrowing(preemptor, victim *ElasticQuotaInfo) bool {
+	preemptorLineage := sets.New[string]()
+	for _, ancestor := range e.lineage(preemptor) {
+		preemptorLineage.Insert(ancestor.Namespace)
+	}
+	for _, ancestor := range e.lineage(victim) {
+		if preemptorLineage.Has(ancestor.Namespace) {
+			break
+		}
+		if e.usedOverMin(ancestor) {
+			return true
+		}
+	}
+	return false
+}
+
 // ElasticQuotaInfo is a wrapper to a ElasticQuota with information.
 // Each namespace can only have one ElasticQuota.
 type ElasticQuotaInfo struct {
 	Namespace string
-	pods      sets.Set[string]
-	Min       *framework.Resource
-	Max       *framework.Resource
-	Used      *framework.Resource
+	// Parent is the namespace of the ElasticQuota this one is nested under.
+	Parent string
+	// namespaces are the other namespaces selected by the NamespaceSelector of the ElasticQuota.
+	// It is nil if the ElasticQuota has no NamespaceSelector.
+	namespaces sets.Set[string]
+	pods       sets.Set[string]
+	Min        *framework.Resource
+	Max        *framework.Resource
+	Used       *framework.Resource
+	// selector is the NamespaceSelector of the ElasticQuota, nil if it has none.
+	selector labels.Selector
+	// usage caches the usage of the subtrees of the snapshot the ElasticQuotaInfo belongs to, if any.
+	usage *subtreeUsage
 }
 
 func newElasticQuotaInfo(namespace string, min, max, used v1.ResourceList) *ElasticQuotaInfo {
@@ -94,6 +383,9 @@ func (e *ElasticQuotaInfo) reserveResource(request framework.Resource) {
 	for name, value := range request.ScalarResources {
 		e.Used.SetScalar(name, e.Used.ScalarResources[name]+value)
 	}
+	if e.usage != nil {
+		e.usage.update(e, request, 1)
+	}
 }
 
 func (e *ElasticQuotaInfo) unreserveResource(request framework.Resource) {
@@ -104,35 +396,15 @@ func (e *ElasticQuotaInfo) unreserveResource(request framework.Resource) {
 	for name, value := range request.ScalarResources {
 		e.Used.SetScalar(name, e.Used.ScalarResources[name]-value)
 	}
-}
-
-func (e *ElasticQuotaInfo) usedOverMinWith(podRequest *framework.Resource) bool {
-	// "ElasticQuotaInfo doesn't have Min" means used values exceeded min(0)
-	if e.Min == nil {
-		return true
+	if e.usage != nil {
+		e.usage.update(e, request, -1)
 	}
-	return cmp2(podRequest, e.Used, e.Min, LowerBoundOfMin)
-}
-
-func (e *ElasticQuotaInfo) usedOverMaxWith(podRequest *framework.Resource) bool {
-	// "ElasticQuotaInfo doesn't have Max" means there are no limitations(infinite)
-	if e.Max == nil {
-		return false
-	}
-	return cmp2(podRequest, e.Used, e.Max, UpperBoundOfMax)
-}
-
-func (e *ElasticQuotaInfo) usedOverMin() bool {
-	// "ElasticQuotaInfo doesn't have Min" means used values exceeded min(0)
-	if e.Min == nil {
-		return true
-	}
-	return cmp(e.Used, e.Min, LowerBoundOfMin)
 }
 
 func (e *ElasticQuotaInfo) clone() *ElasticQuotaInfo {
 	newEQInfo := &ElasticQuotaInfo{
 		Namespace: e.Namespace,
+		Parent:    e.Parent,
 		pods:      sets.New[string](),
 	}
 
@@ -148,6 +420,10 @@ func (e *ElasticQuotaInfo) clone() *ElasticQuotaInfo {
 	for pod := range e.pods {
 		newEQInfo.pods.Insert(pod)
 	}
+	if e.namespaces != nil {
+		newEQInfo.namespaces = e.namespaces.Clone()
+	}
+	newEQInfo.selector = e.selector
 
 	return newEQInfo
 }
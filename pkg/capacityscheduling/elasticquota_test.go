@@ -254,7 +254,8 @@ func TestUsedOverMinWith(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			elasticQuotaInfo := tt.before
 			podRequest := tt.podRequest
-			actual := elasticQuotaInfo.usedOverMinWith(podRequest)
+			elasticQuotaInfos := ElasticQuotaInfos{elasticQuotaInfo.Namespace: elasticQuotaInfo}
+			actual := elasticQuotaInfos.usedOverMinWith(elasticQuotaInfo, podRequest)
 			if actual != tt.expected {
 				t.Errorf("expected %v, got %v", tt.expected, actual)
 			}
@@ -386,7 +387,8 @@ func TestUsedOverMaxWith(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			elasticQuotaInfo := tt.before
 			podRequest := tt.podRequest
-			actual := elasticQuotaInfo.usedOverMaxWith(podRequest)
+			elasticQuotaInfos := ElasticQuotaInfos{elasticQuotaInfo.Namespace: elasticQuotaInfo}
+			actual := elasticQuotaInfos.usedOverMaxWith(elasticQuotaInfo, podRequest)
 			if actual != tt.expected {
 				t.Errorf("expected %v, got %v", tt.expected, actual)
 			}
@@ -495,7 +497,8 @@ func TestUsedOverMin(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			elasticQuotaInfo := tt.before
-			actual := elasticQuotaInfo.usedOverMin()
+			elasticQuotaInfos := ElasticQuotaInfos{elasticQuotaInfo.Namespace: elasticQuotaInfo}
+			actual := elasticQuotaInfos.usedOverMin(elasticQuotaInfo)
 			if actual != tt.expected {
 				t.Errorf("expected %v, got %v", tt.expected, actual)
 			}
@@ -630,3 +633,142 @@ func TestNewElasticQuotaInfo(t *testing.T) {
 		})
 	}
 }
+
+func TestNestedElasticQuotaInfos(t *testing.T) {
+	elasticQuotaInfos := ElasticQuotaInfos{
+		"dept": {
+			Namespace: "dept",
+			Min:       &framework.Resource{Memory: 100},
+			Max:       &framework.Resource{Memory: 150},
+			Used:      &framework.Resource{},
+		},
+		"team-a": {
+			Namespace: "team-a",
+			Parent:    "dept",
+			Min:       &framework.Resource{Memory: 50},
+			Max:       &framework.Resource{Memory: 200},
+			Used:      &framework.Resource{Memory: 40},
+		},
+		"team-b": {
+			Namespace: "team-b",
+			Parent:    "dept",
+			Min:       &framework.Resource{Memory: 50},
+			Max:       &framework.Resource{Memory: 200},
+			Used:      &framework.Resource{Memory: 80},
+		},
+		"other": {
+			Namespace: "other",
+			Min:       &framework.Resource{Memory: 100},
+			Max:       &framework.Resource{Memory: 200},
+			Used:      &framework.Resource{Memory: 50},
+		},
+	}
+
+	t.Run("subtree usage includes nested quotas", func(t *testing.T) {
+		if got := elasticQuotaInfos.subtreeUsed(elasticQuotaInfos["dept"]).Memory; got != 120 {
+			t.Errorf("expected 120, got %v", got)
+		}
+		if got := elasticQuotaInfos.subtreeUsed(elasticQuotaInfos["team-a"]).Memory; got != 40 {
+			t.Errorf("expected 40, got %v", got)
+		}
+	})
+
+	t.Run("max is enforced at every level", func(t *testing.T) {
+		if elasticQuotaInfos.usedOverMaxWith(elasticQuotaInfos["team-a"], &framework.Resource{Memory: 20}) {
+			t.Errorf("expected request within the max of team-a and dept")
+		}
+		if !elasticQuotaInfos.usedOverMaxWith(elasticQuotaInfos["team-a"], &framework.Resource{Memory: 40}) {
+			t.Errorf("expected request over the max of dept")
+		}
+	})
+
+	t.Run("min is evaluated against subtree usage", func(t *testing.T) {
+		if elasticQuotaInfos.usedOverMinWith(elasticQuotaInfos["team-a"], &framework.Resource{Memory: 10}) {
+			t.Errorf("expected team-a within its min")
+		}
+		if !elasticQuotaInfos.usedOverMin(elasticQuotaInfos["team-b"]) {
+			t.Errorf("expected team-b over its min")
+		}
+		if !elasticQuotaInfos.usedOverMin(elasticQuotaInfos["dept"]) {
+			t.Errorf("expected dept over its min")
+		}
+	})
+
+	t.Run("min is enforced at every ancestor", func(t *testing.T) {
+		if !elasticQuotaInfos.ancestorsUsedOverMinWith(elasticQuotaInfos["team-a"], &framework.Resource{}) {
+			t.Errorf("expected team-a and team-b over the min of dept")
+		}
+
+		snapshot := elasticQuotaInfos.clone()
+		snapshot["team-b"].unreserveResource(framework.Resource{Memory: 40})
+		if got := snapshot.subtreeUsed(snapshot["dept"]).Memory; got != 80 {
+			t.Errorf("expected the cached usage of dept updated to 80, got %v", got)
+		}
+		if snapshot.ancestorsUsedOverMinWith(snapshot["team-a"], &framework.Resource{Memory: 20}) {
+			t.Errorf("expected request within the min of dept")
+		}
+		if !snapshot.ancestorsUsedOverMinWith(snapshot["team-a"], &framework.Resource{Memory: 30}) {
+			t.Errorf("expected request over the min of dept")
+		}
+		if got := elasticQuotaInfos.subtreeUsed(elasticQuotaInfos["dept"]).Memory; got != 120 {
+			t.Errorf("expected the usage of the quotas unchanged by their snapshot, got %v", got)
+		}
+	})
+
+	t.Run("min of an ancestor covers the min of its children", func(t *testing.T) {
+		infos := ElasticQuotaInfos{
+			"dept":   {Namespace: "dept", Min: &framework.Resource{Memory: 50}, Used: &framework.Resource{}},
+			"team-a": {Namespace: "team-a", Parent: "dept", Min: &framework.Resource{Memory: 50}, Used: &framework.Resource{Memory: 50}},
+			"team-b": {Namespace: "team-b", Parent: "dept", Min: &framework.Resource{Memory: 50}, Used: &framework.Resource{}},
+		}
+		for _, e := range []ElasticQuotaInfos{infos, infos.clone()} {
+			if got := e.guaranteedMin(e["dept"]).Memory; got != 100 {
+				t.Errorf("expected the min of dept raised to 100, got %v", got)
+			}
+			if e.ancestorsUsedOverMinWith(e["team-b"], &framework.Resource{Memory: 50}) {
+				t.Errorf("expected team-b to get its min")
+			}
+		}
+	})
+
+	t.Run("aggregated min only counts top-level quotas", func(t *testing.T) {
+		if elasticQuotaInfos.aggregatedUsedOverMinWith(framework.Resource{Memory: 20}) {
+			t.Errorf("expected aggregated usage within aggregated min")
+		}
+		if !elasticQuotaInfos.aggregatedUsedOverMinWith(framework.Resource{Memory: 40}) {
+			t.Errorf("expected aggregated usage over aggregated min")
+		}
+	})
+
+	t.Run("distance and borrowing between quotas", func(t *testing.T) {
+		tests := []struct {
+			preemptor, victim string
+			distance          int
+			borrowing         bool
+		}{
+			{preemptor: "team-a", victim: "team-a", distance: 0, borrowing: false},
+			{preemptor: "team-a", victim: "team-b", distance: 1, borrowing: true},
+			{preemptor: "team-a", victim: "other", distance: 2, borrowing: false},
+			{preemptor: "other", victim: "team-a", distance: 1, borrowing: true},
+		}
+		for _, tt := range tests {
+			preemptor, victim := elasticQuotaInfos[tt.preemptor], elasticQuotaInfos[tt.victim]
+			if got := elasticQuotaInfos.distance(preemptor, victim); got != tt.distance {
+				t.Errorf("%s->%s: expected distance %v, got %v", tt.preemptor, tt.victim, tt.distance, got)
+			}
+			if got := elasticQuotaInfos.borrowing(preemptor, victim); got != tt.borrowing {
+				t.Errorf("%s->%s: expected borrowing %v, got %v", tt.preemptor, tt.victim, tt.borrowing, got)
+			}
+		}
+	})
+
+	t.Run("parent cycles are cut", func(t *testing.T) {
+		cyclic := ElasticQuotaInfos{
+			"ns1": {Namespace: "ns1", Parent: "ns2", Used: &framework.Resource{Memory: 10}},
+			"ns2": {Namespace: "ns2", Parent: "ns1", Used: &framework.Resource{Memory: 20}},
+		}
+		if got := len(cyclic.lineage(cyclic["ns1"])); got != 2 {
+			t.Errorf("expected lineage of 2 quotas, got %v", got)
+		}
+	})
+}
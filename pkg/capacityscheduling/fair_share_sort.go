@@ -0,0 +1,262 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package capacityscheduling
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/cache"
+	corev1helpers "k8s.io/component-helpers/scheduling/corev1"
+	fwk "k8s.io/kube-scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"sigs.k8s.io/scheduler-plugins/apis/scheduling/v1alpha1"
+	"sigs.k8s.io/scheduler-plugins/pkg/util"
+)
+
+// FairShareSortName is the name of the fair-share queue sort plugin used in Registry and configurations.
+const FairShareSortName = "FairShareSort"
+
+// FairShareSort is a queue sort plugin that orders pending pods by the Dominant Resource Fairness (DRF)
+// share of the ElasticQuotas they are subject to, so that the tenants under their min are dequeued
+// before the tenants borrowing over their min.
+type FairShareSort struct {
+	// capacityScheduling keeps track of the usage of the ElasticQuotas, shared with the CapacityScheduling
+	// plugin of the same profile.
+	capacityScheduling *CapacityScheduling
+
+	sync.Mutex
+	// keys are the sort keys of the queued pods by UID, computed once each time a pod is added to the
+	// queue, so that the order of the queued pods doesn't change as the usage of the quotas does.
+	keys map[types.UID]*queuedKey
+	// groups are the sort keys of the PodGroups by full name, shared by their queued members.
+	groups map[string]*groupKey
+}
+
+// queuedKey is the sort key of a pod, computed when the pod was added to the queue at timestamp.
+type queuedKey struct {
+	timestamp time.Time
+	share     float64
+	time      time.Time
+	key       string
+	// group is the full name of the PodGroup the key is taken from, empty if the pod doesn't belong to any.
+	group string
+}
+
+// groupKey is the sort key of a PodGroup, computed when its first member was added to the queue, and kept
+// until none of its members is queued anymore, so that the members queued at different times are not
+// interleaved with other pods.
+type groupKey struct {
+	share   float64
+	time    time.Time
+	members sets.Set[types.UID]
+}
+
+var _ framework.QueueSortPlugin = &FairShareSort{}
+var _ io.Closer = &FairShareSort{}
+
+// Name returns name of the plugin. It is used in logs, etc.
+func (pl *FairShareSort) Name() string {
+	return FairShareSortName
+}
+
+// NewFairShareSort initializes a new plugin and returns it.
+func NewFairShareSort(ctx context.Context, obj runtime.Object, handle framework.Handle) (framework.Plugin, error) {
+	c, err := acquire(ctx, handle)
+	if err != nil {
+		return nil, err
+	}
+	pl := &FairShareSort{
+		capacityScheduling: c,
+		keys:               make(map[types.UID]*queuedKey),
+		groups:             make(map[string]*groupKey),
+	}
+	// Forget the keys of the pods leaving the queue for good.
+	handle.SharedInformerFactory().Core().V1().Pods().Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(_, newObj interface{}) {
+			if pod, ok := newObj.(*v1.Pod); ok && assignedPod(pod) {
+				pl.forget(pod.UID)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			if t, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = t.Obj
+			}
+			if pod, ok := obj.(*v1.Pod); ok {
+				pl.forget(pod.UID)
+			}
+		},
+	})
+	return pl, nil
+}
+
+// Close releases the CapacityScheduling shared with the CapacityScheduling plugin.
+func (pl *FairShareSort) Close() error {
+	return pl.capacityScheduling.Close()
+}
+
+// Less is used to sort pods in the scheduling queue in the following order.
+// 1. Compare the priorities of Pods.
+// 2. Compare the dominant shares of the ElasticQuotas of Pods, the lowest first.
+// 3. Compare the creation timestamps of PodGroups, or the initialization timestamps of Pods.
+// 4. Compare the keys of PodGroups/Pods: <namespace>/<name>.
+// The Pods of a PodGroup share the same quota, timestamp and key, so that a gang is never interleaved
+// with other Pods, like with the queue sort of Coscheduling.
+// The dominant shares are those of when the Pods, or the first queued member of their PodGroup,
+// were added to the queue.
+func (pl *FairShareSort) Less(podInfo1, podInfo2 fwk.QueuedPodInfo) bool {
+	pod1, pod2 := podInfo1.GetPodInfo().GetPod(), podInfo2.GetPodInfo().GetPod()
+	prio1, prio2 := corev1helpers.PodPriority(pod1), corev1helpers.PodPriority(pod2)
+	if prio1 != prio2 {
+		return prio1 > prio2
+	}
+
+	key1, key2 := pl.queuedKey(podInfo1), pl.queuedKey(podInfo2)
+	if key1.share != key2.share {
+		return key1.share < key2.share
+	}
+	if !key1.time.Equal(key2.time) {
+		return key1.time.Before(key2.time)
+	}
+	if key1.key != key2.key {
+		return key1.key < key2.key
+	}
+	return fmt.Sprintf("%v/%v", pod1.Namespace, pod1.Name) < fmt.Sprintf("%v/%v", pod2.Namespace, pod2.Name)
+}
+
+// queuedKey returns the sort key of the queued pod, computing it the first time the pod is compared
+// since it was added to the queue.
+func (pl *FairShareSort) queuedKey(podInfo fwk.QueuedPodInfo) *queuedKey {
+	pod := podInfo.GetPodInfo().GetPod()
+	pl.Lock()
+	defer pl.Unlock()
+	if key, ok := pl.keys[pod.UID]; ok && key.timestamp.Equal(podInfo.GetTimestamp()) {
+		return key
+	}
+	key := &queuedKey{timestamp: podInfo.GetTimestamp()}
+	if group, ok := pl.groupKey(pod); ok {
+		group.members.Insert(pod.UID)
+		key.group = util.GetPodGroupFullName(pod)
+		key.share, key.time, key.key = group.share, group.time, key.group
+	} else {
+		key.share = pl.dominantShare(pod)
+		key.time, key.key = *podInfo.GetInitialAttemptTimestamp(), fmt.Sprintf("%v/%v", pod.Namespace, pod.Name)
+	}
+	pl.keys[pod.UID] = key
+	return key
+}
+
+// groupKey returns the sort key of the PodGroup the pod belongs to, computing it the first time one of
+// its members is queued. It returns false if the pod doesn't belong to any existing PodGroup.
+// The caller must hold the lock.
+func (pl *FairShareSort) groupKey(pod *v1.Pod) (*groupKey, bool) {
+	pgName := util.GetPodGroupLabel(pod)
+	if len(pgName) == 0 {
+		return nil, false
+	}
+	fullName := util.GetPodGroupFullName(pod)
+	if group, ok := pl.groups[fullName]; ok {
+		return group, true
+	}
+	pg, err := pl.capacityScheduling.getPodGroup(pod.Namespace, pgName)
+	if err != nil {
+		return nil, false
+	}
+	group := &groupKey{
+		share:   pl.dominantShare(pod),
+		time:    pg.CreationTimestamp.Time,
+		members: sets.New[types.UID](),
+	}
+	pl.groups[fullName] = group
+	return group, true
+}
+
+func (pl *FairShareSort) forget(uid types.UID) {
+	pl.Lock()
+	defer pl.Unlock()
+	key, ok := pl.keys[uid]
+	if !ok {
+		return
+	}
+	delete(pl.keys, uid)
+	if group, ok := pl.groups[key.group]; ok {
+		group.members.Delete(uid)
+		if group.members.Len() == 0 {
+			delete(pl.groups, key.group)
+		}
+	}
+}
+
+// dominantShare returns the DRF share of the ElasticQuota the pod is subject to, i.e. the largest ratio
+// of its usage to its min among all resources. The share is greater than 1 once the quota borrows over
+// its min, and infinite if it uses a resource it has no min for. The usage of the PodGroup of the pod
+// is left out, so that the share of a gang doesn't change while its members are bound one by one.
+// Pods not subject to any ElasticQuota have a share of 0.
+func (pl *FairShareSort) dominantShare(pod *v1.Pod) float64 {
+	c := pl.capacityScheduling
+	c.RLock()
+	defer c.RUnlock()
+	info := c.elasticQuotaInfos.quotaFor(pod.Namespace)
+	if info == nil {
+		return 0
+	}
+	used := &ElasticQuotaInfo{Used: c.elasticQuotaInfos.subtreeUsed(info)}
+	if pgName := util.GetPodGroupLabel(pod); len(pgName) != 0 {
+		siblings, _ := c.podLister.Pods(pod.Namespace).List(
+			labels.SelectorFromSet(labels.Set{v1alpha1.PodGroupLabel: pgName}),
+		)
+		for _, sibling := range siblings {
+			if key, err := framework.GetPodKey(sibling); err == nil && info.pods.Has(key) {
+				used.unreserveResource(*computePodResourceRequest(sibling))
+			}
+		}
+	}
+	min := info.Min
+	if min == nil {
+		min = &framework.Resource{}
+	}
+
+	share := max(
+		ratio(used.Used.MilliCPU, min.MilliCPU),
+		ratio(used.Used.Memory, min.Memory),
+		ratio(used.Used.EphemeralStorage, min.EphemeralStorage),
+	)
+	for name, quant := range used.Used.ScalarResources {
+		share = max(share, ratio(quant, min.ScalarResources[name]))
+	}
+	return share
+}
+
+func ratio(used, min int64) float64 {
+	if used <= 0 {
+		return 0
+	}
+	if min <= 0 {
+		return math.Inf(1)
+	}
+	return float64(used) / float64(min)
+}
@@ -0,0 +1,316 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package capacityscheduling
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/informers"
+	clientsetfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	frameworkruntime "k8s.io/kubernetes/pkg/scheduler/framework/runtime"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+	tf "k8s.io/kubernetes/pkg/scheduler/testing/framework"
+
+	"sigs.k8s.io/scheduler-plugins/apis/scheduling/v1alpha1"
+	testutil "sigs.k8s.io/scheduler-plugins/test/util"
+)
+
+func TestFairShareSortLess(t *testing.T) {
+	now := time.Now()
+	lowPriority, highPriority := int32(10), int32(100)
+	makeQuota := func(namespace string, minMilliCPU int64, pods ...*v1.Pod) *ElasticQuotaInfo {
+		info := newElasticQuotaInfo(namespace, makeResourceList(minMilliCPU, 0), nil, nil)
+		for _, pod := range pods {
+			if err := info.addPodIfNotPresent(pod); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return info
+	}
+	makePod := func(name, namespace, pg string, priority int32, cpu string) *v1.Pod {
+		w := st.MakePod().Name(name).Namespace(namespace).UID(name).Priority(priority).
+			Req(map[v1.ResourceName]string{v1.ResourceCPU: cpu})
+		if pg != "" {
+			w = w.Label(v1alpha1.PodGroupLabel, pg)
+		}
+		return w.Obj()
+	}
+	queued := func(pod *v1.Pod, initialAttempt time.Time) *framework.QueuedPodInfo {
+		return &framework.QueuedPodInfo{
+			PodInfo:                 testutil.MustNewPodInfo(t, pod),
+			InitialAttemptTimestamp: &initialAttempt,
+		}
+	}
+
+	// ns1 uses 1 of its 4 cpus, ns2 uses 6 of its 4 cpus.
+	ns1Bound := makePod("ns1-bound", "ns1", "", lowPriority, "1")
+	ns2Bound := makePod("ns2-bound", "ns2", "", lowPriority, "6")
+	// The members of gang in ns3 use all of its 4 cpus.
+	gangBound := []*v1.Pod{
+		makePod("gang-bound-1", "ns3", "gang", lowPriority, "2"),
+		makePod("gang-bound-2", "ns3", "gang", lowPriority, "2"),
+	}
+	for _, pod := range append([]*v1.Pod{ns1Bound, ns2Bound}, gangBound...) {
+		pod.Spec.NodeName = "node"
+	}
+	quotas := ElasticQuotaInfos{
+		"ns1": makeQuota("ns1", 4000, ns1Bound),
+		"ns2": makeQuota("ns2", 4000, ns2Bound),
+		"ns3": makeQuota("ns3", 4000, gangBound...),
+	}
+
+	tests := []struct {
+		name string
+		p1   *framework.QueuedPodInfo
+		p2   *framework.QueuedPodInfo
+		pgs  []*v1alpha1.PodGroup
+		want bool
+	}{
+		{
+			name: "higher priority comes first regardless of the share",
+			p1:   queued(makePod("p1", "ns1", "", lowPriority, "1"), now),
+			p2:   queued(makePod("p2", "ns2", "", highPriority, "1"), now.Add(time.Second)),
+			want: false,
+		},
+		{
+			name: "quota under its min comes before quota borrowing over its min",
+			p1:   queued(makePod("p1", "ns2", "", lowPriority, "1"), now),
+			p2:   queued(makePod("p2", "ns1", "", lowPriority, "1"), now.Add(time.Second)),
+			want: false,
+		},
+		{
+			name: "pod without quota comes first",
+			p1:   queued(makePod("p1", "ns1", "", lowPriority, "1"), now),
+			p2:   queued(makePod("p2", "default", "", lowPriority, "1"), now.Add(time.Second)),
+			want: false,
+		},
+		{
+			name: "same share, p1 is added to schedulingQ earlier than p2",
+			p1:   queued(makePod("p1", "ns1", "", lowPriority, "1"), now),
+			p2:   queued(makePod("p2", "ns1", "", lowPriority, "1"), now.Add(time.Second)),
+			want: true,
+		},
+		{
+			name: "usage of the PodGroup is left out of its share",
+			p1:   queued(makePod("p1", "ns1", "", lowPriority, "1"), now),
+			p2:   queued(makePod("gang-3", "ns3", "gang", lowPriority, "1"), now.Add(time.Second)),
+			pgs: []*v1alpha1.PodGroup{
+				testutil.MakePodGroup().Name("gang").Namespace("ns3").MinMember(3).Time(now).Obj(),
+			},
+			want: false,
+		},
+		{
+			name: "members of the same PodGroup are not interleaved",
+			p1:   queued(makePod("p1", "ns1", "", lowPriority, "1"), now),
+			p2:   queued(makePod("pg1-2", "ns1", "pg1", lowPriority, "1"), now.Add(time.Second)),
+			pgs: []*v1alpha1.PodGroup{
+				testutil.MakePodGroup().Name("pg1").Namespace("ns1").MinMember(2).Time(now.Add(-time.Second)).Obj(),
+			},
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var objs []runtime.Object
+			for _, pg := range tt.pgs {
+				objs = append(objs, pg)
+			}
+			client, err := testutil.NewFakeClient(objs...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			informerFactory := informers.NewSharedInformerFactory(clientsetfake.NewSimpleClientset(), 0)
+			podInformer := informerFactory.Core().V1().Pods()
+			for _, pod := range append([]*v1.Pod{ns1Bound, ns2Bound}, gangBound...) {
+				podInformer.Informer().GetStore().Add(pod)
+			}
+
+			pl := &FairShareSort{
+				capacityScheduling: &CapacityScheduling{
+					elasticQuotaInfos: quotas,
+					podLister:         podInformer.Lister(),
+					client:            client,
+				},
+				keys:   make(map[types.UID]*queuedKey),
+				groups: make(map[string]*groupKey),
+			}
+			if got := pl.Less(tt.p1, tt.p2); got != tt.want {
+				t.Errorf("Want %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestFairShareSortQueuedShares(t *testing.T) {
+	now := time.Now()
+	client, err := testutil.NewFakeClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	quotas := ElasticQuotaInfos{
+		"ns1": newElasticQuotaInfo("ns1", makeResourceList(4000, 0), nil, makeResourceList(1000, 0)),
+		"ns2": newElasticQuotaInfo("ns2", makeResourceList(4000, 0), nil, makeResourceList(2000, 0)),
+	}
+	pl := &FairShareSort{
+		capacityScheduling: &CapacityScheduling{elasticQuotaInfos: quotas, client: client},
+		keys:               make(map[types.UID]*queuedKey),
+		groups:             make(map[string]*groupKey),
+	}
+	queued := func(name, namespace string, timestamp time.Time) *framework.QueuedPodInfo {
+		return &framework.QueuedPodInfo{
+			PodInfo:                 testutil.MustNewPodInfo(t, st.MakePod().Name(name).Namespace(namespace).UID(name).Obj()),
+			Timestamp:               timestamp,
+			InitialAttemptTimestamp: &now,
+		}
+	}
+	p1, p2 := queued("p1", "ns1", now), queued("p2", "ns2", now)
+	if !pl.Less(p1, p2) {
+		t.Fatalf("Want p1 of the quota with the lowest share first")
+	}
+
+	// ns1 now borrows over its min while both pods are queued.
+	quotas["ns1"].reserveResource(framework.Resource{MilliCPU: 5000})
+	if !pl.Less(p1, p2) || pl.Less(p2, p1) {
+		t.Errorf("Want the order of the queued pods unchanged by the usage of their quotas")
+	}
+
+	// Once p1 is added back to the queue, its share is recomputed.
+	p1 = queued("p1", "ns1", now.Add(time.Second))
+	if pl.Less(p1, p2) {
+		t.Errorf("Want p1 after p2 once p1 is added back to the queue")
+	}
+
+	pl.forget("p1")
+	if _, ok := pl.keys["p1"]; ok {
+		t.Errorf("Want the key of p1 forgotten")
+	}
+}
+
+func TestFairShareSortQueuedGroupShares(t *testing.T) {
+	now := time.Now()
+	client, err := testutil.NewFakeClient(
+		testutil.MakePodGroup().Name("pg1").Namespace("ns1").MinMember(2).Time(now).Obj(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	quotas := ElasticQuotaInfos{
+		"ns1": newElasticQuotaInfo("ns1", makeResourceList(4000, 0), nil, makeResourceList(1000, 0)),
+		"ns2": newElasticQuotaInfo("ns2", makeResourceList(4000, 0), nil, makeResourceList(2000, 0)),
+	}
+	informerFactory := informers.NewSharedInformerFactory(clientsetfake.NewSimpleClientset(), 0)
+	pl := &FairShareSort{
+		capacityScheduling: &CapacityScheduling{
+			elasticQuotaInfos: quotas,
+			podLister:         informerFactory.Core().V1().Pods().Lister(),
+			client:            client,
+		},
+		keys:   make(map[types.UID]*queuedKey),
+		groups: make(map[string]*groupKey),
+	}
+	queued := func(name, namespace, pg string, timestamp time.Time) *framework.QueuedPodInfo {
+		w := st.MakePod().Name(name).Namespace(namespace).UID(name)
+		if pg != "" {
+			w = w.Label(v1alpha1.PodGroupLabel, pg)
+		}
+		return &framework.QueuedPodInfo{
+			PodInfo:                 testutil.MustNewPodInfo(t, w.Obj()),
+			Timestamp:               timestamp,
+			InitialAttemptTimestamp: &now,
+		}
+	}
+	member1, p2 := queued("pg1-1", "ns1", "pg1", now), queued("p2", "ns2", "", now)
+	if !pl.Less(member1, p2) {
+		t.Fatalf("Want the member of the PodGroup of the quota with the lowest share first")
+	}
+
+	// ns1 borrows over its min before the second member is queued.
+	quotas["ns1"].reserveResource(framework.Resource{MilliCPU: 5000})
+	member2 := queued("pg1-2", "ns1", "pg1", now.Add(time.Second))
+	if !pl.Less(member2, p2) {
+		t.Errorf("Want the second member sorted with the share of its PodGroup, not interleaved with p2")
+	}
+
+	pl.forget("pg1-1")
+	if _, ok := pl.groups["ns1/pg1"]; !ok {
+		t.Errorf("Want the key of pg1 kept while a member is queued")
+	}
+	pl.forget("pg1-2")
+	if _, ok := pl.groups["ns1/pg1"]; ok {
+		t.Errorf("Want the key of pg1 forgotten once none of its members is queued")
+	}
+
+	// Once queued again, the share of the PodGroup is recomputed.
+	if pl.Less(queued("pg1-1", "ns1", "pg1", now.Add(2*time.Second)), p2) {
+		t.Errorf("Want pg1 after p2 once its share is recomputed")
+	}
+}
+
+func TestFairShareSortSharesCapacityScheduling(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cs := clientsetfake.NewSimpleClientset()
+	fh, err := tf.NewFramework(ctx, makeRegisteredPlugin(), "default-scheduler",
+		frameworkruntime.WithClientSet(cs),
+		frameworkruntime.WithInformerFactory(informers.NewSharedInformerFactory(cs, 0)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The CapacityScheduling plugin of the profile is already created.
+	c := &CapacityScheduling{fh: fh, elasticQuotaInfos: NewElasticQuotaInfos(), refs: 1}
+	instances.Lock()
+	instances.byHandle[fh] = c
+	instances.Unlock()
+
+	pl, err := NewFairShareSort(ctx, nil, fh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := pl.(*FairShareSort).capacityScheduling; got != c {
+		t.Errorf("Want the CapacityScheduling of the profile shared, got another one")
+	}
+
+	c.Close()
+	pl.(*FairShareSort).Close()
+	instances.Lock()
+	defer instances.Unlock()
+	if _, ok := instances.byHandle[fh]; ok {
+		t.Errorf("Want the CapacityScheduling of the profile released")
+	}
+}
+
+func TestDominantShare(t *testing.T) {
+	info := newElasticQuotaInfo("ns", makeResourceList(4000, 4096), nil, makeResourceList(1000, 3072))
+	pl := &FairShareSort{capacityScheduling: &CapacityScheduling{elasticQuotaInfos: ElasticQuotaInfos{"ns": info}}}
+	if got := pl.dominantShare(st.MakePod().Namespace("ns").Obj()); got != 0.75 {
+		t.Errorf("Want the share of memory 0.75, got %v", got)
+	}
+
+	info.Used.SetScalar(ResourceGPU, 1)
+	if got := pl.dominantShare(st.MakePod().Namespace("ns").Obj()); !math.IsInf(got, 1) {
+		t.Errorf("Want the share of a resource without min to be infinite, got %v", got)
+	}
+}
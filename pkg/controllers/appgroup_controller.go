@@ -0,0 +1,378 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	agv1alpha1 "github.com/diktyo-io/appgroup-api/pkg/apis/appgroup/v1alpha1"
+	"github.com/go-logr/logr"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	networkawareutil "sigs.k8s.io/scheduler-plugins/pkg/networkaware/util"
+)
+
+const (
+	// AppGroupCycleDetected is the reason of the event reported when the dependencies of an AppGroup form a cycle.
+	AppGroupCycleDetected = "CycleDetected"
+	// AppGroupInvalidAlgorithm is the reason of the event reported when the sorting algorithm of an AppGroup is unknown.
+	AppGroupInvalidAlgorithm = "InvalidTopologySortingAlgorithm"
+
+	// AppGroupScheduledWorkloadsAnnotation holds the number of scheduled pods of an AppGroup, as its status has
+	// no field for it.
+	AppGroupScheduledWorkloadsAnnotation = "appgroup.diktyo.x-k8s.io/scheduled-workloads"
+	// AppGroupCycleAnnotation holds the cycle of dependencies last reported for an AppGroup, so that the
+	// CycleDetected event is only reported again when the cycle changes.
+	AppGroupCycleAnnotation = "appgroup.diktyo.x-k8s.io/cycle"
+)
+
+// AppGroupReconciler reconciles an AppGroup object
+type AppGroupReconciler struct {
+	log      logr.Logger
+	recorder record.EventRecorder
+
+	client.Client
+	Scheme  *runtime.Scheme
+	Workers int
+}
+
+// +kubebuilder:rbac:groups=appgroup.diktyo.x-k8s.io,resources=appgroups,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=appgroup.diktyo.x-k8s.io,resources=appgroups/status,verbs=get;update;patch
+
+// Reconcile computes the topology order of the workloads of an AppGroup from their dependencies,
+// and counts its running and scheduled pods.
+func (r *AppGroupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := log.FromContext(ctx)
+	log.Info("reconciling")
+	ag := &agv1alpha1.AppGroup{}
+	if err := r.Get(ctx, req.NamespacedName, ag); err != nil {
+		if apierrs.IsNotFound(err) {
+			log.V(5).Info("App group has been deleted")
+			return ctrl.Result{}, nil
+		}
+		log.V(3).Error(err, "Unable to retrieve app group")
+		return ctrl.Result{}, err
+	}
+
+	podList := &v1.PodList{}
+	if err := r.List(ctx, podList, client.InNamespace(ag.Namespace),
+		client.MatchingLabels{agv1alpha1.AppGroupLabel: ag.Name}); err != nil {
+		log.Error(err, "List pods for app group failed")
+		return ctrl.Result{}, err
+	}
+
+	agCopy := ag.DeepCopy()
+	agCopy.Status.RunningWorkloads = 0
+	scheduled := 0
+	for _, pod := range podList.Items {
+		if pod.Status.Phase == v1.PodRunning {
+			agCopy.Status.RunningWorkloads++
+		}
+		if pod.Spec.NodeName != "" && pod.Status.Phase != v1.PodSucceeded && pod.Status.Phase != v1.PodFailed {
+			scheduled++
+		}
+		if pod.Spec.NodeName != "" && agCopy.Status.ScheduleStartTime.IsZero() {
+			agCopy.Status.ScheduleStartTime = metav1.Now()
+		}
+	}
+	annotations := map[string]string{
+		AppGroupScheduledWorkloadsAnnotation: strconv.Itoa(scheduled),
+	}
+
+	algorithm := ag.Spec.TopologySortingAlgorithm
+	if algorithm == "" {
+		algorithm = agv1alpha1.AppGroupKahnSort
+	}
+	order, cycle, err := sortWorkloads(ag.Spec.Workloads, algorithm)
+	if err != nil {
+		r.recorder.Event(ag, v1.EventTypeWarning, AppGroupInvalidAlgorithm, err.Error())
+		return ctrl.Result{}, nil
+	}
+	if len(cycle) != 0 {
+		// Without a valid order, the pods of the app group are sorted like any other pods.
+		annotations[AppGroupCycleAnnotation] = strings.Join(cycle, " -> ")
+		if ag.Annotations[AppGroupCycleAnnotation] != annotations[AppGroupCycleAnnotation] {
+			r.recorder.Event(ag, v1.EventTypeWarning, AppGroupCycleDetected,
+				fmt.Sprintf("Dependencies of workloads form a cycle: %s", annotations[AppGroupCycleAnnotation]))
+		}
+		order = nil
+	}
+	if !equality.Semantic.DeepEqual(order, ag.Status.TopologyOrder) {
+		agCopy.Status.TopologyOrder = order
+		agCopy.Status.TopologyCalculationTime = metav1.Now()
+	}
+
+	if err := r.patchAnnotations(ctx, ag, annotations); err != nil {
+		log.Error(err, "Patch annotations of app group failed")
+		return ctrl.Result{}, err
+	}
+	if equality.Semantic.DeepEqual(ag.Status, agCopy.Status) {
+		return ctrl.Result{}, nil
+	}
+	return ctrl.Result{}, patchStatus(ctx, r.Client, agCopy, client.MergeFrom(ag))
+}
+
+// patchAnnotations sets the scheduled workloads and cycle annotations of the app group to the given ones,
+// removing the cycle annotation when there is no cycle anymore. The patch is made on a copy, so that
+// the response doesn't overwrite the status computed from the original app group.
+func (r *AppGroupReconciler) patchAnnotations(ctx context.Context, ag *agv1alpha1.AppGroup, annotations map[string]string) error {
+	agCopy := ag.DeepCopy()
+	if agCopy.Annotations == nil {
+		agCopy.Annotations = make(map[string]string)
+	}
+	for _, key := range []string{AppGroupScheduledWorkloadsAnnotation, AppGroupCycleAnnotation} {
+		if value, ok := annotations[key]; ok {
+			agCopy.Annotations[key] = value
+		} else {
+			delete(agCopy.Annotations, key)
+		}
+	}
+	if maps.Equal(ag.Annotations, agCopy.Annotations) {
+		return nil
+	}
+	return r.Patch(ctx, agCopy, client.MergeFrom(ag))
+}
+
+// patchStatus patches the status of the object through its status subresource, or through the object
+// itself if its CRD doesn't define the status subresource, like the CRDs of the diktyo APIs.
+func patchStatus(ctx context.Context, c client.Client, obj client.Object, patch client.Patch) error {
+	err := c.Status().Patch(ctx, obj, patch)
+	if apierrs.IsNotFound(err) {
+		return c.Patch(ctx, obj, patch)
+	}
+	return err
+}
+
+// sortWorkloads returns the topology order of the workloads computed by the given algorithm, sorted by
+// workload selector as expected by the network-aware plugins. A workload comes before the workloads
+// it depends on, and its index starts at 1. If the dependencies form a cycle, it returns the selectors
+// of the workloads along the cycle instead.
+func sortWorkloads(workloads agv1alpha1.AppGroupWorkloadList, algorithm string) (agv1alpha1.AppGroupTopologyList, []string, error) {
+	var sorted []int
+	switch algorithm {
+	case agv1alpha1.AppGroupKahnSort, agv1alpha1.AppGroupReverseKahn, agv1alpha1.AppGroupAlternateKahn:
+		sorted = kahnSort(workloads)
+	case agv1alpha1.AppGroupTarjanSort, agv1alpha1.AppGroupReverseTarjan, agv1alpha1.AppGroupAlternateTarjan:
+		sorted = dfsSort(workloads)
+	default:
+		return nil, nil, fmt.Errorf("unknown topology sorting algorithm %q", algorithm)
+	}
+	if len(sorted) != len(workloads) {
+		return nil, findCycle(workloads), nil
+	}
+
+	switch algorithm {
+	case agv1alpha1.AppGroupReverseKahn, agv1alpha1.AppGroupReverseTarjan:
+		slices.Reverse(sorted)
+	case agv1alpha1.AppGroupAlternateKahn, agv1alpha1.AppGroupAlternateTarjan:
+		sorted = alternate(sorted)
+	}
+
+	order := make(agv1alpha1.AppGroupTopologyList, 0, len(sorted))
+	for i, w := range sorted {
+		order = append(order, agv1alpha1.AppGroupTopologyInfo{
+			Workload: workloads[w].Workload,
+			Index:    int32(i + 1),
+		})
+	}
+	sort.Sort(networkawareutil.ByWorkloadSelector(order))
+	return order, nil, nil
+}
+
+// dependencyGraph returns the indexes of the workloads each workload depends on. Dependencies on
+// workloads that are not part of the app group are ignored.
+func dependencyGraph(workloads agv1alpha1.AppGroupWorkloadList) [][]int {
+	index := make(map[string]int, len(workloads))
+	for i, w := range workloads {
+		index[w.Workload.Selector] = i
+	}
+	graph := make([][]int, len(workloads))
+	for i, w := range workloads {
+		for _, dep := range w.Dependencies {
+			if j, ok := index[dep.Workload.Selector]; ok {
+				graph[i] = append(graph[i], j)
+			}
+		}
+	}
+	return graph
+}
+
+// kahnSort sorts the workloads with Kahn's algorithm, starting from the workloads no other workload
+// depends on. The workloads that are part of a cycle are left out.
+func kahnSort(workloads agv1alpha1.AppGroupWorkloadList) []int {
+	graph := dependencyGraph(workloads)
+	inDegree := make([]int, len(workloads))
+	for _, deps := range graph {
+		for _, j := range deps {
+			inDegree[j]++
+		}
+	}
+	var queue, sorted []int
+	for i := range workloads {
+		if inDegree[i] == 0 {
+			queue = append(queue, i)
+		}
+	}
+	for len(queue) != 0 {
+		i := queue[0]
+		queue = queue[1:]
+		sorted = append(sorted, i)
+		for _, j := range graph[i] {
+			if inDegree[j]--; inDegree[j] == 0 {
+				queue = append(queue, j)
+			}
+		}
+	}
+	return sorted
+}
+
+// dfsSort sorts the workloads in reverse post-order of a depth-first search, like Tarjan's algorithm.
+// The workloads that are part of a cycle are left out.
+func dfsSort(workloads agv1alpha1.AppGroupWorkloadList) []int {
+	graph := dependencyGraph(workloads)
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make([]int, len(workloads))
+	var postOrder []int
+	var visit func(i int) bool
+	visit = func(i int) bool {
+		switch state[i] {
+		case visiting:
+			return false
+		case visited:
+			return true
+		}
+		state[i] = visiting
+		for _, j := range graph[i] {
+			if !visit(j) {
+				return false
+			}
+		}
+		state[i] = visited
+		postOrder = append(postOrder, i)
+		return true
+	}
+	for i := range workloads {
+		if !visit(i) {
+			return nil
+		}
+	}
+	slices.Reverse(postOrder)
+	return postOrder
+}
+
+// findCycle returns the selectors of the workloads along a cycle of dependencies, starting and
+// ending with the same workload, or nil if there is no cycle.
+func findCycle(workloads agv1alpha1.AppGroupWorkloadList) []string {
+	graph := dependencyGraph(workloads)
+	state := make([]int, len(workloads))
+	var path []int
+	var visit func(i int) []string
+	visit = func(i int) []string {
+		if state[i] == 1 {
+			start := slices.Index(path, i)
+			var cycle []string
+			for _, j := range append(path[start:], i) {
+				cycle = append(cycle, workloads[j].Workload.Selector)
+			}
+			return cycle
+		}
+		if state[i] == 2 {
+			return nil
+		}
+		state[i] = 1
+		path = append(path, i)
+		for _, j := range graph[i] {
+			if cycle := visit(j); cycle != nil {
+				return cycle
+			}
+		}
+		path = path[:len(path)-1]
+		state[i] = 2
+		return nil
+	}
+	for i := range workloads {
+		if cycle := visit(i); cycle != nil {
+			return cycle
+		}
+	}
+	return nil
+}
+
+// alternate interleaves the head and the tail of the order: first, last, second, second to last, etc.
+func alternate(sorted []int) []int {
+	result := make([]int, 0, len(sorted))
+	for i, j := 0, len(sorted)-1; i <= j; i, j = i+1, j-1 {
+		result = append(result, sorted[i])
+		if i != j {
+			result = append(result, sorted[j])
+		}
+	}
+	return result
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *AppGroupReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	r.recorder = mgr.GetEventRecorderFor("AppGroupController")
+	r.log = mgr.GetLogger()
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Watches(&v1.Pod{}, handler.EnqueueRequestsFromMapFunc(r.podToAppGroup)).
+		For(&agv1alpha1.AppGroup{}).
+		WithOptions(controller.Options{MaxConcurrentReconciles: r.Workers}).
+		Complete(r)
+}
+
+func (r *AppGroupReconciler) podToAppGroup(ctx context.Context, obj client.Object) []ctrl.Request {
+	pod, ok := obj.(*v1.Pod)
+	if !ok {
+		return nil
+	}
+	agName := networkawareutil.GetPodAppGroupLabel(pod)
+	if len(agName) == 0 {
+		return nil
+	}
+
+	r.log.V(5).Info("Add app group when pod gets added", "appGroup", agName, "pod", pod.Name, "namespace", pod.Namespace)
+
+	return []ctrl.Request{{
+		NamespacedName: types.NamespacedName{
+			Namespace: pod.Namespace,
+			Name:      agName,
+		}}}
+}
@@ -0,0 +1,344 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	agv1alpha1 "github.com/diktyo-io/appgroup-api/pkg/apis/appgroup/v1alpha1"
+	"github.com/google/go-cmp/cmp"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2/klogr"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// makeWorkloads builds the workloads of an app group from the dependencies of each workload selector.
+func makeWorkloads(selectors []string, dependencies map[string][]string) agv1alpha1.AppGroupWorkloadList {
+	var workloads agv1alpha1.AppGroupWorkloadList
+	for _, selector := range selectors {
+		w := agv1alpha1.AppGroupWorkload{Workload: agv1alpha1.AppGroupWorkloadInfo{Kind: "Deployment", Name: selector, Selector: selector}}
+		for _, dep := range dependencies[selector] {
+			w.Dependencies = append(w.Dependencies, agv1alpha1.DependenciesInfo{
+				Workload: agv1alpha1.AppGroupWorkloadInfo{Kind: "Deployment", Name: dep, Selector: dep},
+			})
+		}
+		workloads = append(workloads, w)
+	}
+	return workloads
+}
+
+// orderOf returns the selectors of the topology order by index.
+func orderOf(order agv1alpha1.AppGroupTopologyList) []string {
+	selectors := make([]string, len(order))
+	for _, info := range order {
+		selectors[info.Index-1] = info.Workload.Selector
+	}
+	return selectors
+}
+
+func TestSortWorkloads(t *testing.T) {
+	// p1 -> p2 -> p4, p1 -> p3 -> p4, p5 is standalone
+	workloads := makeWorkloads([]string{"p1", "p2", "p3", "p4", "p5"}, map[string][]string{
+		"p1": {"p2", "p3"},
+		"p2": {"p4"},
+		"p3": {"p4"},
+		"p4": {"unknown"},
+	})
+	cases := []struct {
+		name      string
+		workloads agv1alpha1.AppGroupWorkloadList
+		algorithm string
+		wantOrder []string
+		wantCycle []string
+		wantErr   bool
+	}{
+		{
+			name:      "Kahn",
+			workloads: workloads,
+			algorithm: agv1alpha1.AppGroupKahnSort,
+			wantOrder: []string{"p1", "p5", "p2", "p3", "p4"},
+		},
+		{
+			name:      "depth-first search",
+			workloads: workloads,
+			algorithm: agv1alpha1.AppGroupTarjanSort,
+			wantOrder: []string{"p5", "p1", "p3", "p2", "p4"},
+		},
+		{
+			name:      "reverse Kahn",
+			workloads: workloads,
+			algorithm: agv1alpha1.AppGroupReverseKahn,
+			wantOrder: []string{"p4", "p3", "p2", "p5", "p1"},
+		},
+		{
+			name:      "alternate Kahn",
+			workloads: workloads,
+			algorithm: agv1alpha1.AppGroupAlternateKahn,
+			wantOrder: []string{"p1", "p4", "p5", "p3", "p2"},
+		},
+		{
+			name:      "alternate depth-first search",
+			workloads: workloads,
+			algorithm: agv1alpha1.AppGroupAlternateTarjan,
+			wantOrder: []string{"p5", "p4", "p1", "p2", "p3"},
+		},
+		{
+			name: "cycle",
+			workloads: makeWorkloads([]string{"p1", "p2", "p3"}, map[string][]string{
+				"p1": {"p2"},
+				"p2": {"p3"},
+				"p3": {"p2"},
+			}),
+			algorithm: agv1alpha1.AppGroupKahnSort,
+			wantCycle: []string{"p2", "p3", "p2"},
+		},
+		{
+			name:      "self dependency",
+			workloads: makeWorkloads([]string{"p1"}, map[string][]string{"p1": {"p1"}}),
+			algorithm: agv1alpha1.AppGroupTarjanSort,
+			wantCycle: []string{"p1", "p1"},
+		},
+		{
+			name:      "unknown algorithm",
+			workloads: workloads,
+			algorithm: "BubbleSort",
+			wantErr:   true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			order, cycle, err := sortWorkloads(c.workloads, c.algorithm)
+			if (err != nil) != c.wantErr {
+				t.Fatalf("want error %v, got %v", c.wantErr, err)
+			}
+			if diff := cmp.Diff(c.wantCycle, cycle); diff != "" {
+				t.Errorf("unexpected cycle (-want,+got):\n%s", diff)
+			}
+			if c.wantOrder == nil {
+				if order != nil {
+					t.Errorf("want no order, got %v", order)
+				}
+				return
+			}
+			if diff := cmp.Diff(c.wantOrder, orderOf(order)); diff != "" {
+				t.Errorf("unexpected order (-want,+got):\n%s", diff)
+			}
+			for i := 1; i < len(order); i++ {
+				if order[i-1].Workload.Selector > order[i].Workload.Selector {
+					t.Errorf("want order sorted by selector, got %v", order)
+				}
+			}
+		})
+	}
+}
+
+func TestAppGroupReconcile(t *testing.T) {
+	ctx := context.TODO()
+	cases := []struct {
+		name             string
+		workloads        agv1alpha1.AppGroupWorkloadList
+		pods             []*v1.Pod
+		wantOrder        []string
+		wantRunning      int32
+		wantScheduled    string
+		wantScheduleTime bool
+		wantEvent        string
+		// like the CRD of the diktyo API
+		noStatusSubresource bool
+	}{
+		{
+			name:      "topology order is computed",
+			workloads: makeWorkloads([]string{"p3", "p2", "p1"}, map[string][]string{"p1": {"p2"}, "p2": {"p3"}}),
+			pods: []*v1.Pod{
+				st.MakePod().Namespace("default").Name("pod1").Label(agv1alpha1.AppGroupLabel, "ag").Node("node").Phase(v1.PodRunning).Obj(),
+				st.MakePod().Namespace("default").Name("pod2").Label(agv1alpha1.AppGroupLabel, "ag").Node("node").Phase(v1.PodPending).Obj(),
+				st.MakePod().Namespace("default").Name("pod3").Label(agv1alpha1.AppGroupLabel, "ag").Obj(),
+				st.MakePod().Namespace("default").Name("other").Label(agv1alpha1.AppGroupLabel, "other").Phase(v1.PodRunning).Obj(),
+			},
+			wantOrder:        []string{"p1", "p2", "p3"},
+			wantRunning:      1,
+			wantScheduled:    "2",
+			wantScheduleTime: true,
+		},
+		{
+			name:      "status is patched without status subresource",
+			workloads: makeWorkloads([]string{"p1", "p2"}, map[string][]string{"p2": {"p1"}}),
+			pods: []*v1.Pod{
+				st.MakePod().Namespace("default").Name("pod1").Label(agv1alpha1.AppGroupLabel, "ag").Phase(v1.PodRunning).Obj(),
+			},
+			wantOrder:           []string{"p2", "p1"},
+			wantRunning:         1,
+			wantScheduled:       "0",
+			noStatusSubresource: true,
+		},
+		{
+			name:      "cycle is reported",
+			workloads: makeWorkloads([]string{"p1", "p2"}, map[string][]string{"p1": {"p2"}, "p2": {"p1"}}),
+			pods: []*v1.Pod{
+				st.MakePod().Namespace("default").Name("pod1").Label(agv1alpha1.AppGroupLabel, "ag").Obj(),
+			},
+			wantScheduled: "0",
+			wantEvent:     "Warning CycleDetected Dependencies of workloads form a cycle: p1 -> p2 -> p1",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ag := &agv1alpha1.AppGroup{
+				ObjectMeta: metav1.ObjectMeta{Name: "ag", Namespace: "default"},
+				Spec:       agv1alpha1.AppGroupSpec{NumMembers: int32(len(c.workloads)), Workloads: c.workloads},
+			}
+			s := scheme.Scheme
+			s.AddKnownTypes(agv1alpha1.SchemeGroupVersion, ag)
+			objs := []runtime.Object{ag}
+			for _, p := range c.pods {
+				objs = append(objs, p)
+			}
+			builder := fake.NewClientBuilder().WithScheme(s).WithRuntimeObjects(objs...)
+			if !c.noStatusSubresource {
+				builder = builder.WithStatusSubresource(&agv1alpha1.AppGroup{})
+			}
+			kClient := builder.Build()
+			recorder := record.NewFakeRecorder(3)
+			controller := &AppGroupReconciler{
+				Client:   kClient,
+				Scheme:   s,
+				recorder: recorder,
+				log:      klogr.New().WithName("appGroupTest"),
+			}
+
+			if _, err := controller.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(ag)}); err != nil {
+				t.Fatalf("reconcile: (%v)", err)
+			}
+			if err := kClient.Get(ctx, client.ObjectKeyFromObject(ag), ag); err != nil {
+				t.Fatal(err)
+			}
+			var order []string
+			if len(ag.Status.TopologyOrder) != 0 {
+				order = orderOf(ag.Status.TopologyOrder)
+			}
+			if diff := cmp.Diff(c.wantOrder, order); diff != "" {
+				t.Errorf("unexpected order (-want,+got):\n%s", diff)
+			}
+			if ag.Status.RunningWorkloads != c.wantRunning {
+				t.Errorf("want %d running workloads, got %d", c.wantRunning, ag.Status.RunningWorkloads)
+			}
+			if got := ag.Annotations[AppGroupScheduledWorkloadsAnnotation]; got != c.wantScheduled {
+				t.Errorf("want %s scheduled workloads, got %s", c.wantScheduled, got)
+			}
+			if ag.Status.ScheduleStartTime.IsZero() == c.wantScheduleTime {
+				t.Errorf("want schedule start time set %v, got %v", c.wantScheduleTime, ag.Status.ScheduleStartTime)
+			}
+			var events []string
+			for len(recorder.Events) > 0 {
+				events = append(events, <-recorder.Events)
+			}
+			if got := strings.Join(events, "\n"); got != c.wantEvent {
+				t.Errorf("want event %q, got %q", c.wantEvent, got)
+			}
+		})
+	}
+}
+
+func TestAppGroupReconcileCycleEvents(t *testing.T) {
+	ctx := context.TODO()
+	ag := &agv1alpha1.AppGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: "ag", Namespace: "default"},
+		Spec: agv1alpha1.AppGroupSpec{
+			NumMembers: 3,
+			Workloads:  makeWorkloads([]string{"p1", "p2", "p3"}, map[string][]string{"p1": {"p2"}, "p2": {"p1"}}),
+		},
+	}
+	s := scheme.Scheme
+	s.AddKnownTypes(agv1alpha1.SchemeGroupVersion, ag)
+	kClient := fake.NewClientBuilder().WithScheme(s).WithRuntimeObjects(ag).
+		WithStatusSubresource(&agv1alpha1.AppGroup{}).Build()
+	recorder := record.NewFakeRecorder(3)
+	controller := &AppGroupReconciler{
+		Client:   kClient,
+		Scheme:   s,
+		recorder: recorder,
+		log:      klogr.New().WithName("appGroupTest"),
+	}
+	reconcile := func(update func(ag *agv1alpha1.AppGroup)) *agv1alpha1.AppGroup {
+		t.Helper()
+		if update != nil {
+			if err := kClient.Get(ctx, client.ObjectKeyFromObject(ag), ag); err != nil {
+				t.Fatal(err)
+			}
+			update(ag)
+			if err := kClient.Update(ctx, ag); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if _, err := controller.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(ag)}); err != nil {
+			t.Fatalf("reconcile: (%v)", err)
+		}
+		got := &agv1alpha1.AppGroup{}
+		if err := kClient.Get(ctx, client.ObjectKeyFromObject(ag), got); err != nil {
+			t.Fatal(err)
+		}
+		return got
+	}
+	events := func() []string {
+		var events []string
+		for len(recorder.Events) > 0 {
+			events = append(events, <-recorder.Events)
+		}
+		return events
+	}
+
+	reconcile(nil)
+	got := reconcile(nil)
+	want := []string{"Warning CycleDetected Dependencies of workloads form a cycle: p1 -> p2 -> p1"}
+	if diff := cmp.Diff(want, events()); diff != "" {
+		t.Errorf("unexpected events for an unchanged cycle (-want,+got):\n%s", diff)
+	}
+	if got.Annotations[AppGroupCycleAnnotation] != "p1 -> p2 -> p1" {
+		t.Errorf("unexpected cycle annotation %q", got.Annotations[AppGroupCycleAnnotation])
+	}
+
+	reconcile(func(ag *agv1alpha1.AppGroup) {
+		ag.Spec.Workloads = makeWorkloads([]string{"p1", "p2", "p3"}, map[string][]string{"p1": {"p3"}, "p3": {"p1"}})
+	})
+	want = []string{"Warning CycleDetected Dependencies of workloads form a cycle: p1 -> p3 -> p1"}
+	if diff := cmp.Diff(want, events()); diff != "" {
+		t.Errorf("unexpected events for a changed cycle (-want,+got):\n%s", diff)
+	}
+
+	got = reconcile(func(ag *agv1alpha1.AppGroup) {
+		ag.Spec.Workloads = makeWorkloads([]string{"p1", "p2", "p3"}, map[string][]string{"p1": {"p3"}})
+	})
+	if diff := cmp.Diff([]string(nil), events()); diff != "" {
+		t.Errorf("unexpected events without cycle (-want,+got):\n%s", diff)
+	}
+	if _, ok := got.Annotations[AppGroupCycleAnnotation]; ok {
+		t.Errorf("unexpected cycle annotation %q without cycle", got.Annotations[AppGroupCycleAnnotation])
+	}
+	if len(got.Status.TopologyOrder) == 0 {
+		t.Errorf("expected a topology order without cycle")
+	}
+}
@@ -24,7 +24,9 @@ import (
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	quota "k8s.io/apiserver/pkg/quota/v1"
 	"k8s.io/client-go/tools/record"
 
@@ -34,6 +36,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	schedv1alpha1 "sigs.k8s.io/scheduler-plugins/apis/scheduling/v1alpha1"
+	"sigs.k8s.io/scheduler-plugins/pkg/util"
 )
 
 type ElasticQuotaReconciler struct {
@@ -47,11 +50,12 @@ type ElasticQuotaReconciler struct {
 // +kubebuilder:rbac:groups=scheduling.x-k8s.io,resources=elasticquota,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=scheduling.x-k8s.io,resources=elasticquota/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=scheduling.x-k8s.io,resources=elasticquota/finalizers,verbs=update
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
 func (r *ElasticQuotaReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	log := log.FromContext(ctx)
 	log.Info("reconciling")
 	eqList := &schedv1alpha1.ElasticQuotaList{}
-	if err := r.List(ctx, eqList, client.InNamespace(req.Namespace)); err != nil {
+	if err := r.List(ctx, eqList); err != nil {
 		if apierrs.IsNotFound(err) {
 			log.V(5).Info("no elasticquota found")
 			return ctrl.Result{}, nil
@@ -60,14 +64,22 @@ func (r *ElasticQuotaReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		return ctrl.Result{}, err
 	}
 
+	nsLabels, err := r.namespaceLabels(ctx, req.Namespace)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
 	// TODO: When elastic quota supports multiple instances in a namespace, modify this
-	if len(eqList.Items) == 0 {
+	eq := util.GetElasticQuotaForNamespace(req.Namespace, nsLabels, eqList.Items)
+	if eq == nil {
 		log.V(5).Info("no elasticquota found")
 		return ctrl.Result{}, nil
 	}
 
-	eq := &eqList.Items[0]
-	used, err := r.computeElasticQuotaUsed(ctx, req.Namespace, eq)
+	namespaces, err := r.elasticQuotaNamespaces(ctx, eq, eqList.Items)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	used, err := r.computeElasticQuotaUsed(ctx, namespaces, eq)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
@@ -84,7 +96,7 @@ func (r *ElasticQuotaReconciler) Reconcile(ctx context.Context, req ctrl.Request
 	if err = r.patchElasticQuota(ctx, eq, newEQ); err != nil {
 		return ctrl.Result{}, err
 	}
-	r.recorder.Event(eq, v1.EventTypeNormal, "Synced", fmt.Sprintf("Elastic Quota %s synced successfully", req.NamespacedName))
+	r.recorder.Event(eq, v1.EventTypeNormal, "Synced", fmt.Sprintf("Elastic Quota %s synced successfully", client.ObjectKeyFromObject(eq)))
 	return ctrl.Result{}, nil
 }
 
@@ -93,16 +105,58 @@ func (r *ElasticQuotaReconciler) patchElasticQuota(ctx context.Context, old, new
 	return r.Status().Patch(ctx, new, patch)
 }
 
-func (r *ElasticQuotaReconciler) computeElasticQuotaUsed(ctx context.Context, namespace string, eq *schedv1alpha1.ElasticQuota) (v1.ResourceList, error) {
-	used := newZeroUsed(eq)
-	podList := &v1.PodList{}
-	if err := r.List(ctx, podList, client.InNamespace(namespace)); err != nil {
+// namespaceLabels returns the labels of the given namespace, or nil if the namespace doesn't exist.
+func (r *ElasticQuotaReconciler) namespaceLabels(ctx context.Context, namespace string) (map[string]string, error) {
+	ns := &v1.Namespace{}
+	if err := r.Get(ctx, types.NamespacedName{Name: namespace}, ns); err != nil {
+		if apierrs.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return ns.Labels, nil
+}
+
+// elasticQuotaNamespaces returns the namespaces whose pods are subject to the given ElasticQuota,
+// i.e. its own namespace and the namespaces selected by its NamespaceSelector.
+func (r *ElasticQuotaReconciler) elasticQuotaNamespaces(ctx context.Context, eq *schedv1alpha1.ElasticQuota, eqs []schedv1alpha1.ElasticQuota) ([]string, error) {
+	namespaces := []string{eq.Namespace}
+	if eq.Spec.NamespaceSelector == nil {
+		return namespaces, nil
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(eq.Spec.NamespaceSelector)
+	if err != nil {
 		return nil, err
 	}
+	nsList := &v1.NamespaceList{}
+	if err := r.List(ctx, nsList, client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return nil, err
+	}
+	for _, ns := range nsList.Items {
+		if ns.Name == eq.Namespace {
+			continue
+		}
+		if governing := util.GetElasticQuotaForNamespace(ns.Name, ns.Labels, eqs); governing != nil &&
+			governing.Namespace == eq.Namespace && governing.Name == eq.Name {
+			namespaces = append(namespaces, ns.Name)
+		}
+	}
+	return namespaces, nil
+}
+
+func (r *ElasticQuotaReconciler) computeElasticQuotaUsed(ctx context.Context, namespaces []string, eq *schedv1alpha1.ElasticQuota) (v1.ResourceList, error) {
+	used := newZeroUsed(eq)
+	for _, namespace := range namespaces {
+		podList := &v1.PodList{}
+		if err := r.List(ctx, podList, client.InNamespace(namespace)); err != nil {
+			return nil, err
+		}
 
-	for _, p := range podList.Items {
-		if p.Status.Phase == v1.PodRunning {
-			used = quota.Add(used, computePodResourceRequest(&p))
+		for _, p := range podList.Items {
+			if p.Status.Phase == v1.PodRunning {
+				used = quota.Add(used, computePodResourceRequest(&p))
+			}
 		}
 	}
 	return used, nil
@@ -172,7 +226,26 @@ func (r *ElasticQuotaReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	r.recorder = mgr.GetEventRecorderFor("ElasticQuotaController")
 	return ctrl.NewControllerManagedBy(mgr).
 		Watches(&v1.Pod{}, &handler.EnqueueRequestForObject{}).
+		Watches(&v1.Namespace{}, handler.EnqueueRequestsFromMapFunc(r.namespaceToElasticQuotas)).
 		For(&schedv1alpha1.ElasticQuota{}).
 		WithOptions(controller.Options{MaxConcurrentReconciles: r.Workers}).
 		Complete(r)
 }
+
+// namespaceToElasticQuotas enqueues every ElasticQuota with a NamespaceSelector when a namespace
+// changes, since the namespace may have joined or left any of them.
+func (r *ElasticQuotaReconciler) namespaceToElasticQuotas(ctx context.Context, obj client.Object) []ctrl.Request {
+	eqList := &schedv1alpha1.ElasticQuotaList{}
+	if err := r.List(ctx, eqList); err != nil {
+		return nil
+	}
+
+	var requests []ctrl.Request
+	for _, eq := range eqList.Items {
+		if eq.Spec.NamespaceSelector == nil {
+			continue
+		}
+		requests = append(requests, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(&eq)})
+	}
+	return requests
+}
@@ -229,6 +229,65 @@ func TestElasticQuotaController_Run(t *testing.T) {
 	}
 }
 
+func TestElasticQuotaController_NamespaceSelector(t *testing.T) {
+	ctx := context.TODO()
+	tenant := map[string]string{"tenant": "a"}
+	eqs := []*v1alpha1.ElasticQuota{
+		testutil.MakeEQ("tenant-a", "eq").NamespaceSelector(tenant).
+			Min(testutil.MakeResourceList().CPU(3).Mem(5).Obj()).
+			Max(testutil.MakeResourceList().CPU(5).Mem(15).Obj()).Obj(),
+		testutil.MakeEQ("tenant-a-own", "eq").
+			Max(testutil.MakeResourceList().CPU(5).Mem(15).Obj()).Obj(),
+	}
+	pods := []*v1.Pod{
+		testutil.MakePod("tenant-a", "pod1").Phase(v1.PodRunning).
+			Container(testutil.MakeResourceList().CPU(1).Mem(2).Obj()).Obj(),
+		testutil.MakePod("tenant-a-dev", "pod1").Phase(v1.PodRunning).
+			Container(testutil.MakeResourceList().CPU(1).Mem(2).Obj()).Obj(),
+		testutil.MakePod("tenant-a-prod", "pod1").Phase(v1.PodRunning).
+			Container(testutil.MakeResourceList().CPU(2).Mem(1).Obj()).Obj(),
+		// tenant-a-own has its own ElasticQuota, so its pods don't count against tenant-a/eq.
+		testutil.MakePod("tenant-a-own", "pod1").Phase(v1.PodRunning).
+			Container(testutil.MakeResourceList().CPU(1).Mem(1).Obj()).Obj(),
+		testutil.MakePod("other", "pod1").Phase(v1.PodRunning).
+			Container(testutil.MakeResourceList().CPU(1).Mem(1).Obj()).Obj(),
+	}
+	controller, kClient := setUpEQ(ctx, t, eqs, pods)
+	for name, labels := range map[string]map[string]string{
+		"tenant-a-dev":  tenant,
+		"tenant-a-prod": tenant,
+		"tenant-a-own":  tenant,
+		"other":         {"tenant": "b"},
+	} {
+		ns := &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+		if err := kClient.Create(ctx, ns); err != nil {
+			t.Fatal("setup namespaces", err)
+		}
+	}
+
+	// A pod in a selected namespace triggers the reconciliation of the ElasticQuota selecting it.
+	if _, err := controller.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{
+		Namespace: "tenant-a-prod",
+		Name:      "pod1",
+	}}); err != nil {
+		t.Fatalf("reconcile: (%v)", err)
+	}
+
+	eq := &v1alpha1.ElasticQuota{}
+	if err := kClient.Get(ctx, types.NamespacedName{Namespace: "tenant-a", Name: "eq"}, eq); err != nil {
+		t.Fatal(err)
+	}
+	want := testutil.MakeResourceList().CPU(4).Mem(5).Obj()
+	if !quota.Equals(eq.Status.Used, want) {
+		t.Errorf("want %v, got %v", want, eq.Status.Used)
+	}
+
+	requests := controller.namespaceToElasticQuotas(ctx, &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "tenant-a-dev"}})
+	if len(requests) != 1 || requests[0].NamespacedName != (types.NamespacedName{Namespace: "tenant-a", Name: "eq"}) {
+		t.Errorf("unexpected requests for namespace change: %v", requests)
+	}
+}
+
 func setUpEQ(ctx context.Context,
 	t *testing.T,
 	eqs []*v1alpha1.ElasticQuota,
@@ -0,0 +1,335 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	ntv1alpha1 "github.com/diktyo-io/networktopology-api/pkg/apis/networktopology/v1alpha1"
+	"github.com/go-logr/logr"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	networkawareutil "sigs.k8s.io/scheduler-plugins/pkg/networkaware/util"
+)
+
+const (
+	// NetworkTopologyMeasurementFailed is the reason of the event reported when the network could not be measured.
+	NetworkTopologyMeasurementFailed = "MeasurementFailed"
+
+	// DefaultNetworkTopologyPeriod is the default period at which the weights of a NetworkTopology are recomputed.
+	DefaultNetworkTopologyPeriod = 5 * time.Minute
+
+	// maxNetworkCost is the highest cost between an origin and a destination, as expected by the NetworkOverhead plugin.
+	maxNetworkCost = 100
+)
+
+// NetworkTopologyReconciler reconciles a NetworkTopology object
+type NetworkTopologyReconciler struct {
+	log      logr.Logger
+	recorder record.EventRecorder
+
+	client.Client
+	Scheme  *runtime.Scheme
+	Workers int
+	// Source measures the network between the nodes. The ConfigMap of the NetworkTopology is read if not set.
+	Source NetworkCostSource
+	// Period at which the weights are recomputed, DefaultNetworkTopologyPeriod if not set.
+	Period time.Duration
+}
+
+// +kubebuilder:rbac:groups=networktopology.diktyo.x-k8s.io,resources=networktopologies,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=networktopology.diktyo.x-k8s.io,resources=networktopologies/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get
+
+// Reconcile maintains the NetperfCosts weights of a NetworkTopology from the regions and zones of the nodes,
+// and the costs measured between them.
+func (r *NetworkTopologyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := log.FromContext(ctx)
+	log.Info("reconciling")
+	nt := &ntv1alpha1.NetworkTopology{}
+	if err := r.Get(ctx, req.NamespacedName, nt); err != nil {
+		if apierrs.IsNotFound(err) {
+			log.V(5).Info("Network topology has been deleted")
+			return ctrl.Result{}, nil
+		}
+		log.V(3).Error(err, "Unable to retrieve network topology")
+		return ctrl.Result{}, err
+	}
+
+	nodeList := &v1.NodeList{}
+	if err := r.List(ctx, nodeList); err != nil {
+		log.Error(err, "List nodes for network topology failed")
+		return ctrl.Result{}, err
+	}
+
+	period := r.Period
+	if period <= 0 {
+		period = DefaultNetworkTopologyPeriod
+	}
+	source := r.Source
+	if source == nil {
+		source = &ConfigMapCostSource{Reader: r.Client}
+	}
+	measurements, err := source.Measure(ctx, nt)
+	if err != nil {
+		// The previous costs are kept until the next measurement.
+		log.Error(err, "Measure network for network topology failed")
+		r.recorder.Event(nt, v1.EventTypeWarning, NetworkTopologyMeasurementFailed, err.Error())
+		measurements = nil
+	}
+
+	ntCopy := nt.DeepCopy()
+	var previous *ntv1alpha1.WeightInfo
+	index := -1
+	for i := range nt.Spec.Weights {
+		if nt.Spec.Weights[i].Name == ntv1alpha1.NetworkTopologyNetperfCosts {
+			previous, index = &nt.Spec.Weights[i], i
+			break
+		}
+	}
+	weight := computeNetperfWeight(nodeList.Items, measurements, previous)
+	if index < 0 {
+		ntCopy.Spec.Weights = append(ntCopy.Spec.Weights, weight)
+	} else {
+		ntCopy.Spec.Weights[index] = weight
+	}
+
+	weightsChanged := !equality.Semantic.DeepEqual(nt.Spec, ntCopy.Spec)
+	if weightsChanged {
+		if err := r.Patch(ctx, ntCopy, client.MergeFrom(nt)); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	status := ntCopy.Status
+	status.NodeCount = int64(len(nodeList.Items))
+	if weightsChanged || status.WeightCalculationTime.IsZero() {
+		status.WeightCalculationTime = metav1.Now()
+	}
+	if equality.Semantic.DeepEqual(ntCopy.Status, status) {
+		return ctrl.Result{RequeueAfter: period}, nil
+	}
+	base := ntCopy.DeepCopy()
+	ntCopy.Status = status
+	return ctrl.Result{RequeueAfter: period}, patchStatus(ctx, r.Client, ntCopy, client.MergeFrom(base))
+}
+
+// computeNetperfWeight computes the NetperfCosts weights between the regions of the nodes, and between the zones
+// of the nodes in the same region. The cost between two domains is the average latency measured between their
+// nodes, in milliseconds, and their bandwidth capacity is the lowest bandwidth measured between their nodes.
+// The previous costs are kept when no measurement is available, as long as both domains still exist.
+// The lists are sorted as expected by the NetworkOverhead plugin.
+func computeNetperfWeight(nodes []v1.Node, measurements map[networkawareutil.CostKey]NetworkMeasurement, previous *ntv1alpha1.WeightInfo) ntv1alpha1.WeightInfo {
+	type domains struct {
+		region, zone string
+	}
+	nodeDomains := make(map[string]domains, len(nodes))
+	regions := make(map[string]bool)
+	zoneRegions := make(map[string]string)
+	for i := range nodes {
+		d := domains{region: networkawareutil.GetNodeRegion(&nodes[i]), zone: networkawareutil.GetNodeZone(&nodes[i])}
+		nodeDomains[nodes[i].Name] = d
+		if d.region != "" {
+			regions[d.region] = true
+		}
+		if d.zone != "" {
+			zoneRegions[d.zone] = d.region
+		}
+	}
+
+	type aggregate struct {
+		latency   float64
+		count     int
+		bandwidth float64
+	}
+	measured := map[ntv1alpha1.TopologyKey]map[networkawareutil.CostKey]*aggregate{
+		ntv1alpha1.NetworkTopologyRegion: {},
+		ntv1alpha1.NetworkTopologyZone:   {},
+	}
+	for key, m := range measurements {
+		origin, ok := nodeDomains[key.Origin]
+		if !ok {
+			continue
+		}
+		destination, ok := nodeDomains[key.Destination]
+		if !ok {
+			continue
+		}
+		var topologyKey ntv1alpha1.TopologyKey
+		var costKey networkawareutil.CostKey
+		switch {
+		case origin.region != "" && destination.region != "" && origin.region != destination.region:
+			topologyKey = ntv1alpha1.NetworkTopologyRegion
+			costKey = networkawareutil.CostKey{Origin: origin.region, Destination: destination.region}
+		case origin.region == destination.region && origin.zone != "" && destination.zone != "" && origin.zone != destination.zone:
+			topologyKey = ntv1alpha1.NetworkTopologyZone
+			costKey = networkawareutil.CostKey{Origin: origin.zone, Destination: destination.zone}
+		default:
+			// The nodes are in the same domain, or their domains are unknown.
+			continue
+		}
+		a, ok := measured[topologyKey][costKey]
+		if !ok {
+			a = &aggregate{}
+			measured[topologyKey][costKey] = a
+		}
+		if m.Latency > 0 {
+			a.latency += m.Latency
+			a.count++
+		}
+		if m.Bandwidth > 0 && (a.bandwidth == 0 || m.Bandwidth < a.bandwidth) {
+			a.bandwidth = m.Bandwidth
+		}
+	}
+
+	previousCosts := make(map[ntv1alpha1.TopologyKey]map[networkawareutil.CostKey]ntv1alpha1.CostInfo)
+	if previous != nil {
+		for _, t := range previous.TopologyList {
+			costs := make(map[networkawareutil.CostKey]ntv1alpha1.CostInfo)
+			for _, o := range t.OriginList {
+				for _, c := range o.CostList {
+					costs[networkawareutil.CostKey{Origin: o.Origin, Destination: c.Destination}] = c
+				}
+			}
+			previousCosts[t.TopologyKey] = costs
+		}
+	}
+
+	// destinations returns the domains an origin has costs to.
+	destinations := map[ntv1alpha1.TopologyKey]func(origin string) []string{
+		ntv1alpha1.NetworkTopologyRegion: func(origin string) []string {
+			var result []string
+			for region := range regions {
+				if region != origin {
+					result = append(result, region)
+				}
+			}
+			return result
+		},
+		ntv1alpha1.NetworkTopologyZone: func(origin string) []string {
+			var result []string
+			for zone, region := range zoneRegions {
+				if zone != origin && region == zoneRegions[origin] {
+					result = append(result, zone)
+				}
+			}
+			return result
+		},
+	}
+	origins := map[ntv1alpha1.TopologyKey][]string{}
+	for region := range regions {
+		origins[ntv1alpha1.NetworkTopologyRegion] = append(origins[ntv1alpha1.NetworkTopologyRegion], region)
+	}
+	for zone := range zoneRegions {
+		origins[ntv1alpha1.NetworkTopologyZone] = append(origins[ntv1alpha1.NetworkTopologyZone], zone)
+	}
+
+	weight := ntv1alpha1.WeightInfo{Name: ntv1alpha1.NetworkTopologyNetperfCosts, TopologyList: ntv1alpha1.TopologyList{}}
+	for _, topologyKey := range []ntv1alpha1.TopologyKey{ntv1alpha1.NetworkTopologyRegion, ntv1alpha1.NetworkTopologyZone} {
+		if len(origins[topologyKey]) == 0 {
+			continue
+		}
+		var originList ntv1alpha1.OriginList
+		for _, origin := range origins[topologyKey] {
+			var costList ntv1alpha1.CostList
+			for _, destination := range destinations[topologyKey](origin) {
+				costKey := networkawareutil.CostKey{Origin: origin, Destination: destination}
+				cost, known := previousCosts[topologyKey][costKey]
+				cost.Destination = destination
+				if a, ok := measured[topologyKey][costKey]; ok {
+					if a.count > 0 {
+						cost.NetworkCost = latencyToNetworkCost(a.latency / float64(a.count))
+						known = true
+					}
+					if a.bandwidth > 0 {
+						cost.BandwidthCapacity = *resource.NewQuantity(int64(a.bandwidth), resource.BinarySI)
+						known = true
+					}
+				}
+				if known {
+					costList = append(costList, cost)
+				}
+			}
+			sort.Sort(networkawareutil.ByDestination(costList))
+			originList = append(originList, ntv1alpha1.OriginInfo{Origin: origin, CostList: costList})
+		}
+		sort.Sort(networkawareutil.ByOrigin(originList))
+		weight.TopologyList = append(weight.TopologyList, ntv1alpha1.TopologyInfo{TopologyKey: topologyKey, OriginList: originList})
+	}
+	sort.Sort(networkawareutil.ByTopologyKey(weight.TopologyList))
+	return weight
+}
+
+// latencyToNetworkCost converts a latency in microseconds into a network cost in milliseconds, between 1 and maxNetworkCost.
+func latencyToNetworkCost(latency float64) int64 {
+	cost := int64(math.Ceil(latency / 1000))
+	if cost < 1 {
+		return 1
+	}
+	if cost > maxNetworkCost {
+		return maxNetworkCost
+	}
+	return cost
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *NetworkTopologyReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	r.recorder = mgr.GetEventRecorderFor("NetworkTopologyController")
+	r.log = mgr.GetLogger()
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Watches(&v1.Node{}, handler.EnqueueRequestsFromMapFunc(r.nodeToNetworkTopologies),
+			builder.WithPredicates(predicate.LabelChangedPredicate{})).
+		For(&ntv1alpha1.NetworkTopology{}).
+		WithOptions(controller.Options{MaxConcurrentReconciles: r.Workers}).
+		Complete(r)
+}
+
+// nodeToNetworkTopologies enqueues every NetworkTopology when a node is added, removed or relabeled,
+// since its region or zone may have changed.
+func (r *NetworkTopologyReconciler) nodeToNetworkTopologies(ctx context.Context, obj client.Object) []ctrl.Request {
+	ntList := &ntv1alpha1.NetworkTopologyList{}
+	if err := r.List(ctx, ntList); err != nil {
+		return nil
+	}
+
+	r.log.V(5).Info("Add network topologies when node gets updated", "node", obj.GetName())
+
+	var requests []ctrl.Request
+	for _, nt := range ntList.Items {
+		requests = append(requests, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(&nt)})
+	}
+	return requests
+}
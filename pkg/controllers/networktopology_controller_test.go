@@ -0,0 +1,392 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	ntv1alpha1 "github.com/diktyo-io/networktopology-api/pkg/apis/networktopology/v1alpha1"
+	"github.com/google/go-cmp/cmp"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2/klogr"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	networkawareutil "sigs.k8s.io/scheduler-plugins/pkg/networkaware/util"
+)
+
+var quantityComparer = cmp.Comparer(func(a, b resource.Quantity) bool { return a.Cmp(b) == 0 })
+
+// makeNode builds a node in the given region and zone, if not empty.
+func makeNode(name, region, zone string) *v1.Node {
+	node := st.MakeNode().Name(name)
+	if region != "" {
+		node = node.Label(v1.LabelTopologyRegion, region)
+	}
+	if zone != "" {
+		node = node.Label(v1.LabelTopologyZone, zone)
+	}
+	return node.Obj()
+}
+
+// fakeCostSource returns fixed measurements.
+type fakeCostSource struct {
+	measurements map[networkawareutil.CostKey]NetworkMeasurement
+	err          error
+}
+
+func (s *fakeCostSource) Measure(context.Context, *ntv1alpha1.NetworkTopology) (map[networkawareutil.CostKey]NetworkMeasurement, error) {
+	return s.measurements, s.err
+}
+
+func TestComputeNetperfWeight(t *testing.T) {
+	nodes := []v1.Node{
+		*makeNode("n1", "r1", "z1"),
+		*makeNode("n2", "r1", "z2"),
+		*makeNode("n3", "r2", "z3"),
+		*makeNode("n4", "", ""),
+	}
+	cases := []struct {
+		name         string
+		measurements map[networkawareutil.CostKey]NetworkMeasurement
+		previous     *ntv1alpha1.WeightInfo
+		want         ntv1alpha1.WeightInfo
+	}{
+		{
+			name: "costs are aggregated by region and zone",
+			measurements: map[networkawareutil.CostKey]NetworkMeasurement{
+				{Origin: "n1", Destination: "n2"}:      {Latency: 1500, Bandwidth: 1e9},
+				{Origin: "n2", Destination: "n1"}:      {Latency: 2500},
+				{Origin: "n1", Destination: "n3"}:      {Latency: 30000, Bandwidth: 2e9},
+				{Origin: "n2", Destination: "n3"}:      {Latency: 50000, Bandwidth: 1e9},
+				{Origin: "n1", Destination: "n4"}:      {Latency: 100},
+				{Origin: "n1", Destination: "unknown"}: {Latency: 100},
+				{Origin: "n3", Destination: "n1"}:      {Latency: 500000},
+			},
+			previous: &ntv1alpha1.WeightInfo{
+				Name: ntv1alpha1.NetworkTopologyNetperfCosts,
+				TopologyList: ntv1alpha1.TopologyList{{
+					TopologyKey: ntv1alpha1.NetworkTopologyRegion,
+					OriginList: ntv1alpha1.OriginList{
+						{Origin: "r1", CostList: ntv1alpha1.CostList{
+							{Destination: "r2", NetworkCost: 7, BandwidthAllocated: resource.MustParse("1Gi")},
+							{Destination: "r3", NetworkCost: 9},
+						}},
+					},
+				}},
+			},
+			want: ntv1alpha1.WeightInfo{
+				Name: ntv1alpha1.NetworkTopologyNetperfCosts,
+				TopologyList: ntv1alpha1.TopologyList{
+					{
+						TopologyKey: ntv1alpha1.NetworkTopologyRegion,
+						OriginList: ntv1alpha1.OriginList{
+							{Origin: "r1", CostList: ntv1alpha1.CostList{
+								{Destination: "r2", NetworkCost: 40, BandwidthCapacity: resource.MustParse("1e9"), BandwidthAllocated: resource.MustParse("1Gi")},
+							}},
+							{Origin: "r2", CostList: ntv1alpha1.CostList{
+								{Destination: "r1", NetworkCost: maxNetworkCost},
+							}},
+						},
+					},
+					{
+						TopologyKey: ntv1alpha1.NetworkTopologyZone,
+						OriginList: ntv1alpha1.OriginList{
+							{Origin: "z1", CostList: ntv1alpha1.CostList{
+								{Destination: "z2", NetworkCost: 2, BandwidthCapacity: resource.MustParse("1e9")},
+							}},
+							{Origin: "z2", CostList: ntv1alpha1.CostList{
+								{Destination: "z1", NetworkCost: 3},
+							}},
+							{Origin: "z3"},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "previous costs are kept without measurements",
+			previous: &ntv1alpha1.WeightInfo{
+				Name: ntv1alpha1.NetworkTopologyNetperfCosts,
+				TopologyList: ntv1alpha1.TopologyList{{
+					TopologyKey: ntv1alpha1.NetworkTopologyZone,
+					OriginList: ntv1alpha1.OriginList{
+						{Origin: "z2", CostList: ntv1alpha1.CostList{{Destination: "z1", NetworkCost: 5}}},
+						{Origin: "z3", CostList: ntv1alpha1.CostList{{Destination: "z1", NetworkCost: 5}}},
+					},
+				}},
+			},
+			want: ntv1alpha1.WeightInfo{
+				Name: ntv1alpha1.NetworkTopologyNetperfCosts,
+				TopologyList: ntv1alpha1.TopologyList{
+					{
+						TopologyKey: ntv1alpha1.NetworkTopologyRegion,
+						OriginList:  ntv1alpha1.OriginList{{Origin: "r1"}, {Origin: "r2"}},
+					},
+					{
+						TopologyKey: ntv1alpha1.NetworkTopologyZone,
+						OriginList: ntv1alpha1.OriginList{
+							{Origin: "z1"},
+							{Origin: "z2", CostList: ntv1alpha1.CostList{{Destination: "z1", NetworkCost: 5}}},
+							{Origin: "z3"},
+						},
+					},
+				},
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := computeNetperfWeight(nodes, c.measurements, c.previous)
+			if diff := cmp.Diff(c.want, got, quantityComparer); diff != "" {
+				t.Errorf("unexpected weight (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestConfigMapCostSource(t *testing.T) {
+	nt := &ntv1alpha1.NetworkTopology{
+		ObjectMeta: metav1.ObjectMeta{Name: "nt", Namespace: "default"},
+		Spec:       ntv1alpha1.NetworkTopologySpec{ConfigmapName: "netperf"},
+	}
+	cases := []struct {
+		name    string
+		data    map[string]string
+		want    map[networkawareutil.CostKey]NetworkMeasurement
+		wantErr bool
+	}{
+		{
+			name: "measurements are parsed",
+			data: map[string]string{
+				"netperf_p90_latency_microseconds.origin.n1.destination.n2":   "250",
+				"netperf_bandwidth_bytes_per_second.origin.n1.destination.n2": " 1e9\n",
+				"netperf_p90_latency_microseconds.origin.n2.destination.n1":   "300.5",
+				"netperf_p50_latency_microseconds.origin.n2.destination.n1":   "100",
+				"description": "netperf results",
+			},
+			want: map[networkawareutil.CostKey]NetworkMeasurement{
+				{Origin: "n1", Destination: "n2"}: {Latency: 250, Bandwidth: 1e9},
+				{Origin: "n2", Destination: "n1"}: {Latency: 300.5},
+			},
+		},
+		{
+			name:    "invalid measurement",
+			data:    map[string]string{"netperf_p90_latency_microseconds.origin.n1.destination.n2": "fast"},
+			wantErr: true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			cm := &v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "netperf", Namespace: "default"}, Data: c.data}
+			source := &ConfigMapCostSource{Reader: fake.NewClientBuilder().WithRuntimeObjects(cm).Build()}
+			got, err := source.Measure(context.TODO(), nt)
+			if (err != nil) != c.wantErr {
+				t.Fatalf("want error %v, got %v", c.wantErr, err)
+			}
+			if diff := cmp.Diff(c.want, got); diff != "" {
+				t.Errorf("unexpected measurements (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPrometheusCostSource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		resp.Write([]byte(`# TYPE netperf_p90_latency_microseconds gauge
+netperf_p90_latency_microseconds{origin="n1",destination="n2"} 250
+netperf_p90_latency_microseconds{origin="n2"} 300
+# TYPE netperf_bandwidth_bytes_per_second untyped
+netperf_bandwidth_bytes_per_second{origin="n1",destination="n2"} 1e9
+netperf_bandwidth_bytes_per_second{origin="n2",destination="n1"} 2e9
+# TYPE go_goroutines gauge
+go_goroutines 10
+`))
+	}))
+	defer server.Close()
+
+	source, err := NewNetworkCostSource(PrometheusCostSourceName, server.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := source.Measure(context.TODO(), &ntv1alpha1.NetworkTopology{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[networkawareutil.CostKey]NetworkMeasurement{
+		{Origin: "n1", Destination: "n2"}: {Latency: 250, Bandwidth: 1e9},
+		{Origin: "n2", Destination: "n1"}: {Bandwidth: 2e9},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected measurements (-want,+got):\n%s", diff)
+	}
+
+	if _, err := NewNetworkCostSource(PrometheusCostSourceName, "", nil); err == nil {
+		t.Error("want error without address")
+	}
+	if _, err := NewNetworkCostSource("Netperf", "", nil); err == nil {
+		t.Error("want error for unknown source")
+	}
+}
+
+func TestNetworkTopologyReconcile(t *testing.T) {
+	ctx := context.TODO()
+	userDefined := ntv1alpha1.WeightInfo{
+		Name: "UserDefined",
+		TopologyList: ntv1alpha1.TopologyList{{
+			TopologyKey: ntv1alpha1.NetworkTopologyZone,
+			OriginList: ntv1alpha1.OriginList{
+				{Origin: "z1", CostList: ntv1alpha1.CostList{{Destination: "z2", NetworkCost: 10}}},
+			},
+		}},
+	}
+	netperfCosts := ntv1alpha1.WeightInfo{
+		Name: ntv1alpha1.NetworkTopologyNetperfCosts,
+		TopologyList: ntv1alpha1.TopologyList{
+			{
+				TopologyKey: ntv1alpha1.NetworkTopologyRegion,
+				OriginList:  ntv1alpha1.OriginList{{Origin: "r1"}},
+			},
+			{
+				TopologyKey: ntv1alpha1.NetworkTopologyZone,
+				OriginList: ntv1alpha1.OriginList{
+					{Origin: "z1", CostList: ntv1alpha1.CostList{{Destination: "z2", NetworkCost: 2}}},
+					{Origin: "z2"},
+				},
+			},
+		},
+	}
+	cases := []struct {
+		name                string
+		source              NetworkCostSource
+		wantWeights         ntv1alpha1.WeightList
+		wantEvent           string
+		noStatusSubresource bool
+	}{
+		{
+			name: "NetperfCosts weights are added",
+			source: &fakeCostSource{measurements: map[networkawareutil.CostKey]NetworkMeasurement{
+				{Origin: "n1", Destination: "n2"}: {Latency: 1200},
+			}},
+			wantWeights: ntv1alpha1.WeightList{userDefined, netperfCosts},
+		},
+		{
+			name: "status is patched without status subresource",
+			source: &fakeCostSource{measurements: map[networkawareutil.CostKey]NetworkMeasurement{
+				{Origin: "n1", Destination: "n2"}: {Latency: 1200},
+			}},
+			wantWeights:         ntv1alpha1.WeightList{userDefined, netperfCosts},
+			noStatusSubresource: true,
+		},
+		{
+			name:   "measurement failure is reported",
+			source: &fakeCostSource{err: errors.New("netperf is down")},
+			wantWeights: ntv1alpha1.WeightList{userDefined, {
+				Name: ntv1alpha1.NetworkTopologyNetperfCosts,
+				TopologyList: ntv1alpha1.TopologyList{
+					{
+						TopologyKey: ntv1alpha1.NetworkTopologyRegion,
+						OriginList:  ntv1alpha1.OriginList{{Origin: "r1"}},
+					},
+					{
+						TopologyKey: ntv1alpha1.NetworkTopologyZone,
+						OriginList:  ntv1alpha1.OriginList{{Origin: "z1"}, {Origin: "z2"}},
+					},
+				},
+			}},
+			wantEvent: "Warning MeasurementFailed netperf is down",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			nt := &ntv1alpha1.NetworkTopology{
+				ObjectMeta: metav1.ObjectMeta{Name: "nt", Namespace: "default"},
+				Spec:       ntv1alpha1.NetworkTopologySpec{Weights: ntv1alpha1.WeightList{userDefined}},
+			}
+			s := scheme.Scheme
+			s.AddKnownTypes(ntv1alpha1.SchemeGroupVersion, nt, &ntv1alpha1.NetworkTopologyList{})
+			objs := []runtime.Object{nt, makeNode("n1", "r1", "z1"), makeNode("n2", "r1", "z2")}
+			builder := fake.NewClientBuilder().WithScheme(s).WithRuntimeObjects(objs...)
+			if !c.noStatusSubresource {
+				builder = builder.WithStatusSubresource(&ntv1alpha1.NetworkTopology{})
+			}
+			kClient := builder.Build()
+			recorder := record.NewFakeRecorder(3)
+			controller := &NetworkTopologyReconciler{
+				Client:   kClient,
+				Scheme:   s,
+				Source:   c.source,
+				Period:   time.Minute,
+				recorder: recorder,
+				log:      klogr.New().WithName("networkTopologyTest"),
+			}
+
+			result, err := controller.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(nt)})
+			if err != nil {
+				t.Fatalf("reconcile: (%v)", err)
+			}
+			if result.RequeueAfter != time.Minute {
+				t.Errorf("want requeue after %v, got %v", time.Minute, result.RequeueAfter)
+			}
+			if err := kClient.Get(ctx, client.ObjectKeyFromObject(nt), nt); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(c.wantWeights, nt.Spec.Weights, quantityComparer); diff != "" {
+				t.Errorf("unexpected weights (-want,+got):\n%s", diff)
+			}
+			if nt.Status.NodeCount != 2 {
+				t.Errorf("want 2 nodes, got %d", nt.Status.NodeCount)
+			}
+			if nt.Status.WeightCalculationTime.IsZero() {
+				t.Error("want weight calculation time set")
+			}
+			var events []string
+			for len(recorder.Events) > 0 {
+				events = append(events, <-recorder.Events)
+			}
+			if got := strings.Join(events, "\n"); got != c.wantEvent {
+				t.Errorf("want event %q, got %q", c.wantEvent, got)
+			}
+
+			// A second reconcile without changes leaves the network topology as is.
+			before := nt.ResourceVersion
+			if _, err := controller.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(nt)}); err != nil {
+				t.Fatalf("reconcile: (%v)", err)
+			}
+			if err := kClient.Get(ctx, client.ObjectKeyFromObject(nt), nt); err != nil {
+				t.Fatal(err)
+			}
+			if nt.ResourceVersion != before {
+				t.Errorf("want network topology unchanged, got resource version %s instead of %s", nt.ResourceVersion, before)
+			}
+		})
+	}
+}
@@ -0,0 +1,201 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	ntv1alpha1 "github.com/diktyo-io/networktopology-api/pkg/apis/networktopology/v1alpha1"
+	"github.com/prometheus/common/expfmt"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	networkawareutil "sigs.k8s.io/scheduler-plugins/pkg/networkaware/util"
+)
+
+const (
+	// LatencyMetric is the name of the measured latency between two nodes, in microseconds.
+	LatencyMetric = "netperf_p90_latency_microseconds"
+	// BandwidthMetric is the name of the measured bandwidth between two nodes, in bytes per second.
+	BandwidthMetric = "netperf_bandwidth_bytes_per_second"
+
+	// ConfigMapCostSourceName is the name of the source reading the measurements from the ConfigMap of a NetworkTopology.
+	ConfigMapCostSourceName = "ConfigMap"
+	// PrometheusCostSourceName is the name of the source scraping the measurements from a Prometheus-style endpoint.
+	PrometheusCostSourceName = "Prometheus"
+)
+
+// NetworkMeasurement is the measured latency and bandwidth from an origin node to a destination node.
+// A value of 0 means the value is not measured.
+type NetworkMeasurement struct {
+	// Latency in microseconds
+	Latency float64
+	// Bandwidth in bytes per second
+	Bandwidth float64
+}
+
+// NetworkCostSource measures the network between the nodes of the cluster.
+type NetworkCostSource interface {
+	// Measure returns the measurements between pairs of nodes for the given NetworkTopology.
+	Measure(ctx context.Context, nt *ntv1alpha1.NetworkTopology) (map[networkawareutil.CostKey]NetworkMeasurement, error)
+}
+
+// NewNetworkCostSource returns the source of the given name. The reader is used by the ConfigMap source, and should
+// read from the API server directly rather than cache all the ConfigMaps of the cluster.
+func NewNetworkCostSource(name, address string, c client.Reader) (NetworkCostSource, error) {
+	switch name {
+	case "", ConfigMapCostSourceName:
+		return &ConfigMapCostSource{Reader: c}, nil
+	case PrometheusCostSourceName:
+		if address == "" {
+			return nil, fmt.Errorf("address of the %s network cost source is not set", name)
+		}
+		return &PrometheusCostSource{Address: address, HTTPClient: &http.Client{Timeout: 10 * time.Second}}, nil
+	}
+	return nil, fmt.Errorf("unknown network cost source %q", name)
+}
+
+// ConfigMapCostSource reads the measurements from the ConfigMap named in the spec of the NetworkTopology, in the
+// namespace of the NetworkTopology, e.g. as pushed by a netperf component. Each measurement is a key of the form
+// <metric>.origin.<node>.destination.<node>.
+type ConfigMapCostSource struct {
+	client.Reader
+}
+
+var _ NetworkCostSource = &ConfigMapCostSource{}
+
+// Measure implements NetworkCostSource.
+func (s *ConfigMapCostSource) Measure(ctx context.Context, nt *ntv1alpha1.NetworkTopology) (map[networkawareutil.CostKey]NetworkMeasurement, error) {
+	if nt.Spec.ConfigmapName == "" {
+		return nil, nil
+	}
+	cm := &v1.ConfigMap{}
+	if err := s.Get(ctx, types.NamespacedName{Namespace: nt.Namespace, Name: nt.Spec.ConfigmapName}, cm); err != nil {
+		return nil, err
+	}
+	measurements := make(map[networkawareutil.CostKey]NetworkMeasurement)
+	for key, value := range cm.Data {
+		metric, origin, destination, ok := parseMeasurementKey(key)
+		if !ok {
+			continue
+		}
+		v, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid measurement %q of ConfigMap %s/%s: %w", key, cm.Namespace, cm.Name, err)
+		}
+		addMeasurement(measurements, metric, origin, destination, v)
+	}
+	return measurements, nil
+}
+
+// parseMeasurementKey parses a key of the form <metric>.origin.<node>.destination.<node>.
+func parseMeasurementKey(key string) (string, string, string, bool) {
+	metric, rest, ok := strings.Cut(key, ".origin.")
+	if !ok {
+		return "", "", "", false
+	}
+	origin, destination, ok := strings.Cut(rest, ".destination.")
+	if !ok || origin == "" || destination == "" {
+		return "", "", "", false
+	}
+	return metric, origin, destination, true
+}
+
+// PrometheusCostSource scrapes the measurements from an endpoint exposing metrics in the Prometheus text format,
+// labeled with the origin and destination nodes, e.g.
+// netperf_p90_latency_microseconds{origin="node-a",destination="node-b"} 250
+type PrometheusCostSource struct {
+	Address    string
+	HTTPClient *http.Client
+}
+
+var _ NetworkCostSource = &PrometheusCostSource{}
+
+// Measure implements NetworkCostSource.
+func (s *PrometheusCostSource) Measure(ctx context.Context, _ *ntv1alpha1.NetworkTopology) (map[networkawareutil.CostKey]NetworkMeasurement, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.Address, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %q from %s", resp.Status, s.Address)
+	}
+
+	var parser expfmt.TextParser
+	families, err := parser.TextToMetricFamilies(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	measurements := make(map[networkawareutil.CostKey]NetworkMeasurement)
+	for _, name := range []string{LatencyMetric, BandwidthMetric} {
+		family, ok := families[name]
+		if !ok {
+			continue
+		}
+		for _, m := range family.GetMetric() {
+			var origin, destination string
+			for _, label := range m.GetLabel() {
+				switch label.GetName() {
+				case "origin":
+					origin = label.GetValue()
+				case "destination":
+					destination = label.GetValue()
+				}
+			}
+			if origin == "" || destination == "" {
+				continue
+			}
+			var v float64
+			switch {
+			case m.Gauge != nil:
+				v = m.GetGauge().GetValue()
+			case m.Untyped != nil:
+				v = m.GetUntyped().GetValue()
+			default:
+				continue
+			}
+			addMeasurement(measurements, name, origin, destination, v)
+		}
+	}
+	return measurements, nil
+}
+
+func addMeasurement(measurements map[networkawareutil.CostKey]NetworkMeasurement, metric, origin, destination string, v float64) {
+	key := networkawareutil.CostKey{Origin: origin, Destination: destination}
+	m := measurements[key]
+	switch metric {
+	case LatencyMetric:
+		m.Latency = v
+	case BandwidthMetric:
+		m.Bandwidth = v
+	default:
+		return
+	}
+	measurements[key] = m
+}
@@ -99,13 +99,17 @@ func (r *PodGroupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 		return ctrl.Result{}, err
 	}
 	pods := podList.Items
+	podPtrs := make([]*v1.Pod, len(pods))
+	for i := range pods {
+		podPtrs[i] = &pods[i]
+	}
 
 	pgCopy := pg.DeepCopy()
 	switch pgCopy.Status.Phase {
 	case "":
 		pgCopy.Status.Phase = schedv1alpha1.PodGroupPending
 	case schedv1alpha1.PodGroupPending:
-		if len(pods) >= int(pg.Spec.MinMember) {
+		if len(pods) >= int(pg.Spec.MinMember) && util.GetUnsatisfiedRole(pg, util.CountPodsByRole(podPtrs)) == nil {
 			pgCopy.Status.Phase = schedv1alpha1.PodGroupScheduling
 			if len(pods) > 0 {
 				fillOccupiedObj(pgCopy, &pods[0])
@@ -113,16 +117,16 @@ func (r *PodGroupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 		}
 	default:
 		pgCopy.Status.Running, pgCopy.Status.Succeeded, pgCopy.Status.Failed = getCurrentPodStats(pods)
-		if len(pods) < int(pg.Spec.MinMember) {
+		if len(pods) < int(pg.Spec.MinMember) || util.GetUnsatisfiedRole(pg, util.CountPodsByRole(podPtrs)) != nil {
 			pgCopy.Status.Phase = schedv1alpha1.PodGroupPending
 			break
 		}
 
-		if pgCopy.Status.Succeeded+pgCopy.Status.Running < pg.Spec.MinMember {
+		// Every role needs to reach its quorum as well.
+		activeByRole := util.CountPodsByRole(podPtrs, v1.PodRunning, v1.PodSucceeded)
+		if pgCopy.Status.Succeeded+pgCopy.Status.Running < pg.Spec.MinMember || util.GetUnsatisfiedRole(pg, activeByRole) != nil {
 			pgCopy.Status.Phase = schedv1alpha1.PodGroupScheduling
-		}
-
-		if pgCopy.Status.Succeeded+pgCopy.Status.Running >= pg.Spec.MinMember {
+		} else {
 			pgCopy.Status.Phase = schedv1alpha1.PodGroupRunning
 		}
 		// Final state of pod group
@@ -130,10 +134,12 @@ func (r *PodGroupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 			pgCopy.Status.Failed+pgCopy.Status.Running+pgCopy.Status.Succeeded >= pg.Spec.MinMember {
 			pgCopy.Status.Phase = schedv1alpha1.PodGroupFailed
 		}
-		if pgCopy.Status.Succeeded >= pg.Spec.MinMember {
+		if pgCopy.Status.Succeeded >= pg.Spec.MinMember &&
+			util.GetUnsatisfiedRole(pg, util.CountPodsByRole(podPtrs, v1.PodSucceeded)) == nil {
 			pgCopy.Status.Phase = schedv1alpha1.PodGroupFinished
 		}
 	}
+	pgCopy.Status.Desired, pgCopy.Status.Scheduled = getScale(pg, pods)
 
 	return r.patchPodGroup(ctx, pg, pgCopy)
 }
@@ -170,6 +176,22 @@ func getCurrentPodStats(pods []v1.Pod) (int32, int32, int32) {
 	return running, succeeded, failed
 }
 
+// getScale returns the desired scale of the pod group, i.e. the number of its pods capped at MaxMember,
+// and the achieved scale, i.e. the number of its pods bound to a node.
+func getScale(pg *schedv1alpha1.PodGroup, pods []v1.Pod) (int32, int32) {
+	desired := int32(len(pods))
+	if pg.Spec.MaxMember != nil {
+		desired = min(desired, max(*pg.Spec.MaxMember, pg.Spec.MinMember))
+	}
+	var scheduled int32
+	for _, pod := range pods {
+		if pod.Spec.NodeName != "" {
+			scheduled++
+		}
+	}
+	return desired, scheduled
+}
+
 func fillOccupiedObj(pg *schedv1alpha1.PodGroup, pod *v1.Pod) {
 	if len(pod.OwnerReferences) == 0 {
 		return
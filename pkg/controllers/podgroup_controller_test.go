@@ -31,6 +31,7 @@ import (
 	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2/klogr"
 	st "k8s.io/kubernetes/pkg/scheduler/testing"
+	"k8s.io/utils/ptr"
 
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -195,6 +196,157 @@ func Test_Run(t *testing.T) {
 	}
 }
 
+func TestReconcileRoles(t *testing.T) {
+	ctx := context.TODO()
+	makeRolePod := func(name, role string, phase v1.PodPhase) *v1.Pod {
+		pod := st.MakePod().Namespace("default").Name(name).Obj()
+		pod.Labels = map[string]string{v1alpha1.PodGroupLabel: "pg", v1alpha1.PodGroupRoleLabel: role}
+		pod.Status.Phase = phase
+		return pod
+	}
+	cases := []struct {
+		name              string
+		pods              []*v1.Pod
+		previousPhase     v1alpha1.PodGroupPhase
+		desiredGroupPhase v1alpha1.PodGroupPhase
+	}{
+		{
+			name: "enough pods in total but missing a role keeps pending",
+			pods: []*v1.Pod{
+				makeRolePod("worker-1", "worker", v1.PodPending),
+				makeRolePod("worker-2", "worker", v1.PodPending),
+				makeRolePod("worker-3", "worker", v1.PodPending),
+			},
+			previousPhase:     v1alpha1.PodGroupPending,
+			desiredGroupPhase: v1alpha1.PodGroupPending,
+		},
+		{
+			name: "every role reaching its quorum converts from pending to scheduling",
+			pods: []*v1.Pod{
+				makeRolePod("launcher", "launcher", v1.PodPending),
+				makeRolePod("worker-1", "worker", v1.PodPending),
+				makeRolePod("worker-2", "worker", v1.PodPending),
+			},
+			previousPhase:     v1alpha1.PodGroupPending,
+			desiredGroupPhase: v1alpha1.PodGroupScheduling,
+		},
+		{
+			name: "running pods missing a role converts from running to pending",
+			pods: []*v1.Pod{
+				makeRolePod("worker-1", "worker", v1.PodRunning),
+				makeRolePod("worker-2", "worker", v1.PodRunning),
+				makeRolePod("worker-3", "worker", v1.PodRunning),
+			},
+			previousPhase:     v1alpha1.PodGroupRunning,
+			desiredGroupPhase: v1alpha1.PodGroupPending,
+		},
+		{
+			name: "a role not running yet keeps scheduling",
+			pods: []*v1.Pod{
+				makeRolePod("launcher", "launcher", v1.PodPending),
+				makeRolePod("worker-1", "worker", v1.PodRunning),
+				makeRolePod("worker-2", "worker", v1.PodRunning),
+				makeRolePod("worker-3", "worker", v1.PodRunning),
+			},
+			previousPhase:     v1alpha1.PodGroupScheduling,
+			desiredGroupPhase: v1alpha1.PodGroupScheduling,
+		},
+		{
+			name: "every role running",
+			pods: []*v1.Pod{
+				makeRolePod("launcher", "launcher", v1.PodRunning),
+				makeRolePod("worker-1", "worker", v1.PodRunning),
+				makeRolePod("worker-2", "worker", v1.PodRunning),
+			},
+			previousPhase:     v1alpha1.PodGroupScheduling,
+			desiredGroupPhase: v1alpha1.PodGroupRunning,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			s := scheme.Scheme
+			pg := makePG("pg", 3, c.previousPhase, nil)
+			pg.Spec.Roles = []v1alpha1.PodGroupRole{
+				{Name: "launcher", MinMember: 1},
+				{Name: "worker", MinMember: 2},
+			}
+			s.AddKnownTypes(v1alpha1.SchemeGroupVersion, pg)
+			objs := []runtime.Object{pg}
+			for _, p := range c.pods {
+				objs = append(objs, p)
+			}
+			kClient := fake.NewClientBuilder().
+				WithScheme(s).
+				WithStatusSubresource(&v1alpha1.PodGroup{}).
+				WithRuntimeObjects(objs...).
+				Build()
+			controller := &PodGroupReconciler{
+				Client:   kClient,
+				Scheme:   s,
+				recorder: record.NewFakeRecorder(3),
+				log:      klogr.New().WithName("podGroupTest"),
+			}
+
+			if _, err := controller.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(pg)}); err != nil {
+				t.Fatalf("reconcile: (%v)", err)
+			}
+			if err := kClient.Get(ctx, client.ObjectKeyFromObject(pg), pg); err != nil {
+				t.Fatal(err)
+			}
+			if pg.Status.Phase != c.desiredGroupPhase {
+				t.Fatalf("want %v, got %v", c.desiredGroupPhase, pg.Status.Phase)
+			}
+		})
+	}
+}
+
+func TestGetScale(t *testing.T) {
+	pods := []v1.Pod{
+		*st.MakePod().Name("p1").Node("node").Obj(),
+		*st.MakePod().Name("p2").Node("node").Obj(),
+		*st.MakePod().Name("p3").Obj(),
+		*st.MakePod().Name("p4").Obj(),
+	}
+	cases := []struct {
+		name          string
+		minMember     int32
+		maxMember     *int32
+		wantDesired   int32
+		wantScheduled int32
+	}{
+		{
+			name:          "PodGroup is not elastic",
+			minMember:     2,
+			wantDesired:   4,
+			wantScheduled: 2,
+		},
+		{
+			name:          "desired scale is capped at maxMember",
+			minMember:     2,
+			maxMember:     ptr.To[int32](3),
+			wantDesired:   3,
+			wantScheduled: 2,
+		},
+		{
+			name:          "maxMember less than minMember is treated as minMember",
+			minMember:     2,
+			maxMember:     ptr.To[int32](1),
+			wantDesired:   2,
+			wantScheduled: 2,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			pg := makePG("pg", c.minMember, v1alpha1.PodGroupRunning, nil)
+			pg.Spec.MaxMember = c.maxMember
+			desired, scheduled := getScale(pg, pods)
+			if desired != c.wantDesired || scheduled != c.wantScheduled {
+				t.Errorf("want desired %v and scheduled %v, got %v and %v", c.wantDesired, c.wantScheduled, desired, scheduled)
+			}
+		})
+	}
+}
+
 func TestFillGroupStatusOccupied(t *testing.T) {
 	ctx := context.TODO()
 	cases := []struct {
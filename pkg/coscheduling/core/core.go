@@ -24,6 +24,7 @@ import (
 
 	gocache "github.com/patrickmn/go-cache"
 	corev1 "k8s.io/api/core/v1"
+	policy "k8s.io/api/policy/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
@@ -74,6 +75,10 @@ type Manager interface {
 	DeletePermittedPodGroup(context.Context, string)
 	ActivateSiblings(ctx context.Context, pod *corev1.Pod, state fwk.CycleState)
 	BackoffPodGroup(string, time.Duration)
+	SelectVictims(context.Context, *corev1.Pod, []*policy.PodDisruptionBudget) ([]*corev1.Pod, error)
+	GetTopologyPolicy(context.Context, *corev1.Pod) (*v1alpha1.TopologyPolicy, string)
+	ReserveTopologyDomain(context.Context, *corev1.Pod, string)
+	PreemptPodGroup(string) bool
 }
 
 // PodGroupManager defines the scheduling operation called
@@ -93,6 +98,11 @@ type PodGroupManager struct {
 	podLister listerv1.PodLister
 	// assignedPodsByPG stores the pods assumed or bound for podgroups
 	assignedPodsByPG map[string]sets.Set[string]
+	// topologyDomainByPG stores the topology domain chosen for podgroups with a topology policy,
+	// i.e. the domain of their first assigned pods.
+	topologyDomainByPG map[string]topologyDomain
+	// preemptingPG stores the podgroup name which preempted pods in its current scheduling attempt.
+	preemptingPG *gocache.Cache
 	sync.RWMutex
 }
 
@@ -129,6 +139,8 @@ func NewPodGroupManager(client client.Client, snapshotSharedLister framework.Sha
 		permittedPG:          gocache.New(3*time.Second, 3*time.Second),
 		backedOffPG:          gocache.New(10*time.Second, 10*time.Second),
 		assignedPodsByPG:     map[string]sets.Set[string]{},
+		topologyDomainByPG:   map[string]topologyDomain{},
+		preemptingPG:         gocache.New(3*time.Second, 3*time.Second),
 	}
 	podInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc: AddPodFactory(pgMgr),
@@ -140,6 +152,7 @@ func NewPodGroupManager(client client.Client, snapshotSharedLister framework.Sha
 					return
 				}
 				pgMgr.Unreserve(context.Background(), pod)
+				pgMgr.forgetTopologyDomainWithoutPods(pod)
 				return
 			case cache.DeletedFinalStateUnknown:
 				pod, ok := t.Obj.(*corev1.Pod)
@@ -150,6 +163,7 @@ func NewPodGroupManager(client client.Client, snapshotSharedLister framework.Sha
 					return
 				}
 				pgMgr.Unreserve(context.Background(), pod)
+				pgMgr.forgetTopologyDomainWithoutPods(pod)
 				return
 			default:
 				return
@@ -219,8 +233,10 @@ func (pgMgr *PodGroupManager) ActivateSiblings(ctx context.Context, pod *corev1.
 
 // PreFilter filters out a pod if
 // 1. it belongs to a podgroup that was recently denied or
-// 2. the total number of pods in the podgroup is less than the minimum number of pods
+// 2. it belongs to an elastic podgroup that already has maxMember pods assigned or
+// 3. the total number of pods in the podgroup is less than the minimum number of pods
 // that is required to be scheduled.
+// The members beyond minMember of an elastic podgroup are not checked against the quorum.
 func (pgMgr *PodGroupManager) PreFilter(ctx context.Context, pod *corev1.Pod) error {
 	lh := klog.FromContext(ctx)
 	lh.V(5).Info("Pre-filter", "pod", klog.KObj(pod))
@@ -233,6 +249,17 @@ func (pgMgr *PodGroupManager) PreFilter(ctx context.Context, pod *corev1.Pod) er
 		return fmt.Errorf("podGroup %v failed recently", pgFullName)
 	}
 
+	if pg.Spec.MaxMember != nil {
+		assigned := pgMgr.assignedSiblingCount(pgFullName, pod)
+		if assigned >= getMaxMember(pg) {
+			return fmt.Errorf("pre-filter pod %v cannot be scheduled as podGroup %v already has %v pods assigned, "+
+				"maxMember of group: %v", pod.Name, pgFullName, assigned, getMaxMember(pg))
+		}
+		if assigned >= int(pg.Spec.MinMember) {
+			return nil
+		}
+	}
+
 	pods, err := pgMgr.podLister.Pods(pod.Namespace).List(
 		labels.SelectorFromSet(labels.Set{v1alpha1.PodGroupLabel: util.GetPodGroupLabel(pod)}),
 	)
@@ -245,7 +272,13 @@ func (pgMgr *PodGroupManager) PreFilter(ctx context.Context, pod *corev1.Pod) er
 			"current pods number: %v, minMember of group: %v", pod.Name, len(pods), pg.Spec.MinMember)
 	}
 
-	if pg.Spec.MinResources == nil {
+	podsByRole := util.CountPodsByRole(pods)
+	if role := util.GetUnsatisfiedRole(pg, podsByRole); role != nil {
+		return fmt.Errorf("pre-filter pod %v cannot find enough sibling pods of role %v, "+
+			"current pods number: %v, minMember of role: %v", pod.Name, role.Name, podsByRole[role.Name], role.MinMember)
+	}
+
+	if pg.Spec.MinResources == nil && !hasRoleMinResources(pg) {
 		return nil
 	}
 
@@ -261,10 +294,7 @@ func (pgMgr *PodGroupManager) PreFilter(ctx context.Context, pod *corev1.Pod) er
 		return err
 	}
 
-	minResources := pg.Spec.MinResources.DeepCopy()
-	podQuantity := resource.NewQuantity(int64(pg.Spec.MinMember), resource.DecimalSI)
-	minResources[corev1.ResourcePods] = *podQuantity
-	err = CheckClusterResource(ctx, nodes, minResources, pgFullName)
+	err = CheckClusterResource(ctx, nodes, getMinResources(pg), pgFullName)
 	if err != nil {
 		lh.Error(err, "Failed to PreFilter", "podGroup", klog.KObj(pg))
 		return err
@@ -294,7 +324,7 @@ func (pgMgr *PodGroupManager) Permit(ctx context.Context, state fwk.CycleState,
 	assigned.Insert(pod.Name)
 	// The number of pods that have been assigned nodes is calculated from the snapshot.
 	// The current pod in not included in the snapshot during the current scheduling cycle.
-	if len(assigned) >= int(pg.Spec.MinMember) {
+	if len(assigned) >= int(pg.Spec.MinMember) && pgMgr.rolesSatisfied(pg, pod, assigned) {
 		return Success
 	}
 
@@ -313,6 +343,26 @@ func (pgMgr *PodGroupManager) Permit(ctx context.Context, state fwk.CycleState,
 	return Wait
 }
 
+// rolesSatisfied checks whether the assigned pods reach the quorum of every role of the PodGroup.
+func (pgMgr *PodGroupManager) rolesSatisfied(pg *v1alpha1.PodGroup, pod *corev1.Pod, assigned sets.Set[string]) bool {
+	if len(pg.Spec.Roles) == 0 {
+		return true
+	}
+	pods := make([]*corev1.Pod, 0, len(assigned))
+	for name := range assigned {
+		if name == pod.Name {
+			pods = append(pods, pod)
+			continue
+		}
+		p, err := pgMgr.podLister.Pods(pod.Namespace).Get(name)
+		if err != nil {
+			continue
+		}
+		pods = append(pods, p)
+	}
+	return util.GetUnsatisfiedRole(pg, util.CountPodsByRole(pods)) == nil
+}
+
 // Unreserve invalidates assigned pod from assignedPodsByPG when schedule or bind failed.
 func (pgMgr *PodGroupManager) Unreserve(ctx context.Context, pod *corev1.Pod) {
 	pgFullName, _ := pgMgr.GetPodGroup(ctx, pod)
@@ -329,6 +379,10 @@ func (pgMgr *PodGroupManager) Unreserve(ctx context.Context, pod *corev1.Pod) {
 			delete(pgMgr.assignedPodsByPG, pgFullName)
 		}
 	}
+	// Once no member is assigned anymore, the PodGroup can choose a new topology domain.
+	if _, exist := pgMgr.assignedPodsByPG[pgFullName]; !exist {
+		delete(pgMgr.topologyDomainByPG, pgFullName)
+	}
 }
 
 // GetCreationTimestamp returns the creation time of a podGroup or a pod.
@@ -344,9 +398,21 @@ func (pgMgr *PodGroupManager) GetCreationTimestamp(ctx context.Context, pod *cor
 	return pg.CreationTimestamp.Time
 }
 
+// PreemptPodGroup records a preemption for the PodGroup and returns true, unless the PodGroup already
+// preempted pods in its current scheduling attempt, e.g. for another of its pods. The record expires with
+// the schedule timeout, or once the PodGroup gets rejected.
+func (pgMgr *PodGroupManager) PreemptPodGroup(pgFullName string) bool {
+	expiration := gocache.DefaultExpiration
+	if pgMgr.scheduleTimeout != nil {
+		expiration = *pgMgr.scheduleTimeout
+	}
+	return pgMgr.preemptingPG.Add(pgFullName, nil, expiration) == nil
+}
+
 // DeletePermittedPodGroup deletes a podGroup that passes Pre-Filter but reaches PostFilter.
 func (pgMgr *PodGroupManager) DeletePermittedPodGroup(_ context.Context, pgFullName string) {
 	pgMgr.permittedPG.Delete(pgFullName)
+	pgMgr.preemptingPG.Delete(pgFullName)
 }
 
 // GetPodGroup returns the PodGroup that a Pod belongs to in cache.
@@ -365,6 +431,15 @@ func (pgMgr *PodGroupManager) GetPodGroup(ctx context.Context, pod *corev1.Pod)
 // CheckClusterResource checks if resource capacity of the cluster can satisfy <resourceRequest>.
 // It returns an error detailing the resource gap if not satisfied; otherwise returns nil.
 func CheckClusterResource(ctx context.Context, nodeList []fwk.NodeInfo, resourceRequest corev1.ResourceList, desiredPodGroupName string) error {
+	if gap := resourceGap(ctx, nodeList, resourceRequest, desiredPodGroupName); len(gap) != 0 {
+		return fmt.Errorf("resource gap: %v", gap)
+	}
+	return nil
+}
+
+// resourceGap subtracts the resources left on the nodes from <resourceRequest>, and returns
+// the resources that the cluster can't satisfy.
+func resourceGap(ctx context.Context, nodeList []fwk.NodeInfo, resourceRequest corev1.ResourceList, desiredPodGroupName string) corev1.ResourceList {
 	for _, info := range nodeList {
 		if info == nil || info.Node() == nil {
 			continue
@@ -383,7 +458,40 @@ func CheckClusterResource(ctx context.Context, nodeList []fwk.NodeInfo, resource
 			return nil
 		}
 	}
-	return fmt.Errorf("resource gap: %v", resourceRequest)
+	return resourceRequest
+}
+
+// getMinResources returns the MinResources of the PodGroup, along with the pods of MinMember or, if larger,
+// of the quorum of its roles. If MinResources is not set, the MinResources of its roles are summed up instead.
+func getMinResources(pg *v1alpha1.PodGroup) corev1.ResourceList {
+	minResources := pg.Spec.MinResources.DeepCopy()
+	if minResources == nil {
+		minResources = corev1.ResourceList{}
+		for _, role := range pg.Spec.Roles {
+			for name, quant := range role.MinResources {
+				q := minResources[name]
+				q.Add(quant)
+				minResources[name] = q
+			}
+		}
+	}
+	minMember := pg.Spec.MinMember
+	var roleMinMember int32
+	for _, role := range pg.Spec.Roles {
+		roleMinMember += role.MinMember
+	}
+	podQuantity := resource.NewQuantity(int64(max(minMember, roleMinMember)), resource.DecimalSI)
+	minResources[corev1.ResourcePods] = *podQuantity
+	return minResources
+}
+
+func hasRoleMinResources(pg *v1alpha1.PodGroup) bool {
+	for _, role := range pg.Spec.Roles {
+		if len(role.MinResources) != 0 {
+			return true
+		}
+	}
+	return false
 }
 
 // GetNamespacedName returns the namespaced name.
@@ -139,6 +139,56 @@ func TestPreFilter(t *testing.T) {
 			},
 			expectedSuccess: false,
 		},
+		{
+			name: "pod count of a role less than its minMember",
+			pod: st.MakePod().Name("p1a").Namespace("ns").UID("p1a").Label(v1alpha1.PodGroupLabel, "pg1").
+				Label(v1alpha1.PodGroupRoleLabel, "worker").Obj(),
+			pendingPods: []*corev1.Pod{
+				st.MakePod().Name("p1b").Namespace("ns").UID("p1b").Label(v1alpha1.PodGroupLabel, "pg1").
+					Label(v1alpha1.PodGroupRoleLabel, "worker").Obj(),
+				st.MakePod().Name("p1c").Namespace("ns").UID("p1c").Label(v1alpha1.PodGroupLabel, "pg1").
+					Label(v1alpha1.PodGroupRoleLabel, "worker").Obj(),
+			},
+			pgs: []*v1alpha1.PodGroup{
+				tu.MakePodGroup().Name("pg1").Namespace("ns").MinMember(2).
+					Role("launcher", 1, nil).Role("worker", 1, nil).Obj(),
+			},
+			expectedSuccess: false,
+		},
+		{
+			name: "pod count of every role satisfies its minMember",
+			pod: st.MakePod().Name("p1a").Namespace("ns").UID("p1a").Label(v1alpha1.PodGroupLabel, "pg1").
+				Label(v1alpha1.PodGroupRoleLabel, "worker").Obj(),
+			pendingPods: []*corev1.Pod{
+				st.MakePod().Name("p1b").Namespace("ns").UID("p1b").Label(v1alpha1.PodGroupLabel, "pg1").
+					Label(v1alpha1.PodGroupRoleLabel, "launcher").Obj(),
+				st.MakePod().Name("p1c").Namespace("ns").UID("p1c").Label(v1alpha1.PodGroupLabel, "pg1").
+					Label(v1alpha1.PodGroupRoleLabel, "worker").Obj(),
+			},
+			pgs: []*v1alpha1.PodGroup{
+				tu.MakePodGroup().Name("pg1").Namespace("ns").MinMember(2).
+					Role("launcher", 1, nil).Role("worker", 1, nil).Obj(),
+			},
+			expectedSuccess: true,
+		},
+		{
+			// The minResources of the roles sum up to 10 cpus, more than the 8 cpus of the cluster.
+			name: "cluster's resource cannot satisfy the minResources of the roles",
+			pod: st.MakePod().Name("p1a").Namespace("ns").UID("p1a").Label(v1alpha1.PodGroupLabel, "pg1").
+				Label(v1alpha1.PodGroupRoleLabel, "worker").Obj(),
+			pendingPods: []*corev1.Pod{
+				st.MakePod().Name("p1b").Namespace("ns").UID("p1b").Label(v1alpha1.PodGroupLabel, "pg1").
+					Label(v1alpha1.PodGroupRoleLabel, "launcher").Obj(),
+				st.MakePod().Name("p1c").Namespace("ns").UID("p1c").Label(v1alpha1.PodGroupLabel, "pg1").
+					Label(v1alpha1.PodGroupRoleLabel, "worker").Obj(),
+			},
+			pgs: []*v1alpha1.PodGroup{
+				tu.MakePodGroup().Name("pg1").Namespace("ns").MinMember(2).
+					Role("launcher", 1, map[corev1.ResourceName]string{corev1.ResourceCPU: "2"}).
+					Role("worker", 1, map[corev1.ResourceName]string{corev1.ResourceCPU: "8"}).Obj(),
+			},
+			expectedSuccess: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -171,6 +221,7 @@ func TestPreFilter(t *testing.T) {
 				scheduleTimeout:      &scheduleTimeout,
 				permittedPG:          newCache(),
 				backedOffPG:          newCache(),
+				preemptingPG:         newCache(),
 				assignedPodsByPG:     make(map[string]sets.Set[string]),
 			}
 
@@ -241,6 +292,34 @@ func TestPermit(t *testing.T) {
 			},
 			want: Success,
 		},
+		{
+			name: "pod belongs to a pg whose roles don't have quorum",
+			pod: st.MakePod().Name("p1a").Namespace("ns").UID("p1a").Label(v1alpha1.PodGroupLabel, "pg1").
+				Label(v1alpha1.PodGroupRoleLabel, "worker").Obj(),
+			existingPods: []*corev1.Pod{
+				st.MakePod().Name("p1b").Namespace("ns").UID("p1b").Label(v1alpha1.PodGroupLabel, "pg1").
+					Label(v1alpha1.PodGroupRoleLabel, "worker").Node("node").Obj(),
+			},
+			pgs: []*v1alpha1.PodGroup{
+				tu.MakePodGroup().Name("pg1").Namespace("ns").MinMember(2).
+					Role("launcher", 1, nil).Role("worker", 1, nil).Obj(),
+			},
+			want: Wait,
+		},
+		{
+			name: "pod belongs to a pg whose roles have quorum satisfied",
+			pod: st.MakePod().Name("p1a").Namespace("ns").UID("p1a").Label(v1alpha1.PodGroupLabel, "pg1").
+				Label(v1alpha1.PodGroupRoleLabel, "worker").Obj(),
+			existingPods: []*corev1.Pod{
+				st.MakePod().Name("p1b").Namespace("ns").UID("p1b").Label(v1alpha1.PodGroupLabel, "pg1").
+					Label(v1alpha1.PodGroupRoleLabel, "launcher").Node("node").Obj(),
+			},
+			pgs: []*v1alpha1.PodGroup{
+				tu.MakePodGroup().Name("pg1").Namespace("ns").MinMember(2).
+					Role("launcher", 1, nil).Role("worker", 1, nil).Obj(),
+			},
+			want: Success,
+		},
 	}
 
 	for _, tt := range tests {
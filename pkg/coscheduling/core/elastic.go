@@ -0,0 +1,39 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	"sigs.k8s.io/scheduler-plugins/apis/scheduling/v1alpha1"
+)
+
+// assignedSiblingCount returns the number of pods assigned for the PodGroup, excluding the given pod.
+func (pgMgr *PodGroupManager) assignedSiblingCount(pgFullName string, pod *corev1.Pod) int {
+	pgMgr.RWMutex.RLock()
+	defer pgMgr.RWMutex.RUnlock()
+	assigned := pgMgr.assignedPodsByPG[pgFullName]
+	if assigned.Has(pod.Name) {
+		return len(assigned) - 1
+	}
+	return len(assigned)
+}
+
+// getMaxMember returns the MaxMember of an elastic PodGroup, which is at least its MinMember.
+func getMaxMember(pg *v1alpha1.PodGroup) int {
+	return max(int(*pg.Spec.MaxMember), int(pg.Spec.MinMember))
+}
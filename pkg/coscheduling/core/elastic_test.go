@@ -0,0 +1,146 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/informers"
+	clientsetfake "k8s.io/client-go/kubernetes/fake"
+	clicache "k8s.io/client-go/tools/cache"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+
+	"sigs.k8s.io/scheduler-plugins/apis/scheduling/v1alpha1"
+	tu "sigs.k8s.io/scheduler-plugins/test/util"
+)
+
+func TestElasticPodGroup(t *testing.T) {
+	scheduleTimeout := 10 * time.Second
+	nodes := []*corev1.Node{
+		st.MakeNode().Name("node").Capacity(map[corev1.ResourceName]string{corev1.ResourceCPU: "4"}).Obj(),
+	}
+	pod := st.MakePod().Name("p").Namespace("ns").UID("p").Label(v1alpha1.PodGroupLabel, "pg").Obj()
+	makePods := func(n int) []*corev1.Pod {
+		var pods []*corev1.Pod
+		for i := range n {
+			name := fmt.Sprintf("p%d", i)
+			pods = append(pods, st.MakePod().Name(name).Namespace("ns").UID(name).Label(v1alpha1.PodGroupLabel, "pg").Obj())
+		}
+		return pods
+	}
+
+	tests := []struct {
+		name     string
+		pg       *v1alpha1.PodGroup
+		pods     []*corev1.Pod
+		assigned int
+		wantErr  bool
+	}{
+		{
+			name:     "PodGroup is not elastic",
+			pg:       tu.MakePodGroup().Name("pg").Namespace("ns").MinMember(2).Obj(),
+			pods:     makePods(3),
+			assigned: 2,
+		},
+		{
+			name:     "elastic PodGroup doesn't have minMember pods assigned",
+			pg:       tu.MakePodGroup().Name("pg").Namespace("ns").MinMember(2).MaxMember(4).Obj(),
+			pods:     makePods(3),
+			assigned: 1,
+		},
+		{
+			name:     "elastic PodGroup without enough pods is rejected before reaching minMember",
+			pg:       tu.MakePodGroup().Name("pg").Namespace("ns").MinMember(4).MaxMember(6).Obj(),
+			pods:     makePods(3),
+			assigned: 1,
+			wantErr:  true,
+		},
+		{
+			name:     "member beyond minMember is scheduled on its own",
+			pg:       tu.MakePodGroup().Name("pg").Namespace("ns").MinMember(2).MaxMember(4).Obj(),
+			pods:     makePods(2),
+			assigned: 2,
+		},
+		{
+			name: "member beyond minMember skips the minResources check",
+			pg: tu.MakePodGroup().Name("pg").Namespace("ns").MinMember(2).MaxMember(4).
+				MinResources(map[corev1.ResourceName]string{corev1.ResourceCPU: "10"}).Obj(),
+			pods:     makePods(2),
+			assigned: 2,
+		},
+		{
+			name:     "elastic PodGroup already has maxMember pods assigned",
+			pg:       tu.MakePodGroup().Name("pg").Namespace("ns").MinMember(2).MaxMember(3).Obj(),
+			pods:     makePods(3),
+			assigned: 3,
+			wantErr:  true,
+		},
+		{
+			name:     "maxMember less than minMember is treated as minMember",
+			pg:       tu.MakePodGroup().Name("pg").Namespace("ns").MinMember(2).MaxMember(1).Obj(),
+			pods:     makePods(2),
+			assigned: 2,
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			client, err := tu.NewFakeClient(tt.pg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			cs := clientsetfake.NewSimpleClientset()
+			informerFactory := informers.NewSharedInformerFactory(cs, 0)
+			podInformer := informerFactory.Core().V1().Pods()
+			pgMgr := &PodGroupManager{
+				client:               client,
+				snapshotSharedLister: tu.NewFakeSharedLister(nil, nodes),
+				podLister:            podInformer.Lister(),
+				scheduleTimeout:      &scheduleTimeout,
+				permittedPG:          newCache(),
+				backedOffPG:          newCache(),
+				preemptingPG:         newCache(),
+				assignedPodsByPG:     make(map[string]sets.Set[string]),
+			}
+			informerFactory.Start(ctx.Done())
+			if !clicache.WaitForCacheSync(ctx.Done(), podInformer.Informer().HasSynced) {
+				t.Fatal("WaitForCacheSync failed")
+			}
+			assigned := sets.New[string]()
+			for i, p := range tt.pods {
+				podInformer.Informer().GetStore().Add(p)
+				if i < tt.assigned {
+					assigned.Insert(p.Name)
+				}
+			}
+			pgMgr.assignedPodsByPG["ns/pg"] = assigned
+
+			if err := pgMgr.PreFilter(ctx, pod); (err != nil) != tt.wantErr {
+				t.Errorf("Want error %v, but got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,366 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	policy "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+	corev1helpers "k8s.io/component-helpers/scheduling/corev1"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/scheduler-plugins/apis/scheduling/v1alpha1"
+	"sigs.k8s.io/scheduler-plugins/pkg/util"
+)
+
+// victimUnit is a set of pods which can only be preempted together.
+type victimUnit struct {
+	// pods are sorted by ascending priority.
+	pods     []*corev1.Pod
+	priority int32
+	// podGroup is the full name of the PodGroup of the pods, empty for a single pod
+	// not belonging to any PodGroup.
+	podGroup string
+	// surplus is the number of pods that can be reprieved without breaking the
+	// PodGroup below its MinMember, i.e. len(pods) - MinMember.
+	surplus int
+	// elastic marks a single member beyond the MinMember of an elastic PodGroup.
+	elastic bool
+}
+
+// SelectVictims returns the pods to preempt so that the cluster can afford the MinMember and
+// MinResources of the PodGroup of the given pod at once. Only pods with a lower priority than
+// the given pod are preempted, preferring whole PodGroups of the lowest priority over fragments
+// of several PodGroups. A PodGroup is never partially preempted below its MinMember, while the
+// members beyond the MinMember of elastic PodGroups are preempted first, one by one.
+// The pods whose preemption would violate one of the given PodDisruptionBudgets are never preempted.
+// Like CheckClusterResource, the resources are accounted for the whole cluster, so the
+// PodGroup may still fail in Filter due to fragmentation or other constraints.
+// It returns an empty list without error if the pods being terminated already free enough
+// resources, and an error if preempting doesn't help the PodGroup.
+func (pgMgr *PodGroupManager) SelectVictims(ctx context.Context, pod *corev1.Pod, pdbs []*policy.PodDisruptionBudget) ([]*corev1.Pod, error) {
+	lh := klog.FromContext(ctx)
+	pgFullName, pg := pgMgr.GetPodGroup(ctx, pod)
+	if pg == nil {
+		return nil, fmt.Errorf("pod %v does not belong to any PodGroup", klog.KObj(pod))
+	}
+
+	nodes, err := pgMgr.snapshotSharedLister.NodeInfos().List()
+	if err != nil {
+		return nil, err
+	}
+
+	var minResources corev1.ResourceList
+	if pg.Spec.MinResources != nil || hasRoleMinResources(pg) {
+		minResources = getMinResources(pg)
+	} else {
+		minResources = corev1.ResourceList{}
+		for name, quant := range util.GetPodEffectiveRequest(pod) {
+			minResources[name] = *resource.NewMilliQuantity(quant.MilliValue()*int64(pg.Spec.MinMember), quant.Format)
+		}
+		minResources[corev1.ResourcePods] = *resource.NewQuantity(int64(pg.Spec.MinMember), resource.DecimalSI)
+	}
+	gap := resourceGap(ctx, nodes, minResources, pgFullName)
+	if len(gap) == 0 {
+		return nil, fmt.Errorf("cluster resource is enough for PodGroup %v, preemption doesn't help", pgFullName)
+	}
+
+	priority := corev1helpers.PodPriority(pod)
+	freed := corev1.ResourceList{}
+	singles := []*victimUnit{}
+	groups := map[string]*victimUnit{}
+	ineligible := map[string]bool{}
+	for _, info := range nodes {
+		if info == nil || info.Node() == nil {
+			continue
+		}
+		for _, podInfo := range info.GetPods() {
+			p := podInfo.GetPod()
+			if p == nil {
+				continue
+			}
+			// Pods being terminated are about to free their resources.
+			if p.DeletionTimestamp != nil {
+				addPodRequest(freed, p)
+				continue
+			}
+			name := util.GetPodGroupFullName(p)
+			if name == pgFullName {
+				continue
+			}
+			lower := corev1helpers.PodPriority(p) < priority
+			if name == "" {
+				if lower {
+					singles = append(singles, &victimUnit{pods: []*corev1.Pod{p}, priority: corev1helpers.PodPriority(p)})
+				}
+				continue
+			}
+			// A PodGroup is only preempted if all of its pods have a lower priority.
+			if !lower {
+				ineligible[name] = true
+				continue
+			}
+			unit, ok := groups[name]
+			if !ok {
+				unit = &victimUnit{podGroup: name, priority: corev1helpers.PodPriority(p)}
+				groups[name] = unit
+			}
+			unit.pods = append(unit.pods, p)
+			unit.priority = max(unit.priority, corev1helpers.PodPriority(p))
+		}
+	}
+	if covers(freed, gap) {
+		lh.V(4).Info("Terminating pods free enough resources", "podGroup", klog.KObj(pg))
+		return nil, nil
+	}
+
+	units := singles
+	for name, unit := range groups {
+		if ineligible[name] {
+			continue
+		}
+		var victimPG v1alpha1.PodGroup
+		namespace, pgName, _ := cache.SplitMetaNamespaceKey(name)
+		err := pgMgr.client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: pgName}, &victimPG)
+		if err == nil {
+			unit.surplus = max(len(unit.pods)-int(victimPG.Spec.MinMember), 0)
+		} else {
+			// Without a PodGroup there is no MinMember to protect.
+			unit.surplus = len(unit.pods)
+		}
+		// Pods of the same priority are ordered from the newest, which are the last members an elastic PodGroup scaled up to.
+		sort.SliceStable(unit.pods, func(i, j int) bool {
+			if p1, p2 := corev1helpers.PodPriority(unit.pods[i]), corev1helpers.PodPriority(unit.pods[j]); p1 != p2 {
+				return p1 < p2
+			}
+			return unit.pods[j].CreationTimestamp.Before(&unit.pods[i].CreationTimestamp)
+		})
+		if err == nil && victimPG.Spec.MaxMember != nil {
+			// The members beyond MinMember of an elastic PodGroup are preempted one by one.
+			for _, p := range unit.pods[:unit.surplus] {
+				units = append(units, &victimUnit{pods: []*corev1.Pod{p}, priority: corev1helpers.PodPriority(p), podGroup: name, elastic: true})
+			}
+			unit.pods, unit.surplus = unit.pods[unit.surplus:], 0
+			unit.priority = corev1helpers.PodPriority(unit.pods[len(unit.pods)-1])
+		}
+		units = append(units, unit)
+	}
+	// Prefer the members beyond MinMember of elastic PodGroups, then the lowest priority, then whole
+	// PodGroups over single pods, then the largest PodGroups so that the fewest PodGroups are disrupted.
+	sort.SliceStable(units, func(i, j int) bool {
+		if units[i].elastic != units[j].elastic {
+			return units[i].elastic
+		}
+		if units[i].priority != units[j].priority {
+			return units[i].priority < units[j].priority
+		}
+		if (units[i].podGroup != "") != (units[j].podGroup != "") {
+			return units[i].podGroup != ""
+		}
+		if len(units[i].pods) != len(units[j].pods) {
+			return len(units[i].pods) > len(units[j].pods)
+		}
+		return GetNamespacedName(units[i].pods[0]) < GetNamespacedName(units[j].pods[0])
+	})
+
+	budget := newDisruptionBudget(pdbs)
+	var selected, skipped []*victimUnit
+	for _, unit := range units {
+		if covers(freed, gap) {
+			break
+		}
+		if !budget.take(unit.pods) {
+			lh.V(5).Info("Preemption would violate a PodDisruptionBudget", "pod", klog.KObj(unit.pods[0]), "podGroup", unit.podGroup)
+			skipped = append(skipped, unit)
+			continue
+		}
+		for _, p := range unit.pods {
+			addPodRequest(freed, p)
+		}
+		selected = append(selected, unit)
+	}
+	if !covers(freed, gap) {
+		return nil, fmt.Errorf("preempting lower priority pods can't free resource gap %v for PodGroup %v", gap, pgFullName)
+	}
+	selected = reprieve(selected, freed, gap)
+
+	// The reprieved pods don't disrupt anything, so the PodDisruptionBudgets are accounted again on the
+	// remaining victims only. The units skipped for a PodDisruptionBudget may fit now, and they are preferred
+	// over the units selected after them.
+	rank := make(map[*victimUnit]int, len(units))
+	for i, unit := range units {
+		rank[unit] = i
+	}
+	for i := 0; i < len(skipped); i++ {
+		budget = newDisruptionBudget(pdbs)
+		for _, unit := range selected {
+			budget.take(unit.pods)
+		}
+		unit := skipped[i]
+		if !budget.take(unit.pods) {
+			continue
+		}
+		for _, p := range unit.pods {
+			addPodRequest(freed, p)
+		}
+		j := sort.Search(len(selected), func(j int) bool { return rank[selected[j]] > rank[unit] })
+		selected = slices.Insert(selected, j, unit)
+		selected = reprieve(selected, freed, gap)
+	}
+
+	var victims []*corev1.Pod
+	for _, unit := range selected {
+		victims = append(victims, unit.pods...)
+	}
+	lh.V(4).Info("Selected victims", "podGroup", klog.KObj(pg), "victims", len(victims))
+	return victims, nil
+}
+
+// reprieve reprieves as many pods of the selected units as possible, starting from the ones with the highest
+// priority, as long as the freed resources still cover the gap. A PodGroup is either reprieved as a whole, or
+// shrunk down to its surplus pods. It returns the units left to preempt, in the order of the selected ones.
+func reprieve(selected []*victimUnit, freed, gap corev1.ResourceList) []*victimUnit {
+	var kept []*victimUnit
+	for i := len(selected) - 1; i >= 0; i-- {
+		unit := selected[i]
+		for _, p := range unit.pods {
+			subPodRequest(freed, p)
+		}
+		if covers(freed, gap) {
+			continue
+		}
+		if unit.surplus > 0 {
+			surplus := unit.pods[:unit.surplus]
+			for _, p := range surplus {
+				addPodRequest(freed, p)
+			}
+			if covers(freed, gap) {
+				// Reprieve the surplus pods one by one, highest priority first.
+				var pods []*corev1.Pod
+				for j := len(surplus) - 1; j >= 0; j-- {
+					subPodRequest(freed, surplus[j])
+					if covers(freed, gap) {
+						continue
+					}
+					addPodRequest(freed, surplus[j])
+					pods = append(pods, surplus[j])
+				}
+				slices.Reverse(pods)
+				unit.pods, unit.surplus = pods, len(pods)
+				kept = append(kept, unit)
+				continue
+			}
+			for _, p := range surplus {
+				subPodRequest(freed, p)
+			}
+		}
+		for _, p := range unit.pods {
+			addPodRequest(freed, p)
+		}
+		kept = append(kept, unit)
+	}
+	slices.Reverse(kept)
+	return kept
+}
+
+// disruptionBudget tracks the disruptions the PodDisruptionBudgets still allow while the victims are selected.
+type disruptionBudget struct {
+	pdbs    []*policy.PodDisruptionBudget
+	allowed []int32
+}
+
+func newDisruptionBudget(pdbs []*policy.PodDisruptionBudget) *disruptionBudget {
+	allowed := make([]int32, len(pdbs))
+	for i, pdb := range pdbs {
+		allowed[i] = pdb.Status.DisruptionsAllowed
+	}
+	return &disruptionBudget{pdbs: pdbs, allowed: allowed}
+}
+
+// take consumes the disruptions of the pods and returns true if none of the PodDisruptionBudgets
+// is violated, otherwise it leaves the budget untouched and returns false.
+func (b *disruptionBudget) take(pods []*corev1.Pod) bool {
+	allowed := append([]int32(nil), b.allowed...)
+	for _, pod := range pods {
+		// A pod with no labels will not match any PDB.
+		if len(pod.Labels) == 0 {
+			continue
+		}
+		for i, pdb := range b.pdbs {
+			if pdb.Namespace != pod.Namespace {
+				continue
+			}
+			selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+			// A PDB with a nil or empty selector matches nothing.
+			if err != nil || selector.Empty() || !selector.Matches(labels.Set(pod.Labels)) {
+				continue
+			}
+			// Existing in DisruptedPods means it has been processed in API server.
+			if _, ok := pdb.Status.DisruptedPods[pod.Name]; ok {
+				continue
+			}
+			allowed[i]--
+			if allowed[i] < 0 {
+				return false
+			}
+		}
+	}
+	b.allowed = allowed
+	return true
+}
+
+// covers checks whether the freed resources cover the gap.
+func covers(freed, gap corev1.ResourceList) bool {
+	for name, quant := range gap {
+		if f, ok := freed[name]; !ok || f.Cmp(quant) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func addPodRequest(list corev1.ResourceList, pod *corev1.Pod) {
+	for name, quant := range podRequest(pod) {
+		q := list[name]
+		q.Add(quant)
+		list[name] = q
+	}
+}
+
+func subPodRequest(list corev1.ResourceList, pod *corev1.Pod) {
+	for name, quant := range podRequest(pod) {
+		q := list[name]
+		q.Sub(quant)
+		list[name] = q
+	}
+}
+
+// podRequest returns the effective request of the pod, including the pod slot it occupies.
+func podRequest(pod *corev1.Pod) corev1.ResourceList {
+	req := util.GetPodEffectiveRequest(pod)
+	req[corev1.ResourcePods] = *resource.NewQuantity(1, resource.DecimalSI)
+	return req
+}
@@ -0,0 +1,304 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	policy "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+
+	"sigs.k8s.io/scheduler-plugins/apis/scheduling/v1alpha1"
+	tu "sigs.k8s.io/scheduler-plugins/test/util"
+)
+
+func TestSelectVictims(t *testing.T) {
+	scheduleTimeout := 10 * time.Second
+	capacity := map[corev1.ResourceName]string{
+		corev1.ResourceCPU: "4",
+	}
+	// In total, the cluster has 4*2 = 8 cpus.
+	nodes := []*corev1.Node{
+		st.MakeNode().Name("node-a").Capacity(capacity).Obj(),
+		st.MakeNode().Name("node-b").Capacity(capacity).Obj(),
+	}
+	makePod := func(name, node, pg string, priority int32, cpu string) *corev1.Pod {
+		w := st.MakePod().Name(name).Namespace("ns").UID(name).Node(node).Priority(priority).
+			Req(map[corev1.ResourceName]string{corev1.ResourceCPU: cpu})
+		if pg != "" {
+			w = w.Label(v1alpha1.PodGroupLabel, pg)
+		}
+		return w.Obj()
+	}
+	// created makes the pod newer than the others by the given duration.
+	created := func(pod *corev1.Pod, d time.Duration) *corev1.Pod {
+		pod.CreationTimestamp = metav1.NewTime(pod.CreationTimestamp.Add(d))
+		return pod
+	}
+	// labeled makes the pod match the PodDisruptionBudget selecting app=web.
+	labeled := func(pod *corev1.Pod) *corev1.Pod {
+		pod.Labels = map[string]string{"app": "web"}
+		return pod
+	}
+	preemptor := st.MakePod().Name("p").Namespace("ns").UID("p").Priority(100).
+		Label(v1alpha1.PodGroupLabel, "pg").Obj()
+	makePG := func(minCPU string) *v1alpha1.PodGroup {
+		return tu.MakePodGroup().Name("pg").Namespace("ns").MinMember(2).
+			MinResources(map[corev1.ResourceName]string{corev1.ResourceCPU: minCPU}).Obj()
+	}
+
+	tests := []struct {
+		name         string
+		existingPods []*corev1.Pod
+		pgs          []*v1alpha1.PodGroup
+		pdbs         []*policy.PodDisruptionBudget
+		want         []string
+		wantErr      bool
+	}{
+		{
+			name: "cluster resource is enough",
+			existingPods: []*corev1.Pod{
+				makePod("low-1", "node-a", "", 10, "2"),
+			},
+			pgs:     []*v1alpha1.PodGroup{makePG("4")},
+			wantErr: true,
+		},
+		{
+			name: "no lower priority pods",
+			existingPods: []*corev1.Pod{
+				makePod("high-1", "node-a", "", 100, "4"),
+				makePod("high-2", "node-b", "", 200, "4"),
+			},
+			pgs:     []*v1alpha1.PodGroup{makePG("4")},
+			wantErr: true,
+		},
+		{
+			name: "the PodGroup's own pods are not preempted",
+			existingPods: []*corev1.Pod{
+				makePod("pg-1", "node-a", "pg", 10, "2"),
+				makePod("high-1", "node-a", "", 200, "2"),
+				makePod("high-2", "node-b", "", 200, "4"),
+			},
+			pgs:     []*v1alpha1.PodGroup{makePG("6")},
+			wantErr: true,
+		},
+		{
+			name: "lowest priority pods are preempted first",
+			existingPods: []*corev1.Pod{
+				makePod("low-1", "node-a", "", 10, "4"),
+				makePod("mid-1", "node-b", "", 50, "4"),
+			},
+			pgs:  []*v1alpha1.PodGroup{makePG("4")},
+			want: []string{"low-1"},
+		},
+		{
+			name: "whole PodGroup is preferred over single pods of the same priority",
+			existingPods: []*corev1.Pod{
+				makePod("single-1", "node-a", "", 10, "2"),
+				makePod("single-2", "node-a", "", 10, "2"),
+				makePod("gang-1", "node-b", "gang", 10, "2"),
+				makePod("gang-2", "node-b", "gang", 10, "2"),
+			},
+			pgs: []*v1alpha1.PodGroup{
+				makePG("4"),
+				tu.MakePodGroup().Name("gang").Namespace("ns").MinMember(2).Obj(),
+			},
+			want: []string{"gang-1", "gang-2"},
+		},
+		{
+			name: "PodGroup is shrunk down to its surplus pods",
+			existingPods: []*corev1.Pod{
+				makePod("gang-1", "node-a", "gang", 10, "1"),
+				makePod("gang-2", "node-a", "gang", 10, "1"),
+				makePod("gang-3", "node-a", "gang", 10, "1"),
+				makePod("gang-4", "node-a", "gang", 10, "1"),
+				makePod("high-1", "node-b", "", 200, "4"),
+			},
+			pgs: []*v1alpha1.PodGroup{
+				makePG("1"),
+				tu.MakePodGroup().Name("gang").Namespace("ns").MinMember(2).Obj(),
+			},
+			want: []string{"gang-1"},
+		},
+		{
+			name: "PodGroup is never preempted below its MinMember",
+			existingPods: []*corev1.Pod{
+				makePod("gang-1", "node-a", "gang", 10, "1"),
+				makePod("gang-2", "node-a", "gang", 10, "1"),
+				makePod("gang-3", "node-a", "gang", 10, "1"),
+				makePod("gang-4", "node-a", "gang", 10, "1"),
+				makePod("high-1", "node-b", "", 200, "4"),
+			},
+			pgs: []*v1alpha1.PodGroup{
+				makePG("3"),
+				tu.MakePodGroup().Name("gang").Namespace("ns").MinMember(2).Obj(),
+			},
+			want: []string{"gang-1", "gang-2", "gang-3", "gang-4"},
+		},
+		{
+			name: "PodGroup with a higher priority pod is not preempted",
+			existingPods: []*corev1.Pod{
+				makePod("gang-1", "node-a", "gang", 10, "2"),
+				makePod("gang-2", "node-a", "gang", 200, "2"),
+				makePod("high-1", "node-b", "", 200, "4"),
+			},
+			pgs: []*v1alpha1.PodGroup{
+				makePG("2"),
+				tu.MakePodGroup().Name("gang").Namespace("ns").MinMember(1).Obj(),
+			},
+			wantErr: true,
+		},
+		{
+			name: "unneeded lower priority pods are reprieved",
+			existingPods: []*corev1.Pod{
+				makePod("low-1", "node-a", "", 10, "1"),
+				makePod("high-1", "node-a", "", 200, "3"),
+				makePod("mid-1", "node-b", "mid", 50, "4"),
+			},
+			pgs: []*v1alpha1.PodGroup{
+				makePG("4"),
+				tu.MakePodGroup().Name("mid").Namespace("ns").MinMember(1).Obj(),
+			},
+			want: []string{"mid-1"},
+		},
+		{
+			name: "members beyond the minMember of an elastic PodGroup are preempted first",
+			existingPods: []*corev1.Pod{
+				makePod("low-1", "node-a", "", 10, "2"),
+				makePod("elastic-1", "node-a", "elastic", 50, "1"),
+				makePod("elastic-2", "node-a", "elastic", 50, "1"),
+				created(makePod("elastic-3", "node-b", "elastic", 50, "1"), time.Minute),
+				created(makePod("elastic-4", "node-b", "elastic", 50, "1"), time.Minute),
+				makePod("high-1", "node-b", "", 200, "2"),
+			},
+			pgs: []*v1alpha1.PodGroup{
+				makePG("2"),
+				tu.MakePodGroup().Name("elastic").Namespace("ns").MinMember(2).MaxMember(4).Obj(),
+			},
+			want: []string{"elastic-3", "elastic-4"},
+		},
+		{
+			name: "elastic PodGroup is not preempted below its MinMember",
+			existingPods: []*corev1.Pod{
+				makePod("low-1", "node-a", "", 10, "2"),
+				makePod("elastic-1", "node-a", "elastic", 50, "1"),
+				makePod("elastic-2", "node-a", "elastic", 50, "1"),
+				created(makePod("elastic-3", "node-b", "elastic", 50, "1"), time.Minute),
+				makePod("high-1", "node-b", "", 200, "3"),
+			},
+			pgs: []*v1alpha1.PodGroup{
+				makePG("3"),
+				tu.MakePodGroup().Name("elastic").Namespace("ns").MinMember(2).MaxMember(4).Obj(),
+			},
+			want: []string{"elastic-3", "low-1"},
+		},
+		{
+			name: "pods protected by a PodDisruptionBudget are not preempted",
+			existingPods: []*corev1.Pod{
+				makePod("gang-1", "node-a", "gang", 10, "2"),
+				makePod("gang-2", "node-a", "gang", 10, "2"),
+				makePod("mid-1", "node-b", "", 50, "4"),
+			},
+			pgs: []*v1alpha1.PodGroup{
+				makePG("4"),
+				tu.MakePodGroup().Name("gang").Namespace("ns").MinMember(2).Obj(),
+			},
+			pdbs: []*policy.PodDisruptionBudget{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "pdb", Namespace: "ns"},
+					Spec: policy.PodDisruptionBudgetSpec{
+						Selector: &metav1.LabelSelector{MatchLabels: map[string]string{v1alpha1.PodGroupLabel: "gang"}},
+					},
+					Status: policy.PodDisruptionBudgetStatus{DisruptionsAllowed: 1},
+				},
+			},
+			want: []string{"mid-1"},
+		},
+		{
+			name: "disruptions of reprieved pods are given back to the PodDisruptionBudget",
+			existingPods: []*corev1.Pod{
+				labeled(makePod("low-1", "node-a", "", 10, "1")),
+				labeled(makePod("mid-1", "node-b", "", 20, "4")),
+				makePod("high-1", "node-a", "", 30, "3"),
+			},
+			pgs: []*v1alpha1.PodGroup{makePG("3")},
+			pdbs: []*policy.PodDisruptionBudget{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "pdb", Namespace: "ns"},
+					Spec: policy.PodDisruptionBudgetSpec{
+						Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+					},
+					Status: policy.PodDisruptionBudgetStatus{DisruptionsAllowed: 1},
+				},
+			},
+			want: []string{"mid-1"},
+		},
+		{
+			name: "terminating pods free enough resources",
+			existingPods: []*corev1.Pod{
+				st.MakePod().Name("low-1").Namespace("ns").UID("low-1").Node("node-a").Priority(10).Terminating().
+					Req(map[corev1.ResourceName]string{corev1.ResourceCPU: "4"}).Obj(),
+				makePod("low-2", "node-b", "", 10, "4"),
+			},
+			pgs: []*v1alpha1.PodGroup{makePG("4")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var objs []runtime.Object
+			for _, pg := range tt.pgs {
+				objs = append(objs, pg)
+			}
+			client, err := tu.NewFakeClient(objs...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			pgMgr := &PodGroupManager{
+				client:               client,
+				snapshotSharedLister: tu.NewFakeSharedLister(tt.existingPods, nodes),
+				scheduleTimeout:      &scheduleTimeout,
+				permittedPG:          newCache(),
+				backedOffPG:          newCache(),
+				preemptingPG:         newCache(),
+				assignedPodsByPG:     make(map[string]sets.Set[string]),
+			}
+
+			victims, err := pgMgr.SelectVictims(context.Background(), preemptor, tt.pdbs)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Want error %v, but got %v", tt.wantErr, err)
+			}
+			var got []string
+			for _, v := range victims {
+				got = append(got, v.Name)
+			}
+			sort.Strings(got)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Unexpected victims (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
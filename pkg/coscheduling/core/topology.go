@@ -0,0 +1,161 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+	fwk "k8s.io/kube-scheduler/framework"
+
+	"sigs.k8s.io/scheduler-plugins/apis/scheduling/v1alpha1"
+	"sigs.k8s.io/scheduler-plugins/pkg/util"
+)
+
+// topologyDomain is the topology domain chosen for a PodGroup, tied to its UID so that a re-created
+// PodGroup of the same name chooses its own.
+type topologyDomain struct {
+	uid    types.UID
+	domain string
+}
+
+// GetTopologyPolicy returns the topology policy of the PodGroup that a Pod belongs to, and the
+// topology domain chosen for the PodGroup, which is empty if none of its members is assigned yet.
+// It returns a nil policy if the Pod doesn't belong to a PodGroup with a topology policy.
+func (pgMgr *PodGroupManager) GetTopologyPolicy(ctx context.Context, pod *corev1.Pod) (*v1alpha1.TopologyPolicy, string) {
+	pgFullName, pg := pgMgr.GetPodGroup(ctx, pod)
+	if pg == nil || pg.Spec.TopologyPolicy == nil {
+		return nil, ""
+	}
+	policy := pg.Spec.TopologyPolicy.DeepCopy()
+	if policy.Mode == "" {
+		policy.Mode = v1alpha1.TopologyModeRequired
+	}
+
+	pgMgr.RWMutex.RLock()
+	chosen, exist := pgMgr.topologyDomainByPG[pgFullName]
+	pgMgr.RWMutex.RUnlock()
+	// The domain chosen for a previous PodGroup of the same name, e.g. of a rerun Job, doesn't apply.
+	if exist && chosen.uid == pg.UID {
+		return policy, chosen.domain
+	}
+
+	// The domain is not tracked yet, e.g. the scheduler restarted after some members were bound,
+	// so recover it from the members in the snapshot.
+	nodes, err := pgMgr.snapshotSharedLister.NodeInfos().List()
+	if err != nil {
+		klog.FromContext(ctx).Error(err, "Failed to list nodes", "podGroup", pgFullName)
+		return policy, ""
+	}
+	for _, info := range nodes {
+		if info == nil || info.Node() == nil {
+			continue
+		}
+		domain, ok := info.Node().Labels[policy.TopologyKey]
+		if !ok {
+			continue
+		}
+		for _, podInfo := range info.GetPods() {
+			if util.GetPodGroupFullName(podInfo.GetPod()) == pgFullName {
+				pgMgr.setTopologyDomain(pgFullName, pg.UID, domain)
+				return policy, domain
+			}
+		}
+	}
+	return policy, ""
+}
+
+// ReserveTopologyDomain records the topology domain of the given node as the domain of the PodGroup
+// that a Pod belongs to, if the PodGroup has a topology policy and no domain is chosen yet.
+func (pgMgr *PodGroupManager) ReserveTopologyDomain(ctx context.Context, pod *corev1.Pod, nodeName string) {
+	pgFullName, pg := pgMgr.GetPodGroup(ctx, pod)
+	if pg == nil || pg.Spec.TopologyPolicy == nil {
+		return
+	}
+	nodeInfo, err := pgMgr.snapshotSharedLister.NodeInfos().Get(nodeName)
+	if err != nil || nodeInfo.Node() == nil {
+		return
+	}
+	domain, ok := nodeInfo.Node().Labels[pg.Spec.TopologyPolicy.TopologyKey]
+	if !ok {
+		return
+	}
+	pgMgr.setTopologyDomain(pgFullName, pg.UID, domain)
+}
+
+func (pgMgr *PodGroupManager) setTopologyDomain(pgFullName string, uid types.UID, domain string) {
+	pgMgr.RWMutex.Lock()
+	defer pgMgr.RWMutex.Unlock()
+	if chosen, exist := pgMgr.topologyDomainByPG[pgFullName]; !exist || chosen.uid != uid {
+		pgMgr.topologyDomainByPG[pgFullName] = topologyDomain{uid: uid, domain: domain}
+	}
+}
+
+// ForgetPodGroup drops the topology domain chosen for a deleted PodGroup.
+func (pgMgr *PodGroupManager) ForgetPodGroup(pgFullName string) {
+	pgMgr.RWMutex.Lock()
+	defer pgMgr.RWMutex.Unlock()
+	delete(pgMgr.topologyDomainByPG, pgFullName)
+}
+
+// forgetTopologyDomainWithoutPods drops the topology domain chosen for the PodGroup of a deleted pod,
+// once none of its members is assigned anymore.
+func (pgMgr *PodGroupManager) forgetTopologyDomainWithoutPods(pod *corev1.Pod) {
+	pgName := util.GetPodGroupLabel(pod)
+	if pgName == "" {
+		return
+	}
+	pods, err := pgMgr.podLister.Pods(pod.Namespace).List(
+		labels.SelectorFromSet(labels.Set{v1alpha1.PodGroupLabel: pgName}),
+	)
+	if err != nil {
+		return
+	}
+	for _, p := range pods {
+		if p.UID != pod.UID && p.Spec.NodeName != "" {
+			return
+		}
+	}
+	pgMgr.ForgetPodGroup(util.GetPodGroupFullName(pod))
+}
+
+// FeasibleTopologyDomains returns the topology domains whose nodes can satisfy the MinMember and
+// MinResources of the PodGroup, accounted like CheckClusterResource. Nodes without the topology key
+// don't belong to any domain.
+func FeasibleTopologyDomains(ctx context.Context, nodeList []fwk.NodeInfo, pg *v1alpha1.PodGroup, pgFullName string) sets.Set[string] {
+	domains := map[string][]fwk.NodeInfo{}
+	for _, info := range nodeList {
+		if info == nil || info.Node() == nil {
+			continue
+		}
+		if domain, ok := info.Node().Labels[pg.Spec.TopologyPolicy.TopologyKey]; ok {
+			domains[domain] = append(domains[domain], info)
+		}
+	}
+
+	feasible := sets.New[string]()
+	for domain, nodes := range domains {
+		if CheckClusterResource(ctx, nodes, getMinResources(pg), pgFullName) == nil {
+			feasible.Insert(domain)
+		}
+	}
+	return feasible
+}
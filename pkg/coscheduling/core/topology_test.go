@@ -0,0 +1,253 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/informers"
+	clientsetfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+
+	"sigs.k8s.io/scheduler-plugins/apis/scheduling/v1alpha1"
+	tu "sigs.k8s.io/scheduler-plugins/test/util"
+)
+
+const zoneKey = "topology.kubernetes.io/zone"
+
+var topologyNodes = []*corev1.Node{
+	st.MakeNode().Name("node-a1").Label(zoneKey, "a").Capacity(map[corev1.ResourceName]string{corev1.ResourceCPU: "2"}).Obj(),
+	st.MakeNode().Name("node-a2").Label(zoneKey, "a").Capacity(map[corev1.ResourceName]string{corev1.ResourceCPU: "2"}).Obj(),
+	st.MakeNode().Name("node-b1").Label(zoneKey, "b").Capacity(map[corev1.ResourceName]string{corev1.ResourceCPU: "6"}).Obj(),
+	st.MakeNode().Name("node-c1").Capacity(map[corev1.ResourceName]string{corev1.ResourceCPU: "8"}).Obj(),
+}
+
+func newTopologyPodGroupManager(t *testing.T, pods []*corev1.Pod, pgs ...*v1alpha1.PodGroup) *PodGroupManager {
+	var objs []runtime.Object
+	for _, pg := range pgs {
+		objs = append(objs, pg)
+	}
+	client, err := tu.NewFakeClient(objs...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	scheduleTimeout := 10 * time.Second
+	return &PodGroupManager{
+		client:               client,
+		snapshotSharedLister: tu.NewFakeSharedLister(pods, topologyNodes),
+		scheduleTimeout:      &scheduleTimeout,
+		permittedPG:          newCache(),
+		backedOffPG:          newCache(),
+		preemptingPG:         newCache(),
+		assignedPodsByPG:     make(map[string]sets.Set[string]),
+		topologyDomainByPG:   make(map[string]topologyDomain),
+	}
+}
+
+func TestGetTopologyPolicy(t *testing.T) {
+	pod := st.MakePod().Name("p").Namespace("ns").UID("p").Label(v1alpha1.PodGroupLabel, "pg").Obj()
+
+	tests := []struct {
+		name         string
+		pg           *v1alpha1.PodGroup
+		existingPods []*corev1.Pod
+		wantPolicy   *v1alpha1.TopologyPolicy
+		wantDomain   string
+	}{
+		{
+			name: "PodGroup without topology policy",
+			pg:   tu.MakePodGroup().Name("pg").Namespace("ns").MinMember(2).Obj(),
+		},
+		{
+			name:       "mode defaults to required",
+			pg:         tu.MakePodGroup().Name("pg").Namespace("ns").MinMember(2).TopologyPolicy(zoneKey, "").Obj(),
+			wantPolicy: &v1alpha1.TopologyPolicy{TopologyKey: zoneKey, Mode: v1alpha1.TopologyModeRequired},
+		},
+		{
+			name: "domain is recovered from the assigned members",
+			pg: tu.MakePodGroup().Name("pg").Namespace("ns").MinMember(2).
+				TopologyPolicy(zoneKey, v1alpha1.TopologyModePreferred).Obj(),
+			existingPods: []*corev1.Pod{
+				st.MakePod().Name("other").Namespace("ns").UID("other").Node("node-a1").Obj(),
+				st.MakePod().Name("p1").Namespace("ns").UID("p1").Node("node-b1").Label(v1alpha1.PodGroupLabel, "pg").Obj(),
+			},
+			wantPolicy: &v1alpha1.TopologyPolicy{TopologyKey: zoneKey, Mode: v1alpha1.TopologyModePreferred},
+			wantDomain: "b",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pgMgr := newTopologyPodGroupManager(t, tt.existingPods, tt.pg)
+			policy, domain := pgMgr.GetTopologyPolicy(context.Background(), pod)
+			if diff := cmp.Diff(tt.wantPolicy, policy); diff != "" {
+				t.Errorf("Unexpected policy (-want,+got):\n%s", diff)
+			}
+			if domain != tt.wantDomain {
+				t.Errorf("Want domain %q, but got %q", tt.wantDomain, domain)
+			}
+		})
+	}
+}
+
+func TestReserveTopologyDomain(t *testing.T) {
+	ctx := context.Background()
+	pg := tu.MakePodGroup().Name("pg").Namespace("ns").MinMember(2).TopologyPolicy(zoneKey, v1alpha1.TopologyModeRequired).Obj()
+	p1 := st.MakePod().Name("p1").Namespace("ns").UID("p1").Label(v1alpha1.PodGroupLabel, "pg").Obj()
+	p2 := st.MakePod().Name("p2").Namespace("ns").UID("p2").Label(v1alpha1.PodGroupLabel, "pg").Obj()
+	pgMgr := newTopologyPodGroupManager(t, nil, pg)
+
+	pgMgr.ReserveTopologyDomain(ctx, p1, "node-a1")
+	pgMgr.Permit(ctx, framework.NewCycleState(), p1)
+	// The domain of the first member sticks.
+	pgMgr.ReserveTopologyDomain(ctx, p2, "node-b1")
+	pgMgr.Permit(ctx, framework.NewCycleState(), p2)
+	if _, domain := pgMgr.GetTopologyPolicy(ctx, p2); domain != "a" {
+		t.Errorf("Want domain %q, but got %q", "a", domain)
+	}
+
+	pgMgr.Unreserve(ctx, p1)
+	if _, domain := pgMgr.GetTopologyPolicy(ctx, p2); domain != "a" {
+		t.Errorf("Want domain %q while a member is assigned, but got %q", "a", domain)
+	}
+	pgMgr.Unreserve(ctx, p2)
+	if _, domain := pgMgr.GetTopologyPolicy(ctx, p2); domain != "" {
+		t.Errorf("Want no domain once no member is assigned, but got %q", domain)
+	}
+}
+
+func TestTopologyDomainOfRecreatedPodGroup(t *testing.T) {
+	ctx := context.Background()
+	pg := tu.MakePodGroup().Name("pg").Namespace("ns").MinMember(1).TopologyPolicy(zoneKey, v1alpha1.TopologyModeRequired).Obj()
+	pg.UID = "pg-1"
+	p1 := st.MakePod().Name("p1").Namespace("ns").UID("p1").Label(v1alpha1.PodGroupLabel, "pg").Obj()
+	pgMgr := newTopologyPodGroupManager(t, nil, pg)
+
+	pgMgr.ReserveTopologyDomain(ctx, p1, "node-a1")
+	pgMgr.Permit(ctx, framework.NewCycleState(), p1)
+	if _, domain := pgMgr.GetTopologyPolicy(ctx, p1); domain != "a" {
+		t.Fatalf("Want domain %q, but got %q", "a", domain)
+	}
+
+	// The PodGroup is re-created with the same name while the pods of the bound gang are still tracked.
+	recreated := pg.DeepCopy()
+	recreated.UID = "pg-2"
+	recreated.ResourceVersion = ""
+	if err := pgMgr.client.Delete(ctx, pg); err != nil {
+		t.Fatal(err)
+	}
+	if err := pgMgr.client.Create(ctx, recreated); err != nil {
+		t.Fatal(err)
+	}
+	if _, domain := pgMgr.GetTopologyPolicy(ctx, p1); domain != "" {
+		t.Errorf("Want no domain for the re-created PodGroup, but got %q", domain)
+	}
+	p2 := st.MakePod().Name("p2").Namespace("ns").UID("p2").Label(v1alpha1.PodGroupLabel, "pg").Obj()
+	pgMgr.ReserveTopologyDomain(ctx, p2, "node-b1")
+	if _, domain := pgMgr.GetTopologyPolicy(ctx, p2); domain != "b" {
+		t.Errorf("Want domain %q for the re-created PodGroup, but got %q", "b", domain)
+	}
+
+	// The domain is dropped once the PodGroup is deleted.
+	pgMgr.ForgetPodGroup("ns/pg")
+	pgMgr.RWMutex.RLock()
+	defer pgMgr.RWMutex.RUnlock()
+	if _, exist := pgMgr.topologyDomainByPG["ns/pg"]; exist {
+		t.Errorf("Want the domain of the deleted PodGroup dropped")
+	}
+}
+
+func TestTopologyDomainWithoutPods(t *testing.T) {
+	ctx := context.Background()
+	pg := tu.MakePodGroup().Name("pg").Namespace("ns").MinMember(2).TopologyPolicy(zoneKey, v1alpha1.TopologyModeRequired).Obj()
+	p1 := st.MakePod().Name("p1").Namespace("ns").UID("p1").Node("node-a1").Label(v1alpha1.PodGroupLabel, "pg").Obj()
+	p2 := st.MakePod().Name("p2").Namespace("ns").UID("p2").Node("node-a2").Label(v1alpha1.PodGroupLabel, "pg").Obj()
+	pgMgr := newTopologyPodGroupManager(t, nil, pg)
+	podInformer := informers.NewSharedInformerFactory(clientsetfake.NewSimpleClientset(), 0).Core().V1().Pods()
+	pgMgr.podLister = podInformer.Lister()
+	podInformer.Informer().GetStore().Add(p1)
+	podInformer.Informer().GetStore().Add(p2)
+	pgMgr.ReserveTopologyDomain(ctx, p1, "node-a1")
+
+	podInformer.Informer().GetStore().Delete(p1)
+	pgMgr.forgetTopologyDomainWithoutPods(p1)
+	if _, domain := pgMgr.GetTopologyPolicy(ctx, p2); domain != "a" {
+		t.Errorf("Want domain %q while a member is bound, but got %q", "a", domain)
+	}
+	podInformer.Informer().GetStore().Delete(p2)
+	pgMgr.forgetTopologyDomainWithoutPods(p2)
+	if _, domain := pgMgr.GetTopologyPolicy(ctx, p2); domain != "" {
+		t.Errorf("Want no domain once the last pod is deleted, but got %q", domain)
+	}
+}
+
+func TestFeasibleTopologyDomains(t *testing.T) {
+	tests := []struct {
+		name         string
+		pg           *v1alpha1.PodGroup
+		existingPods []*corev1.Pod
+		want         sets.Set[string]
+	}{
+		{
+			name: "all domains fit the PodGroup",
+			pg: tu.MakePodGroup().Name("pg").Namespace("ns").MinMember(2).
+				MinResources(map[corev1.ResourceName]string{corev1.ResourceCPU: "4"}).TopologyPolicy(zoneKey, "").Obj(),
+			want: sets.New("a", "b"),
+		},
+		{
+			name: "domain whose nodes are too small in total",
+			pg: tu.MakePodGroup().Name("pg").Namespace("ns").MinMember(2).
+				MinResources(map[corev1.ResourceName]string{corev1.ResourceCPU: "5"}).TopologyPolicy(zoneKey, "").Obj(),
+			want: sets.New("b"),
+		},
+		{
+			name: "resources used by other pods are not available",
+			pg: tu.MakePodGroup().Name("pg").Namespace("ns").MinMember(2).
+				MinResources(map[corev1.ResourceName]string{corev1.ResourceCPU: "4"}).TopologyPolicy(zoneKey, "").Obj(),
+			existingPods: []*corev1.Pod{
+				st.MakePod().Name("other").Namespace("ns").UID("other").Node("node-b1").
+					Req(map[corev1.ResourceName]string{corev1.ResourceCPU: "4"}).Obj(),
+			},
+			want: sets.New("a"),
+		},
+		{
+			name: "resources used by the members are available",
+			pg: tu.MakePodGroup().Name("pg").Namespace("ns").MinMember(2).
+				MinResources(map[corev1.ResourceName]string{corev1.ResourceCPU: "4"}).TopologyPolicy(zoneKey, "").Obj(),
+			existingPods: []*corev1.Pod{
+				st.MakePod().Name("p1").Namespace("ns").UID("p1").Node("node-b1").Label(v1alpha1.PodGroupLabel, "pg").
+					Req(map[corev1.ResourceName]string{corev1.ResourceCPU: "4"}).Obj(),
+			},
+			want: sets.New("a", "b"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodes, _ := tu.NewFakeSharedLister(tt.existingPods, topologyNodes).NodeInfos().List()
+			got := FeasibleTopologyDomains(context.Background(), nodes, tt.pg, "ns/pg")
+			if diff := cmp.Diff(sets.List(tt.want), sets.List(got)); diff != "" {
+				t.Errorf("Unexpected feasible domains (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -22,14 +22,20 @@ import (
 	"time"
 
 	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
 	clientscheme "k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/tools/cache"
 	corev1helpers "k8s.io/component-helpers/scheduling/corev1"
 	"k8s.io/klog/v2"
+	extenderv1 "k8s.io/kube-scheduler/extender/v1"
 	fwk "k8s.io/kube-scheduler/framework"
 	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/framework/parallelize"
+	"k8s.io/kubernetes/pkg/scheduler/framework/preemption"
+	schedutil "k8s.io/kubernetes/pkg/scheduler/util"
 
 	"sigs.k8s.io/scheduler-plugins/apis/config"
 	"sigs.k8s.io/scheduler-plugins/apis/config/validation"
@@ -46,11 +52,17 @@ type Coscheduling struct {
 	pgMgr            core.Manager
 	scheduleTimeout  *time.Duration
 	pgBackoff        *time.Duration
+	// evaluator evicts the victims like the default preemption does. The victims are selected for the
+	// whole PodGroup by the pgMgr rather than node by node, so only its PreemptPod and PdbLister are used.
+	evaluator *preemption.Evaluator
 }
 
 var _ framework.QueueSortPlugin = &Coscheduling{}
 var _ framework.PreFilterPlugin = &Coscheduling{}
+var _ framework.FilterPlugin = &Coscheduling{}
 var _ framework.PostFilterPlugin = &Coscheduling{}
+var _ framework.PreScorePlugin = &Coscheduling{}
+var _ framework.ScorePlugin = &Coscheduling{}
 var _ framework.PermitPlugin = &Coscheduling{}
 var _ framework.ReservePlugin = &Coscheduling{}
 
@@ -59,8 +71,44 @@ var _ framework.EnqueueExtensions = &Coscheduling{}
 const (
 	// Name is the name of the plugin used in Registry and configurations.
 	Name = "Coscheduling"
+
+	topologyStateKey = "TopologyCoscheduling"
+
+	// ErrReasonNoTopologyKey is the reason for a node without the topology key of the PodGroup.
+	ErrReasonNoTopologyKey = "node(s) didn't have the topology key of the PodGroup"
+	// ErrReasonTopologyDomainMismatch is the reason for a node outside of the topology domain chosen for the PodGroup.
+	ErrReasonTopologyDomainMismatch = "node(s) didn't match the topology domain of the PodGroup"
+	// ErrReasonTopologyDomainInsufficient is the reason for a node in a topology domain which can't fit the PodGroup.
+	ErrReasonTopologyDomainInsufficient = "node(s) in a topology domain that can't fit the PodGroup"
 )
 
+// topologyState holds the topology policy of the PodGroup of the pod being scheduled.
+type topologyState struct {
+	policy *v1alpha1.TopologyPolicy
+	// domain is the topology domain chosen for the PodGroup, empty if none of its members is assigned yet.
+	domain string
+	// feasibleDomains are the topology domains which can fit the PodGroup, only computed if no domain is chosen yet.
+	feasibleDomains sets.Set[string]
+}
+
+// Clone the topology state.
+func (s *topologyState) Clone() fwk.StateData {
+	return s
+}
+
+// inDomain checks whether the node belongs to the topology domain chosen for the PodGroup,
+// or to one of the domains which can fit the PodGroup if no domain is chosen yet.
+func (s *topologyState) inDomain(node *v1.Node) (bool, string) {
+	value, ok := node.Labels[s.policy.TopologyKey]
+	if !ok {
+		return false, ErrReasonNoTopologyKey
+	}
+	if s.domain != "" {
+		return value == s.domain, ErrReasonTopologyDomainMismatch
+	}
+	return s.feasibleDomains.Has(value), ErrReasonTopologyDomainInsufficient
+}
+
 // New initializes and returns a new Coscheduling plugin.
 func New(ctx context.Context, obj runtime.Object, handle framework.Handle) (framework.Plugin, error) {
 
@@ -80,7 +128,11 @@ func New(ctx context.Context, obj runtime.Object, handle framework.Handle) (fram
 	_ = clientscheme.AddToScheme(scheme)
 	_ = v1.AddToScheme(scheme)
 	_ = v1alpha1.AddToScheme(scheme)
-	c, _, err := util.NewClientWithCachedReader(ctx, handle.KubeConfig(), scheme)
+	c, ccache, err := util.NewClientWithCachedReader(ctx, handle.KubeConfig(), scheme)
+	if err != nil {
+		return nil, err
+	}
+	pgInformer, err := ccache.GetInformer(ctx, &v1alpha1.PodGroup{})
 	if err != nil {
 		return nil, err
 	}
@@ -96,11 +148,23 @@ func New(ctx context.Context, obj runtime.Object, handle framework.Handle) (fram
 		// Keep the podInformer (from frameworkHandle) as the single source of Pods.
 		handle.SharedInformerFactory().Core().V1().Pods(),
 	)
+	// A re-created PodGroup of the same name, e.g. of a rerun Job, chooses a new topology domain.
+	pgInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		DeleteFunc: func(obj interface{}) {
+			if t, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = t.Obj
+			}
+			if pg, ok := obj.(*v1alpha1.PodGroup); ok {
+				pgMgr.ForgetPodGroup(fmt.Sprintf("%v/%v", pg.Namespace, pg.Name))
+			}
+		},
+	})
 	plugin := &Coscheduling{
 		logger:           lh,
 		frameworkHandler: handle,
 		pgMgr:            pgMgr,
 		scheduleTimeout:  &scheduleTimeDuration,
+		evaluator:        preemption.NewEvaluator(Name, handle, nil, false),
 	}
 	if args.PodGroupBackoffSeconds < 0 {
 		err := fmt.Errorf("parse arguments failed")
@@ -119,8 +183,9 @@ func (cs *Coscheduling) EventsToRegister(_ context.Context) ([]fwk.ClusterEventW
 	// Please follow: eventhandlers.go#L403-L410
 	pgGVK := fmt.Sprintf("podgroups.v1alpha1.%v", scheduling.GroupName)
 	return []fwk.ClusterEventWithHint{
-		{Event: fwk.ClusterEvent{Resource: fwk.Pod, ActionType: fwk.Add}},
+		{Event: fwk.ClusterEvent{Resource: fwk.Pod, ActionType: fwk.Add | fwk.Delete}},
 		{Event: fwk.ClusterEvent{Resource: fwk.EventResource(pgGVK), ActionType: fwk.Add | fwk.Update}},
+		{Event: fwk.ClusterEvent{Resource: fwk.Node, ActionType: fwk.Add | fwk.UpdateNodeLabel}},
 	}, nil
 }
 
@@ -133,6 +198,8 @@ func (cs *Coscheduling) Name() string {
 // 1. Compare the priorities of Pods.
 // 2. Compare the initialization timestamps of PodGroups or Pods.
 // 3. Compare the keys of PodGroups/Pods: <namespace>/<podname>.
+// Whether a pod is a member beyond the minMember of an elastic PodGroup depends on its siblings being
+// assigned while it's queued, so it's only decided in PreFilter and PostFilter, not here.
 func (cs *Coscheduling) Less(podInfo1, podInfo2 fwk.QueuedPodInfo) bool {
 	prio1 := corev1helpers.PodPriority(podInfo1.GetPodInfo().GetPod())
 	prio2 := corev1helpers.PodPriority(podInfo2.GetPodInfo().GetPod())
@@ -150,6 +217,7 @@ func (cs *Coscheduling) Less(podInfo1, podInfo2 fwk.QueuedPodInfo) bool {
 // PreFilter performs the following validations.
 // 1. Whether the PodGroup that the Pod belongs to is on the deny list.
 // 2. Whether the total number of pods in a PodGroup is less than its `minMember`.
+// It also resolves the topology domain of the PodGroup for Filter and Score.
 func (cs *Coscheduling) PreFilter(ctx context.Context, state fwk.CycleState, pod *v1.Pod, nodes []fwk.NodeInfo) (*framework.PreFilterResult, *fwk.Status) {
 	lh := klog.FromContext(klog.NewContext(ctx, cs.logger)).WithValues("ExtensionPoint", "PreFilter")
 	// If PreFilter fails, return framework.UnschedulableAndUnresolvable to avoid
@@ -158,10 +226,79 @@ func (cs *Coscheduling) PreFilter(ctx context.Context, state fwk.CycleState, pod
 		lh.Error(err, "PreFilter failed", "pod", klog.KObj(pod))
 		return nil, fwk.NewStatus(fwk.UnschedulableAndUnresolvable, err.Error())
 	}
+
+	policy, domain := cs.pgMgr.GetTopologyPolicy(ctx, pod)
+	if policy != nil {
+		s := &topologyState{policy: policy, domain: domain}
+		if domain == "" {
+			pgName, pg := cs.pgMgr.GetPodGroup(ctx, pod)
+			s.feasibleDomains = core.FeasibleTopologyDomains(ctx, nodes, pg, pgName)
+		}
+		lh.V(5).Info("Topology domain of the PodGroup", "pod", klog.KObj(pod), "topologyKey", policy.TopologyKey,
+			"domain", domain, "feasibleDomains", sets.List(s.feasibleDomains))
+		state.Write(topologyStateKey, s)
+	}
 	return nil, fwk.NewStatus(fwk.Success, "")
 }
 
+// Filter rejects the nodes outside of the topology domain of the PodGroup if its topology policy is required.
+// If no domain is chosen yet, only the domains which can fit the whole PodGroup are allowed.
+func (cs *Coscheduling) Filter(ctx context.Context, state fwk.CycleState, pod *v1.Pod, nodeInfo fwk.NodeInfo) *fwk.Status {
+	s, err := getTopologyState(state)
+	if err != nil || s.policy.Mode != v1alpha1.TopologyModeRequired {
+		return nil
+	}
+	if ok, reason := s.inDomain(nodeInfo.Node()); !ok {
+		if reason == ErrReasonTopologyDomainInsufficient {
+			// The domain may fit the PodGroup once some pods are gone.
+			return fwk.NewStatus(fwk.Unschedulable, reason)
+		}
+		return fwk.NewStatus(fwk.UnschedulableAndUnresolvable, reason)
+	}
+	return nil
+}
+
+// PreScore skips the Score of this plugin for the pods without a topology policy.
+func (cs *Coscheduling) PreScore(ctx context.Context, state fwk.CycleState, pod *v1.Pod, nodes []fwk.NodeInfo) *fwk.Status {
+	if _, err := getTopologyState(state); err != nil {
+		return fwk.NewStatus(fwk.Skip)
+	}
+	return nil
+}
+
+// Score favors the nodes in the topology domain of the PodGroup, which is what steers the members of a PodGroup
+// with a preferred topology policy toward the same domain.
+func (cs *Coscheduling) Score(ctx context.Context, state fwk.CycleState, pod *v1.Pod, nodeInfo fwk.NodeInfo) (int64, *fwk.Status) {
+	s, err := getTopologyState(state)
+	if err != nil {
+		return 0, fwk.AsStatus(err)
+	}
+	if ok, _ := s.inDomain(nodeInfo.Node()); ok {
+		return framework.MaxNodeScore, nil
+	}
+	return framework.MinNodeScore, nil
+}
+
+// ScoreExtensions of the Score plugin.
+func (cs *Coscheduling) ScoreExtensions() framework.ScoreExtensions {
+	return nil
+}
+
+func getTopologyState(state fwk.CycleState) (*topologyState, error) {
+	c, err := state.Read(topologyStateKey)
+	if err != nil {
+		return nil, err
+	}
+	s, ok := c.(*topologyState)
+	if !ok {
+		return nil, fmt.Errorf("%+v convert to coscheduling.topologyState error", c)
+	}
+	return s, nil
+}
+
 // PostFilter is used to reject a group of pods if a pod does not pass PreFilter or Filter.
+// Before rejecting the group, it tries to preempt lower priority pods to free room for the
+// MinMember and MinResources of the whole group at once.
 func (cs *Coscheduling) PostFilter(ctx context.Context, state fwk.CycleState, pod *v1.Pod,
 	filteredNodeStatusReader framework.NodeToStatusReader) (*framework.PostFilterResult, *fwk.Status) {
 	lh := klog.FromContext(klog.NewContext(ctx, cs.logger)).WithValues("ExtensionPoint", "PostFilter")
@@ -187,6 +324,30 @@ func (cs *Coscheduling) PostFilter(ctx context.Context, state fwk.CycleState, po
 		return &framework.PostFilterResult{}, fwk.NewStatus(fwk.Unschedulable)
 	}
 
+	// Only one pod of the PodGroup preempts per scheduling attempt, the others wait for its victims to be gone.
+	if !cs.pgMgr.PreemptPodGroup(pgName) {
+		lh.V(4).Info("PodGroup already preempted pods in this attempt", "podGroup", klog.KObj(pg))
+		return &framework.PostFilterResult{}, fwk.NewStatus(fwk.Unschedulable,
+			fmt.Sprintf("PodGroup %v is waiting for the pods it preempted to be gone", pgName))
+	}
+
+	// Try to free room for the whole PodGroup by preempting lower priority pods.
+	// The waiting siblings are kept so that the PodGroup can be admitted as soon as the victims are gone.
+	if victims, err := cs.selectVictims(ctx, pod); err != nil {
+		lh.V(4).Info("Cannot preempt for the PodGroup", "podGroup", klog.KObj(pg), "reason", err.Error())
+	} else {
+		nodeName, err := cs.preempt(ctx, state, pod, pgName, victims)
+		if err != nil {
+			return &framework.PostFilterResult{}, fwk.AsStatus(err)
+		}
+		result := &framework.PostFilterResult{}
+		if nodeName != "" {
+			result = framework.NewPostFilterResultWithNominatedNode(nodeName)
+		}
+		return result, fwk.NewStatus(fwk.Success,
+			fmt.Sprintf("PodGroup %v preempts %v lower priority pods", pgName, len(victims)))
+	}
+
 	// It's based on an implicit assumption: if the nth Pod failed,
 	// it's inferrable other Pods belonging to the same PodGroup would be very likely to fail.
 	cs.frameworkHandler.IterateOverWaitingPods(func(waitingPod framework.WaitingPod) {
@@ -210,6 +371,130 @@ func (cs *Coscheduling) PostFilter(ctx context.Context, state fwk.CycleState, po
 		fmt.Sprintf("PodGroup %v gets rejected due to Pod %v is unschedulable even after PostFilter", pgName, pod.Name))
 }
 
+// selectVictims returns the pods to preempt for the PodGroup of the pod, unless the pod is not allowed to preempt.
+func (cs *Coscheduling) selectVictims(ctx context.Context, pod *v1.Pod) ([]*v1.Pod, error) {
+	if pod.Spec.PreemptionPolicy != nil && *pod.Spec.PreemptionPolicy == v1.PreemptNever {
+		return nil, fmt.Errorf("pod %v is not allowed to preempt", pod.Name)
+	}
+	pdbs, err := cs.evaluator.PdbLister.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	return cs.pgMgr.SelectVictims(ctx, pod, pdbs)
+}
+
+// victimsOnNode is the preemption candidate made of the victims on a node.
+type victimsOnNode struct {
+	name    string
+	victims *extenderv1.Victims
+}
+
+func (c *victimsOnNode) Victims() *extenderv1.Victims {
+	return c.victims
+}
+
+func (c *victimsOnNode) Name() string {
+	return c.name
+}
+
+// preempt evicts the victims and returns the node to nominate for the pod, the first one on which the pod
+// fits once the victims on it are gone, or an empty string if there is none. Like the default preemption,
+// the waiting victims are rejected, and the others get the DisruptionTarget condition and a Preempted event
+// before they are deleted with their graceful termination period, and the lower priority pods nominated to the
+// chosen node lose their nomination. The PodGroup is retried once the victims are deleted.
+func (cs *Coscheduling) preempt(ctx context.Context, state fwk.CycleState, pod *v1.Pod, pgName string, victims []*v1.Pod) (string, error) {
+	lh := klog.FromContext(klog.NewContext(ctx, cs.logger)).WithValues("ExtensionPoint", "PostFilter")
+	candidates := map[string]*victimsOnNode{}
+	for _, victim := range victims {
+		c, ok := candidates[victim.Spec.NodeName]
+		if !ok {
+			c = &victimsOnNode{name: victim.Spec.NodeName, victims: &extenderv1.Victims{}}
+			candidates[victim.Spec.NodeName] = c
+		}
+		c.victims.Pods = append(c.victims.Pods, victim)
+	}
+	nodeName := cs.nominatedNode(ctx, state, pod, candidates)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	errCh := parallelize.NewErrorChannel()
+	cs.frameworkHandler.Parallelizer().Until(ctx, len(victims), func(i int) {
+		victim := victims[i]
+		if victim.DeletionTimestamp != nil {
+			return
+		}
+		err := cs.evaluator.PreemptPod(ctx, candidates[victim.Spec.NodeName], pod, victim, Name)
+		if err != nil && !apierrors.IsNotFound(err) {
+			errCh.SendErrorWithCancel(fmt.Errorf("failed to preempt pod %v for PodGroup %v: %w", klog.KObj(victim), pgName, err), cancel)
+		}
+	}, Name)
+	if err := errCh.ReceiveError(); err != nil {
+		return "", err
+	}
+	if nodeName != "" {
+		cs.clearLowerPriorityNominations(ctx, pod, nodeName)
+	}
+	lh.V(2).Info("Preempted pods", "preemptor", klog.KObj(pod), "podGroup", pgName, "victims", len(victims), "nominatedNode", nodeName)
+	return nodeName, nil
+}
+
+// clearLowerPriorityNominations clears the nomination of the pods with a lower priority than the given pod
+// nominated to the node, as they may no longer fit on it. Like the default preemption, this moves them back
+// to the active queue to find another place, and the errors are only logged.
+func (cs *Coscheduling) clearLowerPriorityNominations(ctx context.Context, pod *v1.Pod, nodeName string) {
+	lh := klog.FromContext(klog.NewContext(ctx, cs.logger))
+	priority := corev1helpers.PodPriority(pod)
+	for _, podInfo := range cs.frameworkHandler.NominatedPodsForNode(nodeName) {
+		p := podInfo.GetPod()
+		if corev1helpers.PodPriority(p) >= priority {
+			continue
+		}
+		var err error
+		if apiCacher := cs.frameworkHandler.APICacher(); apiCacher != nil {
+			_, err = apiCacher.PatchPodStatus(p, nil, &framework.NominatingInfo{NominatedNodeName: "", NominatingMode: framework.ModeOverride})
+		} else if p.Status.NominatedNodeName != "" {
+			status := p.Status.DeepCopy()
+			status.NominatedNodeName = ""
+			err = schedutil.PatchPodStatus(ctx, cs.frameworkHandler.ClientSet(), p.Name, p.Namespace, &p.Status, status)
+		}
+		if err != nil {
+			lh.Error(err, "Cannot clear the nominated node of the pod", "pod", klog.KObj(p), "node", nodeName)
+		}
+	}
+}
+
+// nominatedNode returns the first node, by name, on which the pod passes the filters once the victims on it are gone.
+func (cs *Coscheduling) nominatedNode(ctx context.Context, state fwk.CycleState, pod *v1.Pod, candidates map[string]*victimsOnNode) string {
+	lh := klog.FromContext(klog.NewContext(ctx, cs.logger))
+	for _, nodeName := range sets.List(sets.KeySet(candidates)) {
+		nodeInfo, err := cs.frameworkHandler.SnapshotSharedLister().NodeInfos().Get(nodeName)
+		if err != nil {
+			continue
+		}
+		nodeInfo = nodeInfo.Snapshot()
+		stateCopy := state.Clone()
+		removed := true
+		for _, victim := range candidates[nodeName].victims.Pods {
+			podInfo, err := framework.NewPodInfo(victim)
+			if err == nil {
+				err = nodeInfo.RemovePod(lh, victim)
+			}
+			if err == nil {
+				err = cs.frameworkHandler.RunPreFilterExtensionRemovePod(ctx, stateCopy, pod, podInfo, nodeInfo).AsError()
+			}
+			if err != nil {
+				lh.V(5).Info("Cannot remove the victim from the node", "pod", klog.KObj(victim), "node", nodeName, "err", err)
+				removed = false
+				break
+			}
+		}
+		if removed && cs.frameworkHandler.RunFilterPluginsWithNominatedPods(ctx, stateCopy, pod, nodeInfo).IsSuccess() {
+			return nodeName
+		}
+	}
+	return ""
+}
+
 // PreFilterExtensions returns a PreFilterExtensions interface if the plugin implements one.
 func (cs *Coscheduling) PreFilterExtensions() framework.PreFilterExtensions {
 	return nil
@@ -252,7 +537,11 @@ func (cs *Coscheduling) Permit(ctx context.Context, state fwk.CycleState, pod *v
 }
 
 // Reserve is the functions invoked by the framework at "reserve" extension point.
+// It records the topology domain of the node for the PodGroup if none is chosen yet.
 func (cs *Coscheduling) Reserve(ctx context.Context, state fwk.CycleState, pod *v1.Pod, nodeName string) *fwk.Status {
+	if _, err := getTopologyState(state); err == nil {
+		cs.pgMgr.ReserveTopologyDomain(ctx, pod, nodeName)
+	}
 	return nil
 }
 
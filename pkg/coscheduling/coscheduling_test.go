@@ -26,15 +26,23 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	v1 "k8s.io/api/core/v1"
+	policy "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/informers"
 	clientsetfake "k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
 	clicache "k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/klog/v2"
 	fwk "k8s.io/kube-scheduler/framework"
 	"k8s.io/kubernetes/pkg/scheduler/framework"
 	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/defaultbinder"
 	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/queuesort"
+	"k8s.io/kubernetes/pkg/scheduler/framework/preemption"
 	fwkruntime "k8s.io/kubernetes/pkg/scheduler/framework/runtime"
+	"k8s.io/kubernetes/pkg/scheduler/metrics"
 	st "k8s.io/kubernetes/pkg/scheduler/testing"
 	tf "k8s.io/kubernetes/pkg/scheduler/testing/framework"
 	"k8s.io/utils/pointer"
@@ -126,6 +134,7 @@ func TestPodGroupBackoffTime(t *testing.T) {
 				pgMgr:            pgMgr,
 				scheduleTimeout:  &scheduleDuration,
 				pgBackoff:        pointer.Duration(1 * time.Second),
+				evaluator:        preemption.NewEvaluator(Name, f, nil, false),
 			}
 
 			informerFactory.Start(ctx.Done())
@@ -193,7 +202,9 @@ func TestLess(t *testing.T) {
 		p1   *framework.QueuedPodInfo
 		p2   *framework.QueuedPodInfo
 		pgs  []*v1alpha1.PodGroup
-		want bool
+		// assignedPods are the pods assigned for the PodGroups.
+		assignedPods []*v1.Pod
+		want         bool
 	}{
 		{
 			name: "p1.priority less than p2.priority",
@@ -401,6 +412,27 @@ func TestLess(t *testing.T) {
 			},
 			want: true,
 		},
+		{
+			name: "equal priority, members beyond the minMember of elastic pg1 keep the queue order of pg1",
+			p1: &framework.QueuedPodInfo{
+				PodInfo: tu.MustNewPodInfo(t, st.MakePod().Name("p1").Namespace("ns1").Priority(highPriority).
+					Label(v1alpha1.PodGroupLabel, "pg1").Obj()),
+				InitialAttemptTimestamp: ptrTime(now.Add(time.Second * 1)),
+			},
+			p2: &framework.QueuedPodInfo{
+				PodInfo: tu.MustNewPodInfo(t, st.MakePod().Name("p2").Namespace("ns2").Priority(highPriority).
+					Label(v1alpha1.PodGroupLabel, "pg2").Obj()),
+				InitialAttemptTimestamp: ptrTime(now.Add(time.Second * 2)),
+			},
+			pgs: []*v1alpha1.PodGroup{
+				tu.MakePodGroup().Name("pg1").Namespace("ns1").MinMember(1).MaxMember(3).Time(now.Add(time.Second * 1)).Obj(),
+				tu.MakePodGroup().Name("pg2").Namespace("ns2").MinMember(1).Time(now.Add(time.Second * 2)).Obj(),
+			},
+			assignedPods: []*v1.Pod{
+				st.MakePod().Name("p1-0").Namespace("ns1").Node("node").Label(v1alpha1.PodGroupLabel, "pg1").Obj(),
+			},
+			want: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -422,12 +454,17 @@ func TestLess(t *testing.T) {
 			informerFactory := informers.NewSharedInformerFactory(cs, 0)
 			podInformer := informerFactory.Core().V1().Pods()
 
-			pl := &Coscheduling{pgMgr: core.NewPodGroupManager(client, nil, nil, podInformer)}
+			pgMgr := core.NewPodGroupManager(client, nil, nil, podInformer)
+			pl := &Coscheduling{pgMgr: pgMgr}
 
 			informerFactory.Start(ctx.Done())
 			if !clicache.WaitForCacheSync(ctx.Done(), podInformer.Informer().HasSynced) {
 				t.Fatal("WaitForCacheSync failed")
 			}
+			addFunc := core.AddPodFactory(pgMgr)
+			for _, p := range tt.assignedPods {
+				addFunc(p)
+			}
 
 			if got := pl.Less(tt.p1, tt.p2); got != tt.want {
 				t.Errorf("Want %v, got %v", tt.want, got)
@@ -535,6 +572,9 @@ func TestPermit(t *testing.T) {
 }
 
 func TestPostFilter(t *testing.T) {
+	// Initialize scheduler metrics
+	metrics.Register()
+
 	scheduleTimeout := 10 * time.Second
 	capacity := map[v1.ResourceName]string{
 		v1.ResourceCPU: "4",
@@ -547,11 +587,17 @@ func TestPostFilter(t *testing.T) {
 	nodeStatusReader.Set("node", fwk.NewStatus(fwk.Success, ""))
 
 	tests := []struct {
-		name         string
-		pod          *v1.Pod
-		existingPods []*v1.Pod
-		pgs          []*v1alpha1.PodGroup
-		want         *fwk.Status
+		name              string
+		pod               *v1.Pod
+		existingPods      []*v1.Pod
+		pgs               []*v1alpha1.PodGroup
+		pdbs              []*policy.PodDisruptionBudget
+		nominatedPods     []*v1.Pod
+		preempting        bool
+		want              *fwk.Status
+		wantDeleted       []string
+		wantNominatedNode string
+		wantCleared       []string
 	}{
 		{
 			name: "pod does not belong to any pod group",
@@ -586,6 +632,103 @@ func TestPostFilter(t *testing.T) {
 				"PodGroup ns/pg1 gets rejected due to Pod p is unschedulable even after PostFilter",
 			),
 		},
+		{
+			name: "lower priority PodGroup is preempted",
+			pod:  st.MakePod().Name("p").Namespace("ns").UID("p").Priority(100).Label(v1alpha1.PodGroupLabel, "pg1").Obj(),
+			existingPods: []*v1.Pod{
+				st.MakePod().Name("p2").Namespace("ns").UID("p2").Node("node").Priority(10).Label(v1alpha1.PodGroupLabel, "pg2").
+					Req(map[v1.ResourceName]string{v1.ResourceCPU: "2"}).Obj(),
+				st.MakePod().Name("p3").Namespace("ns").UID("p3").Node("node").Priority(10).Label(v1alpha1.PodGroupLabel, "pg2").
+					Req(map[v1.ResourceName]string{v1.ResourceCPU: "2"}).Obj(),
+			},
+			pgs: []*v1alpha1.PodGroup{
+				tu.MakePodGroup().Name("pg1").Namespace("ns").MinMember(2).
+					MinResources(map[v1.ResourceName]string{v1.ResourceCPU: "4"}).Obj(),
+				tu.MakePodGroup().Name("pg2").Namespace("ns").MinMember(2).Obj(),
+			},
+			want:              fwk.NewStatus(fwk.Success, "PodGroup ns/pg1 preempts 2 lower priority pods"),
+			wantDeleted:       []string{"p2", "p3"},
+			wantNominatedNode: "node",
+		},
+		{
+			name: "lower priority pods nominated to the chosen node lose their nomination",
+			pod:  st.MakePod().Name("p").Namespace("ns").UID("p").Priority(100).Label(v1alpha1.PodGroupLabel, "pg1").Obj(),
+			existingPods: []*v1.Pod{
+				st.MakePod().Name("p2").Namespace("ns").UID("p2").Node("node").Priority(10).Label(v1alpha1.PodGroupLabel, "pg2").
+					Req(map[v1.ResourceName]string{v1.ResourceCPU: "4"}).Obj(),
+			},
+			nominatedPods: []*v1.Pod{
+				st.MakePod().Name("n1").Namespace("ns").UID("n1").Priority(50).NominatedNodeName("node").Obj(),
+				st.MakePod().Name("n2").Namespace("ns").UID("n2").Priority(200).NominatedNodeName("node").Obj(),
+			},
+			pgs: []*v1alpha1.PodGroup{
+				tu.MakePodGroup().Name("pg1").Namespace("ns").MinMember(2).
+					MinResources(map[v1.ResourceName]string{v1.ResourceCPU: "4"}).Obj(),
+				tu.MakePodGroup().Name("pg2").Namespace("ns").MinMember(1).Obj(),
+			},
+			want:              fwk.NewStatus(fwk.Success, "PodGroup ns/pg1 preempts 1 lower priority pods"),
+			wantDeleted:       []string{"p2"},
+			wantNominatedNode: "node",
+			wantCleared:       []string{"n1"},
+		},
+		{
+			name: "PodGroup which already preempted in this attempt does not preempt again",
+			pod:  st.MakePod().Name("p").Namespace("ns").UID("p").Priority(100).Label(v1alpha1.PodGroupLabel, "pg1").Obj(),
+			existingPods: []*v1.Pod{
+				st.MakePod().Name("p2").Namespace("ns").UID("p2").Node("node").Priority(10).Label(v1alpha1.PodGroupLabel, "pg2").
+					Req(map[v1.ResourceName]string{v1.ResourceCPU: "4"}).Obj(),
+			},
+			pgs: []*v1alpha1.PodGroup{
+				tu.MakePodGroup().Name("pg1").Namespace("ns").MinMember(2).
+					MinResources(map[v1.ResourceName]string{v1.ResourceCPU: "4"}).Obj(),
+				tu.MakePodGroup().Name("pg2").Namespace("ns").MinMember(1).Obj(),
+			},
+			preempting: true,
+			want:       fwk.NewStatus(fwk.Unschedulable, "PodGroup ns/pg1 is waiting for the pods it preempted to be gone"),
+		},
+		{
+			name: "lower priority PodGroup protected by a PodDisruptionBudget is not preempted",
+			pod:  st.MakePod().Name("p").Namespace("ns").UID("p").Priority(100).Label(v1alpha1.PodGroupLabel, "pg1").Obj(),
+			existingPods: []*v1.Pod{
+				st.MakePod().Name("p2").Namespace("ns").UID("p2").Node("node").Priority(10).Label(v1alpha1.PodGroupLabel, "pg2").
+					Req(map[v1.ResourceName]string{v1.ResourceCPU: "4"}).Obj(),
+			},
+			pgs: []*v1alpha1.PodGroup{
+				tu.MakePodGroup().Name("pg1").Namespace("ns").MinMember(2).
+					MinResources(map[v1.ResourceName]string{v1.ResourceCPU: "4"}).Obj(),
+				tu.MakePodGroup().Name("pg2").Namespace("ns").MinMember(1).Obj(),
+			},
+			pdbs: []*policy.PodDisruptionBudget{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "pdb", Namespace: "ns"},
+					Spec: policy.PodDisruptionBudgetSpec{
+						Selector: &metav1.LabelSelector{MatchLabels: map[string]string{v1alpha1.PodGroupLabel: "pg2"}},
+					},
+				},
+			},
+			want: fwk.NewStatus(
+				fwk.Unschedulable,
+				"PodGroup ns/pg1 gets rejected due to Pod p is unschedulable even after PostFilter",
+			),
+		},
+		{
+			name: "pod with PreemptNever policy does not preempt",
+			pod: st.MakePod().Name("p").Namespace("ns").UID("p").Priority(100).PreemptionPolicy(v1.PreemptNever).
+				Label(v1alpha1.PodGroupLabel, "pg1").Obj(),
+			existingPods: []*v1.Pod{
+				st.MakePod().Name("p2").Namespace("ns").UID("p2").Node("node").Priority(10).Label(v1alpha1.PodGroupLabel, "pg2").
+					Req(map[v1.ResourceName]string{v1.ResourceCPU: "4"}).Obj(),
+			},
+			pgs: []*v1alpha1.PodGroup{
+				tu.MakePodGroup().Name("pg1").Namespace("ns").MinMember(2).
+					MinResources(map[v1.ResourceName]string{v1.ResourceCPU: "4"}).Obj(),
+				tu.MakePodGroup().Name("pg2").Namespace("ns").MinMember(1).Obj(),
+			},
+			want: fwk.NewStatus(
+				fwk.Unschedulable,
+				"PodGroup ns/pg1 gets rejected due to Pod p is unschedulable even after PostFilter",
+			),
+		},
 	}
 
 	for _, tt := range tests {
@@ -607,6 +750,21 @@ func TestPostFilter(t *testing.T) {
 				t.Fatal(err)
 			}
 
+			var clientObjs []runtime.Object
+			for _, pod := range tt.existingPods {
+				clientObjs = append(clientObjs, pod)
+			}
+			for _, pdb := range tt.pdbs {
+				clientObjs = append(clientObjs, pdb)
+			}
+			for _, pod := range tt.nominatedPods {
+				clientObjs = append(clientObjs, pod)
+			}
+			cs := clientsetfake.NewSimpleClientset(clientObjs...)
+			informerFactory := informers.NewSharedInformerFactory(cs, 0)
+			podInformer := informerFactory.Core().V1().Pods()
+			snapshot := tu.NewFakeSharedLister(tt.existingPods, nodes)
+
 			// Compose a fake framework handle.
 			registeredPlugins := []tf.RegisterPluginFunc{
 				tf.RegisterQueueSortPlugin(queuesort.Name, queuesort.New),
@@ -616,18 +774,20 @@ func TestPostFilter(t *testing.T) {
 				ctx,
 				registeredPlugins,
 				"default-scheduler",
+				fwkruntime.WithClientSet(cs),
+				fwkruntime.WithInformerFactory(informerFactory),
+				fwkruntime.WithSnapshotSharedLister(snapshot),
+				fwkruntime.WithEventRecorder(&events.FakeRecorder{}),
+				fwkruntime.WithPodNominator(tu.NewPodNominator(nil)),
 				fwkruntime.WithWaitingPods(fwkruntime.NewWaitingPodsMap()),
 			)
 			if err != nil {
 				t.Fatal(err)
 			}
 
-			cs := clientsetfake.NewSimpleClientset()
-			informerFactory := informers.NewSharedInformerFactory(cs, 0)
-			podInformer := informerFactory.Core().V1().Pods()
 			pgMgr := core.NewPodGroupManager(
 				client,
-				tu.NewFakeSharedLister(tt.existingPods, nodes),
+				snapshot,
 				&scheduleTimeout,
 				podInformer,
 			)
@@ -635,23 +795,180 @@ func TestPostFilter(t *testing.T) {
 				frameworkHandler: f,
 				pgMgr:            pgMgr,
 				scheduleTimeout:  &scheduleTimeout,
+				evaluator:        preemption.NewEvaluator(Name, f, nil, false),
 			}
 
 			informerFactory.Start(ctx.Done())
-			if !clicache.WaitForCacheSync(ctx.Done(), podInformer.Informer().HasSynced) {
-				t.Fatal("WaitForCacheSync failed")
-			}
+			informerFactory.WaitForCacheSync(ctx.Done())
 			addFunc := core.AddPodFactory(pgMgr)
 			for _, p := range tt.existingPods {
 				podInformer.Informer().GetStore().Add(p)
 				// we call add func here because we can not ensure existing pods are added before premit are called
 				addFunc(p)
 			}
+			nominated := sets.New[string]()
+			for _, p := range tt.nominatedPods {
+				f.AddNominatedPod(klog.FromContext(ctx), tu.MustNewPodInfo(t, p), &framework.NominatingInfo{NominatingMode: framework.ModeNoop})
+				nominated.Insert(p.Name)
+			}
+			if tt.preempting {
+				pgMgr.PreemptPodGroup("ns/pg1")
+			}
 
-			_, got := pl.PostFilter(ctx, framework.NewCycleState(), tt.pod, nodeStatusReader)
+			result, got := pl.PostFilter(ctx, framework.NewCycleState(), tt.pod, nodeStatusReader)
 			if !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("Want %v, but got %v", tt.want, got)
 			}
+			var nominatedNode string
+			if result != nil && result.NominatingInfo != nil {
+				nominatedNode = result.NominatingInfo.NominatedNodeName
+			}
+			if nominatedNode != tt.wantNominatedNode {
+				t.Errorf("Want nominated node %q, but got %q", tt.wantNominatedNode, nominatedNode)
+			}
+			var deleted, disrupted, cleared []string
+			for _, action := range cs.Actions() {
+				switch {
+				case action.GetVerb() == "delete":
+					deleted = append(deleted, action.(clienttesting.DeleteAction).GetName())
+				case action.GetVerb() == "patch" && action.GetSubresource() == "status":
+					if name := action.(clienttesting.PatchAction).GetName(); nominated.Has(name) {
+						cleared = append(cleared, name)
+					} else {
+						disrupted = append(disrupted, name)
+					}
+				}
+			}
+			if diff := cmp.Diff(tt.wantCleared, cleared); diff != "" {
+				t.Errorf("Unexpected pods losing their nomination (-want,+got):\n%s", diff)
+			}
+			sort.Strings(deleted)
+			if diff := cmp.Diff(tt.wantDeleted, deleted); diff != "" {
+				t.Errorf("Unexpected preempted pods (-want,+got):\n%s", diff)
+			}
+			// The victims get the DisruptionTarget condition before they are deleted.
+			sort.Strings(disrupted)
+			if diff := cmp.Diff(tt.wantDeleted, disrupted); diff != "" {
+				t.Errorf("Unexpected disrupted pods (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestTopologyFilterAndScore(t *testing.T) {
+	scheduleTimeout := 10 * time.Second
+	zoneKey := "topology.kubernetes.io/zone"
+	nodes := []*v1.Node{
+		st.MakeNode().Name("node-a1").Label(zoneKey, "a").Capacity(map[v1.ResourceName]string{v1.ResourceCPU: "2"}).Obj(),
+		st.MakeNode().Name("node-a2").Label(zoneKey, "a").Capacity(map[v1.ResourceName]string{v1.ResourceCPU: "2"}).Obj(),
+		st.MakeNode().Name("node-b1").Label(zoneKey, "b").Capacity(map[v1.ResourceName]string{v1.ResourceCPU: "6"}).Obj(),
+		st.MakeNode().Name("node-c1").Capacity(map[v1.ResourceName]string{v1.ResourceCPU: "8"}).Obj(),
+	}
+	pod := st.MakePod().Name("p").Namespace("ns").UID("p").Label(v1alpha1.PodGroupLabel, "pg").Obj()
+
+	tests := []struct {
+		name         string
+		pg           *v1alpha1.PodGroup
+		existingPods []*v1.Pod
+		wantFilter   map[string]fwk.Code
+		wantScore    map[string]int64
+	}{
+		{
+			name: "PodGroup without topology policy",
+			pg:   tu.MakePodGroup().Name("pg").Namespace("ns").MinMember(1).Obj(),
+			wantFilter: map[string]fwk.Code{
+				"node-a1": fwk.Success, "node-a2": fwk.Success, "node-b1": fwk.Success, "node-c1": fwk.Success,
+			},
+		},
+		{
+			name: "required policy without domain chosen only allows the domains fitting the PodGroup",
+			pg: tu.MakePodGroup().Name("pg").Namespace("ns").MinMember(1).
+				MinResources(map[v1.ResourceName]string{v1.ResourceCPU: "5"}).
+				TopologyPolicy(zoneKey, v1alpha1.TopologyModeRequired).Obj(),
+			wantFilter: map[string]fwk.Code{
+				"node-a1": fwk.Unschedulable, "node-a2": fwk.Unschedulable,
+				"node-b1": fwk.Success, "node-c1": fwk.UnschedulableAndUnresolvable,
+			},
+			wantScore: map[string]int64{"node-a1": 0, "node-a2": 0, "node-b1": 100, "node-c1": 0},
+		},
+		{
+			name: "required policy only allows the domain of the assigned members",
+			pg: tu.MakePodGroup().Name("pg").Namespace("ns").MinMember(1).
+				TopologyPolicy(zoneKey, v1alpha1.TopologyModeRequired).Obj(),
+			existingPods: []*v1.Pod{
+				st.MakePod().Name("p1").Namespace("ns").UID("p1").Node("node-a1").Label(v1alpha1.PodGroupLabel, "pg").Obj(),
+			},
+			wantFilter: map[string]fwk.Code{
+				"node-a1": fwk.Success, "node-a2": fwk.Success,
+				"node-b1": fwk.UnschedulableAndUnresolvable, "node-c1": fwk.UnschedulableAndUnresolvable,
+			},
+			wantScore: map[string]int64{"node-a1": 100, "node-a2": 100, "node-b1": 0, "node-c1": 0},
+		},
+		{
+			name: "preferred policy favors the domain of the assigned members",
+			pg: tu.MakePodGroup().Name("pg").Namespace("ns").MinMember(1).
+				TopologyPolicy(zoneKey, v1alpha1.TopologyModePreferred).Obj(),
+			existingPods: []*v1.Pod{
+				st.MakePod().Name("p1").Namespace("ns").UID("p1").Node("node-b1").Label(v1alpha1.PodGroupLabel, "pg").Obj(),
+			},
+			wantFilter: map[string]fwk.Code{
+				"node-a1": fwk.Success, "node-a2": fwk.Success, "node-b1": fwk.Success, "node-c1": fwk.Success,
+			},
+			wantScore: map[string]int64{"node-a1": 0, "node-a2": 0, "node-b1": 100, "node-c1": 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			client, err := tu.NewFakeClient(tt.pg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			cs := clientsetfake.NewSimpleClientset()
+			informerFactory := informers.NewSharedInformerFactory(cs, 0)
+			podInformer := informerFactory.Core().V1().Pods()
+			snapshot := tu.NewFakeSharedLister(tt.existingPods, nodes)
+			pl := &Coscheduling{
+				pgMgr:           core.NewPodGroupManager(client, snapshot, &scheduleTimeout, podInformer),
+				scheduleTimeout: &scheduleTimeout,
+			}
+
+			informerFactory.Start(ctx.Done())
+			if !clicache.WaitForCacheSync(ctx.Done(), podInformer.Informer().HasSynced) {
+				t.Fatal("WaitForCacheSync failed")
+			}
+			podInformer.Informer().GetStore().Add(pod)
+
+			nodeInfos, _ := snapshot.NodeInfos().List()
+			state := framework.NewCycleState()
+			if _, status := pl.PreFilter(ctx, state, pod, nodeInfos); !status.IsSuccess() {
+				t.Fatalf("Unexpected PreFilter status: %v", status)
+			}
+			for _, nodeInfo := range nodeInfos {
+				if got := pl.Filter(ctx, state, pod, nodeInfo).Code(); got != tt.wantFilter[nodeInfo.Node().Name] {
+					t.Errorf("Node %v: want Filter %v, but got %v", nodeInfo.Node().Name, tt.wantFilter[nodeInfo.Node().Name], got)
+				}
+			}
+
+			status := pl.PreScore(ctx, state, pod, nodeInfos)
+			if tt.wantScore == nil {
+				if status.Code() != fwk.Skip {
+					t.Errorf("Want PreScore to skip, but got %v", status)
+				}
+				return
+			}
+			for _, nodeInfo := range nodeInfos {
+				score, status := pl.Score(ctx, state, pod, nodeInfo)
+				if !status.IsSuccess() {
+					t.Fatalf("Unexpected Score status: %v", status)
+				}
+				if score != tt.wantScore[nodeInfo.Node().Name] {
+					t.Errorf("Node %v: want score %v, but got %v", nodeInfo.Node().Name, tt.wantScore[nodeInfo.Node().Name], score)
+				}
+			}
 		})
 	}
 }
@@ -0,0 +1,140 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diskio
+
+import (
+	"encoding/json"
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const (
+	// ThroughputAnnotation is the pod annotation holding the disk IO throughput needed by the pod,
+	// e.g. {"rbps": "20M", "wbps": "30M", "blocksize": "4k"}.
+	ThroughputAnnotation = "blockio.kubernetes.io/throughput"
+	// AllocatableAnnotation is the node annotation holding the normalized disk IO bandwidth the node
+	// can allocate to pods, e.g. {"read": "1100M", "write": "1100M", "total": "2200M"}.
+	// It is maintained by the IO driver of the node.
+	AllocatableAnnotation = "blockio.kubernetes.io/allocatable"
+)
+
+// throughput is the value of the ThroughputAnnotation.
+type throughput struct {
+	Rbps      string `json:"rbps,omitempty"`
+	Wbps      string `json:"wbps,omitempty"`
+	BlockSize string `json:"blocksize,omitempty"`
+}
+
+// allocatable is the value of the AllocatableAnnotation.
+type allocatable struct {
+	Read  string `json:"read,omitempty"`
+	Write string `json:"write,omitempty"`
+	Total string `json:"total,omitempty"`
+}
+
+// bandwidth is a disk IO bandwidth in bytes per second.
+type bandwidth struct {
+	read  int64
+	write int64
+	total int64
+}
+
+func (b bandwidth) add(o bandwidth) bandwidth {
+	return bandwidth{read: b.read + o.read, write: b.write + o.write, total: b.total + o.total}
+}
+
+// fits checks whether b fits into the available bandwidth in every dimension.
+func (b bandwidth) fits(available bandwidth) bool {
+	return b.read <= available.read && b.write <= available.write && b.total <= available.total
+}
+
+func (b bandwidth) String() string {
+	return fmt.Sprintf("read=%d, write=%d, total=%d", b.read, b.write, b.total)
+}
+
+// podRequest returns the disk IO bandwidth needed by the pod, and false if the pod doesn't have the
+// ThroughputAnnotation. The requested bandwidth is not normalized against the disk model, i.e. the
+// block size is validated but doesn't change the request.
+func podRequest(pod *v1.Pod) (bandwidth, bool, error) {
+	value, ok := pod.Annotations[ThroughputAnnotation]
+	if !ok {
+		return bandwidth{}, false, nil
+	}
+	var t throughput
+	if err := json.Unmarshal([]byte(value), &t); err != nil {
+		return bandwidth{}, true, fmt.Errorf("failed to parse annotation %q: %w", ThroughputAnnotation, err)
+	}
+
+	var req bandwidth
+	var err error
+	if req.read, err = parseBandwidth("rbps", t.Rbps); err != nil {
+		return bandwidth{}, true, err
+	}
+	if req.write, err = parseBandwidth("wbps", t.Wbps); err != nil {
+		return bandwidth{}, true, err
+	}
+	if _, err = parseBandwidth("blocksize", t.BlockSize); err != nil {
+		return bandwidth{}, true, err
+	}
+	req.total = req.read + req.write
+	return req, true, nil
+}
+
+// nodeAllocatable returns the disk IO bandwidth the node can allocate to pods, and false if the node
+// doesn't have the AllocatableAnnotation. The total defaults to the sum of read and write.
+func nodeAllocatable(node *v1.Node) (bandwidth, bool, error) {
+	value, ok := node.Annotations[AllocatableAnnotation]
+	if !ok {
+		return bandwidth{}, false, nil
+	}
+	var a allocatable
+	if err := json.Unmarshal([]byte(value), &a); err != nil {
+		return bandwidth{}, true, fmt.Errorf("failed to parse annotation %q: %w", AllocatableAnnotation, err)
+	}
+
+	var alloc bandwidth
+	var err error
+	if alloc.read, err = parseBandwidth("read", a.Read); err != nil {
+		return bandwidth{}, true, err
+	}
+	if alloc.write, err = parseBandwidth("write", a.Write); err != nil {
+		return bandwidth{}, true, err
+	}
+	if len(a.Total) == 0 {
+		alloc.total = alloc.read + alloc.write
+	} else if alloc.total, err = parseBandwidth("total", a.Total); err != nil {
+		return bandwidth{}, true, err
+	}
+	return alloc, true, nil
+}
+
+// parseBandwidth parses a quantity like "30M" into bytes, an empty value is zero.
+func parseBandwidth(name, value string) (int64, error) {
+	if len(value) == 0 {
+		return 0, nil
+	}
+	q, err := resource.ParseQuantity(value)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse %s %q: %w", name, value, err)
+	}
+	if q.Sign() < 0 {
+		return 0, fmt.Errorf("%s %q must not be negative", name, value)
+	}
+	return q.Value(), nil
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diskio
+
+import (
+	"sync"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// reservationCache keeps track of the disk IO bandwidth of the pods bound or reserved on each node.
+type reservationCache struct {
+	sync.RWMutex
+	// nodes maps a node name to the bandwidth of each of its pods, keyed by pod UID.
+	nodes map[string]map[types.UID]bandwidth
+}
+
+func newReservationCache() *reservationCache {
+	return &reservationCache{
+		nodes: make(map[string]map[types.UID]bandwidth),
+	}
+}
+
+// reserve records the bandwidth of the pod on the node. Reserving an already reserved pod
+// overwrites its bandwidth, so that a pod reserved by the scheduler and later observed as
+// bound by the informer is only accounted once.
+func (c *reservationCache) reserve(nodeName string, uid types.UID, req bandwidth) {
+	c.Lock()
+	defer c.Unlock()
+	pods, ok := c.nodes[nodeName]
+	if !ok {
+		pods = make(map[types.UID]bandwidth)
+		c.nodes[nodeName] = pods
+	}
+	pods[uid] = req
+}
+
+// unreserve forgets the bandwidth of the pod on the node.
+func (c *reservationCache) unreserve(nodeName string, uid types.UID) {
+	c.Lock()
+	defer c.Unlock()
+	pods, ok := c.nodes[nodeName]
+	if !ok {
+		return
+	}
+	delete(pods, uid)
+	if len(pods) == 0 {
+		delete(c.nodes, nodeName)
+	}
+}
+
+// reserved returns the total bandwidth of the pods bound or reserved on the node.
+func (c *reservationCache) reserved(nodeName string) bandwidth {
+	return c.reservedWithout(nodeName, nil)
+}
+
+// reservedWithout returns the total bandwidth of the pods bound or reserved on the node, but the skipped pods.
+func (c *reservationCache) reservedWithout(nodeName string, skipped sets.Set[types.UID]) bandwidth {
+	c.RLock()
+	defer c.RUnlock()
+	var total bandwidth
+	for uid, req := range c.nodes[nodeName] {
+		if !skipped.Has(uid) {
+			total = total.add(req)
+		}
+	}
+	return total
+}
@@ -0,0 +1,376 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diskio
+
+import (
+	"context"
+	"fmt"
+	"maps"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+	fwk "k8s.io/kube-scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"sigs.k8s.io/scheduler-plugins/apis/config"
+	"sigs.k8s.io/scheduler-plugins/apis/config/validation"
+)
+
+const (
+	// Name is the name of the plugin used in the Registry and configurations.
+	Name = "DiskIO"
+
+	stateKey = Name + ".request"
+
+	// ErrReasonNoAllocatable is the reason for a node that doesn't report its disk IO bandwidth.
+	ErrReasonNoAllocatable = "node(s) didn't report disk IO allocatable bandwidth"
+	// ErrReasonInsufficient is the reason for a node that doesn't have enough disk IO bandwidth left.
+	ErrReasonInsufficient = "node(s) didn't have enough disk IO bandwidth"
+)
+
+// DiskIO is a plugin that filters and scores nodes by their available disk IO bandwidth,
+// as described in KEP 624. Pods declare the bandwidth they need with the ThroughputAnnotation,
+// and the IO driver of each node reports its allocatable bandwidth with the AllocatableAnnotation.
+type DiskIO struct {
+	logger klog.Logger
+	handle framework.Handle
+	args   *config.DiskIOArgs
+	cache  *reservationCache
+}
+
+var _ framework.PreFilterPlugin = &DiskIO{}
+var _ framework.PreFilterExtensions = &DiskIO{}
+var _ framework.FilterPlugin = &DiskIO{}
+var _ framework.PreScorePlugin = &DiskIO{}
+var _ framework.ScorePlugin = &DiskIO{}
+var _ framework.ReservePlugin = &DiskIO{}
+var _ framework.EnqueueExtensions = &DiskIO{}
+
+// stateData holds the disk IO bandwidth needed by the pod being scheduled, and the pods the
+// preemption adds to or removes from each node while it evaluates the victims.
+type stateData struct {
+	request bandwidth
+	// added maps a node name to the bandwidth of each pod added to it, keyed by pod UID.
+	added map[string]map[types.UID]bandwidth
+	// removed maps a node name to the UIDs of the pods removed from it.
+	removed map[string]sets.Set[types.UID]
+}
+
+// Clone the state data, so that the pods added or removed for a node by the preemption don't leak
+// into the state of the other nodes.
+func (s *stateData) Clone() fwk.StateData {
+	c := &stateData{
+		request: s.request,
+		added:   make(map[string]map[types.UID]bandwidth, len(s.added)),
+		removed: make(map[string]sets.Set[types.UID], len(s.removed)),
+	}
+	for nodeName, pods := range s.added {
+		c.added[nodeName] = maps.Clone(pods)
+	}
+	for nodeName, uids := range s.removed {
+		c.removed[nodeName] = uids.Clone()
+	}
+	return c
+}
+
+// reserved returns the total bandwidth of the pods bound or reserved on the node, accounting for
+// the pods added or removed by the preemption.
+func (s *stateData) reserved(c *reservationCache, nodeName string) bandwidth {
+	added := s.added[nodeName]
+	skipped := s.removed[nodeName].Clone()
+	for uid := range added {
+		skipped.Insert(uid)
+	}
+	total := c.reservedWithout(nodeName, skipped)
+	for _, req := range added {
+		total = total.add(req)
+	}
+	return total
+}
+
+// New initializes a new plugin and returns it.
+func New(ctx context.Context, obj runtime.Object, handle framework.Handle) (framework.Plugin, error) {
+	logger := klog.FromContext(ctx).WithValues("plugin", Name)
+
+	args, ok := obj.(*config.DiskIOArgs)
+	if !ok {
+		return nil, fmt.Errorf("want args to be of type DiskIOArgs, got %T", obj)
+	}
+	if err := validation.ValidateDiskIOArgs(args, nil); err != nil {
+		return nil, fmt.Errorf("invalid DiskIOArgs: %w", err)
+	}
+
+	d := &DiskIO{
+		logger: logger,
+		handle: handle,
+		args:   args,
+		cache:  newReservationCache(),
+	}
+
+	podInformer := handle.SharedInformerFactory().Core().V1().Pods().Informer()
+	podInformer.AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: func(obj interface{}) bool {
+			switch t := obj.(type) {
+			case *v1.Pod:
+				return len(t.Spec.NodeName) != 0
+			case cache.DeletedFinalStateUnknown:
+				if pod, ok := t.Obj.(*v1.Pod); ok {
+					return len(pod.Spec.NodeName) != 0
+				}
+				return false
+			default:
+				return false
+			}
+		},
+		Handler: cache.ResourceEventHandlerFuncs{
+			AddFunc:    d.addPod,
+			UpdateFunc: d.updatePod,
+			DeleteFunc: d.deletePod,
+		},
+	})
+
+	logger.V(5).Info("DiskIO start", "scoringStrategy", args.ScoringStrategy)
+	return d, nil
+}
+
+// Name returns the name of the plugin.
+func (d *DiskIO) Name() string {
+	return Name
+}
+
+// EventsToRegister returns the events that may make a pod rejected by this plugin schedulable.
+func (d *DiskIO) EventsToRegister(_ context.Context) ([]fwk.ClusterEventWithHint, error) {
+	return []fwk.ClusterEventWithHint{
+		{Event: fwk.ClusterEvent{Resource: fwk.Pod, ActionType: fwk.Delete}},
+		{Event: fwk.ClusterEvent{Resource: fwk.Node, ActionType: fwk.Add | fwk.UpdateNodeAnnotation}},
+	}, nil
+}
+
+// PreFilter parses the disk IO bandwidth needed by the pod. Pods without the ThroughputAnnotation
+// skip the Filter and Score of this plugin.
+func (d *DiskIO) PreFilter(ctx context.Context, state fwk.CycleState, pod *v1.Pod, nodes []fwk.NodeInfo) (*framework.PreFilterResult, *fwk.Status) {
+	req, ok, err := podRequest(pod)
+	if err != nil {
+		return nil, fwk.NewStatus(fwk.UnschedulableAndUnresolvable, err.Error())
+	}
+	if !ok {
+		return nil, fwk.NewStatus(fwk.Skip)
+	}
+	state.Write(stateKey, &stateData{
+		request: req,
+		added:   make(map[string]map[types.UID]bandwidth),
+		removed: make(map[string]sets.Set[types.UID]),
+	})
+	return nil, nil
+}
+
+// PreFilterExtensions returns the plugin itself, to account for the pods added or removed during
+// preemption.
+func (d *DiskIO) PreFilterExtensions() framework.PreFilterExtensions {
+	return d
+}
+
+// AddPod accounts the disk IO bandwidth of the pod added to the node during preemption.
+func (d *DiskIO) AddPod(ctx context.Context, state fwk.CycleState, podToSchedule *v1.Pod, podToAdd fwk.PodInfo, nodeInfo fwk.NodeInfo) *fwk.Status {
+	s, err := getStateData(state)
+	if err != nil {
+		return fwk.AsStatus(err)
+	}
+	pod := podToAdd.GetPod()
+	req, ok, err := podRequest(pod)
+	if err != nil || !ok {
+		return nil
+	}
+	nodeName := nodeInfo.Node().Name
+	s.removed[nodeName].Delete(pod.UID)
+	if s.added[nodeName] == nil {
+		s.added[nodeName] = make(map[types.UID]bandwidth)
+	}
+	s.added[nodeName][pod.UID] = req
+	return nil
+}
+
+// RemovePod releases the disk IO bandwidth of the pod removed from the node during preemption,
+// so that the bandwidth of the victims is available to the preemptor.
+func (d *DiskIO) RemovePod(ctx context.Context, state fwk.CycleState, podToSchedule *v1.Pod, podToRemove fwk.PodInfo, nodeInfo fwk.NodeInfo) *fwk.Status {
+	s, err := getStateData(state)
+	if err != nil {
+		return fwk.AsStatus(err)
+	}
+	pod := podToRemove.GetPod()
+	nodeName := nodeInfo.Node().Name
+	delete(s.added[nodeName], pod.UID)
+	if s.removed[nodeName] == nil {
+		s.removed[nodeName] = sets.New[types.UID]()
+	}
+	s.removed[nodeName].Insert(pod.UID)
+	return nil
+}
+
+// Filter rejects the nodes which don't have enough disk IO bandwidth left for the pod.
+func (d *DiskIO) Filter(ctx context.Context, state fwk.CycleState, pod *v1.Pod, nodeInfo fwk.NodeInfo) *fwk.Status {
+	logger := klog.FromContext(klog.NewContext(ctx, d.logger)).WithValues("ExtensionPoint", "Filter")
+	s, err := getStateData(state)
+	if err != nil {
+		return fwk.AsStatus(err)
+	}
+	node := nodeInfo.Node()
+	alloc, ok, err := nodeAllocatable(node)
+	if err != nil {
+		logger.V(4).Info("Invalid disk IO allocatable", "node", klog.KObj(node), "err", err)
+		return fwk.NewStatus(fwk.UnschedulableAndUnresolvable, ErrReasonNoAllocatable)
+	}
+	if !ok {
+		return fwk.NewStatus(fwk.UnschedulableAndUnresolvable, ErrReasonNoAllocatable)
+	}
+
+	requested := s.reserved(d.cache, node.Name).add(s.request)
+	if !requested.fits(alloc) {
+		logger.V(6).Info("Insufficient disk IO bandwidth", "pod", klog.KObj(pod), "node", klog.KObj(node),
+			"requested", requested, "allocatable", alloc)
+		return fwk.NewStatus(fwk.Unschedulable, ErrReasonInsufficient)
+	}
+	return nil
+}
+
+// PreScore skips the Score of this plugin for the pods without the ThroughputAnnotation.
+func (d *DiskIO) PreScore(ctx context.Context, state fwk.CycleState, pod *v1.Pod, nodes []fwk.NodeInfo) *fwk.Status {
+	if _, err := getStateData(state); err != nil {
+		return fwk.NewStatus(fwk.Skip)
+	}
+	return nil
+}
+
+// Score scores the node by its disk IO utilization once the pod is placed on it. With the
+// LeastAllocated strategy the least utilized nodes score the highest, balancing the IO load
+// across nodes, while MostAllocated packs the IO load on the fewest nodes.
+func (d *DiskIO) Score(ctx context.Context, state fwk.CycleState, pod *v1.Pod, nodeInfo fwk.NodeInfo) (int64, *fwk.Status) {
+	logger := klog.FromContext(klog.NewContext(ctx, d.logger)).WithValues("ExtensionPoint", "Score")
+	s, err := getStateData(state)
+	if err != nil {
+		return 0, fwk.AsStatus(err)
+	}
+	node := nodeInfo.Node()
+	alloc, ok, err := nodeAllocatable(node)
+	if err != nil || !ok {
+		return framework.MinNodeScore, nil
+	}
+
+	requested := s.reserved(d.cache, node.Name).add(s.request)
+	score := d.score(requested, alloc)
+	logger.V(6).Info("Score", "pod", klog.KObj(pod), "node", klog.KObj(node), "score", score)
+	return score, nil
+}
+
+// ScoreExtensions of the Score plugin.
+func (d *DiskIO) ScoreExtensions() framework.ScoreExtensions {
+	return nil
+}
+
+// score averages the score of every dimension of the allocatable bandwidth.
+func (d *DiskIO) score(requested, alloc bandwidth) int64 {
+	var sum, count int64
+	for _, dim := range [][2]int64{
+		{requested.read, alloc.read},
+		{requested.write, alloc.write},
+		{requested.total, alloc.total},
+	} {
+		if dim[1] == 0 {
+			continue
+		}
+		utilization := min(dim[0], dim[1]) * framework.MaxNodeScore / dim[1]
+		if d.args.ScoringStrategy == config.DiskIOMostAllocated {
+			sum += utilization
+		} else {
+			sum += framework.MaxNodeScore - utilization
+		}
+		count++
+	}
+	if count == 0 {
+		return framework.MinNodeScore
+	}
+	return sum / count
+}
+
+// Reserve accounts the disk IO bandwidth of the pod to the node.
+func (d *DiskIO) Reserve(ctx context.Context, state fwk.CycleState, pod *v1.Pod, nodeName string) *fwk.Status {
+	s, err := getStateData(state)
+	if err != nil {
+		// The pod doesn't need any disk IO bandwidth.
+		return nil
+	}
+	d.cache.reserve(nodeName, pod.UID, s.request)
+	return nil
+}
+
+// Unreserve releases the disk IO bandwidth of the pod from the node.
+func (d *DiskIO) Unreserve(ctx context.Context, state fwk.CycleState, pod *v1.Pod, nodeName string) {
+	d.cache.unreserve(nodeName, pod.UID)
+}
+
+func (d *DiskIO) addPod(obj interface{}) {
+	pod := obj.(*v1.Pod)
+	if pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed {
+		d.cache.unreserve(pod.Spec.NodeName, pod.UID)
+		return
+	}
+	req, ok, err := podRequest(pod)
+	if err != nil {
+		d.logger.V(4).Info("Invalid disk IO request", "pod", klog.KObj(pod), "err", err)
+		return
+	}
+	if !ok {
+		return
+	}
+	d.cache.reserve(pod.Spec.NodeName, pod.UID, req)
+}
+
+func (d *DiskIO) updatePod(oldObj, newObj interface{}) {
+	d.addPod(newObj)
+}
+
+func (d *DiskIO) deletePod(obj interface{}) {
+	var pod *v1.Pod
+	switch t := obj.(type) {
+	case *v1.Pod:
+		pod = t
+	case cache.DeletedFinalStateUnknown:
+		var ok bool
+		if pod, ok = t.Obj.(*v1.Pod); !ok {
+			return
+		}
+	default:
+		return
+	}
+	d.cache.unreserve(pod.Spec.NodeName, pod.UID)
+}
+
+func getStateData(state fwk.CycleState) (*stateData, error) {
+	c, err := state.Read(stateKey)
+	if err != nil {
+		return nil, err
+	}
+	s, ok := c.(*stateData)
+	if !ok {
+		return nil, fmt.Errorf("%+v  convert to diskio.stateData error", c)
+	}
+	return s, nil
+}
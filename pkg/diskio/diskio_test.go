@@ -0,0 +1,423 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diskio
+
+import (
+	"context"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+	fwk "k8s.io/kube-scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+
+	"sigs.k8s.io/scheduler-plugins/apis/config"
+	tu "sigs.k8s.io/scheduler-plugins/test/util"
+)
+
+func makePod(name, throughput string) *v1.Pod {
+	pod := st.MakePod().Name(name).UID(name).Namespace("default").Obj()
+	if len(throughput) != 0 {
+		pod.Annotations = map[string]string{ThroughputAnnotation: throughput}
+	}
+	return pod
+}
+
+func makeNode(name, allocatable string) *v1.Node {
+	node := st.MakeNode().Name(name).Obj()
+	if len(allocatable) != 0 {
+		node.Annotations = map[string]string{AllocatableAnnotation: allocatable}
+	}
+	return node
+}
+
+func newDiskIO(strategy config.DiskIOScoringStrategy) *DiskIO {
+	return &DiskIO{
+		logger: klog.Background(),
+		args:   &config.DiskIOArgs{ScoringStrategy: strategy},
+		cache:  newReservationCache(),
+	}
+}
+
+func TestPodRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		pod     *v1.Pod
+		want    bandwidth
+		wantOK  bool
+		wantErr bool
+	}{
+		{
+			name: "no annotation",
+			pod:  makePod("p", ""),
+		},
+		{
+			name:   "read and write",
+			pod:    makePod("p", `{"rbps": "20M", "wbps": "30M", "blocksize": "4k"}`),
+			want:   bandwidth{read: 20_000_000, write: 30_000_000, total: 50_000_000},
+			wantOK: true,
+		},
+		{
+			name:   "read only",
+			pod:    makePod("p", `{"rbps": "1Mi"}`),
+			want:   bandwidth{read: 1 << 20, total: 1 << 20},
+			wantOK: true,
+		},
+		{
+			name:    "invalid json",
+			pod:     makePod("p", `{"rbps": 20`),
+			wantOK:  true,
+			wantErr: true,
+		},
+		{
+			name:    "invalid quantity",
+			pod:     makePod("p", `{"rbps": "fast"}`),
+			wantOK:  true,
+			wantErr: true,
+		},
+		{
+			name:    "negative quantity",
+			pod:     makePod("p", `{"wbps": "-1M"}`),
+			wantOK:  true,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok, err := podRequest(tt.pod)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if ok != tt.wantOK {
+				t.Errorf("expected ok %v, got %v", tt.wantOK, ok)
+			}
+			if got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestNodeAllocatable(t *testing.T) {
+	tests := []struct {
+		name    string
+		node    *v1.Node
+		want    bandwidth
+		wantOK  bool
+		wantErr bool
+	}{
+		{
+			name: "no annotation",
+			node: makeNode("n", ""),
+		},
+		{
+			name:   "read, write and total",
+			node:   makeNode("n", `{"read": "1100M", "write": "1100M", "total": "2000M"}`),
+			want:   bandwidth{read: 1_100_000_000, write: 1_100_000_000, total: 2_000_000_000},
+			wantOK: true,
+		},
+		{
+			name:   "total defaults to read and write",
+			node:   makeNode("n", `{"read": "100M", "write": "50M"}`),
+			want:   bandwidth{read: 100_000_000, write: 50_000_000, total: 150_000_000},
+			wantOK: true,
+		},
+		{
+			name:    "invalid quantity",
+			node:    makeNode("n", `{"read": "100M", "write": "a lot"}`),
+			wantOK:  true,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok, err := nodeAllocatable(tt.node)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if ok != tt.wantOK {
+				t.Errorf("expected ok %v, got %v", tt.wantOK, ok)
+			}
+			if got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestPreFilter(t *testing.T) {
+	tests := []struct {
+		name string
+		pod  *v1.Pod
+		want fwk.Code
+	}{
+		{
+			name: "pod without disk IO request is skipped",
+			pod:  makePod("p", ""),
+			want: fwk.Skip,
+		},
+		{
+			name: "pod with disk IO request",
+			pod:  makePod("p", `{"rbps": "20M"}`),
+			want: fwk.Success,
+		},
+		{
+			name: "pod with invalid disk IO request",
+			pod:  makePod("p", `{"rbps": "fast"}`),
+			want: fwk.UnschedulableAndUnresolvable,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newDiskIO(config.DiskIOLeastAllocated)
+			_, status := d.PreFilter(context.Background(), framework.NewCycleState(), tt.pod, nil)
+			if got := status.Code(); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestFilter(t *testing.T) {
+	tests := []struct {
+		name     string
+		pod      *v1.Pod
+		node     *v1.Node
+		reserved map[types.UID]bandwidth
+		want     fwk.Code
+	}{
+		{
+			name: "node without allocatable",
+			pod:  makePod("p", `{"rbps": "20M", "wbps": "20M"}`),
+			node: makeNode("n", ""),
+			want: fwk.UnschedulableAndUnresolvable,
+		},
+		{
+			name: "node with invalid allocatable",
+			pod:  makePod("p", `{"rbps": "20M", "wbps": "20M"}`),
+			node: makeNode("n", `{"read": "fast"}`),
+			want: fwk.UnschedulableAndUnresolvable,
+		},
+		{
+			name: "enough bandwidth",
+			pod:  makePod("p", `{"rbps": "20M", "wbps": "20M"}`),
+			node: makeNode("n", `{"read": "100M", "write": "100M"}`),
+			want: fwk.Success,
+		},
+		{
+			name: "not enough read bandwidth",
+			pod:  makePod("p", `{"rbps": "120M"}`),
+			node: makeNode("n", `{"read": "100M", "write": "100M"}`),
+			want: fwk.Unschedulable,
+		},
+		{
+			name: "not enough total bandwidth",
+			pod:  makePod("p", `{"rbps": "60M", "wbps": "60M"}`),
+			node: makeNode("n", `{"read": "100M", "write": "100M", "total": "100M"}`),
+			want: fwk.Unschedulable,
+		},
+		{
+			name: "not enough bandwidth left by the reserved pods",
+			pod:  makePod("p", `{"rbps": "20M", "wbps": "20M"}`),
+			node: makeNode("n", `{"read": "100M", "write": "100M"}`),
+			reserved: map[types.UID]bandwidth{
+				"other": {read: 90_000_000, total: 90_000_000},
+			},
+			want: fwk.Unschedulable,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			d := newDiskIO(config.DiskIOLeastAllocated)
+			for uid, req := range tt.reserved {
+				d.cache.reserve(tt.node.Name, uid, req)
+			}
+			state := framework.NewCycleState()
+			if _, status := d.PreFilter(ctx, state, tt.pod, nil); !status.IsSuccess() {
+				t.Fatalf("unexpected PreFilter status: %v", status)
+			}
+			nodeInfo := framework.NewNodeInfo()
+			nodeInfo.SetNode(tt.node)
+			if got := d.Filter(ctx, state, tt.pod, nodeInfo).Code(); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestScore(t *testing.T) {
+	pod := makePod("p", `{"rbps": "20M", "wbps": "20M"}`)
+	idle := makeNode("idle", `{"read": "100M", "write": "100M"}`)
+	busy := makeNode("busy", `{"read": "100M", "write": "100M"}`)
+	unknown := makeNode("unknown", "")
+
+	tests := []struct {
+		strategy config.DiskIOScoringStrategy
+		want     map[string]int64
+	}{
+		{
+			strategy: config.DiskIOLeastAllocated,
+			want:     map[string]int64{"idle": 80, "busy": 20, "unknown": framework.MinNodeScore},
+		},
+		{
+			strategy: config.DiskIOMostAllocated,
+			want:     map[string]int64{"idle": 20, "busy": 80, "unknown": framework.MinNodeScore},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.strategy), func(t *testing.T) {
+			ctx := context.Background()
+			d := newDiskIO(tt.strategy)
+			d.cache.reserve("busy", "other", bandwidth{read: 60_000_000, write: 60_000_000, total: 120_000_000})
+
+			state := framework.NewCycleState()
+			if _, status := d.PreFilter(ctx, state, pod, nil); !status.IsSuccess() {
+				t.Fatalf("unexpected PreFilter status: %v", status)
+			}
+			if status := d.PreScore(ctx, state, pod, nil); !status.IsSuccess() {
+				t.Fatalf("unexpected PreScore status: %v", status)
+			}
+			for _, node := range []*v1.Node{idle, busy, unknown} {
+				nodeInfo := framework.NewNodeInfo()
+				nodeInfo.SetNode(node)
+				score, status := d.Score(ctx, state, pod, nodeInfo)
+				if !status.IsSuccess() {
+					t.Fatalf("unexpected Score status: %v", status)
+				}
+				if score != tt.want[node.Name] {
+					t.Errorf("node %s: expected score %d, got %d", node.Name, tt.want[node.Name], score)
+				}
+			}
+		})
+	}
+}
+
+func TestPreScoreSkipsPodsWithoutRequest(t *testing.T) {
+	ctx := context.Background()
+	d := newDiskIO(config.DiskIOLeastAllocated)
+	pod := makePod("p", "")
+	state := framework.NewCycleState()
+	d.PreFilter(ctx, state, pod, nil)
+	if got := d.PreScore(ctx, state, pod, nil).Code(); got != fwk.Skip {
+		t.Errorf("expected %v, got %v", fwk.Skip, got)
+	}
+}
+
+func TestPreFilterExtensions(t *testing.T) {
+	ctx := context.Background()
+	d := newDiskIO(config.DiskIOLeastAllocated)
+	victim := makePod("victim", `{"rbps": "90M"}`)
+	d.cache.reserve("n", victim.UID, bandwidth{read: 90_000_000, total: 90_000_000})
+	pod := makePod("p", `{"rbps": "20M"}`)
+	state := framework.NewCycleState()
+	if _, status := d.PreFilter(ctx, state, pod, nil); !status.IsSuccess() {
+		t.Fatalf("unexpected PreFilter status: %v", status)
+	}
+	nodeInfo := framework.NewNodeInfo()
+	nodeInfo.SetNode(makeNode("n", `{"read": "100M", "write": "100M"}`))
+	if got := d.Filter(ctx, state, pod, nodeInfo).Code(); got != fwk.Unschedulable {
+		t.Fatalf("expected %v, got %v", fwk.Unschedulable, got)
+	}
+
+	// The preemption removes the victim from a clone of the state.
+	victimInfo := tu.MustNewPodInfo(t, victim)
+	simulated := state.Clone()
+	if status := d.PreFilterExtensions().RemovePod(ctx, simulated, pod, victimInfo, nodeInfo); !status.IsSuccess() {
+		t.Fatalf("unexpected RemovePod status: %v", status)
+	}
+	if got := d.Filter(ctx, simulated, pod, nodeInfo).Code(); got != fwk.Success {
+		t.Errorf("expected %v once the victim is removed, got %v", fwk.Success, got)
+	}
+	if got := d.Filter(ctx, state, pod, nodeInfo).Code(); got != fwk.Unschedulable {
+		t.Errorf("expected %v in the original state, got %v", fwk.Unschedulable, got)
+	}
+
+	// Adding the victim back, e.g. when it is reprieved, consumes its bandwidth again.
+	if status := d.PreFilterExtensions().AddPod(ctx, simulated, pod, victimInfo, nodeInfo); !status.IsSuccess() {
+		t.Fatalf("unexpected AddPod status: %v", status)
+	}
+	if got := d.Filter(ctx, simulated, pod, nodeInfo).Code(); got != fwk.Unschedulable {
+		t.Errorf("expected %v once the victim is added back, got %v", fwk.Unschedulable, got)
+	}
+}
+
+func TestReserveAndUnreserve(t *testing.T) {
+	ctx := context.Background()
+	d := newDiskIO(config.DiskIOLeastAllocated)
+	pod := makePod("p", `{"rbps": "20M", "wbps": "10M"}`)
+	state := framework.NewCycleState()
+	if _, status := d.PreFilter(ctx, state, pod, nil); !status.IsSuccess() {
+		t.Fatalf("unexpected PreFilter status: %v", status)
+	}
+
+	if status := d.Reserve(ctx, state, pod, "n"); !status.IsSuccess() {
+		t.Fatalf("unexpected Reserve status: %v", status)
+	}
+	want := bandwidth{read: 20_000_000, write: 10_000_000, total: 30_000_000}
+	if got := d.cache.reserved("n"); got != want {
+		t.Errorf("expected %v reserved, got %v", want, got)
+	}
+
+	// The informer observing the bound pod doesn't account it twice.
+	bound := pod.DeepCopy()
+	bound.Spec.NodeName = "n"
+	d.addPod(bound)
+	if got := d.cache.reserved("n"); got != want {
+		t.Errorf("expected %v reserved, got %v", want, got)
+	}
+
+	d.Unreserve(ctx, state, pod, "n")
+	if got := d.cache.reserved("n"); got != (bandwidth{}) {
+		t.Errorf("expected nothing reserved, got %v", got)
+	}
+}
+
+func TestPodEvents(t *testing.T) {
+	d := newDiskIO(config.DiskIOLeastAllocated)
+	pod := makePod("p", `{"rbps": "20M"}`)
+	pod.Spec.NodeName = "n"
+	want := bandwidth{read: 20_000_000, total: 20_000_000}
+
+	d.addPod(pod)
+	if got := d.cache.reserved("n"); got != want {
+		t.Errorf("expected %v reserved, got %v", want, got)
+	}
+
+	succeeded := pod.DeepCopy()
+	succeeded.Status.Phase = v1.PodSucceeded
+	d.updatePod(pod, succeeded)
+	if got := d.cache.reserved("n"); got != (bandwidth{}) {
+		t.Errorf("expected nothing reserved for a terminated pod, got %v", got)
+	}
+
+	d.addPod(pod)
+	d.deletePod(pod)
+	if got := d.cache.reserved("n"); got != (bandwidth{}) {
+		t.Errorf("expected nothing reserved for a deleted pod, got %v", got)
+	}
+
+	d.addPod(pod)
+	d.deletePod(cache.DeletedFinalStateUnknown{Key: "default/p", Obj: pod})
+	if got := d.cache.reserved("n"); got != (bandwidth{}) {
+		t.Errorf("expected nothing reserved for a deleted pod tombstone, got %v", got)
+	}
+	d.deletePod(cache.DeletedFinalStateUnknown{Key: "default/p", Obj: "unexpected"})
+}
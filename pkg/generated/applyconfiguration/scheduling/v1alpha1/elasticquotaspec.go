@@ -20,13 +20,16 @@ package v1alpha1
 
 import (
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/client-go/applyconfigurations/meta/v1"
 )
 
 // ElasticQuotaSpecApplyConfiguration represents a declarative configuration of the ElasticQuotaSpec type for use
 // with apply.
 type ElasticQuotaSpecApplyConfiguration struct {
-	Min *v1.ResourceList `json:"min,omitempty"`
-	Max *v1.ResourceList `json:"max,omitempty"`
+	Min               *v1.ResourceList                        `json:"min,omitempty"`
+	Max               *v1.ResourceList                        `json:"max,omitempty"`
+	Parent            *string                                 `json:"parent,omitempty"`
+	NamespaceSelector *metav1.LabelSelectorApplyConfiguration `json:"namespaceSelector,omitempty"`
 }
 
 // ElasticQuotaSpecApplyConfiguration constructs a declarative configuration of the ElasticQuotaSpec type for use with
@@ -50,3 +53,19 @@ func (b *ElasticQuotaSpecApplyConfiguration) WithMax(value v1.ResourceList) *Ela
 	b.Max = &value
 	return b
 }
+
+// WithParent sets the Parent field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Parent field is set to the value of the last call.
+func (b *ElasticQuotaSpecApplyConfiguration) WithParent(value string) *ElasticQuotaSpecApplyConfiguration {
+	b.Parent = &value
+	return b
+}
+
+// WithNamespaceSelector sets the NamespaceSelector field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the NamespaceSelector field is set to the value of the last call.
+func (b *ElasticQuotaSpecApplyConfiguration) WithNamespaceSelector(value *metav1.LabelSelectorApplyConfiguration) *ElasticQuotaSpecApplyConfiguration {
+	b.NamespaceSelector = value
+	return b
+}
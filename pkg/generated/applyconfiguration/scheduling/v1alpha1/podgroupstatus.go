@@ -20,18 +20,22 @@ package v1alpha1
 
 import (
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metav1 "k8s.io/client-go/applyconfigurations/meta/v1"
 	schedulingv1alpha1 "sigs.k8s.io/scheduler-plugins/apis/scheduling/v1alpha1"
 )
 
 // PodGroupStatusApplyConfiguration represents a declarative configuration of the PodGroupStatus type for use
 // with apply.
 type PodGroupStatusApplyConfiguration struct {
-	Phase             *schedulingv1alpha1.PodGroupPhase `json:"phase,omitempty"`
-	OccupiedBy        *string                           `json:"occupiedBy,omitempty"`
-	Running           *int32                            `json:"running,omitempty"`
-	Succeeded         *int32                            `json:"succeeded,omitempty"`
-	Failed            *int32                            `json:"failed,omitempty"`
-	ScheduleStartTime *v1.Time                          `json:"scheduleStartTime,omitempty"`
+	Phase             *schedulingv1alpha1.PodGroupPhase    `json:"phase,omitempty"`
+	OccupiedBy        *string                              `json:"occupiedBy,omitempty"`
+	Running           *int32                               `json:"running,omitempty"`
+	Succeeded         *int32                               `json:"succeeded,omitempty"`
+	Failed            *int32                               `json:"failed,omitempty"`
+	ScheduleStartTime *v1.Time                             `json:"scheduleStartTime,omitempty"`
+	Desired           *int32                               `json:"desired,omitempty"`
+	Scheduled         *int32                               `json:"scheduled,omitempty"`
+	Conditions        []metav1.ConditionApplyConfiguration `json:"conditions,omitempty"`
 }
 
 // PodGroupStatusApplyConfiguration constructs a declarative configuration of the PodGroupStatus type for use with
@@ -87,3 +91,32 @@ func (b *PodGroupStatusApplyConfiguration) WithScheduleStartTime(value v1.Time)
 	b.ScheduleStartTime = &value
 	return b
 }
+
+// WithDesired sets the Desired field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Desired field is set to the value of the last call.
+func (b *PodGroupStatusApplyConfiguration) WithDesired(value int32) *PodGroupStatusApplyConfiguration {
+	b.Desired = &value
+	return b
+}
+
+// WithScheduled sets the Scheduled field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Scheduled field is set to the value of the last call.
+func (b *PodGroupStatusApplyConfiguration) WithScheduled(value int32) *PodGroupStatusApplyConfiguration {
+	b.Scheduled = &value
+	return b
+}
+
+// WithConditions adds the given value to the Conditions field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the Conditions field.
+func (b *PodGroupStatusApplyConfiguration) WithConditions(values ...*metav1.ConditionApplyConfiguration) *PodGroupStatusApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithConditions")
+		}
+		b.Conditions = append(b.Conditions, *values[i])
+	}
+	return b
+}
@@ -40,10 +40,14 @@ func ForKind(kind schema.GroupVersionKind) interface{} {
 		return &schedulingv1alpha1.ElasticQuotaStatusApplyConfiguration{}
 	case v1alpha1.SchemeGroupVersion.WithKind("PodGroup"):
 		return &schedulingv1alpha1.PodGroupApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("PodGroupRole"):
+		return &schedulingv1alpha1.PodGroupRoleApplyConfiguration{}
 	case v1alpha1.SchemeGroupVersion.WithKind("PodGroupSpec"):
 		return &schedulingv1alpha1.PodGroupSpecApplyConfiguration{}
 	case v1alpha1.SchemeGroupVersion.WithKind("PodGroupStatus"):
 		return &schedulingv1alpha1.PodGroupStatusApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("TopologyPolicy"):
+		return &schedulingv1alpha1.TopologyPolicyApplyConfiguration{}
 
 	}
 	return nil
@@ -0,0 +1,129 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networkoverhead
+
+import (
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	ntv1alpha1 "github.com/diktyo-io/networktopology-api/pkg/apis/networktopology/v1alpha1"
+
+	networkawareutil "sigs.k8s.io/scheduler-plugins/pkg/networkaware/util"
+)
+
+// networkLink : directed link between two domains of a topology key of the NetworkTopology, e.g. two zones
+type networkLink struct {
+	TopologyKey ntv1alpha1.TopologyKey
+	networkawareutil.CostKey
+}
+
+// bandwidthLedger keeps track of the bandwidth reserved by pods on each link, in bytes per second.
+type bandwidthLedger struct {
+	sync.RWMutex
+	// pods maps a pod UID to the bandwidth it reserved on each link.
+	pods map[types.UID]map[networkLink]int64
+	// links maps a link to the total bandwidth reserved on it.
+	links map[networkLink]int64
+	// pending maps a pod UID to a bound pod whose bandwidth is not in the ledger yet,
+	// e.g. a pod scheduled before a restart of the scheduler.
+	pending map[types.UID]*corev1.Pod
+}
+
+func newBandwidthLedger() *bandwidthLedger {
+	return &bandwidthLedger{
+		pods:    make(map[types.UID]map[networkLink]int64),
+		links:   make(map[networkLink]int64),
+		pending: make(map[types.UID]*corev1.Pod),
+	}
+}
+
+// reserve records the bandwidth of the pod on each link. Reserving an already reserved pod
+// overwrites its bandwidth, so that it is only accounted once.
+func (l *bandwidthLedger) reserve(uid types.UID, bandwidth map[networkLink]int64) {
+	l.Lock()
+	defer l.Unlock()
+	l.release(uid)
+	l.add(uid, bandwidth)
+}
+
+// unreserve forgets the bandwidth of the pod.
+func (l *bandwidthLedger) unreserve(uid types.UID) {
+	l.Lock()
+	defer l.Unlock()
+	l.release(uid)
+}
+
+// track records a bound pod to be restored, unless its bandwidth is already reserved.
+func (l *bandwidthLedger) track(pod *corev1.Pod) {
+	l.Lock()
+	defer l.Unlock()
+	if _, ok := l.pods[pod.UID]; !ok {
+		l.pending[pod.UID] = pod
+	}
+}
+
+// pendingPods returns the pods tracked to be restored.
+func (l *bandwidthLedger) pendingPods() []*corev1.Pod {
+	l.RLock()
+	defer l.RUnlock()
+	pods := make([]*corev1.Pod, 0, len(l.pending))
+	for _, pod := range l.pending {
+		pods = append(pods, pod)
+	}
+	return pods
+}
+
+// restore records the bandwidth of a tracked pod, unless it was released since.
+func (l *bandwidthLedger) restore(uid types.UID, bandwidth map[networkLink]int64) {
+	l.Lock()
+	defer l.Unlock()
+	if _, ok := l.pending[uid]; !ok {
+		return
+	}
+	delete(l.pending, uid)
+	if len(bandwidth) != 0 {
+		l.add(uid, bandwidth)
+	}
+}
+
+func (l *bandwidthLedger) add(uid types.UID, bandwidth map[networkLink]int64) {
+	reserved := make(map[networkLink]int64, len(bandwidth))
+	for link, b := range bandwidth {
+		reserved[link] = b
+		l.links[link] += b
+	}
+	l.pods[uid] = reserved
+}
+
+func (l *bandwidthLedger) release(uid types.UID) {
+	for link, b := range l.pods[uid] {
+		if l.links[link] -= b; l.links[link] <= 0 {
+			delete(l.links, link)
+		}
+	}
+	delete(l.pods, uid)
+	delete(l.pending, uid)
+}
+
+// reserved returns the total bandwidth reserved on the link.
+func (l *bandwidthLedger) reserved(link networkLink) int64 {
+	l.RLock()
+	defer l.RUnlock()
+	return l.links[link]
+}
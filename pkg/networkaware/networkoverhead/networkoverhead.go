@@ -23,17 +23,20 @@ import (
 	"sort"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
 	fwk "k8s.io/kube-scheduler/framework"
 	"k8s.io/kubernetes/pkg/scheduler/framework"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	pluginconfig "sigs.k8s.io/scheduler-plugins/apis/config"
+	"sigs.k8s.io/scheduler-plugins/apis/config/validation"
 	networkawareutil "sigs.k8s.io/scheduler-plugins/pkg/networkaware/util"
 	"sigs.k8s.io/scheduler-plugins/pkg/util"
 
@@ -44,6 +47,7 @@ import (
 var _ framework.PreFilterPlugin = &NetworkOverhead{}
 var _ framework.FilterPlugin = &NetworkOverhead{}
 var _ framework.ScorePlugin = &NetworkOverhead{}
+var _ framework.ReservePlugin = &NetworkOverhead{}
 
 const (
 	// Name : name of plugin used in the plugin registry and configurations.
@@ -55,7 +59,7 @@ const (
 	// SameHostname : If pods belong to the same host, then consider cost as 0
 	SameHostname = 0
 
-	// SameZone : If pods belong to hosts in the same zone, then consider cost as 1
+	// SameZone : If pods belong to hosts in the same finest domain (e.g. zone), then consider cost as 1
 	SameZone = 1
 
 	// preFilterStateKey is the key in CycleState to NetworkOverhead pre-computed data.
@@ -64,6 +68,9 @@ const (
 
 var scheme = runtime.NewScheme()
 
+// defaultTopologyKeys : topology keys used when none are configured, from the finest to the coarsest
+var defaultTopologyKeys = []string{corev1.LabelTopologyZone, corev1.LabelTopologyRegion}
+
 func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 
@@ -71,15 +78,17 @@ func init() {
 	utilruntime.Must(ntv1alpha1.AddToScheme(scheme))
 }
 
-// NetworkOverhead : Filter and Score nodes based on Pod's AppGroup requirements: MaxNetworkCosts requirements among Pods with dependencies
+// NetworkOverhead : Filter and Score nodes based on Pod's AppGroup requirements: MaxNetworkCosts and MinBandwidth requirements among Pods with dependencies
 type NetworkOverhead struct {
 	client.Client
-	logger      klog.Logger
-	podLister   corelisters.PodLister
-	handle      framework.Handle
-	namespaces  []string
-	weightsName string
-	ntName      string
+	logger       klog.Logger
+	podLister    corelisters.PodLister
+	handle       framework.Handle
+	namespaces   []string
+	weightsName  string
+	ntName       string
+	topologyKeys []string
+	ledger       *bandwidthLedger
 }
 
 // PreFilterState computed at PreFilter and used at Filter and Score.
@@ -103,7 +112,7 @@ type PreFilterState struct {
 	scheduledList networkawareutil.ScheduledList
 
 	// node map for cost / destinations. Search for requirements faster...
-	nodeCostMap map[string]map[networkawareutil.CostKey]int64
+	nodeCostMap map[string]map[networkLink]int64
 
 	// node map for satisfied dependencies
 	satisfiedMap map[string]int64
@@ -113,6 +122,12 @@ type PreFilterState struct {
 
 	// node map for costs
 	finalCostMap map[string]int64
+
+	// node map for the bandwidth requested on each link to the pods of the dependencies
+	bandwidthMap map[string]map[networkLink]int64
+
+	// bandwidth of each link not allocated in the NetworkTopology, only for links with a known capacity
+	availableBandwidth map[networkLink]int64
 }
 
 // Clone the preFilter state.
@@ -148,20 +163,37 @@ func New(ctx context.Context, obj runtime.Object, handle framework.Handle) (fram
 	if err != nil {
 		return nil, err
 	}
+	if err := validation.ValidateNetworkOverheadArgs(args, nil); err != nil {
+		return nil, err
+	}
+	topologyKeys := args.TopologyKeys
+	if len(topologyKeys) == 0 {
+		topologyKeys = defaultTopologyKeys
+	}
 	c, _, err := util.NewClientWithCachedReader(ctx, handle.KubeConfig(), scheme)
 	if err != nil {
 		return nil, err
 	}
 
 	no := &NetworkOverhead{
-		Client:      c,
-		logger:      logger,
-		podLister:   handle.SharedInformerFactory().Core().V1().Pods().Lister(),
-		handle:      handle,
-		namespaces:  args.Namespaces,
-		weightsName: args.WeightsName,
-		ntName:      args.NetworkTopologyName,
+		Client:       c,
+		logger:       logger,
+		podLister:    handle.SharedInformerFactory().Core().V1().Pods().Lister(),
+		handle:       handle,
+		namespaces:   args.Namespaces,
+		weightsName:  args.WeightsName,
+		ntName:       args.NetworkTopologyName,
+		topologyKeys: topologyKeys,
+		ledger:       newBandwidthLedger(),
 	}
+
+	// Restore the bandwidth of pods bound without being reserved, e.g. before a restart of the scheduler,
+	// and release the bandwidth reserved by pods once they are deleted or terminated
+	handle.SharedInformerFactory().Core().V1().Pods().Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    no.addPod,
+		UpdateFunc: no.updatePod,
+		DeleteFunc: no.deletePod,
+	})
 	return no, nil
 }
 
@@ -232,36 +264,37 @@ func (no *NetworkOverhead) PreFilter(ctx context.Context, state fwk.CycleState,
 	}
 
 	// Create variables to fill PreFilterState
-	nodeCostMap := make(map[string]map[networkawareutil.CostKey]int64)
+	nodeCostMap := make(map[string]map[networkLink]int64)
 	satisfiedMap := make(map[string]int64)
 	violatedMap := make(map[string]int64)
 	finalCostMap := make(map[string]int64)
+	bandwidthMap := make(map[string]map[networkLink]int64)
 
 	// For each node:
-	// 1 - Get region and zone labels
+	// 1 - Get the domains of the node for each topology key
 	// 2 - Calculate satisfied and violated number of dependencies
 	// 3 - Calculate the final cost of the node to be used by the scoring plugin
+	// 4 - Calculate the bandwidth requested on each link to be checked by the filter plugin
 	for _, nodeInfo := range nodeList {
-		// retrieve region and zone labels
-		region := networkawareutil.GetNodeRegion(nodeInfo.Node())
-		zone := networkawareutil.GetNodeZone(nodeInfo.Node())
+		// retrieve the domains of the node, e.g. zone and region
+		domains := no.getNodeDomains(nodeInfo.Node())
 		logger.V(6).Info("Node info",
 			"name", nodeInfo.Node().Name,
-			"region", region,
-			"zone", zone)
+			"topologyKeys", no.topologyKeys,
+			"domains", domains)
 
 		// Create map for cost / destinations. Search for requirements faster...
-		costMap := make(map[networkawareutil.CostKey]int64)
+		costMap := make(map[networkLink]int64)
 
 		// Populate cost map for the given node
-		no.populateCostMap(costMap, networkTopology, region, zone)
+		no.populateCostMap(costMap, networkTopology, domains)
 		logger.V(6).Info("Map", "costMap", costMap)
 
 		// Update nodeCostMap
 		nodeCostMap[nodeInfo.Node().Name] = costMap
 
 		// Get Satisfied and Violated number of dependencies
-		satisfied, violated, ok := checkMaxNetworkCostRequirements(logger, scheduledList, dependencyList, nodeInfo, region, zone, costMap, no)
+		satisfied, violated, ok := checkMaxNetworkCostRequirements(logger, scheduledList, dependencyList, nodeInfo, domains, costMap, no)
 		if ok != nil {
 			return nil, fwk.NewStatus(fwk.Error, fmt.Sprintf("pod hostname not found: %v", ok))
 		}
@@ -272,12 +305,22 @@ func (no *NetworkOverhead) PreFilter(ctx context.Context, state fwk.CycleState,
 		logger.V(6).Info("Number of dependencies", "satisfied", satisfied, "violated", violated)
 
 		// Get accumulated cost based on pod dependencies
-		cost, ok := no.getAccumulatedCost(scheduledList, dependencyList, nodeInfo.Node().Name, region, zone, costMap)
+		cost, ok := no.getAccumulatedCost(scheduledList, dependencyList, nodeInfo.Node().Name, domains, costMap)
 		if ok != nil {
 			return nil, fwk.NewStatus(fwk.Error, fmt.Sprintf("getting pod hostname from Snapshot: %v", ok))
 		}
 		logger.V(6).Info("Node final cost", "cost", cost)
 		finalCostMap[nodeInfo.Node().Name] = cost
+
+		// Get bandwidth requested on each link based on pod dependencies
+		bandwidth, ok := no.getRequestedBandwidth(scheduledList, dependencyList, nodeInfo.Node().Name, domains)
+		if ok != nil {
+			return nil, fwk.NewStatus(fwk.Error, fmt.Sprintf("getting pod hostname from Snapshot: %v", ok))
+		}
+		if len(bandwidth) != 0 {
+			logger.V(6).Info("Node requested bandwidth", "bandwidth", bandwidth)
+			bandwidthMap[nodeInfo.Node().Name] = bandwidth
+		}
 	}
 
 	// Update PreFilter State
@@ -292,6 +335,11 @@ func (no *NetworkOverhead) PreFilter(ctx context.Context, state fwk.CycleState,
 		satisfiedMap:    satisfiedMap,
 		violatedMap:     violatedMap,
 		finalCostMap:    finalCostMap,
+		bandwidthMap:    bandwidthMap,
+	}
+	if len(bandwidthMap) != 0 {
+		no.restoreReservations(ctx, logger)
+		preFilterState.availableBandwidth = no.getAvailableBandwidth(networkTopology)
 	}
 
 	state.Write(preFilterStateKey, preFilterState)
@@ -356,9 +404,126 @@ func (no *NetworkOverhead) Filter(ctx context.Context,
 		return fwk.NewStatus(fwk.Unschedulable,
 			fmt.Sprintf("Node %v does not meet several network requirements from Workload dependencies: Satisfied: %v Violated: %v", nodeInfo.Node().Name, satisfied, violated))
 	}
+
+	// The pod is filtered out if a link to the pods of its dependencies lacks the requested bandwidth
+	for link, requested := range preFilterState.bandwidthMap[nodeInfo.Node().Name] {
+		available, ok := preFilterState.availableBandwidth[link]
+		if !ok { // The capacity of the link is unknown
+			continue
+		}
+		remaining := available - no.ledger.reserved(link)
+		if requested > remaining {
+			return fwk.NewStatus(fwk.Unschedulable,
+				fmt.Sprintf("Node %v does not meet bandwidth requirements from Workload dependencies: Link: %v -> %v Requested: %v Remaining: %v",
+					nodeInfo.Node().Name, link.Origin, link.Destination,
+					resource.NewQuantity(requested, resource.BinarySI), resource.NewQuantity(max(remaining, 0), resource.BinarySI)))
+		}
+	}
 	return nil
 }
 
+// Reserve : reserve the bandwidth requested on the links between the node and the pods of the dependencies
+func (no *NetworkOverhead) Reserve(ctx context.Context,
+	cycleState fwk.CycleState,
+	pod *corev1.Pod,
+	nodeName string) *fwk.Status {
+	preFilterState, err := getPreFilterState(cycleState)
+	if err != nil || preFilterState.scoreEqually {
+		return nil
+	}
+	if bandwidth := preFilterState.bandwidthMap[nodeName]; len(bandwidth) != 0 {
+		no.ledger.reserve(pod.UID, bandwidth)
+	}
+	return nil
+}
+
+// Unreserve : release the bandwidth reserved for the pod
+func (no *NetworkOverhead) Unreserve(ctx context.Context,
+	cycleState fwk.CycleState,
+	pod *corev1.Pod,
+	nodeName string) {
+	no.ledger.unreserve(pod.UID)
+}
+
+// addPod : track a pod bound without being reserved, so that its bandwidth is restored
+func (no *NetworkOverhead) addPod(obj interface{}) {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok || pod.Spec.NodeName == "" || len(networkawareutil.GetPodAppGroupLabel(pod)) == 0 {
+		return
+	}
+	if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+		return
+	}
+	no.ledger.track(pod)
+}
+
+// restoreReservations : reserve the bandwidth of the tracked pods, as if they were reserved once the pods
+// of their dependencies were deployed. This is deferred from the pod informer to the scheduling cycle,
+// once the listers and the snapshot are synced.
+func (no *NetworkOverhead) restoreReservations(ctx context.Context, logger klog.Logger) {
+	for _, pod := range no.ledger.pendingPods() {
+		bandwidth, err := no.getPodBandwidth(ctx, pod)
+		if err != nil {
+			logger.V(4).Info("Cannot restore the bandwidth of the pod", "pod", klog.KObj(pod), "err", err)
+		} else if len(bandwidth) != 0 {
+			logger.V(5).Info("Restored the bandwidth of the pod", "pod", klog.KObj(pod), "bandwidth", bandwidth)
+		}
+		no.ledger.restore(pod.UID, bandwidth)
+	}
+}
+
+// getPodBandwidth : calculate the bandwidth requested by a bound pod on each link to the pods of its dependencies
+func (no *NetworkOverhead) getPodBandwidth(ctx context.Context, pod *corev1.Pod) (map[networkLink]int64, error) {
+	agName := networkawareutil.GetPodAppGroupLabel(pod)
+	appGroup := no.findAppGroupNetworkOverhead(ctx, agName)
+	if appGroup == nil {
+		return nil, fmt.Errorf("AppGroup %q not found", agName)
+	}
+	dependencyList := networkawareutil.GetDependencyList(pod, appGroup)
+	if dependencyList == nil {
+		return nil, nil
+	}
+	selector := labels.Set(map[string]string{agv1alpha1.AppGroupLabel: agName}).AsSelector()
+	pods, err := no.podLister.List(selector)
+	if err != nil {
+		return nil, err
+	}
+	nodeInfo, err := no.handle.SnapshotSharedLister().NodeInfos().Get(pod.Spec.NodeName)
+	if err != nil {
+		return nil, err
+	}
+	domains := no.getNodeDomains(nodeInfo.Node())
+	return no.getRequestedBandwidth(networkawareutil.GetScheduledList(pods), dependencyList, pod.Spec.NodeName, domains)
+}
+
+// updatePod : release the bandwidth reserved for a pod once it terminated
+func (no *NetworkOverhead) updatePod(oldObj, newObj interface{}) {
+	pod, ok := newObj.(*corev1.Pod)
+	if !ok {
+		return
+	}
+	if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+		no.ledger.unreserve(pod.UID)
+	}
+}
+
+// deletePod : release the bandwidth reserved for a deleted pod
+func (no *NetworkOverhead) deletePod(obj interface{}) {
+	var pod *corev1.Pod
+	switch t := obj.(type) {
+	case *corev1.Pod:
+		pod = t
+	case cache.DeletedFinalStateUnknown:
+		var ok bool
+		if pod, ok = t.Obj.(*corev1.Pod); !ok {
+			return
+		}
+	default:
+		return
+	}
+	no.ledger.unreserve(pod.UID)
+}
+
 // Score : evaluate score for a node
 func (no *NetworkOverhead) Score(ctx context.Context,
 	cycleState fwk.CycleState,
@@ -445,53 +610,72 @@ func (no *NetworkOverhead) sortNetworkTopologyCosts(networkTopology *ntv1alpha1.
 	}
 }
 
+// getNodeDomains : get the domains of the node for each topology key, from the finest to the coarsest
+func (no *NetworkOverhead) getNodeDomains(node *corev1.Node) []string {
+	domains := make([]string, len(no.topologyKeys))
+	for i, key := range no.topologyKeys {
+		domains[i] = node.Labels[key]
+		if domains[i] == "" && key == corev1.LabelHostname {
+			domains[i] = node.Name
+		}
+	}
+	return domains
+}
+
+// getNetworkLink : get the link between the domains of two nodes at the coarsest topology key where they differ.
+// Returns false if the nodes belong to the same domains for all topology keys.
+func (no *NetworkOverhead) getNetworkLink(domains []string, podDomains []string) (networkLink, bool) {
+	for i := len(no.topologyKeys) - 1; i >= 0; i-- {
+		if domains[i] != podDomains[i] {
+			return networkLink{
+				TopologyKey: ntv1alpha1.TopologyKey(no.topologyKeys[i]),
+				CostKey:     networkawareutil.CostKey{Origin: domains[i], Destination: podDomains[i]},
+			}, true
+		}
+	}
+	return networkLink{}, false
+}
+
+// hasDomains : check if the node belongs to a domain for any topology key
+func hasDomains(domains []string) bool {
+	for _, d := range domains {
+		if d != "" {
+			return true
+		}
+	}
+	return false
+}
+
 // populateCostMap : Populates costMap based on the node being filtered/scored
 func (no *NetworkOverhead) populateCostMap(
-	costMap map[networkawareutil.CostKey]int64,
+	costMap map[networkLink]int64,
 	networkTopology *ntv1alpha1.NetworkTopology,
-	region string,
-	zone string) {
+	domains []string) {
 	for _, w := range networkTopology.Spec.Weights { // Check the weights List
 		if w.Name != no.weightsName { // If it is not the Preferred algorithm, continue
 			continue
 		}
 
-		if region != "" { // Add Region Costs
-			// Binary search through CostList: find the Topology Key for region
-			topologyList := networkawareutil.FindTopologyKey(w.TopologyList, ntv1alpha1.NetworkTopologyRegion)
-
-			if no.weightsName != ntv1alpha1.NetworkTopologyNetperfCosts {
-				// Sort Costs by origin, might not be sorted since were manually defined
-				sort.Sort(networkawareutil.ByOrigin(topologyList))
-			}
-
-			// Binary search through TopologyList: find the costs for the given Region
-			costs := networkawareutil.FindOriginCosts(topologyList, region)
-
-			// Add Region Costs
-			for _, c := range costs {
-				costMap[networkawareutil.CostKey{ // Add the cost to the map
-					Origin:      region,
-					Destination: c.Destination}] = c.NetworkCost
+		for i, key := range no.topologyKeys { // Add the costs of each topology key
+			if domains[i] == "" {
+				continue
 			}
-		}
-		if zone != "" { // Add Zone Costs
-			// Binary search through CostList: find the Topology Key for zone
-			topologyList := networkawareutil.FindTopologyKey(w.TopologyList, ntv1alpha1.NetworkTopologyZone)
+			// Binary search through CostList: find the Topology Key
+			topologyList := networkawareutil.FindTopologyKey(w.TopologyList, ntv1alpha1.TopologyKey(key))
 
 			if no.weightsName != ntv1alpha1.NetworkTopologyNetperfCosts {
 				// Sort Costs by origin, might not be sorted since were manually defined
 				sort.Sort(networkawareutil.ByOrigin(topologyList))
 			}
 
-			// Binary search through TopologyList: find the costs for the given Region
-			costs := networkawareutil.FindOriginCosts(topologyList, zone)
+			// Binary search through TopologyList: find the costs for the given domain
+			costs := networkawareutil.FindOriginCosts(topologyList, domains[i])
 
-			// Add Zone Costs
+			// Add the costs
 			for _, c := range costs {
-				costMap[networkawareutil.CostKey{ // Add the cost to the map
-					Origin:      zone,
-					Destination: c.Destination}] = c.NetworkCost
+				costMap[networkLink{ // Add the cost to the map
+					TopologyKey: ntv1alpha1.TopologyKey(key),
+					CostKey:     networkawareutil.CostKey{Origin: domains[i], Destination: c.Destination}}] = c.NetworkCost
 			}
 		}
 	}
@@ -503,9 +687,8 @@ func checkMaxNetworkCostRequirements(
 	scheduledList networkawareutil.ScheduledList,
 	dependencyList []agv1alpha1.DependenciesInfo,
 	nodeInfo fwk.NodeInfo,
-	region string,
-	zone string,
-	costMap map[networkawareutil.CostKey]int64,
+	domains []string,
+	costMap map[networkLink]int64,
 	no *NetworkOverhead) (int64, int64, error) {
 	var satisfied int64 = 0
 	var violated int64 = 0
@@ -532,33 +715,15 @@ func checkMaxNetworkCostRequirements(
 					return satisfied, violated, err
 				}
 
-				// Get zone and region from Pod Hostname
-				regionPodNodeInfo := networkawareutil.GetNodeRegion(podNodeInfo.Node())
-				zonePodNodeInfo := networkawareutil.GetNodeZone(podNodeInfo.Node())
+				// Get the domains of the Pod hostname
+				podDomains := no.getNodeDomains(podNodeInfo.Node())
 
-				if regionPodNodeInfo == "" && zonePodNodeInfo == "" { // Node has no zone and region defined
+				if !hasDomains(podDomains) { // Node has no topology domain defined
 					violated += 1
-				} else if region == regionPodNodeInfo { // If Nodes belong to the same region
-					if zone == zonePodNodeInfo { // If Nodes belong to the same zone
-						satisfied += 1
-					} else { // belong to a different zone, check maxNetworkCost
-						cost, costOK := costMap[networkawareutil.CostKey{ // Retrieve the cost from the map (origin: zone, destination: pod zoneHostname)
-							Origin:      zone, // Time Complexity: O(1)
-							Destination: zonePodNodeInfo,
-						}]
-						if costOK {
-							if cost <= d.MaxNetworkCost {
-								satisfied += 1
-							} else {
-								violated += 1
-							}
-						}
-					}
-				} else { // belong to a different region
-					cost, costOK := costMap[networkawareutil.CostKey{ // Retrieve the cost from the map (origin: zone, destination: pod zoneHostname)
-						Origin:      region, // Time Complexity: O(1)
-						Destination: regionPodNodeInfo,
-					}]
+				} else if link, ok := no.getNetworkLink(domains, podDomains); !ok { // If Nodes belong to the same finest domain
+					satisfied += 1
+				} else { // belong to a different domain, check maxNetworkCost
+					cost, costOK := costMap[link] // Retrieve the cost from the map, Time Complexity: O(1)
 					if costOK {
 						if cost <= d.MaxNetworkCost {
 							satisfied += 1
@@ -578,9 +743,8 @@ func (no *NetworkOverhead) getAccumulatedCost(
 	scheduledList networkawareutil.ScheduledList,
 	dependencyList []agv1alpha1.DependenciesInfo,
 	nodeName string,
-	region string,
-	zone string,
-	costMap map[networkawareutil.CostKey]int64) (int64, error) {
+	domains []string,
+	costMap map[networkLink]int64) (int64, error) {
 	// keep track of the accumulated cost
 	var cost int64 = 0
 
@@ -601,31 +765,15 @@ func (no *NetworkOverhead) getAccumulatedCost(
 					no.logger.Error(err, "getting pod hostname from Snapshot", "nodeInfo", podNodeInfo)
 					return cost, err
 				}
-				// Get zone and region from Pod Hostname
-				regionPodNodeInfo := networkawareutil.GetNodeRegion(podNodeInfo.Node())
-				zonePodNodeInfo := networkawareutil.GetNodeZone(podNodeInfo.Node())
+				// Get the domains of the Pod hostname
+				podDomains := no.getNodeDomains(podNodeInfo.Node())
 
-				if regionPodNodeInfo == "" && zonePodNodeInfo == "" { // Node has no zone and region defined
+				if !hasDomains(podDomains) { // Node has no topology domain defined
 					cost += MaxCost
-				} else if region == regionPodNodeInfo { // If Nodes belong to the same region
-					if zone == zonePodNodeInfo { // If Nodes belong to the same zone
-						cost += SameZone
-					} else { // belong to a different zone
-						value, ok := costMap[networkawareutil.CostKey{ // Retrieve the cost from the map (origin: zone, destination: pod zoneHostname)
-							Origin:      zone, // Time Complexity: O(1)
-							Destination: zonePodNodeInfo,
-						}]
-						if ok {
-							cost += value // Add the cost to the sum
-						} else {
-							cost += MaxCost
-						}
-					}
-				} else { // belong to a different region
-					value, ok := costMap[networkawareutil.CostKey{ // Retrieve the cost from the map (origin: region, destination: pod regionHostname)
-						Origin:      region, // Time Complexity: O(1)
-						Destination: regionPodNodeInfo,
-					}]
+				} else if link, ok := no.getNetworkLink(domains, podDomains); !ok { // If Nodes belong to the same finest domain
+					cost += SameZone
+				} else { // belong to a different domain
+					value, ok := costMap[link] // Retrieve the cost from the map, Time Complexity: O(1)
 					if ok {
 						cost += value // Add the cost to the sum
 					} else {
@@ -638,6 +786,73 @@ func (no *NetworkOverhead) getAccumulatedCost(
 	return cost, nil
 }
 
+// getRequestedBandwidth : calculate the bandwidth requested on each link between the node and the pods of the dependencies.
+// The MinBandwidth of a dependency is requested once on each link to its pods.
+func (no *NetworkOverhead) getRequestedBandwidth(
+	scheduledList networkawareutil.ScheduledList,
+	dependencyList []agv1alpha1.DependenciesInfo,
+	nodeName string,
+	domains []string) (map[networkLink]int64, error) {
+	var requested map[networkLink]int64
+	for _, d := range dependencyList { // For each pod dependency
+		minBandwidth := d.MinBandwidth.Value()
+		if minBandwidth <= 0 {
+			continue
+		}
+		links := make(map[networkLink]bool)
+		for _, podAllocated := range scheduledList { // For each pod already allocated
+			// If the pod allocated is not an established dependency or is on the same node, continue.
+			if podAllocated.Selector != d.Workload.Selector || podAllocated.Hostname == "" || podAllocated.Hostname == nodeName {
+				continue
+			}
+			podNodeInfo, err := no.handle.SnapshotSharedLister().NodeInfos().Get(podAllocated.Hostname)
+			if err != nil {
+				no.logger.Error(err, "getting pod hostname from Snapshot", "nodeInfo", podNodeInfo)
+				return nil, err
+			}
+			// Nodes in the same finest domain share no link, and links to unknown domains have no capacity
+			link, ok := no.getNetworkLink(domains, no.getNodeDomains(podNodeInfo.Node()))
+			if ok && link.Origin != "" && link.Destination != "" {
+				links[link] = true
+			}
+		}
+		for link := range links {
+			if requested == nil {
+				requested = make(map[networkLink]int64)
+			}
+			requested[link] += minBandwidth
+		}
+	}
+	return requested, nil
+}
+
+// getAvailableBandwidth : get the bandwidth capacity of the links minus the bandwidth allocated in the NetworkTopology
+func (no *NetworkOverhead) getAvailableBandwidth(networkTopology *ntv1alpha1.NetworkTopology) map[networkLink]int64 {
+	available := make(map[networkLink]int64)
+	if networkTopology == nil {
+		return available
+	}
+	for _, w := range networkTopology.Spec.Weights { // Check the weights List
+		if w.Name != no.weightsName { // If it is not the Preferred algorithm, continue
+			continue
+		}
+		for _, t := range w.TopologyList {
+			for _, o := range t.OriginList {
+				for _, c := range o.CostList {
+					if c.BandwidthCapacity.IsZero() {
+						continue
+					}
+					available[networkLink{
+						TopologyKey: t.TopologyKey,
+						CostKey:     networkawareutil.CostKey{Origin: o.Origin, Destination: c.Destination},
+					}] = c.BandwidthCapacity.Value() - c.BandwidthAllocated.Value()
+				}
+			}
+		}
+	}
+	return available
+}
+
 func getPreFilterState(cycleState fwk.CycleState) (*PreFilterState, error) {
 	no, err := cycleState.Read(preFilterStateKey)
 	if err != nil {
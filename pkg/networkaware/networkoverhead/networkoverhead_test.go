@@ -26,6 +26,7 @@ import (
 	"time"
 
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
@@ -33,6 +34,7 @@ import (
 	"k8s.io/client-go/informers"
 	testClientSet "k8s.io/client-go/kubernetes/fake"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/util/workqueue"
 	fwk "k8s.io/kube-scheduler/framework"
 	"k8s.io/kubernetes/pkg/scheduler/framework"
@@ -47,6 +49,8 @@ import (
 	agv1alpha1 "github.com/diktyo-io/appgroup-api/pkg/apis/appgroup/v1alpha1"
 	ntv1alpha1 "github.com/diktyo-io/networktopology-api/pkg/apis/networktopology/v1alpha1"
 	"github.com/stretchr/testify/assert"
+
+	networkawareutil "sigs.k8s.io/scheduler-plugins/pkg/networkaware/util"
 )
 
 var _ framework.SharedLister = &testSharedLister{}
@@ -540,12 +544,13 @@ func BenchmarkNetworkOverheadPreFilter(b *testing.B) {
 				schedruntime.WithInformerFactory(informerFactory), schedruntime.WithSnapshotSharedLister(snapshot))
 
 			pl := &NetworkOverhead{
-				Client:      client,
-				podLister:   podLister,
-				handle:      fh,
-				namespaces:  []string{"default"},
-				weightsName: "UserDefined",
-				ntName:      "nt-test",
+				Client:       client,
+				podLister:    podLister,
+				handle:       fh,
+				namespaces:   []string{"default"},
+				weightsName:  "UserDefined",
+				ntName:       "nt-test",
+				topologyKeys: defaultTopologyKeys,
 			}
 
 			state := framework.NewCycleState()
@@ -760,12 +765,13 @@ func TestNetworkOverheadScore(t *testing.T) {
 				schedruntime.WithInformerFactory(informerFactory), schedruntime.WithSnapshotSharedLister(snapshot))
 
 			pl := &NetworkOverhead{
-				Client:      client,
-				podLister:   podLister,
-				handle:      fh,
-				namespaces:  []string{"default"},
-				weightsName: "UserDefined",
-				ntName:      "nt-test",
+				Client:       client,
+				podLister:    podLister,
+				handle:       fh,
+				namespaces:   []string{"default"},
+				weightsName:  "UserDefined",
+				ntName:       "nt-test",
+				topologyKeys: defaultTopologyKeys,
 			}
 
 			// Wait for the pods to be scheduled.
@@ -1009,12 +1015,13 @@ func BenchmarkNetworkOverheadScore(b *testing.B) {
 				schedruntime.WithInformerFactory(informerFactory), schedruntime.WithSnapshotSharedLister(snapshot))
 
 			pl := &NetworkOverhead{
-				Client:      client,
-				podLister:   podLister,
-				handle:      fh,
-				namespaces:  []string{"default"},
-				weightsName: "UserDefined",
-				ntName:      "nt-test",
+				Client:       client,
+				podLister:    podLister,
+				handle:       fh,
+				namespaces:   []string{"default"},
+				weightsName:  "UserDefined",
+				ntName:       "nt-test",
+				topologyKeys: defaultTopologyKeys,
 			}
 
 			state := framework.NewCycleState()
@@ -1242,12 +1249,13 @@ func TestNetworkOverheadFilter(t *testing.T) {
 				schedruntime.WithSnapshotSharedLister(snapshot))
 
 			pl := &NetworkOverhead{
-				Client:      client,
-				podLister:   podLister,
-				handle:      fh,
-				namespaces:  []string{"default"},
-				weightsName: "UserDefined",
-				ntName:      "nt-test",
+				Client:       client,
+				podLister:    podLister,
+				handle:       fh,
+				namespaces:   []string{"default"},
+				weightsName:  "UserDefined",
+				ntName:       "nt-test",
+				topologyKeys: defaultTopologyKeys,
 			}
 
 			// Wait for the pods to be scheduled.
@@ -1275,6 +1283,408 @@ func TestNetworkOverheadFilter(t *testing.T) {
 	}
 }
 
+func TestNetworkOverheadFilterBandwidth(t *testing.T) {
+	linkZ1Z2 := networkLink{
+		TopologyKey: ntv1alpha1.NetworkTopologyZone,
+		CostKey:     networkawareutil.CostKey{Origin: "Z1", Destination: "Z2"},
+	}
+
+	// Get Network Topology CR: nt-test, with the bandwidth of the link from Z1 to Z2
+	networkTopology := GetNetworkTopologyCRBasic()
+	zones := networkTopology.Spec.Weights[0].TopologyList[1].OriginList
+	zones[0].CostList[0].BandwidthCapacity = resource.MustParse("1Gi")
+	zones[0].CostList[0].BandwidthAllocated = resource.MustParse("512Mi")
+
+	// Create Pods
+	pods := []*v1.Pod{
+		makePodAllocated("p2", "p2-deployment", "n-3", 0, "basic", nil, nil),
+		makePodAllocated("p3", "p3-deployment", "n-5", 0, "basic", nil, nil),
+	}
+
+	// Create Nodes
+	nodes := []*v1.Node{
+		st.MakeNode().Name("n-1").Label(v1.LabelTopologyRegion, "us-west-1").Label(v1.LabelTopologyZone, "Z1").Obj(),
+		st.MakeNode().Name("n-2").Label(v1.LabelTopologyRegion, "us-west-1").Label(v1.LabelTopologyZone, "Z1").Obj(),
+		st.MakeNode().Name("n-3").Label(v1.LabelTopologyRegion, "us-west-1").Label(v1.LabelTopologyZone, "Z2").Obj(),
+		st.MakeNode().Name("n-4").Label(v1.LabelTopologyRegion, "us-west-1").Label(v1.LabelTopologyZone, "Z2").Obj(),
+		st.MakeNode().Name("n-5").Label(v1.LabelTopologyRegion, "us-east-1").Label(v1.LabelTopologyZone, "Z3").Obj(),
+	}
+
+	tests := []struct {
+		name          string
+		minBandwidth  string
+		reserved      map[networkLink]int64
+		nodeToFilter  *v1.Node
+		wantStatus    *fwk.Status
+		wantRequested map[networkLink]int64
+	}{
+		{
+			name:          "n-1 has enough bandwidth to p2 in Z2",
+			minBandwidth:  "256Mi",
+			nodeToFilter:  nodes[0],
+			wantRequested: map[networkLink]int64{linkZ1Z2: 256 * 1024 * 1024},
+		},
+		{
+			name:         "n-1 lacks bandwidth to p2 in Z2",
+			minBandwidth: "256Mi",
+			reserved:     map[networkLink]int64{linkZ1Z2: 384 * 1024 * 1024},
+			nodeToFilter: nodes[0],
+			wantStatus: fwk.NewStatus(fwk.Unschedulable,
+				"Node n-1 does not meet bandwidth requirements from Workload dependencies: Link: Z1 -> Z2 Requested: 256Mi Remaining: 128Mi"),
+			wantRequested: map[networkLink]int64{linkZ1Z2: 256 * 1024 * 1024},
+		},
+		{
+			name:         "n-4 shares no link with p2 in the same zone",
+			minBandwidth: "256Mi",
+			reserved:     map[networkLink]int64{linkZ1Z2: 384 * 1024 * 1024},
+			nodeToFilter: nodes[3],
+		},
+		{
+			name:         "n-1 without bandwidth requirements",
+			reserved:     map[networkLink]int64{linkZ1Z2: 1024 * 1024 * 1024},
+			nodeToFilter: nodes[0],
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Get AppGroup CRD: basic, p1 depends on p2 with the minimum bandwidth
+			appGroup := GetAppGroupCRBasic()
+			appGroup.Spec.Workloads[0].Dependencies[0].MaxNetworkCost = 10
+			if tt.minBandwidth != "" {
+				appGroup.Spec.Workloads[0].Dependencies[0].MinBandwidth = resource.MustParse(tt.minBandwidth)
+			}
+
+			s := clientgoscheme.Scheme
+			utilruntime.Must(agv1alpha1.AddToScheme(s))
+			utilruntime.Must(ntv1alpha1.AddToScheme(s))
+
+			ctx := context.Background()
+			client := fake.NewClientBuilder().
+				WithScheme(s).
+				WithObjects(appGroup, networkTopology.DeepCopy()).
+				Build()
+
+			cs := testClientSet.NewSimpleClientset()
+			informerFactory := informers.NewSharedInformerFactory(cs, 0)
+			podInformer := informerFactory.Core().V1().Pods()
+			for _, p := range pods {
+				if err := podInformer.Informer().GetStore().Add(p); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			fh, _ := tf.NewFramework(ctx, []tf.RegisterPluginFunc{
+				tf.RegisterBindPlugin(defaultbinder.Name, defaultbinder.New),
+				tf.RegisterQueueSortPlugin(queuesort.Name, queuesort.New),
+			}, "default-scheduler",
+				schedruntime.WithClientSet(cs),
+				schedruntime.WithInformerFactory(informerFactory),
+				schedruntime.WithSnapshotSharedLister(newTestSharedLister(nil, nodes)))
+
+			pl := &NetworkOverhead{
+				Client:       client,
+				podLister:    podInformer.Lister(),
+				handle:       fh,
+				namespaces:   []string{"default"},
+				weightsName:  "UserDefined",
+				ntName:       "nt-test",
+				topologyKeys: defaultTopologyKeys,
+				ledger:       newBandwidthLedger(),
+			}
+			pl.ledger.reserve("other", tt.reserved)
+
+			pod := makePod("p1", "p1-deployment", 0, "basic", nil, nil)
+			pod.UID = "p1"
+			state := framework.NewCycleState()
+			if _, got := pl.PreFilter(ctx, state, pod, nil); !got.IsSuccess() {
+				t.Fatalf("PreFilter: %v", got.Message())
+			}
+
+			nodeInfo := framework.NewNodeInfo()
+			nodeInfo.SetNode(tt.nodeToFilter)
+			assert.Equal(t, tt.wantStatus, pl.Filter(ctx, state, pod, nodeInfo))
+
+			// Reserve and unreserve the bandwidth of the pod
+			assert.Nil(t, pl.Reserve(ctx, state, pod, tt.nodeToFilter.Name))
+			assert.Equal(t, tt.reserved[linkZ1Z2]+tt.wantRequested[linkZ1Z2], pl.ledger.reserved(linkZ1Z2))
+			pl.Unreserve(ctx, state, pod, tt.nodeToFilter.Name)
+			assert.Equal(t, tt.reserved[linkZ1Z2], pl.ledger.reserved(linkZ1Z2))
+		})
+	}
+}
+
+func TestNetworkOverheadRestoreBandwidth(t *testing.T) {
+	linkZ1Z2 := networkLink{
+		TopologyKey: ntv1alpha1.NetworkTopologyZone,
+		CostKey:     networkawareutil.CostKey{Origin: "Z1", Destination: "Z2"},
+	}
+
+	// Get Network Topology CR: nt-test, with 512Mi remaining on the link from Z1 to Z2
+	networkTopology := GetNetworkTopologyCRBasic()
+	zones := networkTopology.Spec.Weights[0].TopologyList[1].OriginList
+	zones[0].CostList[0].BandwidthCapacity = resource.MustParse("1Gi")
+	zones[0].CostList[0].BandwidthAllocated = resource.MustParse("512Mi")
+
+	// Get AppGroup CRD: basic, p1 depends on p2 with a minimum bandwidth of 384Mi
+	appGroup := GetAppGroupCRBasic()
+	appGroup.Spec.Workloads[0].Dependencies[0].MaxNetworkCost = 10
+	appGroup.Spec.Workloads[0].Dependencies[0].MinBandwidth = resource.MustParse("384Mi")
+
+	// p1 was bound to n-1 in Z1 before a restart of the scheduler, p1-deleted was deleted before being restored
+	bound := makePodAllocated("p1", "p1-bound", "n-1", 0, "basic", nil, nil)
+	bound.UID = "p1-bound"
+	deleted := makePodAllocated("p1", "p1-deleted", "n-2", 0, "basic", nil, nil)
+	deleted.UID = "p1-deleted"
+	pods := []*v1.Pod{
+		makePodAllocated("p2", "p2-deployment", "n-3", 0, "basic", nil, nil),
+		bound,
+	}
+
+	nodes := []*v1.Node{
+		st.MakeNode().Name("n-1").Label(v1.LabelTopologyRegion, "us-west-1").Label(v1.LabelTopologyZone, "Z1").Obj(),
+		st.MakeNode().Name("n-2").Label(v1.LabelTopologyRegion, "us-west-1").Label(v1.LabelTopologyZone, "Z1").Obj(),
+		st.MakeNode().Name("n-3").Label(v1.LabelTopologyRegion, "us-west-1").Label(v1.LabelTopologyZone, "Z2").Obj(),
+	}
+
+	s := clientgoscheme.Scheme
+	utilruntime.Must(agv1alpha1.AddToScheme(s))
+	utilruntime.Must(ntv1alpha1.AddToScheme(s))
+
+	ctx := context.Background()
+	client := fake.NewClientBuilder().
+		WithScheme(s).
+		WithObjects(appGroup, networkTopology).
+		Build()
+
+	cs := testClientSet.NewSimpleClientset()
+	informerFactory := informers.NewSharedInformerFactory(cs, 0)
+	podInformer := informerFactory.Core().V1().Pods()
+	for _, p := range pods {
+		if err := podInformer.Informer().GetStore().Add(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	fh, _ := tf.NewFramework(ctx, []tf.RegisterPluginFunc{
+		tf.RegisterBindPlugin(defaultbinder.Name, defaultbinder.New),
+		tf.RegisterQueueSortPlugin(queuesort.Name, queuesort.New),
+	}, "default-scheduler",
+		schedruntime.WithClientSet(cs),
+		schedruntime.WithInformerFactory(informerFactory),
+		schedruntime.WithSnapshotSharedLister(newTestSharedLister(nil, nodes)))
+
+	pl := &NetworkOverhead{
+		Client:       client,
+		podLister:    podInformer.Lister(),
+		handle:       fh,
+		namespaces:   []string{"default"},
+		weightsName:  "UserDefined",
+		ntName:       "nt-test",
+		topologyKeys: defaultTopologyKeys,
+		ledger:       newBandwidthLedger(),
+	}
+	for _, p := range append(pods, deleted) {
+		pl.addPod(p)
+	}
+	pl.deletePod(deleted)
+	assert.Len(t, pl.ledger.pending, len(pods), "the deleted pod is not tracked anymore")
+
+	pod := makePod("p1", "p1-deployment", 0, "basic", nil, nil)
+	pod.UID = "p1"
+	state := framework.NewCycleState()
+	if _, got := pl.PreFilter(ctx, state, pod, nil); !got.IsSuccess() {
+		t.Fatalf("PreFilter: %v", got.Message())
+	}
+	assert.Empty(t, pl.ledger.pending)
+	assert.Equal(t, int64(384*1024*1024), pl.ledger.reserved(linkZ1Z2))
+
+	nodeInfo := framework.NewNodeInfo()
+	nodeInfo.SetNode(nodes[1])
+	assert.Equal(t, fwk.NewStatus(fwk.Unschedulable,
+		"Node n-2 does not meet bandwidth requirements from Workload dependencies: Link: Z1 -> Z2 Requested: 384Mi Remaining: 128Mi"),
+		pl.Filter(ctx, state, pod, nodeInfo))
+
+	// The restored bandwidth is released once the pod is deleted
+	pl.deletePod(bound)
+	assert.Equal(t, int64(0), pl.ledger.reserved(linkZ1Z2))
+}
+
+func TestBandwidthLedger(t *testing.T) {
+	link1 := networkLink{TopologyKey: ntv1alpha1.NetworkTopologyZone, CostKey: networkawareutil.CostKey{Origin: "Z1", Destination: "Z2"}}
+	link2 := networkLink{TopologyKey: ntv1alpha1.NetworkTopologyRegion, CostKey: networkawareutil.CostKey{Origin: "R1", Destination: "R2"}}
+
+	ledger := newBandwidthLedger()
+	ledger.reserve("p1", map[networkLink]int64{link1: 100, link2: 50})
+	ledger.reserve("p2", map[networkLink]int64{link1: 200})
+	assert.Equal(t, int64(300), ledger.reserved(link1))
+	assert.Equal(t, int64(50), ledger.reserved(link2))
+
+	// Reserving a pod again overwrites its bandwidth
+	ledger.reserve("p1", map[networkLink]int64{link1: 10})
+	assert.Equal(t, int64(210), ledger.reserved(link1))
+	assert.Equal(t, int64(0), ledger.reserved(link2))
+
+	// The bandwidth of a deleted pod is released
+	pod := makePodAllocated("p2", "p2-deployment", "n-1", 0, "basic", nil, nil)
+	pod.UID = "p2"
+	pl := &NetworkOverhead{ledger: ledger}
+	pl.deletePod(cache.DeletedFinalStateUnknown{Key: "default/p2-deployment", Obj: pod})
+	assert.Equal(t, int64(10), ledger.reserved(link1))
+
+	// The bandwidth of a terminated pod is released
+	pod = makePodAllocated("p1", "p1-deployment", "n-1", 0, "basic", nil, nil)
+	pod.UID = "p1"
+	running := pod.DeepCopy()
+	pod.Status.Phase = v1.PodSucceeded
+	pl.updatePod(running, pod)
+	assert.Equal(t, int64(0), ledger.reserved(link1))
+	assert.Empty(t, ledger.pods)
+	assert.Empty(t, ledger.links)
+}
+
+func TestNetworkOverheadTopologyKeys(t *testing.T) {
+	rackKey := "example.com/rack"
+
+	// Get Network Topology CR: nt-test, with the costs between racks
+	networkTopology := GetNetworkTopologyCRBasic()
+	networkTopology.Spec.Weights[0].TopologyList = append(networkTopology.Spec.Weights[0].TopologyList,
+		ntv1alpha1.TopologyInfo{
+			TopologyKey: ntv1alpha1.TopologyKey(rackKey),
+			OriginList: ntv1alpha1.OriginList{
+				ntv1alpha1.OriginInfo{Origin: "R2", CostList: []ntv1alpha1.CostInfo{{Destination: "R1", NetworkCost: 2}}},
+				ntv1alpha1.OriginInfo{Origin: "R1", CostList: []ntv1alpha1.CostInfo{{Destination: "R2", NetworkCost: 2}}},
+			},
+		})
+
+	// Create Pods: p2 is allocated on n-2
+	pods := []*v1.Pod{
+		makePodAllocated("p2", "p2-deployment", "n-2", 0, "basic", nil, nil),
+	}
+
+	// Create Nodes
+	nodes := []*v1.Node{
+		st.MakeNode().Name("n-1").Label(rackKey, "R1").Label(v1.LabelTopologyZone, "Z1").Label(v1.LabelTopologyRegion, "us-west-1").Obj(),
+		st.MakeNode().Name("n-2").Label(rackKey, "R1").Label(v1.LabelTopologyZone, "Z1").Label(v1.LabelTopologyRegion, "us-west-1").Obj(),
+		st.MakeNode().Name("n-3").Label(rackKey, "R2").Label(v1.LabelTopologyZone, "Z1").Label(v1.LabelTopologyRegion, "us-west-1").Obj(),
+		st.MakeNode().Name("n-4").Label(rackKey, "R3").Label(v1.LabelTopologyZone, "Z2").Label(v1.LabelTopologyRegion, "us-west-1").Obj(),
+		st.MakeNode().Name("n-5").Label(rackKey, "R4").Label(v1.LabelTopologyZone, "Z3").Label(v1.LabelTopologyRegion, "us-east-1").Obj(),
+		st.MakeNode().Name("n-6").Obj(),
+	}
+
+	rackKeys := []string{rackKey, v1.LabelTopologyZone, v1.LabelTopologyRegion}
+	tests := []struct {
+		name         string
+		topologyKeys []string
+		nodeToFilter *v1.Node
+		wantScore    int64
+		wantStatus   *fwk.Status
+	}{
+		{
+			name:         "n-2 hosts p2",
+			topologyKeys: rackKeys,
+			nodeToFilter: nodes[1],
+			wantScore:    SameHostname,
+		},
+		{
+			name:         "n-1 shares the rack of p2",
+			topologyKeys: rackKeys,
+			nodeToFilter: nodes[0],
+			wantScore:    SameZone,
+		},
+		{
+			name:         "n-3 shares the zone of p2: rack cost",
+			topologyKeys: rackKeys,
+			nodeToFilter: nodes[2],
+			wantScore:    2,
+		},
+		{
+			name:         "n-3 shares the zone of p2 without rack key",
+			topologyKeys: defaultTopologyKeys,
+			nodeToFilter: nodes[2],
+			wantScore:    SameZone,
+		},
+		{
+			name:         "n-4 shares the region of p2: zone cost",
+			topologyKeys: rackKeys,
+			nodeToFilter: nodes[3],
+			wantScore:    5,
+		},
+		{
+			name:         "n-5 is in another region: region cost",
+			topologyKeys: rackKeys,
+			nodeToFilter: nodes[4],
+			wantScore:    20,
+			wantStatus: fwk.NewStatus(fwk.Unschedulable,
+				"Node n-5 does not meet several network requirements from Workload dependencies: Satisfied: 0 Violated: 1"),
+		},
+		{
+			name:         "n-6 has no topology labels besides its hostname",
+			topologyKeys: []string{v1.LabelHostname, rackKey, v1.LabelTopologyZone, v1.LabelTopologyRegion},
+			nodeToFilter: nodes[5],
+			wantScore:    MaxCost,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Get AppGroup CRD: basic, p1 depends on p2
+			appGroup := GetAppGroupCRBasic()
+			appGroup.Spec.Workloads[0].Dependencies[0].MaxNetworkCost = 10
+
+			s := clientgoscheme.Scheme
+			utilruntime.Must(agv1alpha1.AddToScheme(s))
+			utilruntime.Must(ntv1alpha1.AddToScheme(s))
+
+			ctx := context.Background()
+			client := fake.NewClientBuilder().
+				WithScheme(s).
+				WithObjects(appGroup, networkTopology.DeepCopy()).
+				Build()
+
+			cs := testClientSet.NewSimpleClientset()
+			informerFactory := informers.NewSharedInformerFactory(cs, 0)
+			podInformer := informerFactory.Core().V1().Pods()
+			for _, p := range pods {
+				if err := podInformer.Informer().GetStore().Add(p); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			fh, _ := tf.NewFramework(ctx, []tf.RegisterPluginFunc{
+				tf.RegisterBindPlugin(defaultbinder.Name, defaultbinder.New),
+				tf.RegisterQueueSortPlugin(queuesort.Name, queuesort.New),
+			}, "default-scheduler",
+				schedruntime.WithClientSet(cs),
+				schedruntime.WithInformerFactory(informerFactory),
+				schedruntime.WithSnapshotSharedLister(newTestSharedLister(nil, nodes)))
+
+			pl := &NetworkOverhead{
+				Client:       client,
+				podLister:    podInformer.Lister(),
+				handle:       fh,
+				namespaces:   []string{"default"},
+				weightsName:  "UserDefined",
+				ntName:       "nt-test",
+				topologyKeys: tt.topologyKeys,
+				ledger:       newBandwidthLedger(),
+			}
+
+			pod := makePod("p1", "p1-deployment", 0, "basic", nil, nil)
+			state := framework.NewCycleState()
+			if _, got := pl.PreFilter(ctx, state, pod, nil); !got.IsSuccess() {
+				t.Fatalf("PreFilter: %v", got.Message())
+			}
+
+			nodeInfo := framework.NewNodeInfo()
+			nodeInfo.SetNode(tt.nodeToFilter)
+			assert.Equal(t, tt.wantStatus, pl.Filter(ctx, state, pod, nodeInfo))
+
+			score, gotStatus := pl.Score(ctx, state, pod, nodeInfo)
+			assert.True(t, gotStatus.IsSuccess())
+			assert.Equal(t, tt.wantScore, score)
+		})
+	}
+}
+
 func BenchmarkNetworkOverheadFilter(b *testing.B) {
 	// Get AppGroup CRD: onlineboutique
 	onlineBoutiqueAppGroup := GetAppGroupCROnlineBoutique()
@@ -1468,12 +1878,13 @@ func BenchmarkNetworkOverheadFilter(b *testing.B) {
 				schedruntime.WithSnapshotSharedLister(snapshot))
 
 			pl := &NetworkOverhead{
-				Client:      client,
-				podLister:   podLister,
-				handle:      fh,
-				namespaces:  []string{"default"},
-				weightsName: "UserDefined",
-				ntName:      "nt-test",
+				Client:       client,
+				podLister:    podLister,
+				handle:       fh,
+				namespaces:   []string{"default"},
+				weightsName:  "UserDefined",
+				ntName:       "nt-test",
+				topologyKeys: defaultTopologyKeys,
 			}
 
 			// Wait for the pods to be scheduled.
@@ -57,6 +57,9 @@ type OverReserve struct {
 	resyncMethod           apiconfig.CacheResyncMethod
 	resyncScope            apiconfig.CacheResyncScope
 	isPodRelevant          podprovider.PodFilterFunc
+	// onFlush, if set, is called with the assumed resources dropped from the flushed nodes.
+	// The value is nil if no resources were assumed on a node. Called without holding the lock.
+	onFlush func(lh logr.Logger, flushed map[string]*resourceStore)
 }
 
 func NewOverReserve(ctx context.Context, lh logr.Logger, cfg *apiconfig.NodeResourceTopologyCache, client ctrlclient.WithWatch, podLister podlisterv1.PodLister, isPodRelevant podprovider.PodFilterFunc) (*OverReserve, error) {
@@ -341,12 +344,22 @@ func (ov *OverReserve) Resync() {
 
 // FlushNodes drops all the cached information about a given node, resetting its state clean.
 func (ov *OverReserve) FlushNodes(lh logr.Logger, nrts ...*topologyv1alpha2.NodeResourceTopology) uint64 {
+	generation, flushed := ov.flushNodes(lh, nrts...)
+	if ov.onFlush != nil && len(flushed) > 0 {
+		ov.onFlush(lh, flushed)
+	}
+	return generation
+}
+
+func (ov *OverReserve) flushNodes(lh logr.Logger, nrts ...*topologyv1alpha2.NodeResourceTopology) (uint64, map[string]*resourceStore) {
 	ov.lock.Lock()
 	defer ov.lock.Unlock()
 
+	flushed := make(map[string]*resourceStore, len(nrts))
 	for _, nrt := range nrts {
 		lh.V(2).Info("flushing", logging.KeyNode, nrt.Name)
 		ov.nrts.Update(nrt)
+		flushed[nrt.Name] = ov.assumedResources[nrt.Name]
 		delete(ov.assumedResources, nrt.Name)
 		ov.nodesMaybeOverreserved.Delete(nrt.Name)
 		ov.nodesWithForeignPods.Delete(nrt.Name)
@@ -354,13 +367,13 @@ func (ov *OverReserve) FlushNodes(lh logr.Logger, nrts ...*topologyv1alpha2.Node
 	}
 
 	if len(nrts) == 0 {
-		return ov.generation
+		return ov.generation, flushed
 	}
 
 	// increase only if we mutated the internal state
 	ov.generation += 1
 	lh.V(2).Info("generation", "new", ov.generation)
-	return ov.generation
+	return ov.generation, flushed
 
 }
 
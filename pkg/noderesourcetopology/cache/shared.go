@@ -0,0 +1,496 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	listerv1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiconfig "sigs.k8s.io/scheduler-plugins/apis/config"
+	"sigs.k8s.io/scheduler-plugins/pkg/noderesourcetopology/logging"
+	"sigs.k8s.io/scheduler-plugins/pkg/noderesourcetopology/podprovider"
+	"sigs.k8s.io/scheduler-plugins/pkg/util"
+)
+
+const (
+	// LedgerLabel marks the ConfigMaps holding the reservations shared among the scheduler instances
+	LedgerLabel = "scheduling.x-k8s.io/nrt-reservations"
+	// LedgerNamePrefix is prepended to the node name to get the name of the ConfigMap holding its reservations
+	LedgerNamePrefix = "nrt-reservations-"
+
+	defaultLedgerNamespace = metav1.NamespaceSystem
+	ledgerUpdateTimeout    = 5 * time.Second
+	ledgerMaxRetries       = 5
+	// ledgerEntryTTL is the age beyond which a reservation is ignored, and dropped from the ledger, in case
+	// its owner could not withdraw it. It is longer than the time to bind a pod and to refresh the NRT data.
+	ledgerEntryTTL = 5 * time.Minute
+)
+
+// SharedReserve is an OverReserve cache which also publishes the reservations on a ledger shared among
+// the scheduler instances, and accounts the reservations the other instances published. The pods
+// reserved on the ledger are not foreign, so cooperating schedulers don't need to resync the nodes
+// they both use. The reservations are published asynchronously, so that the scheduling cycle doesn't
+// wait for the API server: the other instances see them once the ledger is updated.
+type SharedReserve struct {
+	*OverReserve
+	ledger *reservationLedger
+	// accountedLock protects accounted
+	accountedLock sync.Mutex
+	// accounted holds, per node, the ledger keys of the pods reserved on the ledger which were
+	// running when the node was last flushed, so they are included in the cached NRT data.
+	accounted map[string]sets.Set[string]
+}
+
+func NewSharedReserve(ctx context.Context, lh logr.Logger, cfg *apiconfig.NodeResourceTopologyCache, client ctrlclient.WithWatch, kubeClient kubernetes.Interface, podLister listerv1.PodLister, isPodRelevant podprovider.PodFilterFunc) (*SharedReserve, error) {
+	ov, err := NewOverReserve(ctx, lh, cfg, client, podLister, isPodRelevant)
+	if err != nil {
+		return nil, err
+	}
+
+	namespace := getReservationNamespace(lh, cfg)
+	informerFactory := informers.NewSharedInformerFactoryWithOptions(kubeClient, 0,
+		informers.WithNamespace(namespace),
+		informers.WithTweakListOptions(func(opts *metav1.ListOptions) {
+			opts.LabelSelector = LedgerLabel
+		}),
+	)
+	cmLister := informerFactory.Core().V1().ConfigMaps().Lister().ConfigMaps(namespace)
+
+	lh.V(5).Info("syncing reservation ledger", "namespace", namespace)
+	informerFactory.Start(ctx.Done())
+	informerFactory.WaitForCacheSync(ctx.Done())
+	lh.V(5).Info("synced reservation ledger", "namespace", namespace)
+
+	sr := &SharedReserve{
+		OverReserve: ov,
+		ledger:      newReservationLedger(lh, kubeClient, cmLister, namespace),
+		accounted:   make(map[string]sets.Set[string]),
+	}
+	ov.onFlush = sr.nodesFlushed
+	go sr.ledger.Run(ctx)
+
+	lh.V(2).Info("initializing shared reservations", "namespace", namespace, "owner", sr.ledger.owner)
+	return sr, nil
+}
+
+func (sr *SharedReserve) GetCachedNRTCopy(ctx context.Context, nodeName string, pod *corev1.Pod) (*topologyv1alpha2.NodeResourceTopology, CachedNRTInfo) {
+	nrt, info := sr.OverReserve.GetCachedNRTCopy(ctx, nodeName, pod)
+	if nrt == nil {
+		return nrt, info
+	}
+
+	reserved := sr.reservedByOthers(nodeName)
+	if len(reserved.data) == 0 {
+		return nrt, info
+	}
+
+	logID := klog.KObj(pod)
+	reserved.UpdateNRT(nrt, logging.KeyPod, logID)
+	sr.lh.V(5).Info("NRT", logging.KeyPod, logID, logging.KeyNode, nodeName, "withshared", reserved.String())
+	return nrt, info
+}
+
+func (sr *SharedReserve) NodeHasForeignPods(nodeName string, pod *corev1.Pod) {
+	if sr.isAccounted(nodeName, ledgerKey(pod.Namespace, pod.Name, pod.UID)) || sr.ledger.Has(nodeName, pod) {
+		sr.lh.V(5).Info("ignoring foreign pod", logging.KeyPod, klog.KObj(pod), logging.KeyPodUID, logging.PodUID(pod), logging.KeyNode, nodeName, "reason", "shared reservation")
+		return
+	}
+	sr.OverReserve.NodeHasForeignPods(nodeName, pod)
+}
+
+func (sr *SharedReserve) ReserveNodeResources(nodeName string, pod *corev1.Pod) {
+	sr.OverReserve.ReserveNodeResources(nodeName, pod)
+	sr.ledger.Reserve(nodeName, pod)
+}
+
+func (sr *SharedReserve) UnreserveNodeResources(nodeName string, pod *corev1.Pod) {
+	sr.OverReserve.UnreserveNodeResources(nodeName, pod)
+	sr.ledger.Unreserve(nodeName, pod.Namespace+"/"+pod.Name)
+}
+
+// reservedByOthers returns the resources the other scheduler instances reserved on the node and
+// which are not yet included in the cached NRT data. Reservations which expired, or of pods which
+// are gone, recreated or bound to another node, are skipped.
+func (sr *SharedReserve) reservedByOthers(nodeName string) *resourceStore {
+	reserved := newResourceStore(sr.lh)
+	now := time.Now()
+	for key, entry := range sr.ledger.Entries(nodeName) {
+		if entry.Owner == sr.ledger.owner || sr.isAccounted(nodeName, key) {
+			continue
+		}
+		if entry.expired(now) {
+			sr.lh.V(5).Info("ignoring expired reservation", "key", key, "owner", entry.Owner, "timestamp", entry.Timestamp)
+			continue
+		}
+		pod, err := sr.podLister.Pods(entry.Namespace).Get(entry.Name)
+		if apierrors.IsNotFound(err) || (err == nil && pod.UID != entry.UID) {
+			sr.lh.V(5).Info("ignoring reservation of missing pod", "key", key, "owner", entry.Owner)
+			continue
+		}
+		if err == nil && pod.Spec.NodeName != "" && pod.Spec.NodeName != nodeName {
+			sr.lh.V(5).Info("ignoring reservation of pod bound to another node", "key", key, "owner", entry.Owner, "boundNode", pod.Spec.NodeName)
+			continue
+		}
+		reserved.data[entry.Namespace+"/"+entry.Name] = entry.Resources
+	}
+	return reserved
+}
+
+func (sr *SharedReserve) isAccounted(nodeName, key string) bool {
+	sr.accountedLock.Lock()
+	defer sr.accountedLock.Unlock()
+	return sr.accounted[nodeName].Has(key)
+}
+
+// nodesFlushed withdraws the reservations this instance published on the flushed nodes, which are now
+// included in the NRT data, and records which reservations of the other instances are included as well.
+func (sr *SharedReserve) nodesFlushed(lh logr.Logger, flushed map[string]*resourceStore) {
+	for nodeName, assumed := range flushed {
+		if assumed == nil || len(assumed.data) == 0 {
+			continue
+		}
+		keys := make([]string, 0, len(assumed.data))
+		for key := range assumed.data {
+			keys = append(keys, key)
+		}
+		sr.ledger.Unreserve(nodeName, keys...)
+	}
+
+	pods, err := sr.podLister.List(labels.Everything())
+	if err != nil {
+		lh.Error(err, "cannot list pods to account shared reservations")
+		return
+	}
+	running := make(map[string]sets.Set[string], len(flushed))
+	for _, pod := range pods {
+		if _, ok := flushed[pod.Spec.NodeName]; !ok {
+			continue
+		}
+		if running[pod.Spec.NodeName] == nil {
+			running[pod.Spec.NodeName] = sets.New[string]()
+		}
+		running[pod.Spec.NodeName].Insert(ledgerKey(pod.Namespace, pod.Name, pod.UID))
+	}
+
+	sr.accountedLock.Lock()
+	defer sr.accountedLock.Unlock()
+	for nodeName := range flushed {
+		accounted := sets.New[string]()
+		for key := range sr.ledger.Entries(nodeName) {
+			if running[nodeName].Has(key) {
+				accounted.Insert(key)
+			}
+		}
+		if accounted.Len() == 0 {
+			delete(sr.accounted, nodeName)
+			continue
+		}
+		lh.V(4).Info("accounted shared reservations", logging.KeyNode, nodeName, "pods", accounted.Len())
+		sr.accounted[nodeName] = accounted
+	}
+}
+
+// ledgerEntry is the reservation of a pod, as published on the ledger
+type ledgerEntry struct {
+	Owner     string              `json:"owner"`
+	Namespace string              `json:"namespace"`
+	Name      string              `json:"name"`
+	UID       types.UID           `json:"uid"`
+	Resources corev1.ResourceList `json:"resources"`
+	// Timestamp is the time of the reservation
+	Timestamp metav1.Time `json:"timestamp"`
+}
+
+// expired tells whether the reservation is older than ledgerEntryTTL
+func (entry *ledgerEntry) expired(now time.Time) bool {
+	return now.Sub(entry.Timestamp.Time) > ledgerEntryTTL
+}
+
+// reservationLedger stores the reservations in ConfigMaps, one per node, keyed by pod namespace, name and UID.
+// Reads are served by an informer, while the reservations of this instance are kept in memory
+// and written to the API server by a worker, one node at a time.
+type reservationLedger struct {
+	lh        logr.Logger
+	client    kubernetes.Interface
+	lister    listerv1.ConfigMapNamespaceLister
+	namespace string
+	// owner identifies the scheduler instance which published an entry
+	owner string
+	// queue holds the nodes whose ConfigMap needs to be synced with published
+	queue workqueue.TypedRateLimitingInterface[string]
+	// lock protects published
+	lock sync.Mutex
+	// published holds, per node, the entries of this instance keyed by ConfigMap key
+	published map[string]map[string]ledgerEntry
+}
+
+func newReservationLedger(lh logr.Logger, client kubernetes.Interface, lister listerv1.ConfigMapNamespaceLister, namespace string) *reservationLedger {
+	return &reservationLedger{
+		lh:        lh,
+		client:    client,
+		lister:    lister,
+		namespace: namespace,
+		owner:     ledgerOwnerIdentity(),
+		queue:     workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[string]()),
+		published: make(map[string]map[string]ledgerEntry),
+	}
+}
+
+// Run syncs the ConfigMaps of the queued nodes until the context is done.
+func (rl *reservationLedger) Run(ctx context.Context) {
+	go func() {
+		<-ctx.Done()
+		rl.queue.ShutDown()
+	}()
+	for rl.processNextNode(ctx) {
+	}
+}
+
+func (rl *reservationLedger) processNextNode(ctx context.Context) bool {
+	nodeName, shutdown := rl.queue.Get()
+	if shutdown {
+		return false
+	}
+	defer rl.queue.Done(nodeName)
+
+	if err := rl.sync(ctx, nodeName); err != nil {
+		if rl.queue.NumRequeues(nodeName) < ledgerMaxRetries {
+			rl.lh.V(4).Info("cannot update reservations, retrying", logging.KeyNode, nodeName, "error", err)
+			rl.queue.AddRateLimited(nodeName)
+			return true
+		}
+		// the other instances will detect the pods as foreign once bound, so we can go ahead
+		rl.lh.Error(err, "cannot update reservations", logging.KeyNode, nodeName)
+	}
+	rl.queue.Forget(nodeName)
+	return true
+}
+
+// Entries returns the reservations on the node keyed by ledgerKey
+func (rl *reservationLedger) Entries(nodeName string) map[string]ledgerEntry {
+	cm, err := rl.lister.Get(ledgerName(nodeName))
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			rl.lh.V(2).Info("cannot get reservations", logging.KeyNode, nodeName, "error", err)
+		}
+		return nil
+	}
+
+	entries := make(map[string]ledgerEntry, len(cm.Data))
+	for dataKey, value := range cm.Data {
+		var entry ledgerEntry
+		if err := json.Unmarshal([]byte(value), &entry); err != nil {
+			rl.lh.V(2).Info("malformed reservation", logging.KeyNode, nodeName, "key", dataKey, "error", err)
+			continue
+		}
+		entries[ledgerKey(entry.Namespace, entry.Name, entry.UID)] = entry
+	}
+	return entries
+}
+
+// Has tells whether the pod is reserved on the node, by this instance or, as far as the informer
+// knows, by another one. The reservation of a former pod with the same name doesn't count.
+func (rl *reservationLedger) Has(nodeName string, pod *corev1.Pod) bool {
+	dataKey := ledgerKey(pod.Namespace, pod.Name, pod.UID)
+	rl.lock.Lock()
+	_, ok := rl.published[nodeName][dataKey]
+	rl.lock.Unlock()
+	if ok {
+		return true
+	}
+	cm, err := rl.lister.Get(ledgerName(nodeName))
+	if err != nil {
+		return false
+	}
+	_, ok = cm.Data[dataKey]
+	return ok
+}
+
+// Reserve queues the publication of the reservation of the pod on the node
+func (rl *reservationLedger) Reserve(nodeName string, pod *corev1.Pod) {
+	entry := ledgerEntry{
+		Owner:     rl.owner,
+		Namespace: pod.Namespace,
+		Name:      pod.Name,
+		UID:       pod.UID,
+		Resources: util.GetPodEffectiveRequest(pod),
+		Timestamp: metav1.Now(),
+	}
+	rl.lock.Lock()
+	defer rl.lock.Unlock()
+	if rl.published[nodeName] == nil {
+		rl.published[nodeName] = make(map[string]ledgerEntry)
+	}
+	rl.published[nodeName][ledgerKey(pod.Namespace, pod.Name, pod.UID)] = entry
+	rl.queue.Add(nodeName)
+}
+
+// Unreserve queues the withdrawal of the reservations of the given pods, identified by namespace + "/" + name,
+// this instance published on the node.
+func (rl *reservationLedger) Unreserve(nodeName string, keys ...string) {
+	rl.lock.Lock()
+	defer rl.lock.Unlock()
+	withdrawn := sets.New(keys...)
+	for dataKey, entry := range rl.published[nodeName] {
+		if withdrawn.Has(entry.Namespace + "/" + entry.Name) {
+			delete(rl.published[nodeName], dataKey)
+		}
+	}
+	if len(rl.published[nodeName]) == 0 {
+		delete(rl.published, nodeName)
+	}
+	rl.queue.Add(nodeName)
+}
+
+// sync updates the ConfigMap of the node with the entries this instance published, and drops the
+// expired entries of any instance, including the ones this instance published, so that it doesn't
+// publish again the entries the other instances dropped. The ConfigMap is deleted once it holds no
+// reservations anymore.
+func (rl *reservationLedger) sync(ctx context.Context, nodeName string) error {
+	ctx, cancel := context.WithTimeout(ctx, ledgerUpdateTimeout)
+	defer cancel()
+
+	now := time.Now()
+	published, err := rl.unexpired(nodeName, now)
+	if err != nil {
+		return err
+	}
+
+	cms := rl.client.CoreV1().ConfigMaps(rl.namespace)
+	cm, err := cms.Get(ctx, ledgerName(nodeName), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		if len(published) == 0 {
+			return nil
+		}
+		cm = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      ledgerName(nodeName),
+				Namespace: rl.namespace,
+				Labels:    map[string]string{LedgerLabel: ""},
+			},
+			Data: published,
+		}
+		_, err = cms.Create(ctx, cm, metav1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	data := make(map[string]string, len(cm.Data)+len(published))
+	for dataKey, value := range cm.Data {
+		// the entries of this instance are replaced by the published ones
+		var entry ledgerEntry
+		if err := json.Unmarshal([]byte(value), &entry); err == nil && (entry.Owner == rl.owner || entry.expired(now)) {
+			continue
+		}
+		data[dataKey] = value
+	}
+	for dataKey, value := range published {
+		data[dataKey] = value
+	}
+	if maps.Equal(data, cm.Data) {
+		return nil
+	}
+
+	if len(data) == 0 {
+		err = cms.Delete(ctx, cm.Name, metav1.DeleteOptions{
+			Preconditions: &metav1.Preconditions{ResourceVersion: &cm.ResourceVersion},
+		})
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	cm = cm.DeepCopy()
+	cm.Data = data
+	_, err = cms.Update(ctx, cm, metav1.UpdateOptions{})
+	return err
+}
+
+// unexpired drops the expired entries this instance published on the node, and returns the others
+// keyed by ConfigMap key.
+func (rl *reservationLedger) unexpired(nodeName string, now time.Time) (map[string]string, error) {
+	rl.lock.Lock()
+	defer rl.lock.Unlock()
+	published := make(map[string]string, len(rl.published[nodeName]))
+	for dataKey, entry := range rl.published[nodeName] {
+		if entry.expired(now) {
+			rl.lh.V(4).Info("dropping expired reservation", logging.KeyNode, nodeName, "key", dataKey, "timestamp", entry.Timestamp)
+			delete(rl.published[nodeName], dataKey)
+			continue
+		}
+		value, err := json.Marshal(entry)
+		if err != nil {
+			return nil, err
+		}
+		published[dataKey] = string(value)
+	}
+	if len(rl.published[nodeName]) == 0 {
+		delete(rl.published, nodeName)
+	}
+	return published, nil
+}
+
+func ledgerName(nodeName string) string {
+	return LedgerNamePrefix + nodeName
+}
+
+// ledgerKey returns the ConfigMap key of the reservation of a pod. It includes the UID of the pod, so
+// that the reservation of a pod is not mistaken for the one of a pod recreated with the same name.
+func ledgerKey(namespace, name string, uid types.UID) string {
+	return namespace + "_" + name + "_" + string(uid)
+}
+
+// ledgerOwnerIdentity returns a unique identity for the scheduler instance, like leader election does
+func ledgerOwnerIdentity() string {
+	id := string(uuid.NewUUID())
+	if hostname, err := os.Hostname(); err == nil {
+		id = hostname + "_" + id
+	}
+	return id
+}
+
+func getReservationNamespace(lh logr.Logger, cfg *apiconfig.NodeResourceTopologyCache) string {
+	if cfg != nil && cfg.ReservationNamespace != nil && *cfg.ReservationNamespace != "" {
+		return *cfg.ReservationNamespace
+	}
+	lh.Info("cache reservation namespace missing", "fallback", defaultLedgerNamespace)
+	return defaultLedgerNamespace
+}
@@ -0,0 +1,350 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	clientsetfake "k8s.io/client-go/kubernetes/fake"
+	podlisterv1 "k8s.io/client-go/listers/core/v1"
+	k8scache "k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/scheduler-plugins/pkg/noderesourcetopology/podprovider"
+	tu "sigs.k8s.io/scheduler-plugins/test/util"
+)
+
+func TestSharedReserve(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	fakeClient, err := tu.NewFakeClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	kubeClient := clientsetfake.NewSimpleClientset()
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "pod1", Namespace: "default", UID: "pod1-uid"},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{
+							corev1.ResourceCPU:    resource.MustParse("8"),
+							corev1.ResourceMemory: resource.MustParse("16Gi"),
+						},
+					},
+				},
+			},
+		},
+	}
+	indexer := k8scache.NewIndexer(k8scache.MetaNamespaceKeyFunc, k8scache.Indexers{})
+	if err := indexer.Add(pod); err != nil {
+		t.Fatal(err)
+	}
+	podLister := podlisterv1.NewPodLister(indexer)
+
+	newCache := func() *SharedReserve {
+		sr, err := NewSharedReserve(ctx, klog.Background(), nil, fakeClient, kubeClient, podLister, podprovider.IsPodRelevantAlways)
+		if err != nil {
+			t.Fatalf("unexpected error creating cache: %v", err)
+		}
+		for _, obj := range makeDefaultTestTopology() {
+			sr.Store().Update(obj)
+		}
+		return sr
+	}
+	cacheA := newCache()
+	cacheB := newCache()
+
+	pristine, _ := cacheB.GetCachedNRTCopy(ctx, "node1", pod)
+
+	cacheA.ReserveNodeResources("node1", pod)
+	reservedA, _ := cacheA.GetCachedNRTCopy(ctx, "node1", pod)
+	if isNRTEqual(reservedA, pristine) {
+		t.Fatalf("reservation not accounted by the owner")
+	}
+
+	var reservedB *topologyv1alpha2.NodeResourceTopology
+	err = wait.PollUntilContextTimeout(ctx, 10*time.Millisecond, 5*time.Second, true, func(ctx context.Context) (bool, error) {
+		reservedB, _ = cacheB.GetCachedNRTCopy(ctx, "node1", pod)
+		return !isNRTEqual(reservedB, pristine), nil
+	})
+	if err != nil {
+		t.Fatalf("reservation not shared: %v", err)
+	}
+	if !isNRTEqual(reservedA, reservedB) {
+		t.Errorf("shared reservation mismatch\ngot: %s\nexpected: %s", dumpNRT(reservedB), dumpNRT(reservedA))
+	}
+	ownA, _ := cacheA.GetCachedNRTCopy(ctx, "node1", pod)
+	if !isNRTEqual(ownA, reservedA) {
+		t.Errorf("own reservation accounted twice\ngot: %s\nexpected: %s", dumpNRT(ownA), dumpNRT(reservedA))
+	}
+
+	bound := pod.DeepCopy()
+	bound.Spec.NodeName = "node1"
+	bound.Spec.SchedulerName = "other-scheduler"
+	cacheB.NodeHasForeignPods("node1", bound)
+	if _, info := cacheB.GetCachedNRTCopy(ctx, "node1", pod); !info.Fresh {
+		t.Errorf("pod reserved on the shared ledger handled as foreign")
+	}
+
+	cacheA.UnreserveNodeResources("node1", pod)
+	err = wait.PollUntilContextTimeout(ctx, 10*time.Millisecond, 5*time.Second, true, func(ctx context.Context) (bool, error) {
+		nrt, _ := cacheB.GetCachedNRTCopy(ctx, "node1", pod)
+		return isNRTEqual(nrt, pristine), nil
+	})
+	if err != nil {
+		t.Errorf("reservation not withdrawn: %v", err)
+	}
+	cms, err := kubeClient.CoreV1().ConfigMaps(metav1.NamespaceSystem).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cms.Items) != 0 {
+		t.Errorf("empty ledger not deleted: %v", cms.Items)
+	}
+
+	other := pod.DeepCopy()
+	other.Name = "pod2"
+	other.UID = "pod2-uid"
+	cacheB.NodeHasForeignPods("node1", other)
+	if _, info := cacheB.GetCachedNRTCopy(ctx, "node1", pod); info.Fresh {
+		t.Errorf("foreign pod not detected")
+	}
+}
+
+func TestSharedReserveFlush(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	fakeClient, err := tu.NewFakeClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	kubeClient := clientsetfake.NewSimpleClientset()
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "pod1", Namespace: "default", UID: "pod1-uid"},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{
+							corev1.ResourceCPU: resource.MustParse("4"),
+						},
+					},
+				},
+			},
+		},
+	}
+	indexer := k8scache.NewIndexer(k8scache.MetaNamespaceKeyFunc, k8scache.Indexers{})
+	if err := indexer.Add(pod); err != nil {
+		t.Fatal(err)
+	}
+	podLister := podlisterv1.NewPodLister(indexer)
+
+	newCache := func() *SharedReserve {
+		sr, err := NewSharedReserve(ctx, klog.Background(), nil, fakeClient, kubeClient, podLister, podprovider.IsPodRelevantAlways)
+		if err != nil {
+			t.Fatalf("unexpected error creating cache: %v", err)
+		}
+		for _, obj := range makeDefaultTestTopology() {
+			sr.Store().Update(obj)
+		}
+		return sr
+	}
+	cacheA := newCache()
+	cacheB := newCache()
+
+	cacheA.ReserveNodeResources("node1", pod)
+	err = wait.PollUntilContextTimeout(ctx, 10*time.Millisecond, 5*time.Second, true, func(ctx context.Context) (bool, error) {
+		return cacheB.ledger.Has("node1", pod), nil
+	})
+	if err != nil {
+		t.Fatalf("reservation not shared: %v", err)
+	}
+
+	// the pod is now running, and the updated NRT data includes its resources
+	bound := pod.DeepCopy()
+	bound.Spec.NodeName = "node1"
+	if err := indexer.Update(bound); err != nil {
+		t.Fatal(err)
+	}
+	updated := makeDefaultTestTopology()[0]
+	for zi := range updated.Zones {
+		updated.Zones[zi].Resources[0].Available = resource.MustParse("26")
+	}
+
+	lh := klog.Background()
+	cacheB.FlushNodes(lh, updated.DeepCopy())
+	if nrt, _ := cacheB.GetCachedNRTCopy(ctx, "node1", pod); !isNRTEqual(nrt, updated) {
+		t.Errorf("running pod accounted twice\ngot: %s\nexpected: %s", dumpNRT(nrt), dumpNRT(updated))
+	}
+
+	cacheA.FlushNodes(lh, updated.DeepCopy())
+	err = wait.PollUntilContextTimeout(ctx, 10*time.Millisecond, 5*time.Second, true, func(ctx context.Context) (bool, error) {
+		return !cacheB.ledger.Has("node1", pod), nil
+	})
+	if err != nil {
+		t.Errorf("reservation not withdrawn after flush: %v", err)
+	}
+}
+
+func TestSharedReserveStaleEntries(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	fakeClient, err := tu.NewFakeClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	makePod := func(name, nodeName string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", UID: types.UID(name + "-uid")},
+			Spec: corev1.PodSpec{
+				NodeName: nodeName,
+				Containers: []corev1.Container{
+					{
+						Resources: corev1.ResourceRequirements{
+							Requests: corev1.ResourceList{
+								corev1.ResourceCPU: resource.MustParse("4"),
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+	makeEntry := func(pod *corev1.Pod, timestamp time.Time) string {
+		value, err := json.Marshal(ledgerEntry{
+			Owner:     "other-scheduler",
+			Namespace: pod.Namespace,
+			Name:      pod.Name,
+			UID:       pod.UID,
+			Resources: pod.Spec.Containers[0].Resources.Requests,
+			Timestamp: metav1.NewTime(timestamp),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(value)
+	}
+
+	// bound to another node, reserved by an instance which didn't withdraw the reservation, or
+	// recreated with the same name since the reservation
+	elsewhere := makePod("elsewhere", "node2")
+	expired := makePod("expired", "")
+	recreated := makePod("recreated", "")
+	former := recreated.DeepCopy()
+	former.UID = "former-uid"
+	pod := makePod("pod1", "")
+	indexer := k8scache.NewIndexer(k8scache.MetaNamespaceKeyFunc, k8scache.Indexers{})
+	for _, p := range []*corev1.Pod{elsewhere, expired, recreated, pod} {
+		if err := indexer.Add(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	kubeClient := clientsetfake.NewSimpleClientset(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ledgerName("node1"),
+			Namespace: metav1.NamespaceSystem,
+			Labels:    map[string]string{LedgerLabel: ""},
+		},
+		Data: map[string]string{
+			ledgerKey("default", "elsewhere", elsewhere.UID): makeEntry(elsewhere, time.Now()),
+			ledgerKey("default", "expired", expired.UID):     makeEntry(expired, time.Now().Add(-2*ledgerEntryTTL)),
+			ledgerKey("default", "recreated", former.UID):    makeEntry(former, time.Now()),
+		},
+	})
+
+	sr, err := NewSharedReserve(ctx, klog.Background(), nil, fakeClient, kubeClient, podlisterv1.NewPodLister(indexer), podprovider.IsPodRelevantAlways)
+	if err != nil {
+		t.Fatalf("unexpected error creating cache: %v", err)
+	}
+	for _, obj := range makeDefaultTestTopology() {
+		sr.Store().Update(obj)
+	}
+	if len(sr.ledger.Entries("node1")) != 3 {
+		t.Fatalf("ledger not synced: %v", sr.ledger.Entries("node1"))
+	}
+
+	pristine := makeDefaultTestTopology()[0]
+	if nrt, _ := sr.GetCachedNRTCopy(ctx, "node1", pod); !isNRTEqual(nrt, pristine) {
+		t.Errorf("stale reservations accounted\ngot: %s\nexpected: %s", dumpNRT(nrt), dumpNRT(pristine))
+	}
+
+	// the expired reservation is dropped once the ledger of the node is updated
+	sr.ReserveNodeResources("node1", pod)
+	err = wait.PollUntilContextTimeout(ctx, 10*time.Millisecond, 5*time.Second, true, func(ctx context.Context) (bool, error) {
+		cm, err := kubeClient.CoreV1().ConfigMaps(metav1.NamespaceSystem).Get(ctx, ledgerName("node1"), metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		_, hasExpired := cm.Data[ledgerKey("default", "expired", expired.UID)]
+		_, hasPod := cm.Data[ledgerKey("default", "pod1", pod.UID)]
+		return !hasExpired && hasPod, nil
+	})
+	if err != nil {
+		t.Errorf("expired reservation not dropped: %v", err)
+	}
+
+	// the owner drops its own reservation once expired, instead of publishing it again
+	sr.ledger.lock.Lock()
+	for dataKey, entry := range sr.ledger.published["node1"] {
+		entry.Timestamp = metav1.NewTime(time.Now().Add(-2 * ledgerEntryTTL))
+		sr.ledger.published["node1"][dataKey] = entry
+	}
+	sr.ledger.lock.Unlock()
+	sr.ledger.queue.Add("node1")
+	err = wait.PollUntilContextTimeout(ctx, 10*time.Millisecond, 5*time.Second, true, func(ctx context.Context) (bool, error) {
+		cm, err := kubeClient.CoreV1().ConfigMaps(metav1.NamespaceSystem).Get(ctx, ledgerName("node1"), metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		_, hasPod := cm.Data[ledgerKey("default", "pod1", pod.UID)]
+		return !hasPod, nil
+	})
+	if err != nil {
+		t.Errorf("expired own reservation not dropped: %v", err)
+	}
+	sr.ledger.lock.Lock()
+	if published := sr.ledger.published["node1"]; len(published) != 0 {
+		t.Errorf("expired own reservation still published: %v", published)
+	}
+	sr.ledger.lock.Unlock()
+
+	// the recreated pod is not the one reserved on the ledger
+	bound := recreated.DeepCopy()
+	bound.Spec.NodeName = "node1"
+	sr.NodeHasForeignPods("node1", bound)
+	if _, info := sr.GetCachedNRTCopy(ctx, "node1", pod); info.Fresh {
+		t.Errorf("recreated pod not detected as foreign")
+	}
+}
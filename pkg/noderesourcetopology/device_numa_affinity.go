@@ -0,0 +1,311 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"sort"
+
+	v1 "k8s.io/api/core/v1"
+	fwk "k8s.io/kube-scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"github.com/go-logr/logr"
+	"gonum.org/v1/gonum/stat/combin"
+
+	"sigs.k8s.io/scheduler-plugins/pkg/util"
+)
+
+// devicePlacement describes how the resources of a pod, or of a container, would be placed on the NUMA nodes
+type devicePlacement struct {
+	// numaNodesCount is the number of NUMA nodes holding any of the resources
+	numaNodesCount int
+	// isMinAvgDistance tells whether the CPUs and memory are placed on the NUMA nodes with the minimal distance between them
+	isMinAvgDistance bool
+	// distanceRatio is the distance between the farthest device and the CPUs and memory, relative to the local distance.
+	// 1 means that all the devices share a NUMA node with the CPUs and memory.
+	distanceRatio float32
+}
+
+func deviceNUMAAffinityContainerScopeScore(lh logr.Logger, pod *v1.Pod, info *scoreInfo) (int64, *fwk.Status) {
+	maxNUMANodesCount := 0
+	allContainersMinAvgDistance := true
+	var maxDistanceRatio float32 = 1
+	// the order how TopologyManager asks for hint is important so doing it in the same order
+	// https://github.com/kubernetes/kubernetes/blob/master/pkg/kubelet/cm/topologymanager/scope_container.go#L52
+	for _, container := range append(pod.Spec.InitContainers, pod.Spec.Containers...) {
+		// if a container requests only non NUMA just continue
+		if onlyNonNUMAResources(info.numaNodes, container.Resources.Requests) {
+			continue
+		}
+		// placeDevices subtracts the resources of the container, so we won't allocate them again for the upcoming containers
+		placement, ok := placeDevices(lh, info.qos, info.numaNodes, container.Resources.Requests)
+		if !ok {
+			// score plugin should be running after resource filter plugin so we should always find sufficient amount of NUMA nodes
+			lh.Info("cannot calculate how to place devices on NUMA nodes", "container", container.Name)
+			return framework.MinNodeScore, nil
+		}
+
+		if !placement.isMinAvgDistance {
+			allContainersMinAvgDistance = false
+		}
+		if placement.numaNodesCount > maxNUMANodesCount {
+			maxNUMANodesCount = placement.numaNodesCount
+		}
+		if placement.distanceRatio > maxDistanceRatio {
+			maxDistanceRatio = placement.distanceRatio
+		}
+	}
+
+	if maxNUMANodesCount == 0 {
+		return framework.MaxNodeScore, nil
+	}
+
+	return normalizeDeviceScore(maxNUMANodesCount, allContainersMinAvgDistance, maxDistanceRatio, info.topologyManager.MaxNUMANodes), nil
+}
+
+func deviceNUMAAffinityPodScopeScore(lh logr.Logger, pod *v1.Pod, info *scoreInfo) (int64, *fwk.Status) {
+	resources := util.GetPodEffectiveRequest(pod)
+	// if a pod requests only non NUMA resources return max score
+	if onlyNonNUMAResources(info.numaNodes, resources) {
+		return framework.MaxNodeScore, nil
+	}
+
+	placement, ok := placeDevices(lh, info.qos, info.numaNodes, resources)
+	if !ok {
+		// score plugin should be running after resource filter plugin so we should always find sufficient amount of NUMA nodes
+		lh.Info("cannot calculate how to place devices on NUMA nodes")
+		return framework.MinNodeScore, nil
+	}
+
+	return normalizeDeviceScore(placement.numaNodesCount, placement.isMinAvgDistance, placement.distanceRatio, info.topologyManager.MaxNUMANodes), nil
+}
+
+// normalizeDeviceScore scores the placement like LeastNUMANodes does, and scales the score down
+// as the devices get farther from the CPUs and memory
+func normalizeDeviceScore(numaNodesCount int, isMinAvgDistance bool, distanceRatio float32, highestNUMAID int) int64 {
+	score := normalizeScore(numaNodesCount, isMinAvgDistance, highestNUMAID)
+	if distanceRatio <= 1 {
+		return score
+	}
+	return int64(float32(score) / distanceRatio)
+}
+
+// placeDevices places the CPUs and memory on the fewest NUMA nodes, and each device on the NUMA nodes closest to them.
+// Among the combinations of the fewest NUMA nodes, the one which lets the devices be the closest wins.
+// The placed resources are subtracted from numaNodes. The second value returned is false if the resources can't fit.
+func placeDevices(lh logr.Logger, qos v1.PodQOSClass, numaNodes NUMANodeList, resources v1.ResourceList) (devicePlacement, bool) {
+	compute, devices := splitDeviceResources(numaNodes, resources)
+
+	computeCombinations := [][]int{nil}
+	var minAvgDistance float32
+	if len(compute) > 0 {
+		computeCombinations, minAvgDistance = computeCombinationsRequired(lh, qos, numaNodes, compute)
+		if len(computeCombinations) == 0 {
+			return devicePlacement{}, false
+		}
+	}
+
+	var (
+		best          devicePlacement
+		bestNUMANodes NUMANodeList
+	)
+	for _, computeNodes := range computeCombinations {
+		nodes := numaNodes.DeepCopy()
+		placement, ok := placeDevicesCloseTo(lh, qos, nodes, compute, devices, computeNodes)
+		if !ok {
+			continue
+		}
+		placement.isMinAvgDistance = computeNodes == nil || nodesAvgDistance(lh, numaNodes, computeNodes...) == minAvgDistance
+		if bestNUMANodes == nil || placement.betterThan(best) {
+			best = placement
+			bestNUMANodes = nodes
+		}
+	}
+	if bestNUMANodes == nil {
+		return devicePlacement{}, false
+	}
+
+	copy(numaNodes, bestNUMANodes)
+	return best, true
+}
+
+// computeCombinationsRequired returns all the combinations of the fewest NUMA nodes which can fit the resources,
+// along with the minimal average distance between the NUMA nodes of a combination of that size
+func computeCombinationsRequired(lh logr.Logger, qos v1.PodQOSClass, numaNodes NUMANodeList, resources v1.ResourceList) ([][]int, float32) {
+	for combinationLen := 1; combinationLen <= len(numaNodes); combinationLen++ {
+		numaNodesCombination := combin.Combinations(len(numaNodes), combinationLen)
+		var suitable [][]int
+		for _, combination := range numaNodesCombination {
+			if !isValidCombineResources(numaNodes, resources, combination) {
+				continue
+			}
+			if checkResourcesFit(lh, qos, resources, combineResources(numaNodes, combination)) {
+				suitable = append(suitable, combination)
+			}
+		}
+		if len(suitable) > 0 {
+			return suitable, minAvgDistanceInCombinations(lh, numaNodes, numaNodesCombination)
+		}
+	}
+
+	return nil, maxDistanceValue
+}
+
+// placeDevicesCloseTo subtracts the CPUs and memory from the given NUMA nodes, then each device from the NUMA nodes closest to them
+func placeDevicesCloseTo(lh logr.Logger, qos v1.PodQOSClass, numaNodes NUMANodeList, compute, devices v1.ResourceList, computeNodes []int) (devicePlacement, bool) {
+	placement := devicePlacement{
+		distanceRatio: 1,
+	}
+	used := make(map[int]struct{})
+
+	subtractFromNUMAs(compute, numaNodes, computeNodes...)
+	for _, idx := range computeNodes {
+		used[idx] = struct{}{}
+	}
+	localDistance := localNUMADistance(numaNodes, computeNodes)
+
+	// sort the devices to make the placement deterministic when they compete for the same NUMA nodes
+	names := make([]v1.ResourceName, 0, len(devices))
+	for name := range devices {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+
+	for _, name := range names {
+		device := v1.ResourceList{name: devices[name]}
+		deviceNodes, distance := closestDeviceCombination(lh, qos, numaNodes, device, computeNodes)
+		if deviceNodes == nil {
+			lh.V(4).Info("cannot place device on NUMA nodes", "resource", name)
+			return placement, false
+		}
+		subtractFromNUMAs(device, numaNodes, deviceNodes...)
+		for _, idx := range deviceNodes {
+			used[idx] = struct{}{}
+		}
+		if computeNodes == nil {
+			continue
+		}
+		if ratio := distance / localDistance; ratio > placement.distanceRatio {
+			placement.distanceRatio = ratio
+		}
+	}
+
+	placement.numaNodesCount = len(used)
+	return placement, true
+}
+
+// betterThan tells whether the placement keeps the devices closer to the CPUs and memory than the other one,
+// or as close using fewer NUMA nodes
+func (p devicePlacement) betterThan(o devicePlacement) bool {
+	if p.distanceRatio != o.distanceRatio {
+		return p.distanceRatio < o.distanceRatio
+	}
+	if p.numaNodesCount != o.numaNodesCount {
+		return p.numaNodesCount < o.numaNodesCount
+	}
+	return p.isMinAvgDistance && !o.isMinAvgDistance
+}
+
+// splitDeviceResources splits the requested resources which are exposed by the NUMA nodes
+// in the CPUs and memory, which must be aligned, and the devices
+func splitDeviceResources(numaNodes NUMANodeList, resources v1.ResourceList) (v1.ResourceList, v1.ResourceList) {
+	compute := v1.ResourceList{}
+	devices := v1.ResourceList{}
+	for name, quantity := range resources {
+		if quantity.IsZero() || onlyNonNUMAResources(numaNodes, v1.ResourceList{name: quantity}) {
+			continue
+		}
+		if isNUMAAffineResource(name) {
+			compute[name] = quantity
+			continue
+		}
+		devices[name] = quantity
+	}
+	return compute, devices
+}
+
+// closestDeviceCombination returns the combination of NUMA nodes which can fit the device and is the closest to
+// the given NUMA nodes, along with its distance to them. Among equally close combinations the smallest wins.
+// Without NUMA nodes to be close to, the device is placed on the fewest NUMA nodes.
+func closestDeviceCombination(lh logr.Logger, qos v1.PodQOSClass, numaNodes NUMANodeList, device v1.ResourceList, nodes []int) ([]int, float32) {
+	if len(nodes) == 0 {
+		combination, _ := numaNodesCombinationRequired(lh, qos, numaNodes, device)
+		return combination, 0
+	}
+
+	var (
+		closestCombination []int
+		// init as max distance
+		minDistance float32 = maxDistanceValue + 1
+	)
+	for combinationLen := 1; combinationLen <= len(numaNodes); combinationLen++ {
+		for _, combination := range combin.Combinations(len(numaNodes), combinationLen) {
+			if !isValidCombineResources(numaNodes, device, combination) {
+				continue
+			}
+			if !checkResourcesFit(lh, qos, device, combineResources(numaNodes, combination)) {
+				continue
+			}
+			if distance := nodesDistanceTo(lh, numaNodes, combination, nodes); distance < minDistance {
+				minDistance = distance
+				closestCombination = combination
+			}
+		}
+	}
+
+	return closestCombination, minDistance
+}
+
+// nodesDistanceTo returns the largest distance between a NUMA node of from and its closest NUMA node of to,
+// i.e. the distance of the farthest NUMA node of from
+func nodesDistanceTo(lh logr.Logger, numaNodes NUMANodeList, from, to []int) float32 {
+	if len(from) == 0 || len(to) == 0 {
+		return maxDistanceValue
+	}
+
+	farthest := 0
+	for _, node1 := range from {
+		closest := maxDistanceValue
+		for _, node2 := range to {
+			cost, ok := numaNodes[node1].Costs[numaNodes[node2].NUMAID]
+			// we couldn't read Costs assign maxDistanceValue
+			if !ok {
+				lh.V(4).Info("cannot retrieve Costs information", "nodeID", numaNodes[node1].NUMAID)
+				cost = maxDistanceValue
+			}
+			if cost < closest {
+				closest = cost
+			}
+		}
+		if closest > farthest {
+			farthest = closest
+		}
+	}
+
+	return float32(farthest)
+}
+
+// localNUMADistance returns the distance of the given NUMA nodes to themselves, maxDistanceValue if unknown
+func localNUMADistance(numaNodes NUMANodeList, nodes []int) float32 {
+	local := maxDistanceValue
+	for _, node := range nodes {
+		if cost, ok := numaNodes[node].Costs[numaNodes[node].NUMAID]; ok && cost > 0 && cost < local {
+			local = cost
+		}
+	}
+	return float32(local)
+}
@@ -0,0 +1,148 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"sigs.k8s.io/scheduler-plugins/pkg/noderesourcetopology/nodeconfig"
+)
+
+func TestDeviceNUMAAffinityScore(t *testing.T) {
+	twoNUMANodes := func(numa0, numa1 v1.ResourceList) NUMANodeList {
+		return NUMANodeList{
+			{NUMAID: 0, Resources: numa0, Costs: map[int]int{0: 10, 1: 20}},
+			{NUMAID: 1, Resources: numa1, Costs: map[int]int{0: 20, 1: 10}},
+		}
+	}
+	cpuGPU := func(cpus, gpus int64) v1.ResourceList {
+		res := v1.ResourceList{
+			v1.ResourceCPU:    *resource.NewQuantity(cpus, resource.DecimalSI),
+			v1.ResourceMemory: resource.MustParse("4Gi"),
+		}
+		if gpus > 0 {
+			res[gpuResource] = *resource.NewQuantity(gpus, resource.DecimalSI)
+		}
+		return res
+	}
+	container := v1.ResourceList{
+		v1.ResourceCPU:    *resource.NewQuantity(2, resource.DecimalSI),
+		v1.ResourceMemory: resource.MustParse("1Gi"),
+		gpuResource:       *resource.NewQuantity(1, resource.DecimalSI),
+	}
+
+	tests := []struct {
+		name          string
+		numaNodes     NUMANodeList
+		pod           *v1.Pod
+		podScope      bool
+		expectedScore int64
+	}{
+		{
+			name:          "CPUs are placed on the NUMA node of the device",
+			numaNodes:     twoNUMANodes(cpuGPU(4, 0), cpuGPU(4, 1)),
+			pod:           makePodByResourceList(&container),
+			podScope:      true,
+			expectedScore: normalizeScore(1, true, nodeconfig.DefaultMaxNUMANodes),
+		},
+		{
+			name:          "device is on the remote NUMA node",
+			numaNodes:     twoNUMANodes(cpuGPU(4, 0), cpuGPU(1, 1)),
+			pod:           makePodByResourceList(&container),
+			podScope:      true,
+			expectedScore: normalizeScore(2, true, nodeconfig.DefaultMaxNUMANodes) / 2,
+		},
+		{
+			name:      "device split between the local and the remote NUMA nodes",
+			numaNodes: twoNUMANodes(cpuGPU(4, 1), cpuGPU(1, 1)),
+			pod: makePodByResourceList(&v1.ResourceList{
+				v1.ResourceCPU:    *resource.NewQuantity(2, resource.DecimalSI),
+				v1.ResourceMemory: resource.MustParse("1Gi"),
+				gpuResource:       *resource.NewQuantity(2, resource.DecimalSI),
+			}),
+			podScope:      true,
+			expectedScore: normalizeScore(2, true, nodeconfig.DefaultMaxNUMANodes) / 2,
+		},
+		{
+			name:      "device can't fit",
+			numaNodes: twoNUMANodes(cpuGPU(4, 1), cpuGPU(4, 0)),
+			pod: makePodByResourceList(&v1.ResourceList{
+				v1.ResourceCPU: *resource.NewQuantity(2, resource.DecimalSI),
+				gpuResource:    *resource.NewQuantity(2, resource.DecimalSI),
+			}),
+			podScope:      true,
+			expectedScore: framework.MinNodeScore,
+		},
+		{
+			name:      "no device requested",
+			numaNodes: twoNUMANodes(cpuGPU(4, 1), cpuGPU(4, 1)),
+			pod: makePodByResourceList(&v1.ResourceList{
+				v1.ResourceCPU: *resource.NewQuantity(2, resource.DecimalSI),
+			}),
+			podScope:      true,
+			expectedScore: normalizeScore(1, true, nodeconfig.DefaultMaxNUMANodes),
+		},
+		{
+			name: "unknown distances don't lower the score",
+			numaNodes: NUMANodeList{
+				{NUMAID: 0, Resources: cpuGPU(4, 0)},
+				{NUMAID: 1, Resources: cpuGPU(1, 1)},
+			},
+			pod:           makePodByResourceList(&container),
+			podScope:      true,
+			expectedScore: normalizeScore(2, true, nodeconfig.DefaultMaxNUMANodes),
+		},
+		{
+			name:          "containers get the devices of their own NUMA node",
+			numaNodes:     twoNUMANodes(cpuGPU(4, 1), cpuGPU(4, 1)),
+			pod:           makePodByResourceLists(container, container),
+			expectedScore: normalizeScore(1, true, nodeconfig.DefaultMaxNUMANodes),
+		},
+		{
+			name:          "second container gets a remote device",
+			numaNodes:     twoNUMANodes(cpuGPU(2, 2), cpuGPU(4, 0)),
+			pod:           makePodByResourceLists(container, container),
+			expectedScore: normalizeScore(2, true, nodeconfig.DefaultMaxNUMANodes) / 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := &scoreInfo{
+				topologyManager: nodeconfig.TopologyManagerDefaults(),
+				qos:             v1.PodQOSGuaranteed,
+				numaNodes:       tt.numaNodes,
+			}
+			scoreFn := deviceNUMAAffinityContainerScopeScore
+			if tt.podScope {
+				scoreFn = deviceNUMAAffinityPodScopeScore
+			}
+			score, status := scoreFn(klog.Background(), tt.pod, info)
+			if !status.IsSuccess() {
+				t.Fatalf("unexpected status: %v", status)
+			}
+			if score != tt.expectedScore {
+				t.Errorf("score does not match: %v, want: %v", score, tt.expectedScore)
+			}
+		})
+	}
+}
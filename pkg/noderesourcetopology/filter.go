@@ -168,11 +168,18 @@ func singleNUMAPodLevelHandler(lh logr.Logger, pod *v1.Pod, info *filterInfo) *f
 		lh.V(2).Info("cannot align pod", "name", pod.Name, "reason", reason)
 		return fwk.NewStatus(fwk.Unschedulable, "cannot align pod")
 	}
+	// subtract the resources requested by the pod from the given NUMA, so that the placement of further pods can be simulated
+	err := subtractResourcesFromNUMANodeList(lh, info.numaNodes, numaID, info.qos, resources)
+	if err != nil {
+		// this is an internal error which should never happen
+		return fwk.NewStatus(fwk.Error, "inconsistent resource accounting", err.Error())
+	}
 	lh.V(4).Info("all container placed", "numaCell", numaID)
 	return nil
 }
 
-// Filter Now only single-numa-node supported
+// Filter supports the single-numa-node and restricted policies, the only ones which make Kubelet reject pods
+// if it can't align their resources.
 func (tm *TopologyMatch) Filter(ctx context.Context, cycleState fwk.CycleState, pod *v1.Pod, nodeInfo fwk.NodeInfo) *fwk.Status {
 	if nodeInfo.Node() == nil {
 		return fwk.NewStatus(fwk.Error, "node not found")
@@ -215,6 +222,7 @@ func (tm *TopologyMatch) Filter(ctx context.Context, cycleState fwk.CycleState,
 		node:            nodeInfo,
 		topologyManager: conf,
 		numaNodes:       numaNodes,
+		numaAllocatable: extractNUMAAllocatable(nodeTopology.Zones),
 		qos:             qos,
 	}
 	status := handler(lh, pod, &fi)
@@ -225,6 +233,16 @@ func (tm *TopologyMatch) Filter(ctx context.Context, cycleState fwk.CycleState,
 }
 
 func filterHandlerFromTopologyManager(conf nodeconfig.TopologyManager) (filterFn, string) {
+	if conf.Policy == kubeletconfig.RestrictedTopologyManagerPolicy {
+		if conf.Scope == kubeletconfig.PodTopologyManagerScope {
+			return restrictedPodLevelHandler, "pod"
+		}
+		if conf.Scope == kubeletconfig.ContainerTopologyManagerScope {
+			return restrictedContainerLevelHandler, "container"
+		}
+		return nil, "" // cannot happen
+	}
+	// best-effort and none policies always admit pods
 	if conf.Policy != kubeletconfig.SingleNumaNodeTopologyManagerPolicy {
 		return nil, ""
 	}
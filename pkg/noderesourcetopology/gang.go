@@ -0,0 +1,295 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/go-logr/logr"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	fwk "k8s.io/kube-scheduler/framework"
+	v1qos "k8s.io/kubernetes/pkg/apis/core/v1/helper/qos"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"sigs.k8s.io/scheduler-plugins/apis/scheduling/v1alpha1"
+	"sigs.k8s.io/scheduler-plugins/pkg/noderesourcetopology/logging"
+	"sigs.k8s.io/scheduler-plugins/pkg/noderesourcetopology/nodeconfig"
+	"sigs.k8s.io/scheduler-plugins/pkg/noderesourcetopology/resourcerequests"
+	"sigs.k8s.io/scheduler-plugins/pkg/util"
+)
+
+const (
+	// ReasonNUMAAligned is the reason of the NUMAAligned condition of a PodGroup whose pods can be aligned.
+	ReasonNUMAAligned = "Aligned"
+	// ReasonNUMAMisaligned is the reason of the NUMAAligned condition of a PodGroup whose pods can't be aligned.
+	ReasonNUMAMisaligned = "CannotAlign"
+)
+
+// gangNode is the state of a candidate node while simulating the placement of the pods of a PodGroup.
+type gangNode struct {
+	info            fwk.NodeInfo
+	topologyManager nodeconfig.TopologyManager
+	// numaNodes are the resources left on the NUMA nodes, nil if the node doesn't align resources.
+	numaNodes       NUMANodeList
+	numaAllocatable map[int]v1.ResourceList
+	// handler aligns the resources of a pod on the NUMA nodes, and subtracts them.
+	handler filterFn
+	// available are the resources left on the node.
+	available v1.ResourceList
+}
+
+// PreFilter checks that the MinMember pods of the PodGroup of the pod can be aligned on NUMA nodes all together,
+// so that the PodGroup fails early rather than once a part of its pods is already placed. The outcome is reported
+// in the NUMAAligned condition of the PodGroup. Pods which don't belong to a PodGroup are not checked.
+func (tm *TopologyMatch) PreFilter(ctx context.Context, cycleState fwk.CycleState, pod *v1.Pod, nodes []fwk.NodeInfo) (*framework.PreFilterResult, *fwk.Status) {
+	pgName := util.GetPodGroupLabel(pod)
+	if pgName == "" || tm.pgClient == nil {
+		return nil, nil
+	}
+	pgFullName := util.GetPodGroupFullName(pod)
+
+	lh := klog.FromContext(klog.NewContext(ctx, tm.logger)).WithValues(logging.KeyPod, klog.KObj(pod), logging.KeyPodUID, logging.PodUID(pod), "podGroup", pgFullName)
+
+	// The members of an admitted PodGroup are reserved in the cache as they are scheduled,
+	// so simulating their placement again would account them twice.
+	if _, ok := tm.admittedPG.Get(pgFullName); ok {
+		return nil, nil
+	}
+
+	pg := &v1alpha1.PodGroup{}
+	if err := tm.pgClient.Get(ctx, types.NamespacedName{Namespace: pod.Namespace, Name: pgName}, pg); err != nil {
+		lh.V(4).Info("cannot get PodGroup, skipping gang admission", "error", err)
+		return nil, nil
+	}
+
+	members, err := tm.gangMembers(pod, pg)
+	if err != nil {
+		return nil, fwk.AsStatus(err)
+	}
+	if len(members) == 0 {
+		return nil, nil
+	}
+
+	unaligned := tm.simulateGangPlacement(ctx, lh, members, nodes)
+	if unaligned == 0 {
+		lh.V(4).Info("gang admitted", "members", len(members))
+		tm.admittedPG.Set(pgFullName, nil, util.GetWaitTimeDuration(pg, nil))
+		tm.setNUMAAlignedCondition(ctx, lh, pg, metav1.ConditionTrue, ReasonNUMAAligned,
+			fmt.Sprintf("%v pods can be aligned on NUMA nodes", len(members)))
+		return nil, nil
+	}
+
+	msg := fmt.Sprintf("cannot align %v of %v pods of PodGroup %v on NUMA nodes", unaligned, len(members), pgFullName)
+	lh.V(2).Info("gang rejected", "members", len(members), "unaligned", unaligned)
+	tm.setNUMAAlignedCondition(ctx, lh, pg, metav1.ConditionFalse, ReasonNUMAMisaligned, msg)
+	// The simulation only accounts the resources of the nodes, not e.g. their taints or the affinity of the pods,
+	// so the pods may fit on other nodes than the ones they are simulated on: preemption may still help.
+	return nil, fwk.NewStatus(fwk.Unschedulable, msg)
+}
+
+// PreFilterExtensions returns a PreFilterExtensions interface if the plugin implements one.
+func (tm *TopologyMatch) PreFilterExtensions() framework.PreFilterExtensions {
+	return nil
+}
+
+// gangMembers returns the pods of the PodGroup still to be placed to reach its MinMember, starting with the given pod.
+// The pods already bound to a node are accounted in the NRT data.
+func (tm *TopologyMatch) gangMembers(pod *v1.Pod, pg *v1alpha1.PodGroup) ([]*v1.Pod, error) {
+	pods, err := tm.podLister.Pods(pod.Namespace).List(
+		labels.SelectorFromSet(labels.Set{v1alpha1.PodGroupLabel: pg.Name}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("podLister list pods failed: %w", err)
+	}
+
+	needed := int(pg.Spec.MinMember)
+	var pending []*v1.Pod
+	for _, p := range pods {
+		if p.Spec.NodeName != "" {
+			needed--
+			continue
+		}
+		if p.UID == pod.UID || p.DeletionTimestamp != nil {
+			continue
+		}
+		pending = append(pending, p)
+	}
+	if needed <= 0 {
+		return nil, nil
+	}
+
+	sort.Slice(pending, func(i, j int) bool { return pending[i].Name < pending[j].Name })
+	members := append([]*v1.Pod{pod}, pending...)
+	if len(members) > needed {
+		members = members[:needed]
+	}
+	return members, nil
+}
+
+// simulateGangPlacement places the pods on the nodes which can align them, and returns the number of pods
+// which can't be placed. Like a best-fit decreasing bin packing, the pods are placed from the largest request,
+// each on the node with the least free capacity left which can host it, so that the large pods are not
+// left without a node fragmented by the small ones.
+func (tm *TopologyMatch) simulateGangPlacement(ctx context.Context, lh logr.Logger, pods []*v1.Pod, nodes []fwk.NodeInfo) int {
+	var gangNodes []*gangNode
+	for _, nodeInfo := range nodes {
+		if nodeInfo == nil || nodeInfo.Node() == nil {
+			continue
+		}
+		if gn := tm.newGangNode(ctx, lh, pods[0], nodeInfo); gn != nil {
+			gangNodes = append(gangNodes, gn)
+		}
+	}
+
+	pods = append([]*v1.Pod(nil), pods...)
+	sort.SliceStable(pods, func(i, j int) bool {
+		return lessCapacity(util.GetPodEffectiveRequest(pods[j]), util.GetPodEffectiveRequest(pods[i]))
+	})
+	unaligned := 0
+	for _, pod := range pods {
+		sort.SliceStable(gangNodes, func(i, j int) bool {
+			return lessCapacity(gangNodes[i].available, gangNodes[j].available)
+		})
+		placed := false
+		for _, gn := range gangNodes {
+			if gn.place(lh, pod) {
+				placed = true
+				break
+			}
+		}
+		if !placed {
+			unaligned++
+		}
+	}
+	return unaligned
+}
+
+// lessCapacity orders the resources by CPU, then by memory.
+func lessCapacity(a, b v1.ResourceList) bool {
+	if c := a.Cpu().Cmp(*b.Cpu()); c != 0 {
+		return c < 0
+	}
+	return a.Memory().Cmp(*b.Memory()) < 0
+}
+
+// newGangNode returns the state of the node for the simulation, or nil if the node can't host any pod,
+// e.g. because its topology data is stale.
+func (tm *TopologyMatch) newGangNode(ctx context.Context, lh logr.Logger, pod *v1.Pod, nodeInfo fwk.NodeInfo) *gangNode {
+	nodeName := nodeInfo.Node().Name
+	nodeTopology, info := tm.nrtCache.GetCachedNRTCopy(ctx, nodeName, pod)
+	if !info.Fresh {
+		lh.V(4).Info("invalid topology data", logging.KeyNode, nodeName)
+		return nil
+	}
+
+	gn := &gangNode{
+		info:      nodeInfo,
+		available: util.ResourceList(nodeInfo.GetAllocatable()),
+	}
+	for name, quantity := range util.ResourceList(nodeInfo.GetRequested()) {
+		if name == v1.ResourcePods {
+			continue
+		}
+		available := gn.available[name]
+		available.Sub(quantity)
+		gn.available[name] = available
+	}
+	gn.available[v1.ResourcePods] = *resource.NewQuantity(int64(nodeInfo.GetAllocatable().GetAllowedPodNumber()-len(nodeInfo.GetPods())), resource.DecimalSI)
+
+	if nodeTopology == nil {
+		return gn
+	}
+	gn.topologyManager = nodeconfig.TopologyManagerFromNodeResourceTopology(lh, nodeTopology)
+	handler, _ := filterHandlerFromTopologyManager(gn.topologyManager)
+	if handler == nil {
+		return gn
+	}
+	gn.handler = handler
+	gn.numaNodes = createNUMANodeList(lh, nodeTopology.Zones)
+	gn.numaAllocatable = extractNUMAAllocatable(nodeTopology.Zones)
+	return gn
+}
+
+// place accounts the resources of the pod on the node, if the node can host it and align its resources.
+func (gn *gangNode) place(lh logr.Logger, pod *v1.Pod) bool {
+	requests := util.GetPodEffectiveRequest(pod)
+	for name, quantity := range requests {
+		if quantity.IsZero() {
+			continue
+		}
+		if available, ok := gn.available[name]; !ok || available.Cmp(quantity) < 0 {
+			return false
+		}
+	}
+	if available := gn.available[v1.ResourcePods]; available.Value() < 1 {
+		return false
+	}
+
+	qos := v1qos.GetPodQOS(pod)
+	if gn.numaNodes != nil && (qos != v1.PodQOSBestEffort || resourcerequests.IncludeNonNative(pod)) {
+		fi := &filterInfo{
+			nodeName:        gn.info.Node().Name,
+			node:            gn.info,
+			topologyManager: gn.topologyManager,
+			numaNodes:       gn.numaNodes.DeepCopy(),
+			numaAllocatable: gn.numaAllocatable,
+			qos:             qos,
+		}
+		if status := gn.handler(lh, pod, fi); status != nil {
+			return false
+		}
+		gn.numaNodes = fi.numaNodes
+	}
+
+	for name, quantity := range requests {
+		if available, ok := gn.available[name]; ok {
+			available.Sub(quantity)
+			gn.available[name] = available
+		}
+	}
+	pods := gn.available[v1.ResourcePods]
+	pods.Sub(*resource.NewQuantity(1, resource.DecimalSI))
+	gn.available[v1.ResourcePods] = pods
+	return true
+}
+
+// setNUMAAlignedCondition reports whether the pods of the PodGroup can be aligned in its status, if it changed.
+func (tm *TopologyMatch) setNUMAAlignedCondition(ctx context.Context, lh logr.Logger, pg *v1alpha1.PodGroup, status metav1.ConditionStatus, reason, message string) {
+	pgCopy := pg.DeepCopy()
+	changed := meta.SetStatusCondition(&pgCopy.Status.Conditions, metav1.Condition{
+		Type:               v1alpha1.PodGroupNUMAAligned,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: pg.Generation,
+	})
+	if !changed {
+		return
+	}
+	if err := tm.pgClient.Status().Patch(ctx, pgCopy, ctrlclient.MergeFrom(pg)); err != nil {
+		lh.Error(err, "cannot update the PodGroup status")
+	}
+}
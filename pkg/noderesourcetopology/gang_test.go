@@ -0,0 +1,200 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+	gocache "github.com/patrickmn/go-cache"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/informers"
+	clientsetfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/klog/v2"
+	fwk "k8s.io/kube-scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"sigs.k8s.io/scheduler-plugins/apis/scheduling/v1alpha1"
+	nrtcache "sigs.k8s.io/scheduler-plugins/pkg/noderesourcetopology/cache"
+)
+
+func TestGangPreFilter(t *testing.T) {
+	// Each NUMA node fits a single pod, while the node fits three pods at node level.
+	nrt := &topologyv1alpha2.NodeResourceTopology{
+		ObjectMeta:       metav1.ObjectMeta{Name: "node1"},
+		TopologyPolicies: []string{string(topologyv1alpha2.SingleNUMANodePodLevel)},
+		Zones: topologyv1alpha2.ZoneList{
+			{
+				Name: "node-0",
+				Type: "Node",
+				Resources: topologyv1alpha2.ResourceInfoList{
+					MakeTopologyResInfo(cpu, "5", "5"),
+					MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+				},
+			},
+			{
+				Name: "node-1",
+				Type: "Node",
+				Resources: topologyv1alpha2.ResourceInfoList{
+					MakeTopologyResInfo(cpu, "5", "5"),
+					MakeTopologyResInfo(memory, "8Gi", "8Gi"),
+				},
+			},
+		},
+	}
+	node := makeNodeFromNodeResourceTopology(nrt)
+	node.Status.Allocatable[v1.ResourcePods] = resource.MustParse("110")
+	nodeInfo := framework.NewNodeInfo()
+	nodeInfo.SetNode(node)
+
+	makeSizedMember := func(name, nodeName, cpu string) *v1.Pod {
+		pod := makePodByResourceList(&v1.ResourceList{
+			v1.ResourceCPU:    resource.MustParse(cpu),
+			v1.ResourceMemory: resource.MustParse("2Gi"),
+		})
+		pod.Name = name
+		pod.Namespace = "default"
+		pod.UID = types.UID(name)
+		pod.Labels = map[string]string{v1alpha1.PodGroupLabel: "pg"}
+		pod.Spec.NodeName = nodeName
+		return pod
+	}
+	makeMember := func(name, nodeName string) *v1.Pod {
+		return makeSizedMember(name, nodeName, "3")
+	}
+
+	tests := []struct {
+		name          string
+		minMember     int32
+		pods          []*v1.Pod
+		pod           *v1.Pod
+		admitted      bool
+		wantStatus    *fwk.Status
+		wantCondition metav1.ConditionStatus
+	}{
+		{
+			name:          "gang fits on NUMA nodes",
+			minMember:     2,
+			pods:          []*v1.Pod{makeMember("p1", ""), makeMember("p2", "")},
+			pod:           makeMember("p1", ""),
+			wantCondition: metav1.ConditionTrue,
+		},
+		{
+			name:      "gang fits at node level but not on NUMA nodes",
+			minMember: 3,
+			pods:      []*v1.Pod{makeMember("p1", ""), makeMember("p2", ""), makeMember("p3", "")},
+			pod:       makeMember("p1", ""),
+			wantStatus: fwk.NewStatus(fwk.Unschedulable,
+				"cannot align 1 of 3 pods of PodGroup default/pg on NUMA nodes"),
+			wantCondition: metav1.ConditionFalse,
+		},
+		{
+			// Placed in order, the two small pods would take both a NUMA node, leaving no room for the second large pod.
+			name:      "large pods are placed first",
+			minMember: 4,
+			pods: []*v1.Pod{
+				makeSizedMember("p1", "", "2"), makeSizedMember("p2", "", "2"),
+				makeSizedMember("p3", "", "3"), makeSizedMember("p4", "", "3"),
+			},
+			pod:           makeSizedMember("p1", "", "2"),
+			wantCondition: metav1.ConditionTrue,
+		},
+		{
+			name:          "bound pods are not placed again",
+			minMember:     3,
+			pods:          []*v1.Pod{makeMember("p1", "node0"), makeMember("p2", ""), makeMember("p3", "")},
+			pod:           makeMember("p2", ""),
+			wantCondition: metav1.ConditionTrue,
+		},
+		{
+			name:      "admitted gang is not checked again",
+			minMember: 3,
+			pods:      []*v1.Pod{makeMember("p1", ""), makeMember("p2", ""), makeMember("p3", "")},
+			pod:       makeMember("p1", ""),
+			admitted:  true,
+		},
+		{
+			name:      "pod without PodGroup",
+			minMember: 3,
+			pod: makePodByResourceList(&v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("3"),
+				v1.ResourceMemory: resource.MustParse("2Gi"),
+			}),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			pg := &v1alpha1.PodGroup{
+				ObjectMeta: metav1.ObjectMeta{Name: "pg", Namespace: "default"},
+				Spec:       v1alpha1.PodGroupSpec{MinMember: tt.minMember},
+			}
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(nrt.DeepCopy(), pg).
+				WithStatusSubresource(&v1alpha1.PodGroup{}).
+				Build()
+
+			informerFactory := informers.NewSharedInformerFactory(clientsetfake.NewSimpleClientset(), 0)
+			podInformer := informerFactory.Core().V1().Pods()
+			for _, p := range tt.pods {
+				if err := podInformer.Informer().GetStore().Add(p); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			tm := TopologyMatch{
+				logger:     klog.Background(),
+				nrtCache:   nrtcache.NewPassthrough(klog.Background(), fakeClient),
+				pgClient:   fakeClient,
+				podLister:  podInformer.Lister(),
+				admittedPG: gocache.New(time.Minute, time.Minute),
+			}
+			if tt.admitted {
+				tm.admittedPG.SetDefault(fmt.Sprintf("%v/%v", pg.Namespace, pg.Name), nil)
+			}
+
+			_, gotStatus := tm.PreFilter(ctx, framework.NewCycleState(), tt.pod, []fwk.NodeInfo{nodeInfo})
+			if !quasiEqualStatus(gotStatus, tt.wantStatus) {
+				t.Errorf("status does not match: %v, want: %v", gotStatus, tt.wantStatus)
+			}
+
+			if err := fakeClient.Get(ctx, types.NamespacedName{Namespace: pg.Namespace, Name: pg.Name}, pg); err != nil {
+				t.Fatal(err)
+			}
+			cond := meta.FindStatusCondition(pg.Status.Conditions, v1alpha1.PodGroupNUMAAligned)
+			if tt.wantCondition == "" {
+				if cond != nil {
+					t.Errorf("unexpected condition: %v", cond)
+				}
+				return
+			}
+			if cond == nil || cond.Status != tt.wantCondition {
+				t.Errorf("condition does not match: %v, want status: %v", cond, tt.wantCondition)
+			}
+		})
+	}
+}
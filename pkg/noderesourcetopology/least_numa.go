@@ -158,16 +158,25 @@ func combineResources(numaNodes NUMANodeList, combination []int) v1.ResourceList
 // or nil when resources can't be fitted onto the worker node
 // second value returned is a boolean indicating if bitmask is optimal from distance perspective
 func numaNodesRequired(lh logr.Logger, qos v1.PodQOSClass, numaNodes NUMANodeList, resources v1.ResourceList) (bitmask.BitMask, bool) {
+	suitableCombination, isMinDistance := numaNodesCombinationRequired(lh, qos, numaNodes, resources)
+	if suitableCombination == nil {
+		return nil, false
+	}
+	bm := bitmask.NewEmptyBitMask()
+	for _, nodeIdx := range suitableCombination {
+		bm.Add(numaNodes[nodeIdx].NUMAID)
+	}
+	return bm, isMinDistance
+}
+
+// numaNodesCombinationRequired is like numaNodesRequired, but returns the indexes in numaNodes of the NUMA nodes required
+func numaNodesCombinationRequired(lh logr.Logger, qos v1.PodQOSClass, numaNodes NUMANodeList, resources v1.ResourceList) ([]int, bool) {
 	for bitmaskLen := 1; bitmaskLen <= len(numaNodes); bitmaskLen++ {
 		numaNodesCombination := combin.Combinations(len(numaNodes), bitmaskLen)
 		suitableCombination, isMinDistance := findSuitableCombination(lh, qos, numaNodes, resources, numaNodesCombination)
 		// we have found suitable combination for given bitmaskLen
 		if suitableCombination != nil {
-			bm := bitmask.NewEmptyBitMask()
-			for _, nodeIdx := range suitableCombination {
-				bm.Add(numaNodes[nodeIdx].NUMAID)
-			}
-			return bm, isMinDistance
+			return suitableCombination, isMinDistance
 		}
 	}
 
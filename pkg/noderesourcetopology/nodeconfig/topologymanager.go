@@ -36,6 +36,9 @@ const (
 	AttributeScope        = "topologyManagerScope"
 	AttributePolicy       = "topologyManagerPolicy"
 	AttributeMaxNUMANodes = "topologyManagerMaxNUMANodes"
+
+	AttributeOptionPreferClosestNUMANodes = "topologyManagerOptionPreferClosestNumaNodes"
+	AttributeOptionMaxAllowableNUMANodes  = "topologyManagerOptionMaxAllowableNumaNodes"
 )
 
 func IsValidScope(scope string) bool {
@@ -62,9 +65,12 @@ func IsValidMaxNUMANodes(value int) bool {
 }
 
 type TopologyManager struct {
-	Scope        string
-	Policy       string
+	Scope  string
+	Policy string
+	// MaxNUMANodes mirrors the max-allowable-numa-nodes policy option
 	MaxNUMANodes int
+	// PreferClosestNUMA mirrors the prefer-closest-numa-nodes policy option
+	PreferClosestNUMA bool
 }
 
 func TopologyManagerDefaults() TopologyManager {
@@ -86,7 +92,7 @@ func TopologyManagerFromNodeResourceTopology(lh logr.Logger, nodeTopology *topol
 }
 
 func (conf TopologyManager) String() string {
-	return fmt.Sprintf("policy=%s scope=%s maxNUMANodes=%d", conf.Policy, conf.Scope, conf.MaxNUMANodes)
+	return fmt.Sprintf("policy=%s scope=%s maxNUMANodes=%d preferClosestNUMA=%v", conf.Policy, conf.Scope, conf.MaxNUMANodes, conf.PreferClosestNUMA)
 }
 
 func (conf TopologyManager) Equal(other TopologyManager) bool {
@@ -96,7 +102,10 @@ func (conf TopologyManager) Equal(other TopologyManager) bool {
 	if conf.Policy != other.Policy {
 		return false
 	}
-	return conf.MaxNUMANodes == other.MaxNUMANodes
+	if conf.MaxNUMANodes != other.MaxNUMANodes {
+		return false
+	}
+	return conf.PreferClosestNUMA == other.PreferClosestNUMA
 }
 
 func (conf *TopologyManager) updateFromAttributes(lh logr.Logger, attrs topologyv1alpha2.AttributeList) {
@@ -109,12 +118,18 @@ func (conf *TopologyManager) updateFromAttributes(lh logr.Logger, attrs topology
 			conf.Policy = attr.Value
 			continue
 		}
-		if attr.Name == AttributeMaxNUMANodes {
+		if attr.Name == AttributeMaxNUMANodes || attr.Name == AttributeOptionMaxAllowableNUMANodes {
 			if val, err := strconv.Atoi(attr.Value); err == nil && IsValidMaxNUMANodes(val) {
 				conf.MaxNUMANodes = clampMaxNUMANodes(lh, val)
 				continue
 			}
 		}
+		if attr.Name == AttributeOptionPreferClosestNUMANodes {
+			if val, err := strconv.ParseBool(attr.Value); err == nil {
+				conf.PreferClosestNUMA = val
+				continue
+			}
+		}
 	}
 }
 
@@ -239,6 +239,21 @@ func TestTopologyManagerEqual(t *testing.T) {
 			},
 			expected: false,
 		},
+		{
+			name: "scope, policy, nodes matching, prefer closest diff",
+			tmA: TopologyManager{
+				Scope:             "container",
+				Policy:            "restricted",
+				MaxNUMANodes:      8,
+				PreferClosestNUMA: true,
+			},
+			tmB: TopologyManager{
+				Scope:        "container",
+				Policy:       "restricted",
+				MaxNUMANodes: 8,
+			},
+			expected: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -412,6 +427,40 @@ func TestConfigFromAttributes(t *testing.T) {
 				MaxNUMANodes: LimitNUMANodes,
 			},
 		},
+		{
+			name: "valid-max-allowable-option",
+			attrs: topologyv1alpha2.AttributeList{
+				{
+					Name:  "topologyManagerOptionMaxAllowableNumaNodes",
+					Value: "16",
+				},
+			},
+			expected: TopologyManager{
+				MaxNUMANodes: 16,
+			},
+		},
+		{
+			name: "prefer-closest",
+			attrs: topologyv1alpha2.AttributeList{
+				{
+					Name:  "topologyManagerOptionPreferClosestNumaNodes",
+					Value: "true",
+				},
+			},
+			expected: TopologyManager{
+				PreferClosestNUMA: true,
+			},
+		},
+		{
+			name: "invalid-prefer-closest",
+			attrs: topologyv1alpha2.AttributeList{
+				{
+					Name:  "topologyManagerOptionPreferClosestNumaNodes",
+					Value: "maybe",
+				},
+			},
+			expected: TopologyManager{},
+		},
 	}
 
 	for _, tt := range tests {
@@ -19,19 +19,25 @@ package noderesourcetopology
 import (
 	"context"
 	"fmt"
+	"time"
 
+	gocache "github.com/patrickmn/go-cache"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	listerv1 "k8s.io/client-go/listers/core/v1"
 	"k8s.io/klog/v2"
 	fwk "k8s.io/kube-scheduler/framework"
 	"k8s.io/kubernetes/pkg/scheduler/framework"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 
 	apiconfig "sigs.k8s.io/scheduler-plugins/apis/config"
 	"sigs.k8s.io/scheduler-plugins/apis/config/validation"
+	schedulingv1alpha1 "sigs.k8s.io/scheduler-plugins/apis/scheduling/v1alpha1"
 	nrtcache "sigs.k8s.io/scheduler-plugins/pkg/noderesourcetopology/cache"
 	"sigs.k8s.io/scheduler-plugins/pkg/noderesourcetopology/nodeconfig"
+	"sigs.k8s.io/scheduler-plugins/pkg/util"
 
 	"github.com/go-logr/logr"
 	topologyapi "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology"
@@ -48,6 +54,7 @@ var scheme = runtime.NewScheme()
 func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 	utilruntime.Must(topologyv1alpha2.AddToScheme(scheme))
+	utilruntime.Must(schedulingv1alpha1.AddToScheme(scheme))
 }
 
 type filterInfo struct {
@@ -55,6 +62,8 @@ type filterInfo struct {
 	node            fwk.NodeInfo
 	topologyManager nodeconfig.TopologyManager
 	numaNodes       NUMANodeList
+	// numaAllocatable are the allocatable resources of each NUMA node by NUMA ID
+	numaAllocatable map[int]v1.ResourceList
 	qos             v1.PodQOSClass
 }
 
@@ -75,8 +84,14 @@ type TopologyMatch struct {
 	nrtCache            nrtcache.Interface
 	scoreStrategyFunc   scoreStrategyFn
 	scoreStrategyType   apiconfig.ScoringStrategyType
+	// pgClient gets and updates the PodGroups for the gang admission in PreFilter.
+	pgClient  ctrlclient.Client
+	podLister listerv1.PodLister
+	// admittedPG stores the PodGroups whose pods were found to fit on NUMA nodes all together.
+	admittedPG *gocache.Cache
 }
 
+var _ framework.PreFilterPlugin = &TopologyMatch{}
 var _ framework.FilterPlugin = &TopologyMatch{}
 var _ framework.ReservePlugin = &TopologyMatch{}
 var _ framework.ScorePlugin = &TopologyMatch{}
@@ -123,12 +138,22 @@ func New(ctx context.Context, args runtime.Object, handle framework.Handle) (fra
 		return nil, err
 	}
 
+	// PodGroups are read on every PreFilter of their pods, so they are served from a cache.
+	pgClient, _, err := util.NewClientWithCachedReader(ctx, handle.KubeConfig(), scheme)
+	if err != nil {
+		lh.Error(err, "cannot create client for PodGroup", "kubeConfig", handle.KubeConfig())
+		return nil, err
+	}
+
 	topologyMatch := &TopologyMatch{
 		logger:              lh,
 		resourceToWeightMap: resToWeightMap,
 		nrtCache:            nrtCache,
 		scoreStrategyFunc:   strategy,
 		scoreStrategyType:   tcfg.ScoringStrategy.Type,
+		pgClient:            pgClient,
+		podLister:           handle.SharedInformerFactory().Core().V1().Pods().Lister(),
+		admittedPG:          gocache.New(time.Minute, time.Minute),
 	}
 
 	return topologyMatch, nil
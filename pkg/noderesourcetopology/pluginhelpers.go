@@ -61,7 +61,15 @@ func initNodeTopologyInformer(ctx context.Context, lh logr.Logger,
 
 	podSharedInformer, podLister, isPodRelevant := podprovider.NewFromHandle(lh, handle, tcfg.Cache)
 
-	nrtCache, err := nrtcache.NewOverReserve(ctx, lh.WithName(logging.SubsystemNRTCache), tcfg.Cache, client, podLister, isPodRelevant)
+	var nrtCache interface {
+		nrtcache.Interface
+		Resync()
+	}
+	if getCacheReservationBackend(lh, tcfg.Cache) == apiconfig.CacheReservationBackendShared {
+		nrtCache, err = nrtcache.NewSharedReserve(ctx, lh.WithName(logging.SubsystemNRTCache), tcfg.Cache, client, handle.ClientSet(), podLister, isPodRelevant)
+	} else {
+		nrtCache, err = nrtcache.NewOverReserve(ctx, lh.WithName(logging.SubsystemNRTCache), tcfg.Cache, client, podLister, isPodRelevant)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -76,7 +84,7 @@ func initNodeTopologyInformer(ctx context.Context, lh logr.Logger,
 	return nrtCache, nil
 }
 
-func initNodeTopologyForeignPodsDetection(lh logr.Logger, cfg *apiconfig.NodeResourceTopologyCache, handle framework.Handle, podSharedInformer k8scache.SharedInformer, nrtCache *nrtcache.OverReserve) {
+func initNodeTopologyForeignPodsDetection(lh logr.Logger, cfg *apiconfig.NodeResourceTopologyCache, handle framework.Handle, podSharedInformer k8scache.SharedInformer, nrtCache nrtcache.Interface) {
 	foreignPodsDetect := getForeignPodsDetectMode(lh, cfg)
 
 	if foreignPodsDetect == apiconfig.ForeignPodsDetectNone {
@@ -151,6 +159,26 @@ func extractCosts(costs topologyv1alpha2.CostList) map[int]int {
 	return nodeCosts
 }
 
+// extractNUMAAllocatable returns the allocatable resources of each NUMA node, by NUMA ID
+func extractNUMAAllocatable(zones topologyv1alpha2.ZoneList) map[int]corev1.ResourceList {
+	allocatable := make(map[int]corev1.ResourceList)
+	for _, zone := range zones {
+		if zone.Type != helper.ZoneTypeNUMANode {
+			continue
+		}
+		numaID, err := numanode.NameToID(zone.Name)
+		if err != nil || numaID > maxNUMAId {
+			continue
+		}
+		res := make(corev1.ResourceList)
+		for _, resInfo := range zone.Resources {
+			res[corev1.ResourceName(resInfo.Name)] = resInfo.Allocatable.DeepCopy()
+		}
+		allocatable[numaID] = res
+	}
+	return allocatable
+}
+
 func extractResources(zone topologyv1alpha2.Zone) corev1.ResourceList {
 	res := make(corev1.ResourceList)
 	for _, resInfo := range zone.Resources {
@@ -181,3 +209,14 @@ func getForeignPodsDetectMode(lh logr.Logger, cfg *apiconfig.NodeResourceTopolog
 	}
 	return foreignPodsDetect
 }
+
+func getCacheReservationBackend(lh logr.Logger, cfg *apiconfig.NodeResourceTopologyCache) apiconfig.CacheReservationBackend {
+	var reservationBackend apiconfig.CacheReservationBackend
+	if cfg != nil && cfg.ReservationBackend != nil {
+		reservationBackend = *cfg.ReservationBackend
+	} else { // explicitly set to nil?
+		reservationBackend = apiconfig.CacheReservationBackendLocal
+		lh.Info("cache reservation backend missing", "fallback", reservationBackend)
+	}
+	return reservationBackend
+}
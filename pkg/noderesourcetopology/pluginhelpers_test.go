@@ -156,3 +156,47 @@ func TestGetForeignPodsDetectMode(t *testing.T) {
 		})
 	}
 }
+
+func TestGetCacheReservationBackend(t *testing.T) {
+	backendLocal := apiconfig.CacheReservationBackendLocal
+	backendShared := apiconfig.CacheReservationBackendShared
+
+	testCases := []struct {
+		description string
+		cfg         *apiconfig.NodeResourceTopologyCache
+		expected    apiconfig.CacheReservationBackend
+	}{
+		{
+			description: "nil config",
+			expected:    apiconfig.CacheReservationBackendLocal,
+		},
+		{
+			description: "empty config",
+			cfg:         &apiconfig.NodeResourceTopologyCache{},
+			expected:    apiconfig.CacheReservationBackendLocal,
+		},
+		{
+			description: "explicit local",
+			cfg: &apiconfig.NodeResourceTopologyCache{
+				ReservationBackend: &backendLocal,
+			},
+			expected: apiconfig.CacheReservationBackendLocal,
+		},
+		{
+			description: "explicit shared",
+			cfg: &apiconfig.NodeResourceTopologyCache{
+				ReservationBackend: &backendShared,
+			},
+			expected: apiconfig.CacheReservationBackendShared,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			got := getCacheReservationBackend(klog.Background(), testCase.cfg)
+			if got != testCase.expected {
+				t.Errorf("cache reservation backend got %v expected %v", got, testCase.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,297 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/go-logr/logr"
+	"gonum.org/v1/gonum/stat/combin"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	fwk "k8s.io/kube-scheduler/framework"
+	bm "k8s.io/kubernetes/pkg/kubelet/cm/topologymanager/bitmask"
+
+	"sigs.k8s.io/scheduler-plugins/pkg/noderesourcetopology/logging"
+	"sigs.k8s.io/scheduler-plugins/pkg/noderesourcetopology/stringify"
+	"sigs.k8s.io/scheduler-plugins/pkg/util"
+)
+
+// restrictedContainerLevelHandler simulates the restricted policy of the Topology Manager with container scope.
+// https://github.com/kubernetes/kubernetes/blob/v1.34.1/pkg/kubelet/cm/topologymanager/policy_restricted.go
+func restrictedContainerLevelHandler(lh logr.Logger, pod *v1.Pod, info *filterInfo) *fwk.Status {
+	if status := checkMaxNUMANodes(lh, info); status != nil {
+		return status
+	}
+
+	// the init containers are running SERIALLY and BEFORE the normal containers.
+	// https://kubernetes.io/docs/concepts/workloads/pods/init-containers/#understanding-init-containers
+	// therefore, we don't need to accumulate their resources together
+	for _, initContainer := range pod.Spec.InitContainers {
+		cntKind := logging.GetInitContainerKind(&initContainer)
+		clh := lh.WithValues(logging.KeyContainer, initContainer.Name, logging.KeyContainerKind, cntKind)
+		clh.V(6).Info("desired resources", stringify.ResourceListToLoggable(initContainer.Resources.Requests)...)
+
+		if _, match, reason := resourcesAvailableInPreferredNUMANodes(clh, info, initContainer.Resources.Requests); !match {
+			msg := "cannot align " + cntKind + " container"
+			// we can't align init container, so definitely we can't align a pod
+			clh.V(2).Info(msg, "reason", reason)
+			return fwk.NewStatus(fwk.Unschedulable, msg)
+		}
+	}
+
+	for _, container := range pod.Spec.Containers {
+		clh := lh.WithValues(logging.KeyContainer, container.Name, logging.KeyContainerKind, logging.KindContainerApp)
+		clh.V(6).Info("container requests", stringify.ResourceListToLoggable(container.Resources.Requests)...)
+
+		affinity, match, reason := resourcesAvailableInPreferredNUMANodes(clh, info, container.Resources.Requests)
+		if !match {
+			// we can't align container, so definitely we can't align a pod
+			clh.V(2).Info("cannot align container", "reason", reason)
+			return fwk.NewStatus(fwk.Unschedulable, "cannot align container")
+		}
+
+		// subtract the resources requested by the container from the given NUMA nodes.
+		// this is necessary, so we won't allocate the same resources for the upcoming containers
+		subtractFromNUMAAffinity(info, affinity, container.Resources.Requests)
+		clh.V(4).Info("container aligned", "numaCells", affinity.GetBits())
+	}
+	return nil
+}
+
+// restrictedPodLevelHandler simulates the restricted policy of the Topology Manager with pod scope.
+func restrictedPodLevelHandler(lh logr.Logger, pod *v1.Pod, info *filterInfo) *fwk.Status {
+	if status := checkMaxNUMANodes(lh, info); status != nil {
+		return status
+	}
+
+	resources := util.GetPodEffectiveRequest(pod)
+	lh.V(6).Info("pod desired resources", stringify.ResourceListToLoggable(resources)...)
+
+	affinity, match, reason := resourcesAvailableInPreferredNUMANodes(lh, info, resources)
+	if !match {
+		lh.V(2).Info("cannot align pod", "name", pod.Name, "reason", reason)
+		return fwk.NewStatus(fwk.Unschedulable, "cannot align pod")
+	}
+	subtractFromNUMAAffinity(info, affinity, resources)
+	lh.V(4).Info("all container placed", "numaCells", affinity.GetBits())
+	return nil
+}
+
+// checkMaxNUMANodes rejects the nodes having more NUMA nodes than allowed, on which the Topology Manager can't run.
+func checkMaxNUMANodes(lh logr.Logger, info *filterInfo) *fwk.Status {
+	if len(info.numaNodes) <= info.topologyManager.MaxNUMANodes {
+		return nil
+	}
+	lh.V(2).Info("too many NUMA nodes", "numaCells", len(info.numaNodes), "maxNUMANodes", info.topologyManager.MaxNUMANodes)
+	return fwk.NewStatus(fwk.UnschedulableAndUnresolvable, fmt.Sprintf("more than %d NUMA nodes", info.topologyManager.MaxNUMANodes))
+}
+
+// resourcesAvailableInPreferredNUMANodes merges the topology hints of the requested resources like the Topology Manager does,
+// and tells if the merged hint is preferred, which is what the restricted policy requires to admit the resources.
+// returns:
+// - the NUMA nodes that would be selected by Kubelet,
+// - a boolean which tells if the worker node can satisfy the request with a preferred hint
+// - the reason for reject.
+//
+// A hint is preferred for a resource if it spans the minimal amount of NUMA nodes able to hold the resource,
+// considering the allocatable resources of the NUMA nodes. Like the Topology Manager, the hints of the resources
+// are merged by ANDing their NUMA nodes, and a merged hint is preferred as long as all of its hints are preferred,
+// hence only the preferred hints are merged. The merges spanning no NUMA node are skipped.
+// https://github.com/kubernetes/kubernetes/blob/v1.34.1/pkg/kubelet/cm/topologymanager/policy.go#L43
+// Among those, the narrowest one is selected or, with the prefer-closest-numa-nodes option, the closest one.
+func resourcesAvailableInPreferredNUMANodes(lh logr.Logger, info *filterInfo, resources v1.ResourceList) (bm.BitMask, bool, string) {
+	nodeResources := util.ResourceList(info.node.GetAllocatable())
+
+	// nil means no resource expressed a preference yet
+	var candidates []bm.BitMask
+	for resource, quantity := range resources {
+		clh := lh.WithValues("resource", resource)
+		if quantity.IsZero() {
+			// why bother? everything's fine from the perspective of this resource
+			clh.V(4).Info("ignoring zero-qty resource request")
+			continue
+		}
+
+		if _, ok := nodeResources[resource]; !ok {
+			// some resources may not expose NUMA affinity (device plugins, extended resources), but all resources
+			// must be reported at node level; thus, if they are not present at node level, we can safely assume
+			// we don't have the resource at all.
+			clh.V(2).Info("early verdict: cannot meet request")
+			return nil, false, string(resource)
+		}
+
+		if info.qos != v1.PodQOSGuaranteed && isNUMAAffineResource(resource) {
+			// CPU and memory managers only align exclusive resources
+			clh.V(6).Info("no NUMA preference for QoS", "QoS", info.qos)
+			continue
+		}
+
+		hints, hasNUMAAffinity := preferredNUMAHints(clh, info, resource, quantity)
+		// non-native resources or ephemeral-storage may not expose NUMA affinity,
+		// but since they are available at node level, this is fine
+		if !hasNUMAAffinity {
+			if isHostLevelResource(resource) {
+				clh.V(6).Info("resource available at host level (no NUMA affinity)")
+				continue
+			}
+			clh.V(2).Info("early verdict: cannot find affinity")
+			return nil, false, string(resource)
+		}
+
+		candidates = intersectHints(candidates, hints)
+		if len(candidates) == 0 {
+			lh.V(2).Info("early verdict: cannot find preferred affinity")
+			return nil, false, string(resource)
+		}
+	}
+
+	if candidates == nil {
+		// no resource expressed a preference, so any NUMA node is fine
+		affinity := bm.NewEmptyBitMask()
+		for _, numaNode := range info.numaNodes {
+			affinity.Add(numaNode.NUMAID)
+		}
+		return affinity, true, "generic"
+	}
+
+	affinity := candidates[0]
+	for _, candidate := range candidates[1:] {
+		if isBetterNUMAAffinity(lh, info, candidate, affinity) {
+			affinity = candidate
+		}
+	}
+	lh.V(2).Info("final verdict", "suitable", true, "numaCells", affinity.GetBits())
+	return affinity, true, "generic"
+}
+
+// preferredNUMAHints returns the combinations of the fewest NUMA nodes able to hold the resource,
+// which can fit the requested quantity. The second value returned tells if any NUMA node exposes the resource.
+func preferredNUMAHints(lh logr.Logger, info *filterInfo, resName v1.ResourceName, quantity resource.Quantity) ([]bm.BitMask, bool) {
+	hasNUMAAffinity := false
+	for _, numaNode := range info.numaNodes {
+		if _, ok := numaNode.Resources[resName]; ok {
+			hasNUMAAffinity = true
+			break
+		}
+	}
+	if !hasNUMAAffinity {
+		return nil, false
+	}
+
+	for combinationLen := 1; combinationLen <= len(info.numaNodes); combinationLen++ {
+		hints := []bm.BitMask{}
+		canHold := false
+		for _, combination := range combin.Combinations(len(info.numaNodes), combinationLen) {
+			var available, allocatable resource.Quantity
+			for _, idx := range combination {
+				numaNode := info.numaNodes[idx]
+				availableQty := numaNode.Resources[resName]
+				available.Add(availableQty)
+				// fall back to the available resources if the allocatable ones are unknown or inconsistent
+				allocatableQty, ok := info.numaAllocatable[numaNode.NUMAID][resName]
+				if !ok || allocatableQty.Cmp(availableQty) < 0 {
+					allocatableQty = availableQty
+				}
+				allocatable.Add(allocatableQty)
+			}
+			if allocatable.Cmp(quantity) < 0 {
+				continue
+			}
+			canHold = true
+			if available.Cmp(quantity) < 0 {
+				lh.V(6).Info("discarded", "combination", combination, "quantity", quantity.String(), "numaQuantity", available.String())
+				continue
+			}
+			hint := bm.NewEmptyBitMask()
+			for _, idx := range combination {
+				hint.Add(info.numaNodes[idx].NUMAID)
+			}
+			hints = append(hints, hint)
+		}
+		// wider combinations are not preferred, even if the narrowest ones can't fit the request
+		if canHold {
+			return hints, true
+		}
+	}
+	return []bm.BitMask{}, true
+}
+
+// intersectHints merges each hint of a list with each hint of the other one, ANDing their NUMA nodes, and returns the
+// distinct merges spanning at least a NUMA node. A nil list means no preference.
+func intersectHints(hints, others []bm.BitMask) []bm.BitMask {
+	if hints == nil {
+		return others
+	}
+	res := []bm.BitMask{}
+	for _, hint := range hints {
+		for _, other := range others {
+			merged := bm.And(hint, other)
+			if merged.IsEmpty() || slices.ContainsFunc(res, merged.IsEqual) {
+				continue
+			}
+			res = append(res, merged)
+		}
+	}
+	return res
+}
+
+// isBetterNUMAAffinity tells if the candidate NUMA nodes would be selected by the Topology Manager over the current ones.
+// https://github.com/kubernetes/kubernetes/blob/v1.34.1/pkg/kubelet/cm/topologymanager/numa_info.go#L60
+func isBetterNUMAAffinity(lh logr.Logger, info *filterInfo, candidate, current bm.BitMask) bool {
+	if candidate.Count() != current.Count() {
+		return candidate.IsNarrowerThan(current)
+	}
+	if info.topologyManager.PreferClosestNUMA {
+		candidateDistance := numaAffinityAvgDistance(lh, info.numaNodes, candidate)
+		currentDistance := numaAffinityAvgDistance(lh, info.numaNodes, current)
+		if candidateDistance != currentDistance {
+			return candidateDistance < currentDistance
+		}
+	}
+	return candidate.IsLessThan(current)
+}
+
+func numaAffinityAvgDistance(lh logr.Logger, numaNodes NUMANodeList, affinity bm.BitMask) float32 {
+	var nodes []int
+	for idx, numaNode := range numaNodes {
+		if affinity.IsSet(numaNode.NUMAID) {
+			nodes = append(nodes, idx)
+		}
+	}
+	return nodesAvgDistance(lh, numaNodes, nodes...)
+}
+
+// subtractFromNUMAAffinity subtracts the resources from the given NUMA nodes, starting from the lowest NUMA ID.
+func subtractFromNUMAAffinity(info *filterInfo, affinity bm.BitMask, resources v1.ResourceList) {
+	aligned := v1.ResourceList{}
+	for name, quantity := range resources {
+		if info.qos != v1.PodQOSGuaranteed && isNUMAAffineResource(name) {
+			continue
+		}
+		aligned[name] = quantity
+	}
+
+	var nodes []int
+	for idx, numaNode := range info.numaNodes {
+		if affinity.IsSet(numaNode.NUMAID) {
+			nodes = append(nodes, idx)
+		}
+	}
+	subtractFromNUMAs(aligned, info.numaNodes, nodes...)
+}
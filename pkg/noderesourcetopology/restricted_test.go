@@ -0,0 +1,238 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	topologyv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	fwk "k8s.io/kube-scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	nrtcache "sigs.k8s.io/scheduler-plugins/pkg/noderesourcetopology/cache"
+	"sigs.k8s.io/scheduler-plugins/pkg/noderesourcetopology/nodeconfig"
+	tu "sigs.k8s.io/scheduler-plugins/test/util"
+)
+
+// makeRestrictedNRT returns a NRT object whose NUMA nodes have 8 allocatable CPUs, and the given available CPUs
+func makeRestrictedNRT(scope string, availableCPUs []string, attrs ...topologyv1alpha2.AttributeInfo) *topologyv1alpha2.NodeResourceTopology {
+	nrt := &topologyv1alpha2.NodeResourceTopology{
+		ObjectMeta: metav1.ObjectMeta{Name: "node1"},
+		Attributes: append(topologyv1alpha2.AttributeList{
+			{Name: nodeconfig.AttributePolicy, Value: "restricted"},
+			{Name: nodeconfig.AttributeScope, Value: scope},
+		}, attrs...),
+	}
+	for i, cpus := range availableCPUs {
+		var costs topologyv1alpha2.CostList
+		for j := range availableCPUs {
+			cost := int64(20)
+			if i == j {
+				cost = 10
+			}
+			costs = append(costs, topologyv1alpha2.CostInfo{Name: fmt.Sprintf("node-%d", j), Value: cost})
+		}
+		nrt.Zones = append(nrt.Zones, topologyv1alpha2.Zone{
+			Name:  fmt.Sprintf("node-%d", i),
+			Type:  "Node",
+			Costs: costs,
+			Resources: topologyv1alpha2.ResourceInfoList{
+				{Name: cpu, Capacity: resource.MustParse("8"), Allocatable: resource.MustParse("8"), Available: resource.MustParse(cpus)},
+				MakeTopologyResInfo(memory, "16Gi", "16Gi"),
+			},
+		})
+	}
+	return nrt
+}
+
+// withDevices adds the given available devices to the NUMA nodes of the NRT object, out of a single allocatable device each
+func withDevices(nrt *topologyv1alpha2.NodeResourceTopology, availableDevices ...string) *topologyv1alpha2.NodeResourceTopology {
+	for i, devices := range availableDevices {
+		nrt.Zones[i].Resources = append(nrt.Zones[i].Resources, topologyv1alpha2.ResourceInfo{
+			Name: nicResourceName, Capacity: resource.MustParse("1"), Allocatable: resource.MustParse("1"), Available: resource.MustParse(devices),
+		})
+	}
+	return nrt
+}
+
+func TestRestrictedFilter(t *testing.T) {
+	guaranteed := func(cpus ...string) *v1.Pod {
+		var resources []v1.ResourceList
+		for _, c := range cpus {
+			resources = append(resources, v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse(c),
+				v1.ResourceMemory: resource.MustParse("1Gi"),
+			})
+		}
+		return makePodByResourceLists(resources...)
+	}
+
+	tests := []struct {
+		name       string
+		nrt        *topologyv1alpha2.NodeResourceTopology
+		pod        *v1.Pod
+		wantStatus *fwk.Status
+	}{
+		{
+			name: "fits on a single NUMA node",
+			nrt:  makeRestrictedNRT("pod", []string{"4", "8"}),
+			pod:  guaranteed("6"),
+		},
+		{
+			name: "spans NUMA nodes when a single one can't hold it",
+			nrt:  makeRestrictedNRT("pod", []string{"4", "8"}),
+			pod: makePodByResourceList(&v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("12"),
+				v1.ResourceMemory: resource.MustParse("20Gi"),
+			}),
+		},
+		{
+			// the CPUs prefer {0,1} and the memory prefers {0} or {1}: the merged hints are preferred.
+			name: "resources preferring different amounts of NUMA nodes",
+			nrt:  makeRestrictedNRT("pod", []string{"8", "8"}),
+			pod:  guaranteed("12"),
+		},
+		{
+			// the CPUs prefer {0} and the device prefers {0,1}: the merged hint {0} is preferred.
+			name: "device spanning the NUMA node preferred by the CPUs",
+			nrt:  withDevices(makeRestrictedNRT("pod", []string{"8", "2"}), "1", "1"),
+			pod: makePodByResourceList(&v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("6"),
+				v1.ResourceMemory: resource.MustParse("1Gi"),
+				nicResourceName:   resource.MustParse("2"),
+			}),
+		},
+		{
+			// the CPUs prefer {0} and the device prefers {1}: the merged hint spans no NUMA node.
+			name: "device on another NUMA node than the one preferred by the CPUs",
+			nrt:  withDevices(makeRestrictedNRT("pod", []string{"8", "2"}), "0", "1"),
+			pod: makePodByResourceList(&v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("6"),
+				v1.ResourceMemory: resource.MustParse("1Gi"),
+				nicResourceName:   resource.MustParse("1"),
+			}),
+			wantStatus: fwk.NewStatus(fwk.Unschedulable, "cannot align pod"),
+		},
+		{
+			name:       "a single NUMA node could hold it but none has enough available",
+			nrt:        makeRestrictedNRT("pod", []string{"4", "4"}),
+			pod:        guaranteed("6"),
+			wantStatus: fwk.NewStatus(fwk.Unschedulable, "cannot align pod"),
+		},
+		{
+			name: "burstable pods are not aligned",
+			nrt:  makeRestrictedNRT("pod", []string{"4", "4"}),
+			pod: makePodWithReqByResourceList(&v1.ResourceList{
+				v1.ResourceCPU: resource.MustParse("6"),
+			}),
+		},
+		{
+			name:       "containers are aligned one after the other",
+			nrt:        makeRestrictedNRT("container", []string{"8", "4"}),
+			pod:        guaranteed("6", "6"),
+			wantStatus: fwk.NewStatus(fwk.Unschedulable, "cannot align container"),
+		},
+		{
+			name: "more NUMA nodes than allowed",
+			nrt: makeRestrictedNRT("pod", []string{"8", "8", "8"},
+				topologyv1alpha2.AttributeInfo{Name: nodeconfig.AttributeOptionMaxAllowableNUMANodes, Value: "2"}),
+			pod:        guaranteed("2"),
+			wantStatus: fwk.NewStatus(fwk.UnschedulableAndUnresolvable, "more than 2 NUMA nodes"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeClient, err := tu.NewFakeClient()
+			if err != nil {
+				t.Fatalf("failed to create fake client: %v", err)
+			}
+			if err := fakeClient.Create(context.Background(), tt.nrt.DeepCopy()); err != nil {
+				t.Fatal(err)
+			}
+
+			tm := TopologyMatch{
+				nrtCache: nrtcache.NewPassthrough(klog.Background(), fakeClient),
+			}
+
+			nodeInfo := framework.NewNodeInfo()
+			nodeInfo.SetNode(makeNodeFromNodeResourceTopology(tt.nrt))
+			gotStatus := tm.Filter(context.Background(), framework.NewCycleState(), tt.pod, nodeInfo)
+
+			if !quasiEqualStatus(gotStatus, tt.wantStatus) {
+				t.Errorf("status does not match: %v, want: %v", gotStatus, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestRestrictedPreferClosestNUMA(t *testing.T) {
+	// NUMA node 0 is closer to NUMA node 2 than to NUMA node 1
+	nrt := makeRestrictedNRT("pod", []string{"8", "8", "8", "8"})
+	nrt.Zones[0].Costs[2].Value = 12
+	nrt.Zones[2].Costs[0].Value = 12
+
+	tests := []struct {
+		name          string
+		preferClosest bool
+		expected      []int
+	}{
+		{
+			name:     "narrowest NUMA nodes",
+			expected: []int{0, 1},
+		},
+		{
+			name:          "closest NUMA nodes",
+			preferClosest: true,
+			expected:      []int{0, 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lh := klog.Background()
+			conf := nodeconfig.TopologyManagerFromNodeResourceTopology(lh, nrt)
+			conf.PreferClosestNUMA = tt.preferClosest
+			nodeInfo := framework.NewNodeInfo()
+			nodeInfo.SetNode(makeNodeFromNodeResourceTopology(nrt))
+			info := &filterInfo{
+				nodeName:        nrt.Name,
+				node:            nodeInfo,
+				topologyManager: conf,
+				numaNodes:       createNUMANodeList(lh, nrt.Zones),
+				numaAllocatable: extractNUMAAllocatable(nrt.Zones),
+				qos:             v1.PodQOSGuaranteed,
+			}
+
+			affinity, match, reason := resourcesAvailableInPreferredNUMANodes(lh, info, v1.ResourceList{
+				v1.ResourceCPU: resource.MustParse("12"),
+			})
+			if !match {
+				t.Fatalf("unexpected reject, reason: %v", reason)
+			}
+			if got := affinity.GetBits(); fmt.Sprint(got) != fmt.Sprint(tt.expected) {
+				t.Errorf("affinity does not match: %v, want: %v", got, tt.expected)
+			}
+		})
+	}
+}
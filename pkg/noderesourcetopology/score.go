@@ -132,8 +132,8 @@ func getScoringStrategyFunction(strategy apiconfig.ScoringStrategyType) (scoreSt
 		return leastAllocatedScoreStrategy, nil
 	case apiconfig.BalancedAllocation:
 		return balancedAllocationScoreStrategy, nil
-	case apiconfig.LeastNUMANodes:
-		// this is a special case handled down the flow. We just need to NOT error out.
+	case apiconfig.LeastNUMANodes, apiconfig.DeviceNUMAAffinity:
+		// these are special cases handled down the flow. We just need to NOT error out.
 		return nil, nil
 	default:
 		return nil, fmt.Errorf("illegal scoring strategy found")
@@ -175,6 +175,15 @@ func (tm *TopologyMatch) scoringHandlerFromTopologyManagerConfig(conf nodeconfig
 		}
 		return nil // cannot happen
 	}
+	if tm.scoreStrategyType == apiconfig.DeviceNUMAAffinity {
+		if conf.Scope == kubeletconfig.PodTopologyManagerScope {
+			return deviceNUMAAffinityPodScopeScore
+		}
+		if conf.Scope == kubeletconfig.ContainerTopologyManagerScope {
+			return deviceNUMAAffinityContainerScopeScore
+		}
+		return nil // cannot happen
+	}
 	if conf.Policy != kubeletconfig.SingleNumaNodeTopologyManagerPolicy {
 		return nil
 	}
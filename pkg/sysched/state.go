@@ -0,0 +1,338 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sysched
+
+import (
+	"sync"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// profileCache holds the system calls of the seccomp profiles, parsed once
+// and dropped when the profile changes
+type profileCache struct {
+	lock     sync.RWMutex
+	profiles map[types.NamespacedName]sets.Set[string]
+}
+
+func newProfileCache() *profileCache {
+	return &profileCache{
+		profiles: make(map[types.NamespacedName]sets.Set[string]),
+	}
+}
+
+// get returns the system calls of the profile, fetching them on a cache miss.
+// Failed fetches are not cached, so a profile created later is picked up.
+func (pc *profileCache) get(key types.NamespacedName, fetch func(types.NamespacedName) (sets.Set[string], error)) (sets.Set[string], error) {
+	pc.lock.RLock()
+	syscalls, ok := pc.profiles[key]
+	pc.lock.RUnlock()
+	if ok {
+		return syscalls, nil
+	}
+
+	syscalls, err := fetch(key)
+	if err != nil {
+		return syscalls, err
+	}
+
+	pc.lock.Lock()
+	defer pc.lock.Unlock()
+	pc.profiles[key] = syscalls
+	return syscalls, nil
+}
+
+func (pc *profileCache) invalidate(key types.NamespacedName) {
+	pc.lock.Lock()
+	defer pc.lock.Unlock()
+	delete(pc.profiles, key)
+}
+
+// podSyscalls are the system calls of a pod running on a node, along with the profiles they come from
+type podSyscalls struct {
+	pod      *v1.Pod
+	profiles sets.Set[types.NamespacedName]
+	syscalls sets.Set[string]
+	// sensitive pods are isolated from risky co-tenants by the Filter
+	sensitive bool
+}
+
+// nodeSyscalls tracks the system calls used by the pods running on a node
+type nodeSyscalls struct {
+	// key: pod namespace + "/" + name
+	pods map[string]*podSyscalls
+	// the sensitive pods, a subset of pods
+	sensitive map[string]*podSyscalls
+	// refs counts the pods using each system call
+	refs map[string]int
+	// syscalls are the system calls used by at least one pod, the keys of refs
+	syscalls sets.Set[string]
+	// totalRefs is the sum of refs, that is the sum of the system call counts of the pods
+	totalRefs int
+}
+
+func newNodeSyscalls() *nodeSyscalls {
+	return &nodeSyscalls{
+		pods:      make(map[string]*podSyscalls),
+		sensitive: make(map[string]*podSyscalls),
+		refs:      make(map[string]int),
+		syscalls:  sets.New[string](),
+	}
+}
+
+func (ns *nodeSyscalls) add(key string, entry *podSyscalls) {
+	ns.pods[key] = entry
+	if entry.sensitive {
+		ns.sensitive[key] = entry
+	}
+	ns.ref(entry.syscalls)
+}
+
+func (ns *nodeSyscalls) remove(key string, entry *podSyscalls) {
+	delete(ns.pods, key)
+	delete(ns.sensitive, key)
+	ns.unref(entry.syscalls)
+}
+
+func (ns *nodeSyscalls) ref(syscalls sets.Set[string]) {
+	for syscall := range syscalls {
+		if ns.refs[syscall] == 0 {
+			ns.syscalls.Insert(syscall)
+		}
+		ns.refs[syscall]++
+	}
+	ns.totalRefs += syscalls.Len()
+}
+
+func (ns *nodeSyscalls) unref(syscalls sets.Set[string]) {
+	for syscall := range syscalls {
+		ns.refs[syscall]--
+		if ns.refs[syscall] <= 0 {
+			delete(ns.refs, syscall)
+			ns.syscalls.Delete(syscall)
+		}
+	}
+	ns.totalRefs -= syscalls.Len()
+}
+
+// hostSnapshot is a copy of the state of a node, consistent at the time it is taken
+type hostSnapshot struct {
+	syscalls  sets.Set[string]
+	pods      int
+	totalRefs int
+}
+
+// hostSyscalls tracks the system calls used on each node. It is updated incrementally
+// from the informer callbacks and read concurrently by Score.
+type hostSyscalls struct {
+	lock  sync.RWMutex
+	nodes map[string]*nodeSyscalls
+}
+
+func newHostSyscalls() *hostSyscalls {
+	return &hostSyscalls{
+		nodes: make(map[string]*nodeSyscalls),
+	}
+}
+
+// addPod returns false if the pod was already tracked on the node
+func (hs *hostSyscalls) addPod(nodeName string, entry *podSyscalls) bool {
+	hs.lock.Lock()
+	defer hs.lock.Unlock()
+	node, ok := hs.nodes[nodeName]
+	if !ok {
+		node = newNodeSyscalls()
+		hs.nodes[nodeName] = node
+	}
+	key := podKey(entry.pod)
+	if _, ok := node.pods[key]; ok {
+		return false
+	}
+	node.add(key, entry)
+	return true
+}
+
+// removePod returns false if the pod was not tracked on the node
+func (hs *hostSyscalls) removePod(nodeName string, pod *v1.Pod) bool {
+	hs.lock.Lock()
+	defer hs.lock.Unlock()
+	node, ok := hs.nodes[nodeName]
+	if !ok {
+		return false
+	}
+	key := podKey(pod)
+	entry, ok := node.pods[key]
+	if !ok {
+		return false
+	}
+	node.remove(key, entry)
+	return true
+}
+
+// podsUsingProfile returns, per node, the pods whose system calls come from the profile
+func (hs *hostSyscalls) podsUsingProfile(key types.NamespacedName) map[string][]*podSyscalls {
+	hs.lock.RLock()
+	defer hs.lock.RUnlock()
+	ret := make(map[string][]*podSyscalls)
+	for nodeName, node := range hs.nodes {
+		for _, entry := range node.pods {
+			if entry.profiles.Has(key) {
+				ret[nodeName] = append(ret[nodeName], entry)
+			}
+		}
+	}
+	return ret
+}
+
+// replacePod swaps the tracked system calls of a pod, unless the pod was removed or replaced meanwhile
+func (hs *hostSyscalls) replacePod(nodeName string, old, updated *podSyscalls) {
+	hs.lock.Lock()
+	defer hs.lock.Unlock()
+	node, ok := hs.nodes[nodeName]
+	if !ok {
+		return
+	}
+	key := podKey(old.pod)
+	if node.pods[key] != old {
+		return
+	}
+	node.remove(key, old)
+	node.add(key, updated)
+}
+
+func (hs *hostSyscalls) snapshot(nodeName string) (hostSnapshot, bool) {
+	hs.lock.RLock()
+	defer hs.lock.RUnlock()
+	node, ok := hs.nodes[nodeName]
+	if !ok {
+		return hostSnapshot{}, false
+	}
+	return hostSnapshot{
+		syscalls:  node.syscalls.Clone(),
+		pods:      len(node.pods),
+		totalRefs: node.totalRefs,
+	}, true
+}
+
+// checkIsolation returns why running the pod on the node would break the isolation of a sensitive pod,
+// either the incoming pod or one already on the node, or an empty string if it would not.
+// The pods on the node are the given ones, e.g. of the scheduler snapshot: the pods not tracked yet,
+// such as the pods assumed by the scheduler, are added to the tracked state, and the tracked pods missing
+// from them, such as the victims of a preemption, are removed.
+func (hs *hostSyscalls) checkIsolation(nodeName string, pods []*v1.Pod, getPodSyscalls func(*v1.Pod) *podSyscalls,
+	incoming *podSyscalls, denied sets.Set[string], maxExtraneous *int64) string {
+	hs.lock.RLock()
+	node, ok := hs.nodes[nodeName]
+	if !ok {
+		node = newNodeSyscalls()
+	}
+	present := sets.New[string]()
+	var untracked []*v1.Pod
+	for _, pod := range pods {
+		key := podKey(pod)
+		present.Insert(key)
+		if _, ok := node.pods[key]; !ok {
+			untracked = append(untracked, pod)
+		}
+	}
+	if len(untracked) == 0 && len(node.pods) == present.Len() {
+		defer hs.lock.RUnlock()
+		return node.checkIsolation(incoming, denied, maxExtraneous)
+	}
+	view := node.clone(present)
+	hs.lock.RUnlock()
+
+	// the system calls of the untracked pods are read without holding the lock
+	for _, pod := range untracked {
+		view.add(podKey(pod), getPodSyscalls(pod))
+	}
+	return view.checkIsolation(incoming, denied, maxExtraneous)
+}
+
+// clone returns a copy of the state of the node with the given pods only
+func (ns *nodeSyscalls) clone(keys sets.Set[string]) *nodeSyscalls {
+	ret := &nodeSyscalls{
+		pods:      make(map[string]*podSyscalls, len(ns.pods)),
+		sensitive: make(map[string]*podSyscalls, len(ns.sensitive)),
+		refs:      make(map[string]int, len(ns.refs)),
+		syscalls:  ns.syscalls.Clone(),
+		totalRefs: ns.totalRefs,
+	}
+	for key, entry := range ns.pods {
+		ret.pods[key] = entry
+	}
+	for key, entry := range ns.sensitive {
+		ret.sensitive[key] = entry
+	}
+	for syscall, refs := range ns.refs {
+		ret.refs[syscall] = refs
+	}
+	for key, entry := range ns.pods {
+		if !keys.Has(key) {
+			ret.remove(key, entry)
+		}
+	}
+	return ret
+}
+
+// checkIsolation returns why running the pod on the node would break the isolation of a sensitive pod,
+// either the incoming pod or one already on the node, or an empty string if it would not
+func (ns *nodeSyscalls) checkIsolation(incoming *podSyscalls, denied sets.Set[string], maxExtraneous *int64) string {
+	// the syscalls of the incoming pod the node does not use yet
+	newSyscalls := 0
+	for syscall := range incoming.syscalls {
+		if ns.refs[syscall] == 0 {
+			newSyscalls++
+		}
+	}
+
+	if incoming.sensitive {
+		for syscall := range denied {
+			if ns.refs[syscall] > 0 {
+				return ErrReasonDeniedCoTenants
+			}
+		}
+		extraneous := ns.syscalls.Len() - (incoming.syscalls.Len() - newSyscalls)
+		if maxExtraneous != nil && int64(extraneous) > *maxExtraneous {
+			return ErrReasonExtraneousCoTenants
+		}
+	}
+
+	if len(ns.sensitive) == 0 {
+		return ""
+	}
+	if incoming.syscalls.HasAny(denied.UnsortedList()...) {
+		return ErrReasonDeniedSensitive
+	}
+	if maxExtraneous == nil || newSyscalls == 0 {
+		return ""
+	}
+	for _, entry := range ns.sensitive {
+		// the syscalls of a pod are a subset of the node ones
+		extraneous := ns.syscalls.Len() - entry.syscalls.Len() + newSyscalls
+		if int64(extraneous) > *maxExtraneous {
+			return ErrReasonExtraneousSensitive
+		}
+	}
+	return ""
+}
+
+func podKey(pod *v1.Pod) string {
+	return pod.Namespace + "/" + pod.Name
+}
@@ -6,10 +6,12 @@ import (
 	"math"
 	"path"
 	"strings"
+	"sync"
 
 	"github.com/containers/common/pkg/seccomp"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
 	clientscheme "k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/tools/cache"
@@ -21,6 +23,7 @@ import (
 	"sigs.k8s.io/security-profiles-operator/api/seccompprofile/v1beta1"
 
 	pluginconfig "sigs.k8s.io/scheduler-plugins/apis/config"
+	"sigs.k8s.io/scheduler-plugins/apis/config/validation"
 	"sigs.k8s.io/scheduler-plugins/apis/scheduling/v1alpha1"
 	"sigs.k8s.io/scheduler-plugins/pkg/util"
 )
@@ -32,34 +35,57 @@ type SySched struct {
 	// Maintain state of what pods on each node
 	// Cached state from SharedLister does not hold system wide info of pods
 	// scheduled by other schedulers
-	HostToPods map[string][]*v1.Pod
-	// Key: node name
-	// Value: set of system call names
-	HostSyscalls            map[string]sets.Set[string]
+	hosts *hostSyscalls
+	// system calls of the seccomp profiles, parsed once
+	profiles *profileCache
+	// exsLock protects ExSAvg and ExSAvgCount, updated by Score running in parallel
+	exsLock                 sync.Mutex
 	ExSAvg                  float64
 	ExSAvgCount             int64
 	DefaultProfileNamespace string
 	DefaultProfileName      string
 	WeightedSyscallProfile  string
+	// Filter configuration, see SySchedArgs
+	SensitivePodLabel     string
+	DeniedSyscalls        sets.Set[string]
+	MaxExtraneousSyscalls *int64
 }
 
+var _ framework.PreFilterPlugin = &SySched{}
+var _ framework.FilterPlugin = &SySched{}
+var _ framework.PreScorePlugin = &SySched{}
 var _ framework.ScorePlugin = &SySched{}
 
 // Name is the name of the plugin used in Registry and configurations.
 const Name = "SySched"
 
-// SPO annotation string
-const SPO_ANNOTATION = "seccomp.security.alpha.kubernetes.io"
+// stateKey is the key of the system calls of the pod being scheduled in the cycle state
+const stateKey = Name + ".syscalls"
 
-func remove(s []*v1.Pod, i int) []*v1.Pod {
-	if len(s) == 0 {
-		return nil
-	}
-	s[i] = s[len(s)-1]
+// stateData holds the system calls of the pod being scheduled, read once per scheduling cycle
+type stateData struct {
+	podSyscalls *podSyscalls
+}
 
-	return s[:len(s)-1]
+// Clone the state data, which is never modified
+func (s *stateData) Clone() fwk.StateData {
+	return s
 }
 
+// SPO annotation string
+const SPO_ANNOTATION = "seccomp.security.alpha.kubernetes.io"
+
+const (
+	// ErrReasonDeniedCoTenants is used when the pods on the node use syscalls denied to the co-tenants of the sensitive pod
+	ErrReasonDeniedCoTenants = "node runs pods using denied syscalls"
+	// ErrReasonExtraneousCoTenants is used when the pods on the node expose the sensitive pod to too many extraneous syscalls
+	ErrReasonExtraneousCoTenants = "node exposes the pod to too many extraneous syscalls"
+	// ErrReasonDeniedSensitive is used when the pod uses syscalls denied to the co-tenants of the sensitive pods on the node
+	ErrReasonDeniedSensitive = "pod uses syscalls denied next to the sensitive pods on the node"
+	// ErrReasonExtraneousSensitive is used when the pod exposes the sensitive pods on the node to too many extraneous syscalls
+	ErrReasonExtraneousSensitive = "pod exposes the sensitive pods on the node to too many extraneous syscalls"
+)
+
 // extracts filename and namespace from the relative seccomp
 // profile path with the following formats
 // e.g., localhost/operator/<namespace>/<filename>.json OR
@@ -118,32 +144,24 @@ func (sc *SySched) readSPOProfileCR(name string, namespace string) (sets.Set[str
 	return syscalls, nil
 }
 
-// obtains the system call list for a pod from the pod's seccomp profile
-// SPO is used to generate and input the seccomp profile to a pod
-// If a pod does not have a SPO seccomp profile, then an unconfined
-// system call set is return for the pod
-func (sc *SySched) getSyscalls(pod *v1.Pod) sets.Set[string] {
-	logger := sc.logger
-	r := sets.New[string]()
+// returns the seccomp profiles referenced by a pod, from the pod and container
+// security contexts, and from the SPO annotations
+func podProfiles(pod *v1.Pod) []types.NamespacedName {
+	var profiles []types.NamespacedName
+	addProfile := func(profilePath string) bool {
+		ns, name := parseNameNS(profilePath)
+		if len(ns) == 0 || len(name) == 0 {
+			return false
+		}
+		profiles = append(profiles, types.NamespacedName{Namespace: ns, Name: name})
+		return true
+	}
 
 	// read the seccomp profile from the security context of a pod
 	podSC := pod.Spec.SecurityContext
-
 	if podSC != nil && podSC.SeccompProfile != nil && podSC.SeccompProfile.Type == "Localhost" {
 		if podSC.SeccompProfile.LocalhostProfile != nil {
-			profilePath := *podSC.SeccompProfile.LocalhostProfile
-			ns, name := parseNameNS(profilePath)
-
-			if len(ns) > 0 && len(name) > 0 {
-				syscalls, err := sc.readSPOProfileCR(name, ns)
-				if err != nil {
-					logger.Error(err, "Failed to read syscall CR by parsing pod security context")
-				}
-
-				if len(syscalls) > 0 {
-					r = r.Union(syscalls)
-				}
-			}
+			addProfile(*podSC.SeccompProfile.LocalhostProfile)
 		}
 	}
 
@@ -152,62 +170,79 @@ func (sc *SySched) getSyscalls(pod *v1.Pod) sets.Set[string] {
 		conSC := container.SecurityContext
 		if conSC != nil && conSC.SeccompProfile != nil && conSC.SeccompProfile.Type == "Localhost" {
 			if conSC.SeccompProfile.LocalhostProfile != nil {
-				profilePath := *conSC.SeccompProfile.LocalhostProfile
-				ns, name := parseNameNS(profilePath)
-
-				if len(ns) > 0 && len(name) > 0 {
-					syscalls, err := sc.readSPOProfileCR(name, ns)
-					if err != nil {
-						logger.Error(err, "Failed to read syscall CR by parsing container security context")
-					}
-
-					if len(syscalls) > 0 {
-						r = r.Union(syscalls)
-					}
-				}
+				addProfile(*conSC.SeccompProfile.LocalhostProfile)
 			}
 		}
 	}
 
 	// SPO seccomp profiles are sometimes automatically annotated to a pod
-	if pod.ObjectMeta.Annotations != nil {
-		// there could be multiple SPO seccomp profile annotations for a pod
-		// merge all profiles to obtain the syscal set for a pod
-		for k, v := range pod.ObjectMeta.Annotations {
-			// looks for annotation related to the seccomp
-			if strings.Contains(k, SPO_ANNOTATION) {
-				ns, name := parseNameNS(v)
-
-				if len(ns) > 0 && len(name) > 0 {
-					syscalls, err := sc.readSPOProfileCR(name, ns)
-
-					if err != nil {
-						logger.Error(err, "Failed to read syscall CR by parsing pod annotation")
-						continue
-					}
-
-					if len(syscalls) > 0 {
-						r = r.Union(syscalls)
-					}
-				}
-				break
-			}
+	for k, v := range pod.ObjectMeta.Annotations {
+		// looks for annotation related to the seccomp
+		if strings.Contains(k, SPO_ANNOTATION) {
+			addProfile(v)
+			break
 		}
 	}
 
-	// if a pod does not have a seccomp profile specified, return the set of all syscalls
-	if len(r) == 0 {
-		syscalls, err := sc.readSPOProfileCR(sc.DefaultProfileName, sc.DefaultProfileNamespace)
+	return profiles
+}
+
+// returns the system calls of a seccomp profile, read from the CR only once if cached
+func (sc *SySched) profileSyscalls(key types.NamespacedName) (sets.Set[string], error) {
+	if sc.profiles == nil {
+		return sc.readSPOProfileCR(key.Name, key.Namespace)
+	}
+	return sc.profiles.get(key, func(key types.NamespacedName) (sets.Set[string], error) {
+		return sc.readSPOProfileCR(key.Name, key.Namespace)
+	})
+}
+
+// obtains the system call list for a pod from the pod's seccomp profiles, along with the profiles
+// SPO is used to generate and input the seccomp profile to a pod
+// If a pod does not have a SPO seccomp profile, then an unconfined
+// system call set is return for the pod
+func (sc *SySched) getPodSyscalls(pod *v1.Pod) *podSyscalls {
+	logger := sc.logger
+	entry := &podSyscalls{
+		pod:       pod,
+		profiles:  sets.New[types.NamespacedName](),
+		syscalls:  sets.New[string](),
+		sensitive: sc.isSensitive(pod),
+	}
+
+	// the cached sets are shared, so they are merged into a new one
+	for _, key := range podProfiles(pod) {
+		entry.profiles.Insert(key)
+		syscalls, err := sc.profileSyscalls(key)
 		if err != nil {
-			logger.Error(err, "Failed to read the CR of all syscalls")
+			logger.Error(err, "Failed to read syscall CR", "profile", key)
+			continue
 		}
+		entry.syscalls = entry.syscalls.Union(syscalls)
+	}
 
-		if syscalls.Len() > 0 {
-			r = r.Union(syscalls)
+	// if a pod does not have a seccomp profile specified, return the set of all syscalls
+	if entry.syscalls.Len() == 0 {
+		key := types.NamespacedName{Namespace: sc.DefaultProfileNamespace, Name: sc.DefaultProfileName}
+		entry.profiles.Insert(key)
+		syscalls, err := sc.profileSyscalls(key)
+		if err != nil {
+			logger.Error(err, "Failed to read the CR of all syscalls")
 		}
+		entry.syscalls = entry.syscalls.Union(syscalls)
 	}
 
-	return r
+	return entry
+}
+
+// tells whether the pod must be isolated from risky co-tenants
+func (sc *SySched) isSensitive(pod *v1.Pod) bool {
+	return sc.SensitivePodLabel != "" && pod.Labels[sc.SensitivePodLabel] == "true"
+}
+
+// obtains the system call list for a pod from the pod's seccomp profiles
+func (sc *SySched) getSyscalls(pod *v1.Pod) sets.Set[string] {
+	return sc.getPodSyscalls(pod).syscalls
 }
 
 // Name returns name of the plugin. It is used in logs, etc.
@@ -231,6 +266,70 @@ func (sc *SySched) calcScore(syscalls sets.Set[string]) int {
 	return score
 }
 
+// PreFilter invoked at the prefilter extension point.
+// Reads the system calls of the pod once for the Filter and Score of all the nodes, and skips the Filter
+// when no isolation constraint is configured.
+func (sc *SySched) PreFilter(ctx context.Context, cs fwk.CycleState, pod *v1.Pod, nodes []fwk.NodeInfo) (*framework.PreFilterResult, *fwk.Status) {
+	cs.Write(stateKey, &stateData{podSyscalls: sc.getPodSyscalls(pod)})
+	if sc.DeniedSyscalls.Len() == 0 && sc.MaxExtraneousSyscalls == nil {
+		return nil, fwk.NewStatus(fwk.Skip)
+	}
+	return nil, nil
+}
+
+// PreFilterExtensions returns nil as the pods added or removed during preemption are
+// taken from the node info by Filter.
+func (sc *SySched) PreFilterExtensions() framework.PreFilterExtensions {
+	return nil
+}
+
+// PreScore invoked at the prescore extension point.
+// Reads the system calls of the pod once for the Score of all the nodes, unless PreFilter already did.
+func (sc *SySched) PreScore(ctx context.Context, cs fwk.CycleState, pod *v1.Pod, nodes []fwk.NodeInfo) *fwk.Status {
+	if _, err := cs.Read(stateKey); err != nil {
+		cs.Write(stateKey, &stateData{podSyscalls: sc.getPodSyscalls(pod)})
+	}
+	return nil
+}
+
+// returns the system calls of the pod read by PreFilter or PreScore, or reads them if neither is enabled
+func (sc *SySched) cycleSyscalls(cs fwk.CycleState, pod *v1.Pod) *podSyscalls {
+	if cs != nil {
+		if c, err := cs.Read(stateKey); err == nil {
+			if s, ok := c.(*stateData); ok {
+				return s.podSyscalls
+			}
+		}
+	}
+	return sc.getPodSyscalls(pod)
+}
+
+// Filter invoked at the filter extension point.
+// Rejects the nodes where the co-tenants of a sensitive pod use denied syscalls, or expose it to more extraneous
+// syscalls than allowed. The same applies to the sensitive pods on the node, with the incoming pod as a co-tenant.
+func (sc *SySched) Filter(ctx context.Context, cs fwk.CycleState, pod *v1.Pod, nodeInfo fwk.NodeInfo) *fwk.Status {
+	if sc.DeniedSyscalls.Len() == 0 && sc.MaxExtraneousSyscalls == nil {
+		return nil
+	}
+	node := nodeInfo.Node()
+	if node == nil {
+		return fwk.NewStatus(fwk.Error, "node not found")
+	}
+
+	// the pods of the snapshot include the pods assumed by the scheduler, not bound yet
+	podInfos := nodeInfo.GetPods()
+	pods := make([]*v1.Pod, 0, len(podInfos))
+	for _, podInfo := range podInfos {
+		pods = append(pods, podInfo.GetPod())
+	}
+	if reason := sc.hosts.checkIsolation(node.Name, pods, sc.getPodSyscalls, sc.cycleSyscalls(cs, pod), sc.DeniedSyscalls, sc.MaxExtraneousSyscalls); reason != "" {
+		logger := klog.FromContext(klog.NewContext(ctx, sc.logger)).WithValues("ExtensionPoint", "Filter")
+		logger.V(5).Info("Filter: ", "pod", pod.Name, "node", node.Name, "reason", reason)
+		return fwk.NewStatus(fwk.Unschedulable, reason)
+	}
+	return nil
+}
+
 // Score invoked at the score extension point.
 func (sc *SySched) Score(ctx context.Context, cs fwk.CycleState, pod *v1.Pod, nodeInfo fwk.NodeInfo) (int64, *fwk.Status) {
 	logger := klog.FromContext(klog.NewContext(ctx, sc.logger)).WithValues("ExtensionPoint", "Score")
@@ -242,7 +341,7 @@ func (sc *SySched) Score(ctx context.Context, cs fwk.CycleState, pod *v1.Pod, no
 	}
 	nodeName := node.Name
 
-	podSyscalls := sc.getSyscalls(pod)
+	podSyscalls := sc.cycleSyscalls(cs, pod).syscalls
 
 	// NOTE: this condition is true only when a pod does not
 	// have a syscall profile, or the unconfined syscall is
@@ -251,30 +350,31 @@ func (sc *SySched) Score(ctx context.Context, cs fwk.CycleState, pod *v1.Pod, no
 		return math.MaxInt64, nil
 	}
 
-	_, hostSyscalls := sc.getHostSyscalls(node.Name)
+	host, ok := sc.hosts.snapshot(nodeName)
 
 	// when a host or node does not have any pods
 	// running, the extraneous syscall score is zero
-	if hostSyscalls == nil {
+	if !ok {
 		return 0, nil
 	}
 
-	diffSyscalls := hostSyscalls.Difference(podSyscalls)
+	diffSyscalls := host.syscalls.Difference(podSyscalls)
 	totalDiffs := sc.calcScore(diffSyscalls)
 
-	// add the difference existing pods will see if new Pod is added into this host
-	newHostSyscalls := hostSyscalls.Clone()
-	newHostSyscalls = newHostSyscalls.Union(podSyscalls)
-	for _, p := range sc.HostToPods[node.Name] {
-		podSyscalls = sc.getSyscalls(p)
-		diffSyscalls = newHostSyscalls.Difference(podSyscalls)
-		totalDiffs += sc.calcScore(diffSyscalls)
-	}
+	// add the difference existing pods will see if new Pod is added into this host.
+	// The syscalls of each existing pod are a subset of the host syscalls, so the sum of
+	// their differences is the count of the new host syscalls for each pod, minus the sum
+	// of the syscall counts of the pods. This matches calcScore, which counts the syscalls.
+	newHostSyscalls := host.syscalls.Len() + podSyscalls.Difference(host.syscalls).Len()
+	totalDiffs += host.pods*newHostSyscalls - host.totalRefs
 
+	sc.exsLock.Lock()
 	sc.ExSAvg = sc.ExSAvg + (float64(totalDiffs)-sc.ExSAvg)/float64(sc.ExSAvgCount)
 	sc.ExSAvgCount += 1
+	exsAvg := sc.ExSAvg
+	sc.exsLock.Unlock()
 
-	logger.V(10).Info("Score: ", "totalDiffs", totalDiffs, "ExSAvg", sc.ExSAvg, "pod", pod.Name, "node", nodeName)
+	logger.V(10).Info("Score: ", "totalDiffs", totalDiffs, "ExSAvg", exsAvg, "pod", pod.Name, "node", nodeName)
 
 	return int64(totalDiffs), nil
 }
@@ -295,75 +395,52 @@ func (sc *SySched) ScoreExtensions() framework.ScoreExtensions {
 
 func (sc *SySched) getHostSyscalls(nodeName string) (int, sets.Set[string]) {
 	count := 0
-	h, ok := sc.HostSyscalls[nodeName]
+	host, ok := sc.hosts.snapshot(nodeName)
 	if !ok {
 		sc.logger.V(5).Info(fmt.Sprintf("getHostSyscalls: no nodeName %s", nodeName))
 		return count, nil
 	}
-	return h.Len(), h
+	return host.syscalls.Len(), host.syscalls
 }
 
-func (sc *SySched) updateHostSyscalls(pod *v1.Pod) {
-	syscall := sc.getSyscalls(pod)
-	sc.HostSyscalls[pod.Spec.NodeName] = sc.HostSyscalls[pod.Spec.NodeName].Union(syscall)
+func (sc *SySched) addPod(pod *v1.Pod) {
+	sc.hosts.addPod(pod.Spec.NodeName, sc.getPodSyscalls(pod))
 }
 
-func (sc *SySched) addPod(pod *v1.Pod) {
+func (sc *SySched) removePod(pod *v1.Pod) {
+	logger := sc.logger
 	nodeName := pod.Spec.NodeName
-	name := pod.Name
 
-	_, ok := sc.HostToPods[nodeName]
-	if !ok {
-		sc.HostToPods[nodeName] = make([]*v1.Pod, 0)
-		sc.HostToPods[nodeName] = append(sc.HostToPods[nodeName], pod)
-		sc.HostSyscalls[nodeName] = sets.New[string]()
-		sc.updateHostSyscalls(pod)
+	if !sc.hosts.removePod(nodeName, pod) {
+		logger.V(5).Info(fmt.Sprintf("removePod: pod %s/%s not cached on host %s", pod.Namespace, pod.Name, nodeName))
 		return
 	}
-
-	for _, p := range sc.HostToPods[nodeName] {
-		if p.Name == name {
-			return
-		}
-	}
-
-	sc.HostToPods[nodeName] = append(sc.HostToPods[nodeName], pod)
-	sc.updateHostSyscalls(pod)
-
-	return
+	c, _ := sc.getHostSyscalls(nodeName)
+	logger.V(5).Info("remaining ", "syscalls", c, "node", nodeName)
 }
 
-func (sc *SySched) recomputeHostSyscalls(pods []*v1.Pod) sets.Set[string] {
-	syscalls := sets.New[string]()
-
-	for _, p := range pods {
-		syscall := sc.getSyscalls(p)
-		syscalls = syscalls.Union(syscall)
+// profileChanged drops the cached syscalls of a seccomp profile, and updates
+// the syscalls of the pods using it
+func (sc *SySched) profileChanged(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
 	}
-
-	return syscalls
-}
-
-func (sc *SySched) removePod(pod *v1.Pod) {
-	logger := sc.logger
-	nodeName := pod.Spec.NodeName
-
-	_, ok := sc.HostToPods[nodeName]
+	profile, ok := obj.(*v1beta1.SeccompProfile)
 	if !ok {
-		logger.V(5).Info(fmt.Sprintf("removePod: Host %s not yet cached", nodeName))
+		sc.logger.V(5).Info(fmt.Sprintf("profileChanged: unexpected object %T", obj))
 		return
 	}
-	for i, p := range sc.HostToPods[nodeName] {
-		if p.Name == pod.Name {
-			sc.HostToPods[nodeName] = remove(sc.HostToPods[nodeName], i)
-			sc.HostSyscalls[nodeName] = sc.recomputeHostSyscalls(sc.HostToPods[nodeName])
-			c, _ := sc.getHostSyscalls(nodeName)
-			logger.V(5).Info("remaining ", "syscalls", c, "node", nodeName)
-			return
+
+	key := types.NamespacedName{Namespace: profile.Namespace, Name: profile.Name}
+	sc.logger.V(10).Info(fmt.Sprintf("PROFILE CHANGED: %s", key))
+	if sc.profiles != nil {
+		sc.profiles.invalidate(key)
+	}
+	for nodeName, entries := range sc.hosts.podsUsingProfile(key) {
+		for _, entry := range entries {
+			sc.hosts.replacePod(nodeName, entry, sc.getPodSyscalls(entry.pod))
 		}
 	}
-
-	return
 }
 
 func (sc *SySched) podAdded(obj interface{}) {
@@ -410,8 +487,8 @@ func getArgs(obj runtime.Object) (*pluginconfig.SySchedArgs, error) {
 func New(ctx context.Context, obj runtime.Object, handle framework.Handle) (framework.Plugin, error) {
 	logger := klog.FromContext(ctx).WithValues("plugin", Name)
 	sc := SySched{logger: logger, handle: handle}
-	sc.HostToPods = make(map[string][]*v1.Pod)
-	sc.HostSyscalls = make(map[string]sets.Set[string])
+	sc.hosts = newHostSyscalls()
+	sc.profiles = newProfileCache()
 	sc.ExSAvg = 0
 	sc.ExSAvgCount = 1
 
@@ -420,10 +497,18 @@ func New(ctx context.Context, obj runtime.Object, handle framework.Handle) (fram
 		return nil, err
 	}
 
+	if err := validation.ValidateSySchedArgs(args, nil); err != nil {
+		return nil, err
+	}
+
 	// get the default syscall profile CR namespace and name for all syscalls
 	sc.DefaultProfileNamespace = args.DefaultProfileNamespace
 	sc.DefaultProfileName = args.DefaultProfileName
 
+	sc.SensitivePodLabel = args.SensitivePodLabel
+	sc.DeniedSyscalls = sets.New[string](args.DeniedSyscalls...)
+	sc.MaxExtraneousSyscalls = args.MaxExtraneousSyscalls
+
 	scheme := runtime.NewScheme()
 	_ = clientscheme.AddToScheme(scheme)
 	_ = v1.AddToScheme(scheme)
@@ -431,13 +516,30 @@ func New(ctx context.Context, obj runtime.Object, handle framework.Handle) (fram
 
 	v1beta1.AddToScheme(scheme)
 
-	c, _, err := util.NewClientWithCachedReader(ctx, handle.KubeConfig(), scheme)
+	c, ccache, err := util.NewClientWithCachedReader(ctx, handle.KubeConfig(), scheme)
 	if err != nil {
 		return nil, err
 	}
 
 	sc.client = c
 
+	// the parsed profiles can be cached only if their changes are watched
+	profileInformer, err := ccache.GetInformer(ctx, &v1beta1.SeccompProfile{})
+	if err != nil {
+		logger.Error(err, "Failed to watch SeccompProfiles, the profiles will be read on every use")
+		sc.profiles = nil
+	} else {
+		profileInformer.AddEventHandler(
+			cache.ResourceEventHandlerFuncs{
+				AddFunc: sc.profileChanged,
+				UpdateFunc: func(old, new interface{}) {
+					sc.profileChanged(new)
+				},
+				DeleteFunc: sc.profileChanged,
+			},
+		)
+	}
+
 	podInformer := handle.SharedInformerFactory().Core().V1().Pods()
 
 	podInformer.Informer().AddEventHandler(
@@ -2,6 +2,8 @@ package sysched
 
 import (
 	"context"
+	"fmt"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -15,12 +17,14 @@ import (
 	clientsetfake "k8s.io/client-go/kubernetes/fake"
 	clientscheme "k8s.io/client-go/kubernetes/scheme"
 	restclient "k8s.io/client-go/rest"
+	fwk "k8s.io/kube-scheduler/framework"
 	"k8s.io/kubernetes/pkg/scheduler/framework"
 	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/defaultbinder"
 	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/queuesort"
 	frameworkruntime "k8s.io/kubernetes/pkg/scheduler/framework/runtime"
 	st "k8s.io/kubernetes/pkg/scheduler/testing"
 	tf "k8s.io/kubernetes/pkg/scheduler/testing/framework"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	pluginconfig "sigs.k8s.io/scheduler-plugins/apis/config"
@@ -95,57 +99,6 @@ var (
 	}
 )
 
-func TestRemove(t *testing.T) {
-	tests := []struct {
-		name        string
-		pods        []*v1.Pod
-		removeIndex []int
-		expectedLen int
-	}{
-		{
-			name: "Remove one",
-			pods: []*v1.Pod{
-				st.MakePod().Name("Pod-1").Obj(),
-				st.MakePod().Name("Pod-2").Obj(),
-				st.MakePod().Name("Pod-3").Obj(),
-			},
-			removeIndex: []int{1},
-			expectedLen: 2,
-		},
-		{
-			name: "Remove two",
-			pods: []*v1.Pod{
-				st.MakePod().Name("Pod-1").Obj(),
-				st.MakePod().Name("Pod-2").Obj(),
-				st.MakePod().Name("Pod-3").Obj(),
-			},
-			removeIndex: []int{1, 0},
-			expectedLen: 1,
-		},
-		{
-			name: "Remove all",
-			pods: []*v1.Pod{
-				st.MakePod().Name("Pod-1").Obj(),
-				st.MakePod().Name("Pod-2").Obj(),
-				st.MakePod().Name("Pod-3").Obj(),
-			},
-			removeIndex: []int{2, 1, 0},
-			expectedLen: 0,
-		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			p := tt.pods
-			for _, i := range tt.removeIndex {
-				p = remove(p, i)
-			}
-			if len(p) != tt.expectedLen {
-				t.Errorf("remove pod failed %d", len(p))
-			}
-		})
-	}
-}
-
 func TestParseNameNS(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -201,8 +154,8 @@ func mockSysched() (*SySched, error) {
 
 	sys := SySched{handle: fr}
 	sys.client = client
-	sys.HostToPods = make(map[string][]*v1.Pod)
-	sys.HostSyscalls = make(map[string]sets.Set[string])
+	sys.hosts = newHostSyscalls()
+	sys.profiles = newProfileCache()
 	sys.ExSAvg = 0
 	sys.ExSAvgCount = 1
 	sys.DefaultProfileName = "full-seccomp"
@@ -377,8 +330,8 @@ func TestScore(t *testing.T) {
 
 	sys := SySched{handle: fr}
 	sys.client = client
-	sys.HostToPods = make(map[string][]*v1.Pod)
-	sys.HostSyscalls = make(map[string]sets.Set[string])
+	sys.hosts = newHostSyscalls()
+	sys.profiles = newProfileCache()
 	sys.ExSAvg = 0
 	sys.ExSAvgCount = 0
 
@@ -412,6 +365,251 @@ func TestScore(t *testing.T) {
 	}
 }
 
+func TestScoreManyPods(t *testing.T) {
+	sys, _ := mockSysched()
+	profiles := []string{"z-seccomp", "x-seccomp", "full-seccomp"}
+	var pods []*v1.Pod
+	for i := 0; i < 7; i++ {
+		pod := st.MakePod().Annotation("seccomp.security.alpha.kubernetes.io",
+			fmt.Sprintf("localhost/operator/default/%s.json", profiles[i%len(profiles)])).
+			Name(fmt.Sprintf("pod%d", i)).Node("test").Obj()
+		pods = append(pods, pod)
+		sys.addPod(pod)
+	}
+
+	for _, profile := range profiles {
+		t.Run(profile, func(t *testing.T) {
+			pod := st.MakePod().Annotation("seccomp.security.alpha.kubernetes.io",
+				fmt.Sprintf("localhost/operator/default/%s.json", profile)).Name("pod").Obj()
+
+			// the extraneous syscalls of the pod, and the ones each existing pod sees
+			podSyscalls := sys.getSyscalls(pod)
+			_, hostSyscalls := sys.getHostSyscalls("test")
+			expected := hostSyscalls.Difference(podSyscalls).Len()
+			newHostSyscalls := hostSyscalls.Union(podSyscalls)
+			for _, p := range pods {
+				expected += newHostSyscalls.Difference(sys.getSyscalls(p)).Len()
+			}
+
+			nodeInfo := framework.NewNodeInfo()
+			nodeInfo.SetNode(&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "test"}})
+			score, _ := sys.Score(context.Background(), nil, pod, nodeInfo)
+			assert.EqualValues(t, expected, score)
+		})
+	}
+}
+
+func TestConcurrentScore(t *testing.T) {
+	sys, _ := mockSysched()
+	nodeInfo := framework.NewNodeInfo()
+	nodeInfo.SetNode(&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "test"}})
+	pod := st.MakePod().Annotation("seccomp.security.alpha.kubernetes.io",
+		"localhost/operator/default/x-seccomp.json").Name("pod").Obj()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				p := st.MakePod().Annotation("seccomp.security.alpha.kubernetes.io",
+					"localhost/operator/default/z-seccomp.json").Name(fmt.Sprintf("pod%d-%d", i, j)).Node("test").Obj()
+				sys.addPod(p)
+				if j%2 == 0 {
+					sys.removePod(p)
+				}
+			}
+		}(i)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				sys.Score(context.Background(), nil, pod, nodeInfo)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.EqualValues(t, 100, len(sys.hosts.nodes["test"].pods))
+	assert.EqualValues(t, 100*len(spoResponse.Spec.Syscalls[0].Names), sys.hosts.nodes["test"].totalRefs)
+}
+
+func BenchmarkScore(b *testing.B) {
+	pod := st.MakePod().Annotation("seccomp.security.alpha.kubernetes.io",
+		"localhost/operator/default/x-seccomp.json").Name("pod").Obj()
+	nodeInfo := framework.NewNodeInfo()
+	nodeInfo.SetNode(&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "test"}})
+
+	for _, podsPerNode := range []int{1, 10, 100, 1000} {
+		b.Run(fmt.Sprintf("%d pods per node", podsPerNode), func(b *testing.B) {
+			sys, err := mockSysched()
+			if err != nil {
+				b.Fatal(err)
+			}
+			for i := 0; i < podsPerNode; i++ {
+				sys.addPod(st.MakePod().Annotation("seccomp.security.alpha.kubernetes.io",
+					"localhost/operator/default/z-seccomp.json").Name(fmt.Sprintf("pod%d", i)).Node("test").Obj())
+			}
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				sys.Score(context.Background(), nil, pod, nodeInfo)
+			}
+		})
+	}
+}
+
+func TestFilter(t *testing.T) {
+	const sensitiveLabel = "sysched.scheduling.x-k8s.io/sensitive"
+	makePod := func(name, profile string, sensitive bool) *st.PodWrapper {
+		pod := st.MakePod().Annotation("seccomp.security.alpha.kubernetes.io",
+			fmt.Sprintf("localhost/operator/default/%s.json", profile)).Name(name)
+		if sensitive {
+			pod.Label(sensitiveLabel, "true")
+		}
+		return pod
+	}
+
+	tests := []struct {
+		name     string
+		existing []*v1.Pod
+		// assumed pods are in the snapshot, but not bound yet
+		assumed []*v1.Pod
+		// victims are bound, but removed from the snapshot, e.g. by preemption
+		victims       []*v1.Pod
+		pod           *v1.Pod
+		denied        []string
+		maxExtraneous *int64
+		expected      *fwk.Status
+	}{
+		{
+			name:     "no constraints",
+			existing: []*v1.Pod{makePod("existing", "full-seccomp", false).Node("test").Obj()},
+			pod:      makePod("pod", "z-seccomp", true).Obj(),
+		},
+		{
+			name:     "co-tenants use denied syscalls",
+			existing: []*v1.Pod{makePod("existing", "full-seccomp", false).Node("test").Obj()},
+			pod:      makePod("pod", "z-seccomp", true).Obj(),
+			denied:   []string{"pivot_root"},
+			expected: fwk.NewStatus(fwk.Unschedulable, ErrReasonDeniedCoTenants),
+		},
+		{
+			name:     "pod not sensitive",
+			existing: []*v1.Pod{makePod("existing", "full-seccomp", false).Node("test").Obj()},
+			pod:      makePod("pod", "z-seccomp", false).Obj(),
+			denied:   []string{"pivot_root"},
+		},
+		{
+			name:          "co-tenants expose too many extraneous syscalls",
+			existing:      []*v1.Pod{makePod("existing", "full-seccomp", false).Node("test").Obj()},
+			pod:           makePod("pod", "z-seccomp", true).Obj(),
+			maxExtraneous: ptr.To[int64](5),
+			expected:      fwk.NewStatus(fwk.Unschedulable, ErrReasonExtraneousCoTenants),
+		},
+		{
+			name:          "co-tenants within the extraneous syscalls threshold",
+			existing:      []*v1.Pod{makePod("existing", "x-seccomp", false).Node("test").Obj()},
+			pod:           makePod("pod", "z-seccomp", true).Obj(),
+			maxExtraneous: ptr.To[int64](1),
+		},
+		{
+			name:     "pod uses syscalls denied next to a sensitive pod",
+			existing: []*v1.Pod{makePod("existing", "z-seccomp", true).Node("test").Obj()},
+			pod:      makePod("pod", "full-seccomp", false).Obj(),
+			denied:   []string{"pivot_root"},
+			expected: fwk.NewStatus(fwk.Unschedulable, ErrReasonDeniedSensitive),
+		},
+		{
+			name:          "pod exposes a sensitive pod to too many extraneous syscalls",
+			existing:      []*v1.Pod{makePod("existing", "z-seccomp", true).Node("test").Obj()},
+			pod:           makePod("pod", "full-seccomp", false).Obj(),
+			maxExtraneous: ptr.To[int64](5),
+			expected:      fwk.NewStatus(fwk.Unschedulable, ErrReasonExtraneousSensitive),
+		},
+		{
+			name: "pod brings no new syscalls to a sensitive pod",
+			existing: []*v1.Pod{
+				makePod("existing", "z-seccomp", true).Node("test").Obj(),
+				makePod("other", "x-seccomp", false).Node("test").Obj(),
+			},
+			pod:           makePod("pod", "x-seccomp", false).Obj(),
+			maxExtraneous: ptr.To[int64](1),
+		},
+		{
+			name:     "assumed co-tenants use denied syscalls",
+			assumed:  []*v1.Pod{makePod("assumed", "full-seccomp", false).Node("test").Obj()},
+			pod:      makePod("pod", "z-seccomp", true).Obj(),
+			denied:   []string{"pivot_root"},
+			expected: fwk.NewStatus(fwk.Unschedulable, ErrReasonDeniedCoTenants),
+		},
+		{
+			name:     "pod uses syscalls denied next to an assumed sensitive pod",
+			existing: []*v1.Pod{makePod("existing", "x-seccomp", false).Node("test").Obj()},
+			assumed:  []*v1.Pod{makePod("assumed", "z-seccomp", true).Node("test").Obj()},
+			pod:      makePod("pod", "full-seccomp", false).Obj(),
+			denied:   []string{"pivot_root"},
+			expected: fwk.NewStatus(fwk.Unschedulable, ErrReasonDeniedSensitive),
+		},
+		{
+			name:     "co-tenants using denied syscalls preempted",
+			existing: []*v1.Pod{makePod("existing", "x-seccomp", false).Node("test").Obj()},
+			victims:  []*v1.Pod{makePod("victim", "full-seccomp", false).Node("test").Obj()},
+			pod:      makePod("pod", "z-seccomp", true).Obj(),
+			denied:   []string{"pivot_root"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sys, _ := mockSysched()
+			sys.SensitivePodLabel = sensitiveLabel
+			sys.DeniedSyscalls = sets.New(tt.denied...)
+			sys.MaxExtraneousSyscalls = tt.maxExtraneous
+			for _, pod := range append(tt.existing, tt.victims...) {
+				sys.addPod(pod)
+			}
+
+			nodeInfo := framework.NewNodeInfo(append(tt.existing, tt.assumed...)...)
+			nodeInfo.SetNode(&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "test"}})
+			status := sys.Filter(context.Background(), nil, tt.pod, nodeInfo)
+			assert.Equal(t, tt.expected, status)
+		})
+	}
+}
+
+func TestPreFilter(t *testing.T) {
+	sys, err := mockSysched()
+	assert.Nil(t, err)
+	sys.SensitivePodLabel = "sysched.scheduling.x-k8s.io/sensitive"
+	existing := st.MakePod().Annotation("seccomp.security.alpha.kubernetes.io",
+		"localhost/operator/default/z-seccomp.json").Label(sys.SensitivePodLabel, "true").Name("existing").Node("test").Obj()
+	sys.addPod(existing)
+	pod := st.MakePod().Annotation("seccomp.security.alpha.kubernetes.io",
+		"localhost/operator/default/x-seccomp.json").Name("pod").Obj()
+	nodeInfo := framework.NewNodeInfo(existing)
+	nodeInfo.SetNode(&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "test"}})
+
+	// without isolation constraints the Filter is skipped, but the syscalls are still read for the Score
+	state := framework.NewCycleState()
+	_, status := sys.PreFilter(context.Background(), state, pod, nil)
+	assert.Equal(t, fwk.Skip, status.Code())
+	_, err = state.Read(stateKey)
+	assert.Nil(t, err)
+
+	sys.DeniedSyscalls = sets.New("pivot_root")
+	state = framework.NewCycleState()
+	_, status = sys.PreFilter(context.Background(), state, pod, nil)
+	assert.True(t, status.IsSuccess())
+
+	// the profile of the pod is gone, so reading its syscalls again falls back to the default profile
+	assert.Nil(t, sys.client.Delete(context.Background(), spoResponse1.DeepCopy()))
+	sys.profiles = nil
+	assert.Nil(t, sys.Filter(context.Background(), state, pod, nodeInfo))
+	score, _ := sys.Score(context.Background(), state, pod, nodeInfo)
+	assert.EqualValues(t, 2, score)
+	assert.Equal(t, fwk.NewStatus(fwk.Unschedulable, ErrReasonDeniedSensitive),
+		sys.Filter(context.Background(), framework.NewCycleState(), pod, nodeInfo))
+}
+
 func TestNormalizeScore(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -511,11 +709,11 @@ func TestUpdateHostSyscalls(t *testing.T) {
 				st.MakeNode().Name("test1").Obj(),
 			},
 			basePods: []*v1.Pod{
-				st.MakePod().Annotation("seccomp.security.alpha.kubernetes.io",
+				st.MakePod().Name("pod1").Annotation("seccomp.security.alpha.kubernetes.io",
 					"localhost/operator/default/z-seccomp.json").Node("test").Obj(),
 			},
 			newPods: []*v1.Pod{
-				st.MakePod().Annotation("seccomp.security.alpha.kubernetes.io",
+				st.MakePod().Name("pod2").Annotation("seccomp.security.alpha.kubernetes.io",
 					"localhost/operator/default/x-seccomp.json").Node("test").Obj(),
 			},
 			expected: sets.New[string](spoResponse.Spec.Syscalls[0].Names...).Union(sets.New[string](spoResponse1.Spec.Syscalls[0].Names...)).Len(),
@@ -556,8 +754,8 @@ func TestUpdateHostSyscalls(t *testing.T) {
 
 			sys := SySched{handle: fr}
 			sys.client = client
-			sys.HostToPods = make(map[string][]*v1.Pod)
-			sys.HostSyscalls = make(map[string]sets.Set[string])
+			sys.hosts = newHostSyscalls()
+			sys.profiles = newProfileCache()
 			sys.ExSAvg = 0
 			sys.ExSAvgCount = 0
 
@@ -566,7 +764,7 @@ func TestUpdateHostSyscalls(t *testing.T) {
 			}
 
 			for i := range tt.newPods {
-				sys.updateHostSyscalls(tt.newPods[i])
+				sys.addPod(tt.newPods[i])
 			}
 			sc, _ := sys.getHostSyscalls("test")
 			assert.EqualValues(t, tt.expected, sc)
@@ -597,39 +795,42 @@ func TestAddPod(t *testing.T) {
 			for i := range tt.pods {
 				sys.addPod(tt.pods[i])
 			}
-			for i := range sys.HostToPods["test"] {
-				assert.EqualValues(t, tt.pods[i], sys.HostToPods["test"][i])
+			for i := range tt.pods {
+				assert.EqualValues(t, tt.pods[i], sys.hosts.nodes["test"].pods[podKey(tt.pods[i])].pod)
 			}
-			assert.EqualValues(t, tt.expected, len(sys.HostSyscalls["test"]))
+			assert.EqualValues(t, tt.expected, sys.hosts.nodes["test"].syscalls.Len())
 		})
 	}
 }
 
-func TestRecomputeHostSyscalls(t *testing.T) {
+func TestProfileChanged(t *testing.T) {
 	sys, _ := mockSysched()
-	tests := []struct {
-		name     string
-		pods     []*v1.Pod
-		expected int
-	}{
-		{
-			name: "Recompute 2 pods",
-			pods: []*v1.Pod{
-				st.MakePod().Annotation("seccomp.security.alpha.kubernetes.io",
-					"localhost/operator/default/z-seccomp.json").Node("test").Obj(),
-				st.MakePod().Annotation("seccomp.security.alpha.kubernetes.io",
-					"localhost/operator/default/x-seccomp.json").Node("test").Obj(),
-			},
-			expected: sets.New[string](spoResponse.Spec.Syscalls[0].Names...).Union(sets.New[string](spoResponse1.Spec.Syscalls[0].Names...)).Len(),
-		},
+	pods := []*v1.Pod{
+		st.MakePod().Annotation("seccomp.security.alpha.kubernetes.io",
+			"localhost/operator/default/z-seccomp.json").Name("pod1").Node("test").Obj(),
+		st.MakePod().Annotation("seccomp.security.alpha.kubernetes.io",
+			"localhost/operator/default/x-seccomp.json").Name("pod2").Node("test").Obj(),
 	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			syscalls := sys.recomputeHostSyscalls(tt.pods)
-			assert.EqualValues(t, tt.expected, len(syscalls))
-		})
+	for i := range pods {
+		sys.addPod(pods[i])
 	}
+
+	// the profile is parsed once, so changes are not seen until invalidated
+	updated := spoResponse1.DeepCopy()
+	updated.Spec.Syscalls[0].Names = []string{"read", "write", "ptrace"}
+	assert.Nil(t, sys.client.Update(context.TODO(), updated))
+	assert.EqualValues(t, len(spoResponse1.Spec.Syscalls[0].Names), sys.getSyscalls(pods[1]).Len())
+
+	sys.profileChanged(updated)
+
+	expected := sets.New[string](spoResponse.Spec.Syscalls[0].Names...).Insert("ptrace")
+	assert.EqualValues(t, 3, sys.getSyscalls(pods[1]).Len())
+	assert.EqualValues(t, expected.Len(), sys.hosts.nodes["test"].syscalls.Len())
+	assert.EqualValues(t, len(spoResponse.Spec.Syscalls[0].Names)+3, sys.hosts.nodes["test"].totalRefs)
+
+	sys.removePod(pods[0])
+	assert.EqualValues(t, 3, sys.hosts.nodes["test"].syscalls.Len())
+	assert.EqualValues(t, 3, sys.hosts.nodes["test"].totalRefs)
 }
 
 func TestRemovePod(t *testing.T) {
@@ -666,8 +867,8 @@ func TestRemovePod(t *testing.T) {
 				sys.removePod(tt.removePods[i])
 			}
 
-			assert.EqualValues(t, tt.expectedPodNum, len(sys.HostToPods["test"]))
-			assert.EqualValues(t, tt.expected, len(sys.HostSyscalls["test"]))
+			assert.EqualValues(t, tt.expectedPodNum, len(sys.hosts.nodes["test"].pods))
+			assert.EqualValues(t, tt.expected, sys.hosts.nodes["test"].syscalls.Len())
 		})
 	}
 }
@@ -700,8 +901,8 @@ func TestPodAdded(t *testing.T) {
 				sys.podAdded(tt.basePods[i])
 			}
 
-			assert.EqualValues(t, tt.expectedPodNum, len(sys.HostToPods["test"]))
-			assert.EqualValues(t, tt.expected, len(sys.HostSyscalls["test"]))
+			assert.EqualValues(t, tt.expectedPodNum, len(sys.hosts.nodes["test"].pods))
+			assert.EqualValues(t, tt.expected, sys.hosts.nodes["test"].syscalls.Len())
 		})
 	}
 }
@@ -740,8 +941,8 @@ func TestPodUpdated(t *testing.T) {
 				sys.podUpdated(tt.updatedPods[i], nil)
 			}
 
-			assert.EqualValues(t, tt.expectedPodNum, len(sys.HostToPods["test"]))
-			assert.EqualValues(t, tt.expected, len(sys.HostSyscalls["test"]))
+			assert.EqualValues(t, tt.expectedPodNum, len(sys.hosts.nodes["test"].pods))
+			assert.EqualValues(t, tt.expected, sys.hosts.nodes["test"].syscalls.Len())
 		})
 	}
 }
@@ -780,8 +981,8 @@ func TestPodDeleted(t *testing.T) {
 				sys.podDeleted(tt.deletedPods[i])
 			}
 
-			assert.EqualValues(t, tt.expectedPodNum, len(sys.HostToPods["test"]))
-			assert.EqualValues(t, tt.expected, len(sys.HostSyscalls["test"]))
+			assert.EqualValues(t, tt.expectedPodNum, len(sys.hosts.nodes["test"].pods))
+			assert.EqualValues(t, tt.expected, sys.hosts.nodes["test"].syscalls.Len())
 		})
 	}
 }
@@ -27,25 +27,36 @@ import (
 	"k8s.io/klog/v2"
 
 	pluginConfig "sigs.k8s.io/scheduler-plugins/apis/config"
+	"sigs.k8s.io/scheduler-plugins/apis/config/validation"
 )
 
 const (
 	metricsUpdateIntervalSeconds = 30
+	metricsRetentionSeconds      = 900
 )
 
 // Collector : get data from load watcher, encapsulating the load watcher and its operations
 //
-// Trimaran plugins have different, potentially conflicting, objectives. Thus, it is recommended not
-// to enable them concurrently. As such, they are currently designed to each have its own Collector.
-// If a need arises in the future to enable multiple Trimaran plugins, a restructuring to have a single
-// Collector, serving the multiple plugins, may be beneficial for performance reasons.
+// Plugins get collectors through AcquireCollector, so that a single Collector, polling the metric
+// source once, serves all the Trimaran plugins and profiles using the same metric source.
 type Collector struct {
 	// load watcher client
 	client loadwatcherapi.Client
 	// data collected by load watcher
 	metrics watcher.WatcherMetrics
-	// for safe access to metrics
+	// retained samples of the metrics of each node, oldest first
+	history map[string][]MetricsSample
+	// duration for which the samples are retained
+	retention time.Duration
+	// maximum number of samples retained for a node
+	maxSamples int
+	// for safe access to metrics and history
 	mu sync.RWMutex
+	// registry key, empty if the collector is not shared
+	key string
+	// closed to stop the periodic updates
+	stopCh   chan struct{}
+	stopOnce sync.Once
 }
 
 // NewCollector : create an instance of a data collector
@@ -62,6 +73,8 @@ func NewCollector(logger klog.Logger, trimaranSpec *pluginConfig.TrimaranSpec) (
 	)
 	if trimaranSpec.WatcherAddress != "" {
 		client, err = loadwatcherapi.NewServiceClient(trimaranSpec.WatcherAddress)
+	} else if len(trimaranSpec.MetricProvider.Queries) > 0 {
+		client, err = newPrometheusClient(logger, &trimaranSpec.MetricProvider)
 	} else {
 		opts := watcher.MetricsProviderOpts{
 			Name:               string(trimaranSpec.MetricProvider.Type),
@@ -76,8 +89,13 @@ func NewCollector(logger klog.Logger, trimaranSpec *pluginConfig.TrimaranSpec) (
 		return nil, err
 	}
 
+	updateInterval, retention := collectorIntervals(trimaranSpec)
 	collector := &Collector{
-		client: client,
+		client:     client,
+		history:    make(map[string][]MetricsSample),
+		retention:  retention,
+		maxSamples: int(retention/updateInterval) + 1,
+		stopCh:     make(chan struct{}),
 	}
 
 	// populate metrics before returning
@@ -87,17 +105,43 @@ func NewCollector(logger klog.Logger, trimaranSpec *pluginConfig.TrimaranSpec) (
 	}
 	// start periodic updates
 	go func() {
-		metricsUpdaterTicker := time.NewTicker(time.Second * metricsUpdateIntervalSeconds)
-		for range metricsUpdaterTicker.C {
-			err = collector.updateMetrics(logger)
-			if err != nil {
-				logger.Error(err, "Unable to update metrics")
+		metricsUpdaterTicker := time.NewTicker(updateInterval)
+		defer metricsUpdaterTicker.Stop()
+		for {
+			select {
+			case <-collector.stopCh:
+				return
+			case <-metricsUpdaterTicker.C:
+				if err := collector.updateMetrics(logger); err != nil {
+					logger.Error(err, "Unable to update metrics")
+				}
 			}
 		}
 	}()
 	return collector, nil
 }
 
+// collectorIntervals : get the interval between two updates of the metrics, and the retention of the history,
+// the defaults for the unset (zero) ones; negative ones are rejected by checkSpecs
+func collectorIntervals(trimaranSpec *pluginConfig.TrimaranSpec) (time.Duration, time.Duration) {
+	updateInterval := time.Second * metricsUpdateIntervalSeconds
+	if trimaranSpec.MetricsRefreshIntervalSeconds > 0 {
+		updateInterval = time.Second * time.Duration(trimaranSpec.MetricsRefreshIntervalSeconds)
+	}
+	retention := time.Second * metricsRetentionSeconds
+	if trimaranSpec.MetricsRetentionSeconds > 0 {
+		retention = time.Second * time.Duration(trimaranSpec.MetricsRetentionSeconds)
+	}
+	return updateInterval, retention
+}
+
+// stop : stop the periodic updates of the metrics
+func (collector *Collector) stop() {
+	collector.stopOnce.Do(func() {
+		close(collector.stopCh)
+	})
+}
+
 // getAllMetrics : get all metrics from watcher
 func (collector *Collector) getAllMetrics() *watcher.WatcherMetrics {
 	collector.mu.RLock()
@@ -107,8 +151,15 @@ func (collector *Collector) getAllMetrics() *watcher.WatcherMetrics {
 }
 
 // GetNodeMetrics : get metrics for a node from watcher
+// (falls back to the latest retained sample of the node if the node is missing from the last metrics)
 func (collector *Collector) GetNodeMetrics(logger klog.Logger, nodeName string) ([]watcher.Metric, *watcher.WatcherMetrics) {
 	allMetrics := collector.getAllMetrics()
+	if _, ok := allMetrics.Data.NodeMetricsMap[nodeName]; !ok {
+		if sample := collector.latestSample(nodeName, time.Now()); sample != nil {
+			logger.V(4).Info("Using retained metrics for node", "nodeName", nodeName, "timestamp", sample.Timestamp)
+			return sample.Metrics, allMetrics
+		}
+	}
 	// This happens if metrics were never populated since scheduler started
 	if allMetrics.Data.NodeMetricsMap == nil {
 		logger.Error(nil, "Metrics not available from watcher")
@@ -124,6 +175,9 @@ func (collector *Collector) GetNodeMetrics(logger klog.Logger, nodeName string)
 
 // checkSpecs : check trimaran specs
 func checkSpecs(trimaranSpec *pluginConfig.TrimaranSpec) error {
+	if err := validation.ValidateTrimaranSpec(trimaranSpec, nil); err != nil {
+		return err
+	}
 	if trimaranSpec.WatcherAddress == "" {
 		metricProviderType := string(trimaranSpec.MetricProvider.Type)
 		validMetricProviderType := metricProviderType == string(pluginConfig.KubernetesMetricsServer) ||
@@ -132,6 +186,14 @@ func checkSpecs(trimaranSpec *pluginConfig.TrimaranSpec) error {
 		if !validMetricProviderType {
 			return fmt.Errorf("invalid MetricProvider.Type, got %v", trimaranSpec.MetricProvider.Type)
 		}
+		if len(trimaranSpec.MetricProvider.Queries) > 0 && trimaranSpec.MetricProvider.Type != pluginConfig.Prometheus {
+			return fmt.Errorf("MetricProvider.Queries are only supported by %v, got %v", pluginConfig.Prometheus, trimaranSpec.MetricProvider.Type)
+		}
+		for i, query := range trimaranSpec.MetricProvider.Queries {
+			if query.Type == "" || query.Query == "" || query.NodeLabel == "" {
+				return fmt.Errorf("MetricProvider.Queries[%d] must set type, query and nodeLabel", i)
+			}
+		}
 	}
 	return nil
 }
@@ -139,12 +201,15 @@ func checkSpecs(trimaranSpec *pluginConfig.TrimaranSpec) error {
 // updateMetrics : request to load watcher to update all metrics
 func (collector *Collector) updateMetrics(logger klog.Logger) error {
 	metrics, err := collector.client.GetLatestWatcherMetrics()
+	now := time.Now()
+	collector.mu.Lock()
+	defer collector.mu.Unlock()
 	if err != nil {
 		logger.Error(err, "Load watcher client failed")
+		collector.pruneHistory(now)
 		return err
 	}
-	collector.mu.Lock()
 	collector.metrics = *metrics
-	collector.mu.Unlock()
+	collector.recordHistory(metrics, now)
 	return nil
 }
@@ -102,6 +102,14 @@ func TestNewCollectorSpecs(t *testing.T) {
 	assert.Nil(t, col)
 	expectedErr := "invalid MetricProvider.Type, got " + string(metricProvider.Type)
 	assert.EqualError(t, err, expectedErr)
+
+	trimaranSpec = pluginConfig.TrimaranSpec{
+		WatcherAddress:                server.URL,
+		MetricsRefreshIntervalSeconds: -30,
+	}
+	col, err = AcquireCollector(logger, &trimaranSpec)
+	assert.Nil(t, col)
+	assert.ErrorContains(t, err, "metricsRefreshIntervalSeconds should be a non-negative value")
 }
 
 func TestGetAllMetrics(t *testing.T) {
@@ -0,0 +1,232 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package energycost provides a K8s scheduler plugin scoring nodes by the cost of the energy a pod would draw on them:
+the increase of the power of the node predicted by the Peaks power model, weighted by the carbon intensity of the grid
+or the price of electricity of the zone of the node, at the time of scheduling.
+It contains plugin for Score extension point.
+*/
+
+package energycost
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/klog/v2"
+	fwk "k8s.io/kube-scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	pluginConfig "sigs.k8s.io/scheduler-plugins/apis/config"
+	"sigs.k8s.io/scheduler-plugins/apis/config/validation"
+	"sigs.k8s.io/scheduler-plugins/pkg/trimaran"
+	"sigs.k8s.io/scheduler-plugins/pkg/trimaran/peaks"
+)
+
+const (
+	// Name : name of plugin used in the plugin registry and configurations.
+	Name = "EnergyCost"
+	// preScoreStateKey is the key in CycleState to the intensities of the scheduling cycle
+	preScoreStateKey = "PreScore" + Name
+	// unknownCost is the score of the nodes whose cost is unknown, ranked last by NormalizeScore
+	unknownCost = -1
+	// costScale keeps the fractional part of the costs, scored as integers
+	costScale = 1000
+)
+
+// EnergyCost : scheduler plugin
+type EnergyCost struct {
+	logger      klog.Logger
+	handle      framework.Handle
+	collector   *trimaran.Collector
+	powerModels *peaks.PowerModels
+	source      IntensitySource
+	zoneLabel   string
+}
+
+var _ framework.PreScorePlugin = &EnergyCost{}
+var _ framework.ScorePlugin = &EnergyCost{}
+var _ io.Closer = &EnergyCost{}
+
+// preScoreState holds the intensities of the zones at the time of the scheduling cycle, so all nodes are scored
+// against the same signal
+type preScoreState struct {
+	intensities map[string]float64
+	// fallback is the intensity of the zones without a signal, the highest one of the other zones,
+	// so missing signals do not make a zone look cheap
+	fallback float64
+}
+
+func (s *preScoreState) Clone() fwk.StateData {
+	return s
+}
+
+// New : create an instance of an EnergyCost plugin
+func New(ctx context.Context, obj runtime.Object, handle framework.Handle) (framework.Plugin, error) {
+	logger := klog.FromContext(ctx).WithValues("plugin", Name)
+	logger.V(4).Info("Creating new instance of the EnergyCost plugin")
+
+	args, ok := obj.(*pluginConfig.EnergyCostArgs)
+	if !ok {
+		return nil, fmt.Errorf("want args to be of type EnergyCostArgs, got %T", obj)
+	}
+	if err := validation.ValidateEnergyCostArgs(args, nil); err != nil {
+		return nil, err
+	}
+
+	collector, err := trimaran.AcquireCollector(logger, &args.TrimaranSpec)
+	if err != nil {
+		return nil, err
+	}
+	powerModels, err := peaks.NewPowerModels(ctx, logger, handle, args.NodePowerModel)
+	if err != nil {
+		logger.Error(err, "Unable to create power model from the input configuration")
+		collector.Release()
+		return nil, err
+	}
+	source, err := NewIntensitySource(ctx, logger, handle.ClientSet(), &args.IntensitySource)
+	if err != nil {
+		collector.Release()
+		powerModels.Release()
+		return nil, err
+	}
+	pl := &EnergyCost{
+		logger:      logger,
+		handle:      handle,
+		collector:   collector,
+		powerModels: powerModels,
+		source:      source,
+		zoneLabel:   args.ZoneLabel,
+	}
+	return pl, nil
+}
+
+// Name : name of plugin
+func (pl *EnergyCost) Name() string {
+	return Name
+}
+
+// Close releases the collector and the PowerModel informer, shared with the other Trimaran plugins,
+// and stops the intensity source
+func (pl *EnergyCost) Close() error {
+	pl.collector.Release()
+	pl.powerModels.Release()
+	pl.source.Stop()
+	return nil
+}
+
+// PreScore : snapshot the intensities of the zones for the scheduling cycle
+func (pl *EnergyCost) PreScore(ctx context.Context, cycleState fwk.CycleState, pod *v1.Pod, nodes []fwk.NodeInfo) *fwk.Status {
+	cycleState.Write(preScoreStateKey, pl.intensities(time.Now()))
+	return nil
+}
+
+func (pl *EnergyCost) intensities(at time.Time) *preScoreState {
+	state := &preScoreState{
+		intensities: pl.source.Intensities(at),
+	}
+	if len(state.intensities) == 0 {
+		// without any signal, every watt is equal
+		state.fallback = 1
+		return state
+	}
+	for _, intensity := range state.intensities {
+		state.fallback = math.Max(state.fallback, intensity)
+	}
+	return state
+}
+
+// Score : score the nodes by the cost of the energy the pod would draw on them, the lower the better
+func (pl *EnergyCost) Score(ctx context.Context, cycleState fwk.CycleState, pod *v1.Pod, nodeInfo fwk.NodeInfo) (int64, *fwk.Status) {
+	logger := klog.FromContext(klog.NewContext(ctx, pl.logger)).WithValues("ExtensionPoint", "Score")
+	node := nodeInfo.Node()
+
+	metrics, _ := pl.collector.GetNodeMetrics(logger, node.Name)
+	if metrics == nil {
+		logger.V(4).Info("Failed to get metrics for node; ranking it last", "nodeName", node.Name)
+		return unknownCost, nil
+	}
+	current, predicted, ok := peaks.PredictCPUUtilisation(pod, node, metrics)
+	if !ok {
+		logger.V(4).Info("CPU metric not found in node metrics; ranking it last", "nodeName", node.Name)
+		return unknownCost, nil
+	}
+	predicted = math.Min(predicted, 100)
+	jump := math.Max(peaks.PowerJump(current, predicted, pl.powerModels.Get(node)), 0)
+
+	state, err := getPreScoreState(cycleState)
+	if err != nil {
+		state = pl.intensities(time.Now())
+	}
+	intensity, ok := state.intensities[node.Labels[pl.zoneLabel]]
+	if !ok {
+		intensity = state.fallback
+	}
+
+	cost := jump * intensity * costScale
+	logger.V(6).Info("Score:", "pod", pod.GetName(), "node", node.Name, "powerJump", jump, "intensity", intensity)
+	if cost >= math.MaxInt64 {
+		return math.MaxInt64, nil
+	}
+	return int64(math.Round(cost)), nil
+}
+
+func getPreScoreState(cycleState fwk.CycleState) (*preScoreState, error) {
+	c, err := cycleState.Read(preScoreStateKey)
+	if err != nil {
+		return nil, err
+	}
+	s, ok := c.(*preScoreState)
+	if !ok {
+		return nil, fmt.Errorf("%+v  convert to energycost.preScoreState error", c)
+	}
+	return s, nil
+}
+
+// ScoreExtensions : an interface for Score extended functionality
+func (pl *EnergyCost) ScoreExtensions() framework.ScoreExtensions {
+	return pl
+}
+
+// NormalizeScore : map the costs to scores, the cheapest node getting the maximum score, and the nodes of unknown cost the minimum one
+func (pl *EnergyCost) NormalizeScore(ctx context.Context, state fwk.CycleState, pod *v1.Pod, scores framework.NodeScoreList) *fwk.Status {
+	var minCost, maxCost int64 = math.MaxInt64, 0
+	for _, score := range scores {
+		if score.Score == unknownCost {
+			continue
+		}
+		minCost = min(minCost, score.Score)
+		maxCost = max(maxCost, score.Score)
+	}
+	for i := range scores {
+		switch {
+		case scores[i].Score == unknownCost:
+			scores[i].Score = framework.MinNodeScore
+		case maxCost == minCost:
+			scores[i].Score = framework.MaxNodeScore
+		default:
+			normCost := float64(framework.MaxNodeScore) * float64(scores[i].Score-minCost) / float64(maxCost-minCost)
+			scores[i].Score = framework.MaxNodeScore - int64(normCost)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,229 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package energycost
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/paypal/load-watcher/pkg/watcher"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	testClientSet "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/defaultbinder"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/queuesort"
+	"k8s.io/kubernetes/pkg/scheduler/framework/runtime"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+	tf "k8s.io/kubernetes/pkg/scheduler/testing/framework"
+
+	pluginConfig "sigs.k8s.io/scheduler-plugins/apis/config"
+	"sigs.k8s.io/scheduler-plugins/pkg/trimaran/peaks"
+	testutil "sigs.k8s.io/scheduler-plugins/test/util"
+)
+
+func cpuMetrics(value float64) watcher.NodeMetrics {
+	return watcher.NodeMetrics{
+		Metrics: []watcher.Metric{
+			{
+				Type:     watcher.CPU,
+				Operator: watcher.Average,
+				Value:    value,
+			},
+		},
+	}
+}
+
+func TestEnergyCostScore(t *testing.T) {
+	watcherResponse := watcher.WatcherMetrics{
+		Data: watcher.Data{
+			NodeMetricsMap: map[string]watcher.NodeMetrics{
+				"node-a":      cpuMetrics(10),
+				"node-b":      cpuMetrics(10),
+				"node-c":      cpuMetrics(10),
+				"node-no-cpu": {Metrics: []watcher.Metric{{Type: watcher.Memory, Operator: watcher.Average, Value: 10}}},
+			},
+		},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		bytes, err := json.Marshal(watcherResponse)
+		assert.Nil(t, err)
+		resp.Write(bytes)
+	}))
+	defer server.Close()
+
+	model := pluginConfig.PowerModel{K0: 100, K1: 10, K2: 0.02}
+	args := pluginConfig.EnergyCostArgs{
+		TrimaranSpec: pluginConfig.TrimaranSpec{WatcherAddress: server.URL},
+		NodePowerModel: map[string]pluginConfig.PowerModel{
+			"node-a": model,
+			"node-b": model,
+			"node-c": model,
+		},
+		ZoneLabel: v1.LabelTopologyZone,
+		IntensitySource: pluginConfig.IntensitySourceSpec{
+			Type:               pluginConfig.ConfigMapIntensitySource,
+			ConfigMapNamespace: metav1.NamespaceSystem,
+			ConfigMapName:      "energy-cost-intensity",
+		},
+	}
+	cm := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceSystem, Name: "energy-cost-intensity"},
+		Data: map[string]string{
+			"zone-a": "100",
+			"zone-b": "400",
+		},
+	}
+
+	nodeResources := map[v1.ResourceName]string{
+		v1.ResourceCPU:    "1000m",
+		v1.ResourceMemory: "1Gi",
+	}
+	nodes := []*v1.Node{
+		st.MakeNode().Name("node-a").Label(v1.LabelTopologyZone, "zone-a").Capacity(nodeResources).Obj(),
+		st.MakeNode().Name("node-b").Label(v1.LabelTopologyZone, "zone-b").Capacity(nodeResources).Obj(),
+		st.MakeNode().Name("node-c").Label(v1.LabelTopologyZone, "zone-c").Capacity(nodeResources).Obj(),
+		st.MakeNode().Name("node-no-cpu").Label(v1.LabelTopologyZone, "zone-a").Capacity(nodeResources).Obj(),
+		st.MakeNode().Name("node-no-metrics").Label(v1.LabelTopologyZone, "zone-a").Capacity(nodeResources).Obj(),
+	}
+	pod := st.MakePod().Name("p").Req(map[v1.ResourceName]string{v1.ResourceCPU: "500m"}).Obj()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	registeredPlugins := []tf.RegisterPluginFunc{
+		tf.RegisterBindPlugin(defaultbinder.Name, defaultbinder.New),
+		tf.RegisterQueueSortPlugin(queuesort.Name, queuesort.New),
+	}
+	cs := testClientSet.NewSimpleClientset(cm)
+	informerFactory := informers.NewSharedInformerFactory(cs, 0)
+	snapshot := testutil.NewFakeSharedLister(nil, nodes)
+	fh, err := testutil.NewFramework(ctx, registeredPlugins, nil, "default-scheduler",
+		runtime.WithClientSet(cs), runtime.WithInformerFactory(informerFactory), runtime.WithSnapshotSharedLister(snapshot))
+	assert.Nil(t, err)
+
+	p, err := New(ctx, &args, fh)
+	assert.Nil(t, err)
+	defer p.(io.Closer).Close()
+	pl := p.(*EnergyCost)
+
+	state := framework.NewCycleState()
+	nodeInfos, err := snapshot.NodeInfos().List()
+	assert.Nil(t, err)
+	status := pl.PreScore(ctx, state, pod, nodeInfos)
+	assert.True(t, status.IsSuccess())
+
+	var scores framework.NodeScoreList
+	for _, n := range nodes {
+		nodeInfo, err := snapshot.NodeInfos().Get(n.Name)
+		assert.Nil(t, err)
+		score, status := pl.Score(ctx, state, pod, nodeInfo)
+		assert.True(t, status.IsSuccess())
+		scores = append(scores, framework.NodeScore{Name: n.Name, Score: score})
+	}
+
+	jump := peaks.PowerJump(10, 60, model)
+	costA := int64(math.Round(jump * 100 * costScale))
+	costB := int64(math.Round(jump * 400 * costScale))
+	assert.Equal(t, framework.NodeScoreList{
+		{Name: "node-a", Score: costA},
+		{Name: "node-b", Score: costB},
+		{Name: "node-c", Score: costB},
+		{Name: "node-no-cpu", Score: unknownCost},
+		{Name: "node-no-metrics", Score: unknownCost},
+	}, scores, "zones without a signal get the highest intensity")
+
+	status = pl.NormalizeScore(ctx, state, pod, scores)
+	assert.True(t, status.IsSuccess())
+	assert.Equal(t, framework.NodeScoreList{
+		{Name: "node-a", Score: framework.MaxNodeScore},
+		{Name: "node-b", Score: framework.MinNodeScore},
+		{Name: "node-c", Score: framework.MinNodeScore},
+		{Name: "node-no-cpu", Score: framework.MinNodeScore},
+		{Name: "node-no-metrics", Score: framework.MinNodeScore},
+	}, scores)
+}
+
+func TestEnergyCostNormalizeScore(t *testing.T) {
+	tests := []struct {
+		name     string
+		scores   framework.NodeScoreList
+		expected framework.NodeScoreList
+	}{
+		{
+			name: "costs scaled to scores, the cheapest first",
+			scores: framework.NodeScoreList{
+				{Name: "node-1", Score: 1000},
+				{Name: "node-2", Score: 2000},
+				{Name: "node-3", Score: 5000},
+			},
+			expected: framework.NodeScoreList{
+				{Name: "node-1", Score: framework.MaxNodeScore},
+				{Name: "node-2", Score: 75},
+				{Name: "node-3", Score: framework.MinNodeScore},
+			},
+		},
+		{
+			name: "equal costs",
+			scores: framework.NodeScoreList{
+				{Name: "node-1", Score: 0},
+				{Name: "node-2", Score: 0},
+			},
+			expected: framework.NodeScoreList{
+				{Name: "node-1", Score: framework.MaxNodeScore},
+				{Name: "node-2", Score: framework.MaxNodeScore},
+			},
+		},
+		{
+			name: "unknown costs ranked last",
+			scores: framework.NodeScoreList{
+				{Name: "node-1", Score: unknownCost},
+				{Name: "node-2", Score: 3000},
+			},
+			expected: framework.NodeScoreList{
+				{Name: "node-1", Score: framework.MinNodeScore},
+				{Name: "node-2", Score: framework.MaxNodeScore},
+			},
+		},
+		{
+			name: "all costs unknown",
+			scores: framework.NodeScoreList{
+				{Name: "node-1", Score: unknownCost},
+				{Name: "node-2", Score: unknownCost},
+			},
+			expected: framework.NodeScoreList{
+				{Name: "node-1", Score: framework.MinNodeScore},
+				{Name: "node-2", Score: framework.MinNodeScore},
+			},
+		},
+	}
+	pl := &EnergyCost{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status := pl.NormalizeScore(context.Background(), framework.NewCycleState(), nil, tt.scores)
+			assert.True(t, status.IsSuccess())
+			assert.Equal(t, tt.expected, tt.scores)
+		})
+	}
+}
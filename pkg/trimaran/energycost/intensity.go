@@ -0,0 +1,248 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package energycost
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/klog/v2"
+
+	pluginConfig "sigs.k8s.io/scheduler-plugins/apis/config"
+)
+
+const (
+	// hoursPerDay is the length of the schedule of a zone varying by hour of the day
+	hoursPerDay = 24
+	// httpTimeout bounds a poll of the HTTP source
+	httpTimeout = 10 * time.Second
+)
+
+// IntensitySource provides the intensity signal of the zones, such as the carbon intensity of their grid
+// or their price of electricity, by unit of energy
+type IntensitySource interface {
+	// Intensities returns the intensity of each zone with a signal at the given time
+	Intensities(at time.Time) map[string]float64
+	// Stop stops the updates of the signal
+	Stop()
+}
+
+// NewIntensitySource returns the source of the intensity signal of the spec
+func NewIntensitySource(ctx context.Context, logger klog.Logger, client kubernetes.Interface, spec *pluginConfig.IntensitySourceSpec) (IntensitySource, error) {
+	switch spec.Type {
+	case pluginConfig.ConfigMapIntensitySource:
+		return newConfigMapSource(ctx, logger, client, spec.ConfigMapNamespace, spec.ConfigMapName), nil
+	case pluginConfig.HTTPIntensitySource:
+		return newHTTPSource(logger, spec.Address, time.Duration(spec.RefreshIntervalSeconds)*time.Second), nil
+	default:
+		return nil, fmt.Errorf("unsupported intensity source type %q", spec.Type)
+	}
+}
+
+// configMapSource reads the intensity of each zone from a ConfigMap, whose keys are the zones and whose
+// values are either a single intensity, or 24 comma-separated intensities by hour of the day in UTC
+type configMapSource struct {
+	logger   klog.Logger
+	lister   corelisters.ConfigMapNamespaceLister
+	name     string
+	stopCh   chan struct{}
+	stopOnce sync.Once
+
+	// the schedules parsed from the ConfigMap, as of its resourceVersion
+	lock            sync.Mutex
+	resourceVersion string
+	schedules       map[string][]float64
+}
+
+func newConfigMapSource(ctx context.Context, logger klog.Logger, client kubernetes.Interface, namespace, name string) *configMapSource {
+	informerFactory := informers.NewSharedInformerFactoryWithOptions(client, 0,
+		informers.WithNamespace(namespace),
+		informers.WithTweakListOptions(func(opts *metav1.ListOptions) {
+			opts.FieldSelector = fields.OneTermEqualSelector(metav1.ObjectNameField, name).String()
+		}),
+	)
+	lister := informerFactory.Core().V1().ConfigMaps().Lister().ConfigMaps(namespace)
+
+	s := &configMapSource{
+		logger: logger,
+		lister: lister,
+		name:   name,
+		stopCh: make(chan struct{}),
+	}
+	go func() {
+		select {
+		case <-ctx.Done():
+			s.Stop()
+		case <-s.stopCh:
+		}
+	}()
+	informerFactory.Start(s.stopCh)
+	informerFactory.WaitForCacheSync(s.stopCh)
+	logger.V(4).Info("Watching the intensity ConfigMap", "namespace", namespace, "name", name)
+	return s
+}
+
+func (s *configMapSource) Intensities(at time.Time) map[string]float64 {
+	cm, err := s.lister.Get(s.name)
+	if err != nil {
+		s.logger.V(4).Info("Intensity ConfigMap not available", "name", s.name, "err", err)
+		return nil
+	}
+
+	s.lock.Lock()
+	if cm.ResourceVersion != s.resourceVersion || s.schedules == nil {
+		s.schedules = make(map[string][]float64, len(cm.Data))
+		for zone, value := range cm.Data {
+			schedule, err := parseSchedule(value)
+			if err != nil {
+				s.logger.Error(err, "Invalid intensity schedule, ignored", "zone", zone)
+				continue
+			}
+			s.schedules[zone] = schedule
+		}
+		s.resourceVersion = cm.ResourceVersion
+	}
+	schedules := s.schedules
+	s.lock.Unlock()
+
+	hour := at.UTC().Hour()
+	intensities := make(map[string]float64, len(schedules))
+	for zone, schedule := range schedules {
+		if len(schedule) == hoursPerDay {
+			intensities[zone] = schedule[hour]
+		} else {
+			intensities[zone] = schedule[0]
+		}
+	}
+	return intensities
+}
+
+func (s *configMapSource) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.stopCh)
+	})
+}
+
+// parseSchedule parses either a single intensity, or one intensity by hour of the day
+func parseSchedule(value string) ([]float64, error) {
+	values := strings.Split(value, ",")
+	if len(values) != 1 && len(values) != hoursPerDay {
+		return nil, fmt.Errorf("got %d intensities, expected 1 or %d", len(values), hoursPerDay)
+	}
+	schedule := make([]float64, len(values))
+	for i, v := range values {
+		intensity, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return nil, err
+		}
+		if intensity < 0 || math.IsNaN(intensity) || math.IsInf(intensity, 0) {
+			return nil, fmt.Errorf("invalid intensity %v", intensity)
+		}
+		schedule[i] = intensity
+	}
+	return schedule, nil
+}
+
+// httpSource polls the current intensity of each zone from an endpoint serving a JSON object,
+// whose keys are the zones and whose values are their intensities. The last intensities
+// polled successfully are kept when a poll fails.
+type httpSource struct {
+	logger   klog.Logger
+	client   *http.Client
+	address  string
+	stopCh   chan struct{}
+	stopOnce sync.Once
+
+	lock        sync.RWMutex
+	intensities map[string]float64
+}
+
+func newHTTPSource(logger klog.Logger, address string, refreshInterval time.Duration) *httpSource {
+	s := &httpSource{
+		logger:  logger,
+		client:  &http.Client{Timeout: httpTimeout},
+		address: address,
+		stopCh:  make(chan struct{}),
+	}
+	if err := s.update(); err != nil {
+		logger.Error(err, "Unable to get the intensities initially", "address", address)
+	}
+	go func() {
+		ticker := time.NewTicker(refreshInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-s.stopCh:
+				return
+			case <-ticker.C:
+				if err := s.update(); err != nil {
+					s.logger.Error(err, "Unable to update the intensities", "address", s.address)
+				}
+			}
+		}
+	}()
+	return s
+}
+
+func (s *httpSource) update() error {
+	resp, err := s.client.Get(s.address)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %v", resp.Status)
+	}
+	var intensities map[string]float64
+	if err := json.NewDecoder(resp.Body).Decode(&intensities); err != nil {
+		return err
+	}
+	for zone, intensity := range intensities {
+		if intensity < 0 {
+			s.logger.Error(nil, "Invalid intensity, ignored", "zone", zone, "intensity", intensity)
+			delete(intensities, zone)
+		}
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.intensities = intensities
+	return nil
+}
+
+func (s *httpSource) Intensities(time.Time) map[string]float64 {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.intensities
+}
+
+func (s *httpSource) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.stopCh)
+	})
+}
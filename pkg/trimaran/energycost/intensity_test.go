@@ -0,0 +1,161 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package energycost
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	testClientSet "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/klog/v2"
+
+	pluginConfig "sigs.k8s.io/scheduler-plugins/apis/config"
+)
+
+func hourly(values ...string) string {
+	return strings.Join(values, ",")
+}
+
+func TestParseSchedule(t *testing.T) {
+	day := make([]string, hoursPerDay)
+	expectedDay := make([]float64, hoursPerDay)
+	for i := range day {
+		day[i] = "1.5"
+		expectedDay[i] = 1.5
+	}
+	tests := []struct {
+		name     string
+		value    string
+		expected []float64
+		wantErr  bool
+	}{
+		{
+			name:     "single intensity",
+			value:    " 420.5 ",
+			expected: []float64{420.5},
+		},
+		{
+			name:     "hourly intensities",
+			value:    hourly(day...),
+			expected: expectedDay,
+		},
+		{
+			name:    "wrong number of intensities",
+			value:   "1,2",
+			wantErr: true,
+		},
+		{
+			name:    "negative intensity",
+			value:   "-1",
+			wantErr: true,
+		},
+		{
+			name:    "not a number",
+			value:   "high",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := parseSchedule(tt.value)
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expected, schedule)
+		})
+	}
+}
+
+func TestConfigMapSource(t *testing.T) {
+	day := make([]string, hoursPerDay)
+	for i := range day {
+		day[i] = "100"
+	}
+	day[13] = "50"
+	cm := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "kube-system", Name: "intensity", ResourceVersion: "1"},
+		Data: map[string]string{
+			"zone-a": "200",
+			"zone-b": hourly(day...),
+			"zone-c": "invalid",
+		},
+	}
+	cs := testClientSet.NewSimpleClientset(cm)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	source, err := NewIntensitySource(ctx, klog.Background(), cs, &pluginConfig.IntensitySourceSpec{
+		Type:               pluginConfig.ConfigMapIntensitySource,
+		ConfigMapNamespace: "kube-system",
+		ConfigMapName:      "intensity",
+	})
+	assert.Nil(t, err)
+	defer source.Stop()
+
+	assert.Equal(t, map[string]float64{"zone-a": 200, "zone-b": 50},
+		source.Intensities(time.Date(2025, 1, 1, 13, 30, 0, 0, time.UTC)))
+	assert.Equal(t, map[string]float64{"zone-a": 200, "zone-b": 100},
+		source.Intensities(time.Date(2025, 1, 1, 14, 0, 0, 0, time.UTC)))
+
+	updated := cm.DeepCopy()
+	updated.ResourceVersion = "2"
+	updated.Data = map[string]string{"zone-a": "300"}
+	_, err = cs.CoreV1().ConfigMaps("kube-system").Update(ctx, updated, metav1.UpdateOptions{})
+	assert.Nil(t, err)
+	assert.Eventually(t, func() bool {
+		return assert.ObjectsAreEqual(map[string]float64{"zone-a": 300}, source.Intensities(time.Now()))
+	}, 5*time.Second, 10*time.Millisecond)
+
+	err = cs.CoreV1().ConfigMaps("kube-system").Delete(ctx, "intensity", metav1.DeleteOptions{})
+	assert.Nil(t, err)
+	assert.Eventually(t, func() bool {
+		return source.Intensities(time.Now()) == nil
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestHTTPSource(t *testing.T) {
+	var fail atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		if fail.Load() {
+			resp.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		bytes, err := json.Marshal(map[string]float64{"zone-a": 0.25, "zone-b": -1})
+		assert.Nil(t, err)
+		resp.Write(bytes)
+	}))
+	defer server.Close()
+
+	source := newHTTPSource(klog.Background(), server.URL, time.Hour)
+	defer source.Stop()
+	assert.Equal(t, map[string]float64{"zone-a": 0.25}, source.Intensities(time.Now()), "negative intensity ignored")
+
+	fail.Store(true)
+	assert.NotNil(t, source.update())
+	assert.Equal(t, map[string]float64{"zone-a": 0.25}, source.Intensities(time.Now()), "last intensities kept on failure")
+}
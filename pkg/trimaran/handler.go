@@ -29,6 +29,7 @@ import (
 
 	v1 "k8s.io/api/core/v1"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/informers"
 	clientcache "k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
 	"k8s.io/kubernetes/pkg/scheduler/framework"
@@ -48,6 +49,14 @@ type PodAssignEventHandler struct {
 	// Maintains the node-name to podInfo mapping for pods successfully bound to nodes
 	ScheduledPodsCache map[string][]podInfo
 	sync.RWMutex
+	// informer factory the handler is shared for, nil if the handler is not shared
+	factory informers.SharedInformerFactory
+	// registration of the handler to the pod informer, removed when the handler is stopped
+	informer     clientcache.SharedIndexInformer
+	registration clientcache.ResourceEventHandlerRegistration
+	// closed to stop the cache cleanup
+	stopCh   chan struct{}
+	stopOnce sync.Once
 }
 
 // Stores Timestamp and Pod spec info object
@@ -59,11 +68,20 @@ type podInfo struct {
 
 // New returns a new instance of PodAssignEventHandler, after starting a background go routine for cache cleanup
 func New() *PodAssignEventHandler {
-	p := PodAssignEventHandler{ScheduledPodsCache: make(map[string][]podInfo)}
+	p := PodAssignEventHandler{
+		ScheduledPodsCache: make(map[string][]podInfo),
+		stopCh:             make(chan struct{}),
+	}
 	go func() {
 		cacheCleanerTicker := time.NewTicker(time.Minute * cacheCleanupIntervalMinutes)
-		for range cacheCleanerTicker.C {
-			p.cleanupCache()
+		defer cacheCleanerTicker.Stop()
+		for {
+			select {
+			case <-p.stopCh:
+				return
+			case <-cacheCleanerTicker.C:
+				p.cleanupCache()
+			}
 		}
 	}()
 	return &p
@@ -71,7 +89,8 @@ func New() *PodAssignEventHandler {
 
 // AddToHandle : add event handler to framework handle
 func (p *PodAssignEventHandler) AddToHandle(handle framework.Handle) {
-	handle.SharedInformerFactory().Core().V1().Pods().Informer().AddEventHandler(
+	informer := handle.SharedInformerFactory().Core().V1().Pods().Informer()
+	registration, err := informer.AddEventHandler(
 		clientcache.FilteringResourceEventHandler{
 			FilterFunc: func(obj interface{}) bool {
 				switch t := obj.(type) {
@@ -91,6 +110,24 @@ func (p *PodAssignEventHandler) AddToHandle(handle framework.Handle) {
 			Handler: p,
 		},
 	)
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("unable to add pod event handler: %w", err))
+		return
+	}
+	p.informer = informer
+	p.registration = registration
+}
+
+// stop : stop the cache cleanup, and remove the handler from the pod informer
+func (p *PodAssignEventHandler) stop() {
+	p.stopOnce.Do(func() {
+		close(p.stopCh)
+		if p.registration != nil {
+			if err := p.informer.RemoveEventHandler(p.registration); err != nil {
+				utilruntime.HandleError(fmt.Errorf("unable to remove pod event handler: %w", err))
+			}
+		}
+	})
 }
 
 func (p *PodAssignEventHandler) OnAdd(obj interface{}, _ bool) {
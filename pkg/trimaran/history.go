@@ -0,0 +1,171 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trimaran
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/paypal/load-watcher/pkg/watcher"
+)
+
+// MetricsSample : metrics of a node collected from watcher at a given time
+type MetricsSample struct {
+	// time at which the metrics were collected by watcher
+	Timestamp time.Time
+	// metrics of the node
+	Metrics []watcher.Metric
+}
+
+// GetNodeMetricsHistory : get the retained samples of the metrics of a node, oldest first,
+// within the given window before its latest sample (all retained samples if the window is not positive)
+func (collector *Collector) GetNodeMetricsHistory(nodeName string, window time.Duration) []MetricsSample {
+	collector.mu.RLock()
+	defer collector.mu.RUnlock()
+	samples := collector.history[nodeName]
+	if len(samples) == 0 {
+		return nil
+	}
+	start := 0
+	if window > 0 {
+		since := samples[len(samples)-1].Timestamp.Add(-window)
+		start = sort.Search(len(samples), func(i int) bool {
+			return !samples[i].Timestamp.Before(since)
+		})
+	}
+	return append([]MetricsSample(nil), samples[start:]...)
+}
+
+// GetLatestNodeMetrics : get the latest retained sample of the metrics of a node, nil if none is retained.
+// The timestamp of the sample tells how stale the metrics of the node are.
+func (collector *Collector) GetLatestNodeMetrics(nodeName string) *MetricsSample {
+	return collector.latestSample(nodeName, time.Now())
+}
+
+// latestSample : get the latest retained sample of a node, nil if none is retained at the given time
+func (collector *Collector) latestSample(nodeName string, now time.Time) *MetricsSample {
+	collector.mu.RLock()
+	defer collector.mu.RUnlock()
+	samples := collector.history[nodeName]
+	if len(samples) == 0 {
+		return nil
+	}
+	sample := samples[len(samples)-1]
+	if now.Sub(sample.Timestamp) > collector.retention {
+		return nil
+	}
+	return &sample
+}
+
+// recordHistory : append the metrics of each node to its history and drop the expired samples
+// (the caller must hold the lock)
+func (collector *Collector) recordHistory(metrics *watcher.WatcherMetrics, now time.Time) {
+	if collector.history == nil {
+		collector.history = make(map[string][]MetricsSample)
+	}
+	timestamp := now
+	if metrics.Timestamp > 0 {
+		timestamp = time.Unix(metrics.Timestamp, 0)
+	}
+	for nodeName, nodeMetrics := range metrics.Data.NodeMetricsMap {
+		samples := collector.history[nodeName]
+		// watcher did not refresh the metrics since the last sample
+		if len(samples) > 0 && !timestamp.After(samples[len(samples)-1].Timestamp) {
+			continue
+		}
+		collector.history[nodeName] = append(samples, MetricsSample{
+			Timestamp: timestamp,
+			Metrics:   nodeMetrics.Metrics,
+		})
+	}
+	collector.pruneHistory(now)
+}
+
+// pruneHistory : drop the samples older than the retention, and the oldest samples beyond the maximum number
+// (the caller must hold the lock)
+func (collector *Collector) pruneHistory(now time.Time) {
+	expiry := now.Add(-collector.retention)
+	for nodeName, samples := range collector.history {
+		start := sort.Search(len(samples), func(i int) bool {
+			return samples[i].Timestamp.After(expiry)
+		})
+		if collector.maxSamples > 0 && len(samples)-start > collector.maxSamples {
+			start = len(samples) - collector.maxSamples
+		}
+		if start == len(samples) {
+			delete(collector.history, nodeName)
+			continue
+		}
+		if start > 0 {
+			collector.history[nodeName] = append([]MetricsSample(nil), samples[start:]...)
+		}
+	}
+}
+
+// GetMetricValues : get the values of a metric type and operator from samples, oldest first
+func GetMetricValues(samples []MetricsSample, metricType string, operator string) []float64 {
+	var values []float64
+	for _, sample := range samples {
+		for _, metric := range sample.Metrics {
+			if metric.Type == metricType && metric.Operator == operator {
+				values = append(values, metric.Value)
+				break
+			}
+		}
+	}
+	return values
+}
+
+// Mean : get the mean of values (0 if empty)
+func Mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+// StdDev : get the (population) standard deviation of values (0 if empty)
+func StdDev(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	mu := Mean(values)
+	var sum float64
+	for _, v := range values {
+		sum += (v - mu) * (v - mu)
+	}
+	return math.Sqrt(sum / float64(len(values)))
+}
+
+// Percentile : get the p-th percentile of values, 0 <= p <= 100, interpolating linearly between closest ranks (0 if empty)
+func Percentile(values []float64, p float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	p = math.Max(0, math.Min(100, p))
+	rank := p / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	return sorted[lower] + (rank-float64(lower))*(sorted[upper]-sorted[lower])
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trimaran
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/paypal/load-watcher/pkg/watcher"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/klog/v2"
+
+	pluginConfig "sigs.k8s.io/scheduler-plugins/apis/config"
+)
+
+func cpuMetrics(timestamp time.Time, nodeValues map[string]float64) *watcher.WatcherMetrics {
+	metrics := &watcher.WatcherMetrics{
+		Timestamp: timestamp.Unix(),
+		Data:      watcher.Data{NodeMetricsMap: map[string]watcher.NodeMetrics{}},
+	}
+	for nodeName, value := range nodeValues {
+		metrics.Data.NodeMetricsMap[nodeName] = watcher.NodeMetrics{
+			Metrics: []watcher.Metric{{Type: watcher.CPU, Operator: watcher.Average, Value: value}},
+		}
+	}
+	return metrics
+}
+
+func TestRecordHistory(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	collector := &Collector{retention: 120 * time.Second, maxSamples: 3}
+	for i, value := range []float64{10, 20, 30, 40, 50} {
+		now := start.Add(time.Duration(i) * 30 * time.Second)
+		collector.recordHistory(cpuMetrics(now, map[string]float64{"node-1": value}), now)
+	}
+	// watcher did not refresh its metrics
+	now := start.Add(150 * time.Second)
+	collector.recordHistory(cpuMetrics(start.Add(120*time.Second), map[string]float64{"node-1": 60}), now)
+
+	samples := collector.GetNodeMetricsHistory("node-1", 0)
+	assert.EqualValues(t, []float64{30, 40, 50}, GetMetricValues(samples, watcher.CPU, watcher.Average))
+	assert.Equal(t, start.Add(60*time.Second), samples[0].Timestamp)
+
+	samples = collector.GetNodeMetricsHistory("node-1", 30*time.Second)
+	assert.EqualValues(t, []float64{40, 50}, GetMetricValues(samples, watcher.CPU, watcher.Average))
+	assert.Nil(t, collector.GetNodeMetricsHistory("node-2", 0))
+	assert.Equal(t, start.Add(120*time.Second), collector.latestSample("node-1", now).Timestamp)
+	assert.Nil(t, collector.latestSample("node-2", now))
+
+	// samples expire during an outage of watcher
+	now = start.Add(230 * time.Second)
+	collector.pruneHistory(now)
+	assert.EqualValues(t, []float64{50}, GetMetricValues(collector.GetNodeMetricsHistory("node-1", 0), watcher.CPU, watcher.Average))
+	assert.NotNil(t, collector.latestSample("node-1", now))
+	now = start.Add(300 * time.Second)
+	collector.pruneHistory(now)
+	assert.Nil(t, collector.GetNodeMetricsHistory("node-1", 0))
+	assert.Nil(t, collector.latestSample("node-1", now))
+}
+
+func TestGetNodeMetricsFromHistory(t *testing.T) {
+	timestamp := time.Now()
+	responses := []*watcher.WatcherMetrics{
+		cpuMetrics(timestamp, map[string]float64{"node-1": 80}),
+		cpuMetrics(timestamp.Add(time.Second), map[string]float64{}),
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		bytes, err := json.Marshal(responses[0])
+		assert.Nil(t, err)
+		resp.Write(bytes)
+	}))
+	defer server.Close()
+
+	trimaranSpec := pluginConfig.TrimaranSpec{
+		WatcherAddress:          server.URL,
+		MetricsRetentionSeconds: 60,
+	}
+	logger := klog.FromContext(context.TODO())
+	collector, err := NewCollector(logger, &trimaranSpec)
+	assert.Nil(t, err)
+	assert.Equal(t, 60*time.Second, collector.retention)
+	assert.Equal(t, 3, collector.maxSamples)
+
+	// node-1 is missing from the latest metrics of watcher
+	responses = responses[1:]
+	assert.Nil(t, collector.updateMetrics(logger))
+	metrics, allMetrics := collector.GetNodeMetrics(logger, "node-1")
+	assert.EqualValues(t, []watcher.Metric{{Type: watcher.CPU, Operator: watcher.Average, Value: 80}}, metrics)
+	assert.NotNil(t, allMetrics)
+
+	metrics, allMetrics = collector.GetNodeMetrics(logger, "node-2")
+	assert.Nil(t, metrics)
+	assert.NotNil(t, allMetrics)
+
+	sample := collector.GetLatestNodeMetrics("node-1")
+	assert.NotNil(t, sample)
+	assert.Equal(t, time.Unix(timestamp.Unix(), 0), sample.Timestamp, "latest sample timestamped by watcher")
+	assert.Nil(t, collector.GetLatestNodeMetrics("node-2"))
+}
+
+func TestHistoryStats(t *testing.T) {
+	values := []float64{40, 10, 30, 20}
+	assert.Equal(t, 25.0, Mean(values))
+	assert.InDelta(t, 11.1803, StdDev(values), 1e-4)
+	assert.Equal(t, 10.0, Percentile(values, 0))
+	assert.Equal(t, 25.0, Percentile(values, 50))
+	assert.InDelta(t, 37.0, Percentile(values, 90), 1e-9)
+	assert.Equal(t, 40.0, Percentile(values, 100))
+
+	assert.Equal(t, 0.0, Mean(nil))
+	assert.Equal(t, 0.0, StdDev(nil))
+	assert.Equal(t, 0.0, Percentile(nil, 50))
+}
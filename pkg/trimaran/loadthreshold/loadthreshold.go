@@ -0,0 +1,259 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package loadthreshold provides a K8s scheduler plugin rejecting the nodes whose observed, or predicted, CPU or memory
+utilization exceeds a hard threshold.
+It contains plugin for Filter extension point.
+*/
+
+package loadthreshold
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/paypal/load-watcher/pkg/watcher"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/klog/v2"
+	fwk "k8s.io/kube-scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	pluginConfig "sigs.k8s.io/scheduler-plugins/apis/config"
+	pluginv1 "sigs.k8s.io/scheduler-plugins/apis/config/v1"
+	"sigs.k8s.io/scheduler-plugins/apis/config/validation"
+	"sigs.k8s.io/scheduler-plugins/pkg/trimaran"
+)
+
+const (
+	// Name : name of plugin used in the plugin registry and configurations.
+	Name = "LoadThreshold"
+
+	// ErrReasonMissingMetrics is used when the metrics of the node are missing, with the Reject grace policy
+	ErrReasonMissingMetrics = "node metrics missing"
+	// ErrReasonStaleMetrics is used when the metrics of the node are stale, with the Reject grace policy
+	ErrReasonStaleMetrics = "node metrics stale"
+	// ErrReasonCPUThreshold is used when the CPU utilization of the node exceeds the threshold
+	ErrReasonCPUThreshold = "node CPU utilization above threshold"
+	// ErrReasonMemoryThreshold is used when the memory utilization of the node exceeds the threshold
+	ErrReasonMemoryThreshold = "node memory utilization above threshold"
+)
+
+// LoadThreshold : scheduler plugin
+type LoadThreshold struct {
+	logger    klog.Logger
+	handle    framework.Handle
+	collector *trimaran.Collector
+	// nil unless the predicted utilization is checked
+	eventHandler  *trimaran.PodAssignEventHandler
+	thresholds    []resourceThreshold
+	reject        bool
+	maxMetricsAge time.Duration
+	// predicts the utilization of the pods like TargetLoadPacking, with its default requests and multiplier
+	predictor trimaran.Predictor
+}
+
+// resourceThreshold is the utilization percent of a resource above which nodes are rejected
+type resourceThreshold struct {
+	name       v1.ResourceName
+	metricType string
+	threshold  float64
+	reason     string
+}
+
+var _ framework.FilterPlugin = &LoadThreshold{}
+var _ framework.EnqueueExtensions = &LoadThreshold{}
+var _ io.Closer = &LoadThreshold{}
+
+// New : create an instance of a LoadThreshold plugin
+func New(ctx context.Context, obj runtime.Object, handle framework.Handle) (framework.Plugin, error) {
+	logger := klog.FromContext(ctx).WithValues("plugin", Name)
+	logger.V(4).Info("Creating new instance of the LoadThreshold plugin")
+
+	args, ok := obj.(*pluginConfig.LoadThresholdArgs)
+	if !ok {
+		return nil, fmt.Errorf("want args to be of type LoadThresholdArgs, got %T", obj)
+	}
+	if err := validation.ValidateLoadThresholdArgs(args, nil); err != nil {
+		return nil, err
+	}
+
+	var thresholds []resourceThreshold
+	if args.CPUThreshold > 0 {
+		thresholds = append(thresholds, resourceThreshold{
+			name: v1.ResourceCPU, metricType: watcher.CPU, threshold: float64(args.CPUThreshold), reason: ErrReasonCPUThreshold,
+		})
+	}
+	if args.MemoryThreshold > 0 {
+		thresholds = append(thresholds, resourceThreshold{
+			name: v1.ResourceMemory, metricType: watcher.Memory, threshold: float64(args.MemoryThreshold), reason: ErrReasonMemoryThreshold,
+		})
+	}
+
+	collector, err := trimaran.AcquireCollector(logger, &args.TrimaranSpec)
+	if err != nil {
+		return nil, err
+	}
+	pl := &LoadThreshold{
+		logger:        logger,
+		handle:        handle,
+		collector:     collector,
+		thresholds:    thresholds,
+		reject:        args.MetricsGracePolicy == pluginConfig.RejectMetricsGracePolicy,
+		maxMetricsAge: time.Duration(args.MaxMetricsAgeSeconds) * time.Second,
+	}
+	if args.Utilization == pluginConfig.PredictedUtilization {
+		pl.eventHandler = trimaran.AcquirePodAssignEventHandler(handle)
+		requestsMultiplier, _ := strconv.ParseFloat(pluginv1.DefaultRequestsMultiplier, 64)
+		pl.predictor = trimaran.Predictor{
+			RequestsMultiplier: requestsMultiplier,
+			DefaultRequests: v1.ResourceList{
+				v1.ResourceCPU: *resource.NewMilliQuantity(pluginv1.DefaultRequestsMilliCores, resource.DecimalSI),
+			},
+		}
+	}
+
+	logger.V(4).Info("Using LoadThresholdArgs",
+		"cpuThreshold", args.CPUThreshold,
+		"memoryThreshold", args.MemoryThreshold,
+		"utilization", args.Utilization,
+		"metricsGracePolicy", args.MetricsGracePolicy,
+		"maxMetricsAge", pl.maxMetricsAge)
+	return pl, nil
+}
+
+// Name : name of plugin
+func (pl *LoadThreshold) Name() string {
+	return Name
+}
+
+// Close releases the collector and the event handler, shared with the other Trimaran plugins
+func (pl *LoadThreshold) Close() error {
+	pl.collector.Release()
+	if pl.eventHandler != nil {
+		pl.eventHandler.Release()
+	}
+	return nil
+}
+
+// EventsToRegister returns the events that may make a pod rejected by this plugin schedulable.
+// The metrics of the nodes are not cluster events: pods rejected by a node utilization going down
+// are retried when the scheduler flushes its unschedulable pods.
+func (pl *LoadThreshold) EventsToRegister(_ context.Context) ([]fwk.ClusterEventWithHint, error) {
+	return []fwk.ClusterEventWithHint{
+		{Event: fwk.ClusterEvent{Resource: fwk.Pod, ActionType: fwk.Delete}},
+		{Event: fwk.ClusterEvent{Resource: fwk.Node, ActionType: fwk.Add}},
+	}, nil
+}
+
+// Filter : reject the node if its utilization of a resource, observed or predicted once the pod runs on it,
+// exceeds the threshold of the resource. Nodes with missing or stale metrics are let through, or rejected,
+// according to the grace policy.
+func (pl *LoadThreshold) Filter(ctx context.Context, cycleState fwk.CycleState, pod *v1.Pod, nodeInfo fwk.NodeInfo) *fwk.Status {
+	if len(pl.thresholds) == 0 {
+		return nil
+	}
+	node := nodeInfo.Node()
+	if node == nil {
+		return fwk.NewStatus(fwk.Error, "node not found")
+	}
+	logger := klog.FromContext(klog.NewContext(ctx, pl.logger)).WithValues("ExtensionPoint", "Filter")
+
+	sample := pl.collector.GetLatestNodeMetrics(node.Name)
+	if sample == nil {
+		return pl.grace(logger, node.Name, ErrReasonMissingMetrics)
+	}
+	if age := time.Since(sample.Timestamp); age > pl.maxMetricsAge {
+		logger.V(6).Info("Stale metrics for node", "nodeName", node.Name, "age", age)
+		return pl.grace(logger, node.Name, ErrReasonStaleMetrics)
+	}
+
+	var extra *framework.Resource
+	if pl.eventHandler != nil {
+		extra = pl.missingRequests(node.Name, sample.Timestamp)
+		pl.addPrediction(extra, pod)
+	}
+	for _, t := range pl.thresholds {
+		utilPercent, _, ok := trimaran.GetResourceData(sample.Metrics, t.metricType)
+		if !ok {
+			// the other resources are still checked when the node is let through
+			if status := pl.grace(logger, node.Name, ErrReasonMissingMetrics); status != nil {
+				return status
+			}
+			continue
+		}
+		if extra != nil {
+			utilPercent += predictedPercent(node, extra, t.name)
+		}
+		if utilPercent > t.threshold {
+			logger.V(5).Info("Filter: ", "pod", pod.Name, "node", node.Name, "resource", t.name,
+				"utilization", utilPercent, "threshold", t.threshold)
+			return fwk.NewStatus(fwk.UnschedulableAndUnresolvable, t.reason)
+		}
+	}
+	return nil
+}
+
+// grace applies the grace policy to a node with missing or stale metrics
+func (pl *LoadThreshold) grace(logger klog.Logger, nodeName, reason string) *fwk.Status {
+	logger.V(5).Info("Applying the metrics grace policy", "nodeName", nodeName, "reason", reason, "reject", pl.reject)
+	if pl.reject {
+		return fwk.NewStatus(fwk.UnschedulableAndUnresolvable, reason)
+	}
+	return nil
+}
+
+// missingRequests sums the predicted utilization of the pods assigned to the node since its metrics, not reported in them yet
+func (pl *LoadThreshold) missingRequests(nodeName string, since time.Time) *framework.Resource {
+	result := &framework.Resource{}
+	pl.eventHandler.RLock()
+	defer pl.eventHandler.RUnlock()
+	for _, info := range pl.eventHandler.ScheduledPodsCache[nodeName] {
+		if info.Timestamp.After(since) {
+			pl.addPrediction(result, info.Pod)
+		}
+	}
+	return result
+}
+
+// addPrediction adds the predicted CPU and memory utilization of the pod to the result: its limits, or its requests
+// times the default requests multiplier, or the default requests
+func (pl *LoadThreshold) addPrediction(result *framework.Resource, pod *v1.Pod) {
+	result.MilliCPU += pl.predictor.PredictPodUtilisation(pod, v1.ResourceCPU)
+	result.Memory += pl.predictor.PredictPodUtilisation(pod, v1.ResourceMemory)
+}
+
+// predictedPercent returns the utilization percent of a resource of the node the predicted utilization amounts to
+func predictedPercent(node *v1.Node, requests *framework.Resource, resourceName v1.ResourceName) float64 {
+	var requested, capacity float64
+	switch resourceName {
+	case v1.ResourceCPU:
+		requested = float64(requests.MilliCPU)
+		capacity = float64(node.Status.Capacity.Cpu().MilliValue())
+	case v1.ResourceMemory:
+		requested = float64(requests.Memory)
+		capacity = float64(node.Status.Capacity.Memory().Value())
+	}
+	if capacity == 0 {
+		return 0
+	}
+	return 100 * requested / capacity
+}
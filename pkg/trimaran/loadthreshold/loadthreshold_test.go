@@ -0,0 +1,279 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadthreshold
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/paypal/load-watcher/pkg/watcher"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/informers"
+	testClientSet "k8s.io/client-go/kubernetes/fake"
+	fwk "k8s.io/kube-scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/defaultbinder"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/queuesort"
+	"k8s.io/kubernetes/pkg/scheduler/framework/runtime"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+	tf "k8s.io/kubernetes/pkg/scheduler/testing/framework"
+
+	pluginConfig "sigs.k8s.io/scheduler-plugins/apis/config"
+	testutil "sigs.k8s.io/scheduler-plugins/test/util"
+)
+
+const nodeName = "node-1"
+
+func nodeMetrics(timestamp time.Time, metrics ...watcher.Metric) watcher.WatcherMetrics {
+	return watcher.WatcherMetrics{
+		Timestamp: timestamp.Unix(),
+		Data: watcher.Data{
+			NodeMetricsMap: map[string]watcher.NodeMetrics{
+				nodeName: {Metrics: metrics},
+			},
+		},
+	}
+}
+
+func cpu(value float64) watcher.Metric {
+	return watcher.Metric{Type: watcher.CPU, Operator: watcher.Average, Value: value}
+}
+
+func memory(value float64) watcher.Metric {
+	return watcher.Metric{Type: watcher.Memory, Operator: watcher.Average, Value: value}
+}
+
+func newPlugin(t *testing.T, ctx context.Context, watcherResponse watcher.WatcherMetrics, args pluginConfig.LoadThresholdArgs) (*LoadThreshold, func()) {
+	server := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		bytes, err := json.Marshal(watcherResponse)
+		assert.Nil(t, err)
+		resp.Write(bytes)
+	}))
+
+	registeredPlugins := []tf.RegisterPluginFunc{
+		tf.RegisterBindPlugin(defaultbinder.Name, defaultbinder.New),
+		tf.RegisterQueueSortPlugin(queuesort.Name, queuesort.New),
+	}
+	cs := testClientSet.NewSimpleClientset()
+	informerFactory := informers.NewSharedInformerFactory(cs, 0)
+	fh, err := testutil.NewFramework(ctx, registeredPlugins, nil, "default-scheduler",
+		runtime.WithClientSet(cs), runtime.WithInformerFactory(informerFactory),
+		runtime.WithSnapshotSharedLister(testutil.NewFakeSharedLister(nil, nil)))
+	assert.Nil(t, err)
+
+	args.WatcherAddress = server.URL
+	p, err := New(ctx, &args, fh)
+	assert.Nil(t, err)
+	pl := p.(*LoadThreshold)
+	return pl, func() {
+		pl.Close()
+		server.Close()
+	}
+}
+
+func TestLoadThresholdFilter(t *testing.T) {
+	now := time.Now()
+	defaultArgs := pluginConfig.LoadThresholdArgs{
+		CPUThreshold:         90,
+		MemoryThreshold:      90,
+		Utilization:          pluginConfig.ObservedUtilization,
+		MetricsGracePolicy:   pluginConfig.AllowMetricsGracePolicy,
+		MaxMetricsAgeSeconds: 300,
+	}
+	withArgs := func(update func(args *pluginConfig.LoadThresholdArgs)) pluginConfig.LoadThresholdArgs {
+		args := defaultArgs
+		update(&args)
+		return args
+	}
+	pod := st.MakePod().Name("p").Req(map[v1.ResourceName]string{
+		v1.ResourceCPU:    "500m",
+		v1.ResourceMemory: "256Mi",
+	}).Obj()
+	node := st.MakeNode().Name(nodeName).Capacity(map[v1.ResourceName]string{
+		v1.ResourceCPU:    "1000m",
+		v1.ResourceMemory: "1Gi",
+	}).Obj()
+
+	tests := []struct {
+		name            string
+		watcherResponse watcher.WatcherMetrics
+		args            pluginConfig.LoadThresholdArgs
+		expected        *fwk.Status
+	}{
+		{
+			name:            "utilization below thresholds",
+			watcherResponse: nodeMetrics(now, cpu(60), memory(60)),
+			args:            defaultArgs,
+		},
+		{
+			name:            "CPU utilization above threshold",
+			watcherResponse: nodeMetrics(now, cpu(98), memory(60)),
+			args:            defaultArgs,
+			expected:        fwk.NewStatus(fwk.UnschedulableAndUnresolvable, ErrReasonCPUThreshold),
+		},
+		{
+			name:            "memory utilization above threshold",
+			watcherResponse: nodeMetrics(now, cpu(60), memory(95)),
+			args:            defaultArgs,
+			expected:        fwk.NewStatus(fwk.UnschedulableAndUnresolvable, ErrReasonMemoryThreshold),
+		},
+		{
+			name:            "memory utilization not checked",
+			watcherResponse: nodeMetrics(now, cpu(60), memory(95)),
+			args:            withArgs(func(args *pluginConfig.LoadThresholdArgs) { args.MemoryThreshold = 0 }),
+		},
+		{
+			name:            "predicted CPU utilization above threshold",
+			watcherResponse: nodeMetrics(now, cpu(60), memory(10)),
+			args:            withArgs(func(args *pluginConfig.LoadThresholdArgs) { args.Utilization = pluginConfig.PredictedUtilization }),
+			expected:        fwk.NewStatus(fwk.UnschedulableAndUnresolvable, ErrReasonCPUThreshold),
+		},
+		{
+			name:            "predicted utilization below thresholds",
+			watcherResponse: nodeMetrics(now, cpu(10), memory(40)),
+			args:            withArgs(func(args *pluginConfig.LoadThresholdArgs) { args.Utilization = pluginConfig.PredictedUtilization }),
+		},
+		{
+			name:            "missing metrics allowed",
+			watcherResponse: watcher.WatcherMetrics{Timestamp: now.Unix(), Data: watcher.Data{NodeMetricsMap: map[string]watcher.NodeMetrics{}}},
+			args:            defaultArgs,
+		},
+		{
+			name:            "missing metrics rejected",
+			watcherResponse: watcher.WatcherMetrics{Timestamp: now.Unix(), Data: watcher.Data{NodeMetricsMap: map[string]watcher.NodeMetrics{}}},
+			args: withArgs(func(args *pluginConfig.LoadThresholdArgs) {
+				args.MetricsGracePolicy = pluginConfig.RejectMetricsGracePolicy
+			}),
+			expected: fwk.NewStatus(fwk.UnschedulableAndUnresolvable, ErrReasonMissingMetrics),
+		},
+		{
+			name:            "stale metrics allowed",
+			watcherResponse: nodeMetrics(now.Add(-10*time.Minute), cpu(98), memory(98)),
+			args:            defaultArgs,
+		},
+		{
+			name:            "stale metrics rejected",
+			watcherResponse: nodeMetrics(now.Add(-10*time.Minute), cpu(10), memory(10)),
+			args: withArgs(func(args *pluginConfig.LoadThresholdArgs) {
+				args.MetricsGracePolicy = pluginConfig.RejectMetricsGracePolicy
+			}),
+			expected: fwk.NewStatus(fwk.UnschedulableAndUnresolvable, ErrReasonStaleMetrics),
+		},
+		{
+			name:            "missing CPU metric allowed, memory still checked",
+			watcherResponse: nodeMetrics(now, memory(95)),
+			args:            defaultArgs,
+			expected:        fwk.NewStatus(fwk.UnschedulableAndUnresolvable, ErrReasonMemoryThreshold),
+		},
+		{
+			name:            "missing memory metric rejected",
+			watcherResponse: nodeMetrics(now, cpu(10)),
+			args: withArgs(func(args *pluginConfig.LoadThresholdArgs) {
+				args.MetricsGracePolicy = pluginConfig.RejectMetricsGracePolicy
+			}),
+			expected: fwk.NewStatus(fwk.UnschedulableAndUnresolvable, ErrReasonMissingMetrics),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			pl, closePlugin := newPlugin(t, ctx, tt.watcherResponse, tt.args)
+			defer closePlugin()
+
+			nodeInfo := framework.NewNodeInfo()
+			nodeInfo.SetNode(node)
+			status := pl.Filter(ctx, framework.NewCycleState(), pod, nodeInfo)
+			assert.Equal(t, tt.expected, status)
+		})
+	}
+}
+
+func TestLoadThresholdFilterAssignedPods(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	pl, closePlugin := newPlugin(t, ctx, nodeMetrics(time.Now(), cpu(40), memory(10)), pluginConfig.LoadThresholdArgs{
+		CPUThreshold:         90,
+		Utilization:          pluginConfig.PredictedUtilization,
+		MetricsGracePolicy:   pluginConfig.AllowMetricsGracePolicy,
+		MaxMetricsAgeSeconds: 300,
+	})
+	defer closePlugin()
+
+	pod := st.MakePod().Name("p").Req(map[v1.ResourceName]string{v1.ResourceCPU: "300m"}).Obj()
+	nodeInfo := framework.NewNodeInfo()
+	nodeInfo.SetNode(st.MakeNode().Name(nodeName).Capacity(map[v1.ResourceName]string{v1.ResourceCPU: "1000m"}).Obj())
+	assert.Nil(t, pl.Filter(ctx, framework.NewCycleState(), pod, nodeInfo))
+
+	// the pods assigned since the metrics are not reported in them yet
+	assigned := st.MakePod().Name("assigned").Node(nodeName).Req(map[v1.ResourceName]string{v1.ResourceCPU: "300m"}).Obj()
+	pl.eventHandler.OnAdd(assigned, false)
+	assert.Equal(t, fwk.NewStatus(fwk.UnschedulableAndUnresolvable, ErrReasonCPUThreshold),
+		pl.Filter(ctx, framework.NewCycleState(), pod, nodeInfo))
+}
+
+func TestLoadThresholdFilterPrediction(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	pl, closePlugin := newPlugin(t, ctx, nodeMetrics(time.Now(), cpu(20), memory(10)), pluginConfig.LoadThresholdArgs{
+		CPUThreshold:         90,
+		Utilization:          pluginConfig.PredictedUtilization,
+		MetricsGracePolicy:   pluginConfig.AllowMetricsGracePolicy,
+		MaxMetricsAgeSeconds: 300,
+	})
+	defer closePlugin()
+
+	nodeInfo := framework.NewNodeInfo()
+	nodeInfo.SetNode(st.MakeNode().Name(nodeName).Capacity(map[v1.ResourceName]string{v1.ResourceCPU: "2000m"}).Obj())
+
+	tests := []struct {
+		name     string
+		pod      *v1.Pod
+		expected *fwk.Status
+	}{
+		{
+			name: "requests times the multiplier",
+			pod:  st.MakePod().Name("p").Req(map[v1.ResourceName]string{v1.ResourceCPU: "800m"}).Obj(),
+		},
+		{
+			name:     "requests times the multiplier above threshold",
+			pod:      st.MakePod().Name("p").Req(map[v1.ResourceName]string{v1.ResourceCPU: "1200m"}).Obj(),
+			expected: fwk.NewStatus(fwk.UnschedulableAndUnresolvable, ErrReasonCPUThreshold),
+		},
+		{
+			name: "limits rather than requests",
+			pod: st.MakePod().Name("p").Containers([]v1.Container{st.MakeContainer().
+				ResourceRequests(map[v1.ResourceName]string{v1.ResourceCPU: "200m"}).
+				ResourceLimits(map[v1.ResourceName]string{v1.ResourceCPU: "1500m"}).Obj()}).Obj(),
+			expected: fwk.NewStatus(fwk.UnschedulableAndUnresolvable, ErrReasonCPUThreshold),
+		},
+		{
+			name: "default requests for best effort pods",
+			pod:  st.MakePod().Name("p").Container("c").Obj(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, pl.Filter(ctx, framework.NewCycleState(), tt.pod, nodeInfo))
+		})
+	}
+}
@@ -23,6 +23,7 @@ package loadvariationriskbalancing
 import (
 	"context"
 	"fmt"
+	"io"
 	"math"
 
 	"github.com/paypal/load-watcher/pkg/watcher"
@@ -52,6 +53,7 @@ type LoadVariationRiskBalancing struct {
 }
 
 var _ framework.ScorePlugin = &LoadVariationRiskBalancing{}
+var _ io.Closer = &LoadVariationRiskBalancing{}
 
 // New : create an instance of a LoadVariationRiskBalancing plugin
 func New(ctx context.Context, obj runtime.Object, handle framework.Handle) (framework.Plugin, error) {
@@ -62,19 +64,16 @@ func New(ctx context.Context, obj runtime.Object, handle framework.Handle) (fram
 	if !ok {
 		return nil, fmt.Errorf("want args to be of type LoadVariationRiskBalancingArgs, got %T", obj)
 	}
-	collector, err := trimaran.NewCollector(logger, &args.TrimaranSpec)
+	collector, err := trimaran.AcquireCollector(logger, &args.TrimaranSpec)
 	if err != nil {
 		return nil, err
 	}
 	logger.V(4).Info("Using LoadVariationRiskBalancingArgs", "margin", args.SafeVarianceMargin, "sensitivity", args.SafeVarianceSensitivity)
 
-	podAssignEventHandler := trimaran.New()
-	podAssignEventHandler.AddToHandle(handle)
-
 	pl := &LoadVariationRiskBalancing{
 		logger:       logger,
 		handle:       handle,
-		eventHandler: podAssignEventHandler,
+		eventHandler: trimaran.AcquirePodAssignEventHandler(handle),
 		collector:    collector,
 		args:         args,
 	}
@@ -127,6 +126,13 @@ func (pl *LoadVariationRiskBalancing) Name() string {
 	return Name
 }
 
+// Close : release the collector and the event handler, shared with the other Trimaran plugins
+func (pl *LoadVariationRiskBalancing) Close() error {
+	pl.collector.Release()
+	pl.eventHandler.Release()
+	return nil
+}
+
 // ScoreExtensions : an interface for Score extended functionality
 func (pl *LoadVariationRiskBalancing) ScoreExtensions() framework.ScoreExtensions {
 	return pl
@@ -24,6 +24,7 @@ package lowriskovercommitment
 import (
 	"context"
 	"fmt"
+	"io"
 	"math"
 
 	"github.com/paypal/load-watcher/pkg/watcher"
@@ -50,6 +51,8 @@ const (
 	PodResourcesKey = Name + ".PodResources"
 )
 
+var _ io.Closer = &LowRiskOverCommitment{}
+
 // LowRiskOverCommitment : scheduler plugin
 type LowRiskOverCommitment struct {
 	logger              klog.Logger
@@ -68,7 +71,7 @@ func New(ctx context.Context, obj runtime.Object, handle framework.Handle) (fram
 	if !ok {
 		return nil, fmt.Errorf("want args to be of type LowRiskOverCommitmentArgs, got %T", obj)
 	}
-	collector, err := trimaran.NewCollector(logger, &args.TrimaranSpec)
+	collector, err := trimaran.AcquireCollector(logger, &args.TrimaranSpec)
 	if err != nil {
 		return nil, err
 	}
@@ -144,6 +147,12 @@ func (pl *LowRiskOverCommitment) Name() string {
 	return Name
 }
 
+// Close : release the collector, shared with the other Trimaran plugins
+func (pl *LowRiskOverCommitment) Close() error {
+	pl.collector.Release()
+	return nil
+}
+
 // ScoreExtensions : an interface for Score extended functionality
 func (pl *LowRiskOverCommitment) ScoreExtensions() framework.ScoreExtensions {
 	return pl
@@ -25,6 +25,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"math"
 	"os"
 	"strings"
@@ -49,29 +50,44 @@ type Peaks struct {
 	handle    framework.Handle
 	collector *trimaran.Collector
 	args      *config.PeaksArgs
+	// powerModels resolves the power model of the nodes
+	powerModels *PowerModels
 }
 
 var _ framework.ScorePlugin = &Peaks{}
+var _ io.Closer = &Peaks{}
 
 func (pl *Peaks) Name() string {
 	return Name
 }
 
-func initNodePowerModels(powerModel map[string]config.PowerModel) error {
-	fmt.Printf("args power model : %+v\n", powerModel)
+// Close releases the collector and the PowerModel informer, shared with the other Trimaran plugins
+func (pl *Peaks) Close() error {
+	pl.collector.Release()
+	pl.powerModels.Release()
+	return nil
+}
+
+// initNodePowerModels returns the power models keyed by node name: the ones of the args if any,
+// otherwise the ones of the JSON file at the NODE_POWER_MODEL path, if set
+func initNodePowerModels(powerModel map[string]config.PowerModel) (map[string]config.PowerModel, error) {
 	if len(powerModel) > 0 {
-		return nil
+		return powerModel, nil
+	}
+	path := os.Getenv("NODE_POWER_MODEL")
+	if path == "" {
+		return nil, nil
 	}
-	data, err := os.ReadFile(os.Getenv("NODE_POWER_MODEL"))
+	data, err := os.ReadFile(path)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	decoder := json.NewDecoder(strings.NewReader(string(data)))
 	decoder.DisallowUnknownFields()
 	if err = decoder.Decode(&powerModel); err != nil {
-		return err
+		return nil, err
 	}
-	return nil
+	return powerModel, nil
 }
 
 func New(ctx context.Context, obj runtime.Object, handle framework.Handle) (framework.Plugin, error) {
@@ -82,21 +98,23 @@ func New(ctx context.Context, obj runtime.Object, handle framework.Handle) (fram
 	if !ok {
 		return nil, fmt.Errorf("want args to be of type PeaksArgs, got %T", obj)
 	}
-	collector, err := trimaran.NewCollector(logger, &config.TrimaranSpec{WatcherAddress: args.WatcherAddress})
+	collector, err := trimaran.AcquireCollector(logger, &config.TrimaranSpec{WatcherAddress: args.WatcherAddress})
 	if err != nil {
 		return nil, err
 	}
 
-	err = initNodePowerModels(args.NodePowerModel)
+	powerModels, err := NewPowerModels(ctx, logger, handle, args.NodePowerModel)
 	if err != nil {
 		logger.Error(err, "Unable to create power model from the input configuration")
+		collector.Release()
 		return nil, err
 	}
 	pl := &Peaks{
-		logger:    logger,
-		handle:    handle,
-		collector: collector,
-		args:      args,
+		logger:      logger,
+		handle:      handle,
+		collector:   collector,
+		args:        args,
+		powerModels: powerModels,
 	}
 	return pl, nil
 }
@@ -112,6 +130,23 @@ func (pl *Peaks) Score(ctx context.Context, cycleState fwk.CycleState, pod *v1.P
 		return score, nil
 	}
 
+	nodeCPUUtilPercent, predictedCPUUsage, cpuMetricFound := PredictCPUUtilisation(pod, nodeInfo.Node(), metrics)
+	if !cpuMetricFound {
+		logger.Error(nil, "Cpu metric not found in node metrics for nodeName", nodeName)
+		return score, nil
+	}
+	if predictedCPUUsage > 100 {
+		return score, fwk.NewStatus(fwk.Success, "")
+	} else {
+		logger.V(4).Info("Node :", nodeName, ", Node cpu usage current :", nodeCPUUtilPercent, ", predicted :", predictedCPUUsage)
+		jumpInPower := PowerJump(nodeCPUUtilPercent, predictedCPUUsage, pl.powerModels.Get(nodeInfo.Node()))
+		return int64(jumpInPower * math.Pow(10, 15)), fwk.NewStatus(fwk.Success, "")
+	}
+}
+
+// PredictCPUUtilisation returns the current CPU utilisation percent of the node, from its metrics, and the one
+// predicted once the pod runs on it. It returns false if the metrics have no CPU utilisation.
+func PredictCPUUtilisation(pod *v1.Pod, node *v1.Node, metrics []watcher.Metric) (float64, float64, bool) {
 	quantity := resource.GetResourceRequestQuantity(pod, v1.ResourceCPU)
 	curPodCPUUsage := quantity.MilliValue()
 
@@ -127,23 +162,16 @@ func (pl *Peaks) Score(ctx context.Context, cycleState fwk.CycleState, pod *v1.P
 		}
 	}
 	if !cpuMetricFound {
-		logger.Error(nil, "Cpu metric not found in node metrics for nodeName", nodeName)
-		return score, nil
+		return 0, 0, false
 	}
-	nodeCPUCapMillis := float64(nodeInfo.Node().Status.Capacity.Cpu().MilliValue())
+	nodeCPUCapMillis := float64(node.Status.Capacity.Cpu().MilliValue())
 	nodeCPUUtilMillis := (nodeCPUUtilPercent / 100) * nodeCPUCapMillis
 
 	var predictedCPUUsage float64
 	if nodeCPUCapMillis != 0 {
 		predictedCPUUsage = 100 * (nodeCPUUtilMillis + float64(curPodCPUUsage)) / nodeCPUCapMillis
 	}
-	if predictedCPUUsage > 100 {
-		return score, fwk.NewStatus(fwk.Success, "")
-	} else {
-		logger.V(4).Info("Node :", nodeName, ", Node cpu usage current :", nodeCPUUtilPercent, ", predicted :", predictedCPUUsage)
-		jumpInPower := getPowerJumpForUtilisation(nodeCPUUtilPercent, predictedCPUUsage, getPowerModel(nodeName, pl.args.NodePowerModel))
-		return int64(jumpInPower * math.Pow(10, 15)), fwk.NewStatus(fwk.Success, "")
-	}
+	return nodeCPUUtilPercent, predictedCPUUsage, true
 }
 
 func (pl *Peaks) ScoreExtensions() framework.ScoreExtensions {
@@ -184,7 +212,9 @@ func getMinMaxScores(scores framework.NodeScoreList) (int64, int64) {
 	return min, max
 }
 
-func getPowerJumpForUtilisation(x, p float64, m config.PowerModel) float64 {
+// PowerJump returns the increase of the power drawn by a node of power model m, when its
+// CPU utilisation goes from x to p percent
+func PowerJump(x, p float64, m config.PowerModel) float64 {
 	return m.K1 * (math.Exp(m.K2*p) - math.Exp(m.K2*x))
 }
 
@@ -193,5 +223,5 @@ func getPowerModel(nodeName string, powerModelMap map[string]config.PowerModel)
 	if ok {
 		return powerModel
 	}
-	return config.PowerModel{0, 0, 0}
+	return config.PowerModel{}
 }
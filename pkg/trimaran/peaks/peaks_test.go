@@ -162,7 +162,7 @@ func TestPeaksNew(t *testing.T) {
 		"node-1": 10,
 	}
 	testutil.CreateErroredPowerModel(t, errData)
-	err = initNodePowerModels(map[string]pluginConfig.PowerModel{})
+	_, err = initNodePowerModels(map[string]pluginConfig.PowerModel{})
 	assert.NotNil(t, err)
 	assert.EqualError(t, err, "json: cannot unmarshal number into Go value of type config.PowerModel")
 	os.Setenv("NODE_POWER_MODEL", envVarNodePowerModel)
@@ -231,11 +231,11 @@ func TestPeaksScore(t *testing.T) {
 		},
 	})
 
-	err := initNodePowerModels(peaksArgs.NodePowerModel)
+	_, err := initNodePowerModels(peaksArgs.NodePowerModel)
 	if err != nil {
 		assert.Nil(t, err)
 	}
-	jumpInPower := getPowerJumpForUtilisation(0, 100, getPowerModel("node-1", peaksArgs.NodePowerModel))
+	jumpInPower := PowerJump(0, 100, getPowerModel("node-1", peaksArgs.NodePowerModel))
 	t.Logf("node-1 power model %+v", getPowerModel("node-1", peaksArgs.NodePowerModel))
 	scoreToUse := int64(jumpInPower * math.Pow(10, 15))
 
@@ -0,0 +1,305 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package peaks
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	ctrlcache "sigs.k8s.io/controller-runtime/pkg/cache"
+
+	"sigs.k8s.io/scheduler-plugins/apis/config"
+	"sigs.k8s.io/scheduler-plugins/apis/scheduling/v1alpha1"
+	"sigs.k8s.io/scheduler-plugins/pkg/util"
+)
+
+const (
+	// PowerModelK0Key, PowerModelK1Key and PowerModelK2Key are the annotations, or labels, setting the
+	// coefficients of the power model of a node. Label values cannot start with a minus sign, so negative
+	// coefficients must be set as annotations.
+	PowerModelK0Key = "peaks.scheduling.x-k8s.io/power-model-k0"
+	PowerModelK1Key = "peaks.scheduling.x-k8s.io/power-model-k1"
+	PowerModelK2Key = "peaks.scheduling.x-k8s.io/power-model-k2"
+)
+
+// crdPowerModel is a parsed PowerModel object
+type crdPowerModel struct {
+	name          string
+	instanceTypes sets.Set[string]
+	// selector is nil if the object has no node selector
+	selector labels.Selector
+	model    config.PowerModel
+}
+
+// PowerModels resolves the power model of the nodes, in order from:
+//   - the models keyed by node name, of the plugin args or of the NODE_POWER_MODEL file
+//   - the annotations of the node, then its labels
+//   - the PowerModel objects matching the instance type of the node, then the ones selecting it, by name
+//
+// Nodes matching none get the zero model. The PowerModel objects are kept up to date by an informer,
+// and the node metadata is read on every lookup, so nodes joining the cluster get their model right away.
+type PowerModels struct {
+	logger klog.Logger
+	byName map[string]config.PowerModel
+	crds   *powerModelInformer
+	// kubeConfig the informer is shared for, nil if the informer is not shared
+	kubeConfig *rest.Config
+}
+
+// powerModelInformer keeps the PowerModel objects up to date
+type powerModelInformer struct {
+	logger klog.Logger
+
+	lock sync.RWMutex
+	// crds are sorted by name
+	crds []*crdPowerModel
+
+	// refs and cancel are guarded by powerModelInformersLock
+	refs   int
+	cancel context.CancelFunc
+}
+
+var (
+	powerModelInformersLock sync.Mutex
+	// powerModelInformers are shared by the Peaks and EnergyCost plugins, of any profile, using the same kubeconfig
+	powerModelInformers = make(map[*rest.Config]*powerModelInformer)
+)
+
+// NewPowerModels returns the power models of the nodes, keyed by node name in nodePowerModel or
+// in the JSON file at the NODE_POWER_MODEL path, and watches the PowerModel objects.
+// Release must be called once the power models are not used anymore.
+func NewPowerModels(ctx context.Context, logger klog.Logger, handle framework.Handle, nodePowerModel map[string]config.PowerModel) (*PowerModels, error) {
+	byName, err := initNodePowerModels(nodePowerModel)
+	if err != nil {
+		return nil, err
+	}
+	pm := newPowerModels(logger, byName)
+	kubeConfig := handle.KubeConfig()
+	if kubeConfig == nil {
+		logger.V(4).Info("No kubeconfig, PowerModels are not watched")
+		return pm, nil
+	}
+	pm.acquireInformer(ctx, kubeConfig, func(ctx context.Context, informer *powerModelInformer) error {
+		return informer.watch(ctx, kubeConfig)
+	})
+	return pm, nil
+}
+
+func newPowerModels(logger klog.Logger, byName map[string]config.PowerModel) *PowerModels {
+	return &PowerModels{
+		logger: logger,
+		byName: byName,
+		crds:   &powerModelInformer{logger: logger},
+	}
+}
+
+// acquireInformer shares the informer of the PowerModel objects of the kubeconfig, started on first use.
+// Without it, the other sources still apply.
+func (pm *PowerModels) acquireInformer(ctx context.Context, kubeConfig *rest.Config, start func(context.Context, *powerModelInformer) error) {
+	powerModelInformersLock.Lock()
+	defer powerModelInformersLock.Unlock()
+	if informer, ok := powerModelInformers[kubeConfig]; ok {
+		informer.refs++
+		pm.crds = informer
+		pm.kubeConfig = kubeConfig
+		return
+	}
+	// the informer outlives the plugin it is created for, until released by all of them
+	ctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	informer := &powerModelInformer{logger: klog.FromContext(ctx), refs: 1, cancel: cancel}
+	if err := start(ctx, informer); err != nil {
+		cancel()
+		pm.logger.Error(err, "Failed to watch PowerModels, they are ignored")
+		return
+	}
+	powerModelInformers[kubeConfig] = informer
+	pm.crds = informer
+	pm.kubeConfig = kubeConfig
+}
+
+// Release releases the informer of the PowerModel objects, stopped once released by all the plugins sharing it
+func (pm *PowerModels) Release() {
+	if pm.kubeConfig == nil {
+		return
+	}
+	powerModelInformersLock.Lock()
+	defer powerModelInformersLock.Unlock()
+	kubeConfig := pm.kubeConfig
+	pm.kubeConfig = nil
+	pm.crds.refs--
+	if pm.crds.refs > 0 {
+		return
+	}
+	delete(powerModelInformers, kubeConfig)
+	pm.crds.cancel()
+}
+
+// watch starts a cache of the PowerModel objects, stopped when the context is done
+func (i *powerModelInformer) watch(ctx context.Context, kubeConfig *rest.Config) error {
+	scheme := runtime.NewScheme()
+	_ = v1alpha1.AddToScheme(scheme)
+	_, ccache, err := util.NewClientWithCachedReader(ctx, kubeConfig, scheme)
+	if err != nil {
+		return err
+	}
+	informer, err := ccache.GetInformer(ctx, &v1alpha1.PowerModel{}, ctrlcache.BlockUntilSynced(false))
+	if err != nil {
+		return err
+	}
+	_, err = informer.AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc: i.update,
+			UpdateFunc: func(old, new interface{}) {
+				i.update(new)
+			},
+			DeleteFunc: i.delete,
+		},
+	)
+	return err
+}
+
+func (i *powerModelInformer) update(obj interface{}) {
+	p, ok := obj.(*v1alpha1.PowerModel)
+	if !ok {
+		return
+	}
+	parsed, err := parsePowerModel(p)
+	if err != nil {
+		i.logger.Error(err, "Invalid PowerModel, ignored", "powerModel", p.Name)
+		i.remove(p.Name)
+		return
+	}
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	j := sort.Search(len(i.crds), func(j int) bool { return i.crds[j].name >= p.Name })
+	if j < len(i.crds) && i.crds[j].name == p.Name {
+		i.crds[j] = parsed
+		return
+	}
+	i.crds = append(i.crds, nil)
+	copy(i.crds[j+1:], i.crds[j:])
+	i.crds[j] = parsed
+}
+
+func (i *powerModelInformer) delete(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	if p, ok := obj.(*v1alpha1.PowerModel); ok {
+		i.remove(p.Name)
+	}
+}
+
+func (i *powerModelInformer) remove(name string) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	j := sort.Search(len(i.crds), func(j int) bool { return i.crds[j].name >= name })
+	if j < len(i.crds) && i.crds[j].name == name {
+		i.crds = append(i.crds[:j], i.crds[j+1:]...)
+	}
+}
+
+// Get returns the power model of the node
+func (pm *PowerModels) Get(node *v1.Node) config.PowerModel {
+	if model, ok := pm.byName[node.Name]; ok {
+		return model
+	}
+	for _, metadata := range []map[string]string{node.Annotations, node.Labels} {
+		model, ok, err := powerModelFromMetadata(metadata)
+		if err != nil {
+			pm.logger.V(4).Info("Invalid power model in the node metadata, ignored", "nodeName", node.Name, "err", err)
+		}
+		if ok {
+			return model
+		}
+	}
+
+	pm.crds.lock.RLock()
+	defer pm.crds.lock.RUnlock()
+	if instanceType, ok := node.Labels[v1.LabelInstanceTypeStable]; ok {
+		for _, p := range pm.crds.crds {
+			if p.instanceTypes.Has(instanceType) {
+				return p.model
+			}
+		}
+	}
+	nodeLabels := labels.Set(node.Labels)
+	for _, p := range pm.crds.crds {
+		if p.selector != nil && p.selector.Matches(nodeLabels) {
+			return p.model
+		}
+	}
+	return getPowerModel(node.Name, nil)
+}
+
+// powerModelFromMetadata returns the power model set by the annotations or labels, if all its coefficients are
+func powerModelFromMetadata(metadata map[string]string) (config.PowerModel, bool, error) {
+	k0, ok0 := metadata[PowerModelK0Key]
+	k1, ok1 := metadata[PowerModelK1Key]
+	k2, ok2 := metadata[PowerModelK2Key]
+	if !ok0 && !ok1 && !ok2 {
+		return config.PowerModel{}, false, nil
+	}
+	if !ok0 || !ok1 || !ok2 {
+		return config.PowerModel{}, false, fmt.Errorf("%s, %s and %s must all be set", PowerModelK0Key, PowerModelK1Key, PowerModelK2Key)
+	}
+	model, err := parseCoefficients(k0, k1, k2)
+	return model, err == nil, err
+}
+
+func parsePowerModel(p *v1alpha1.PowerModel) (*crdPowerModel, error) {
+	model, err := parseCoefficients(p.Spec.K0, p.Spec.K1, p.Spec.K2)
+	if err != nil {
+		return nil, err
+	}
+	parsed := &crdPowerModel{
+		name:          p.Name,
+		instanceTypes: sets.New(p.Spec.InstanceTypes...),
+		model:         model,
+	}
+	if p.Spec.NodeSelector != nil {
+		parsed.selector, err = metav1.LabelSelectorAsSelector(p.Spec.NodeSelector)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return parsed, nil
+}
+
+func parseCoefficients(k0, k1, k2 string) (config.PowerModel, error) {
+	var coefficients [3]float64
+	for i, k := range []string{k0, k1, k2} {
+		c, err := strconv.ParseFloat(k, 64)
+		if err != nil {
+			return config.PowerModel{}, fmt.Errorf("invalid coefficient k%d: %w", i, err)
+		}
+		coefficients[i] = c
+	}
+	return config.PowerModel{K0: coefficients[0], K1: coefficients[1], K2: coefficients[2]}, nil
+}
@@ -0,0 +1,199 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package peaks
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	pluginConfig "sigs.k8s.io/scheduler-plugins/apis/config"
+	"sigs.k8s.io/scheduler-plugins/apis/scheduling/v1alpha1"
+)
+
+func makePowerModel(name string, instanceTypes []string, selector *metav1.LabelSelector, k0, k1, k2 string) *v1alpha1.PowerModel {
+	return &v1alpha1.PowerModel{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: v1alpha1.PowerModelSpec{
+			InstanceTypes: instanceTypes,
+			NodeSelector:  selector,
+			K0:            k0,
+			K1:            k1,
+			K2:            k2,
+		},
+	}
+}
+
+func TestPowerModels(t *testing.T) {
+	pm := newPowerModels(klog.Background(), map[string]pluginConfig.PowerModel{
+		"static": {K0: 1, K1: 1, K2: 1},
+	})
+	pm.crds.update(makePowerModel("b-selector", nil, &metav1.LabelSelector{MatchLabels: map[string]string{"pool": "batch"}}, "2", "2", "2"))
+	pm.crds.update(makePowerModel("a-selector", nil, &metav1.LabelSelector{MatchLabels: map[string]string{"pool": "batch"}}, "3", "3", "3"))
+	pm.crds.update(makePowerModel("m5", []string{"m5.large", "m5.xlarge"}, nil, "471.74", "-91.5", "-0.0718"))
+	pm.crds.update(makePowerModel("invalid", []string{"c5.large"}, nil, "1", "x", "1"))
+
+	annotated := map[string]string{
+		PowerModelK0Key: "10",
+		PowerModelK1Key: "-20",
+		PowerModelK2Key: "-0.5",
+	}
+	tests := []struct {
+		name        string
+		nodeName    string
+		annotations map[string]string
+		labels      map[string]string
+		expected    pluginConfig.PowerModel
+	}{
+		{
+			name:        "static model takes precedence",
+			nodeName:    "static",
+			annotations: annotated,
+			expected:    pluginConfig.PowerModel{K0: 1, K1: 1, K2: 1},
+		},
+		{
+			name:        "annotations",
+			nodeName:    "node",
+			annotations: annotated,
+			labels:      map[string]string{v1.LabelInstanceTypeStable: "m5.large"},
+			expected:    pluginConfig.PowerModel{K0: 10, K1: -20, K2: -0.5},
+		},
+		{
+			name:     "labels",
+			nodeName: "node",
+			labels:   map[string]string{PowerModelK0Key: "10", PowerModelK1Key: "20", PowerModelK2Key: "0.5"},
+			expected: pluginConfig.PowerModel{K0: 10, K1: 20, K2: 0.5},
+		},
+		{
+			name:        "partial annotations are ignored",
+			nodeName:    "node",
+			annotations: map[string]string{PowerModelK0Key: "10"},
+			labels:      map[string]string{v1.LabelInstanceTypeStable: "m5.xlarge"},
+			expected:    pluginConfig.PowerModel{K0: 471.74, K1: -91.5, K2: -0.0718},
+		},
+		{
+			name:     "instance type match takes precedence over selectors",
+			nodeName: "node",
+			labels:   map[string]string{v1.LabelInstanceTypeStable: "m5.large", "pool": "batch"},
+			expected: pluginConfig.PowerModel{K0: 471.74, K1: -91.5, K2: -0.0718},
+		},
+		{
+			name:     "first selector match by name",
+			nodeName: "node",
+			labels:   map[string]string{v1.LabelInstanceTypeStable: "m6.large", "pool": "batch"},
+			expected: pluginConfig.PowerModel{K0: 3, K1: 3, K2: 3},
+		},
+		{
+			name:     "invalid power model is ignored",
+			nodeName: "node",
+			labels:   map[string]string{v1.LabelInstanceTypeStable: "c5.large"},
+			expected: pluginConfig.PowerModel{},
+		},
+		{
+			name:     "no match",
+			nodeName: "node",
+			expected: pluginConfig.PowerModel{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node := &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: tt.nodeName, Annotations: tt.annotations, Labels: tt.labels}}
+			assert.Equal(t, tt.expected, pm.Get(node))
+		})
+	}
+}
+
+func TestPowerModelsUpdates(t *testing.T) {
+	pm := newPowerModels(klog.Background(), nil)
+	node := &v1.Node{ObjectMeta: metav1.ObjectMeta{
+		Name:   "node",
+		Labels: map[string]string{v1.LabelInstanceTypeStable: "m5.large"},
+	}}
+	assert.Equal(t, pluginConfig.PowerModel{}, pm.Get(node))
+
+	model := makePowerModel("m5", []string{"m5.large"}, nil, "1", "2", "3")
+	pm.crds.update(model)
+	assert.Equal(t, pluginConfig.PowerModel{K0: 1, K1: 2, K2: 3}, pm.Get(node))
+
+	updated := model.DeepCopy()
+	updated.Spec.K0 = "4"
+	pm.crds.update(updated)
+	assert.Equal(t, pluginConfig.PowerModel{K0: 4, K1: 2, K2: 3}, pm.Get(node))
+	assert.Len(t, pm.crds.crds, 1)
+
+	invalid := updated.DeepCopy()
+	invalid.Spec.K0 = "four"
+	pm.crds.update(invalid)
+	assert.Equal(t, pluginConfig.PowerModel{}, pm.Get(node), "invalid update drops the previous model")
+
+	pm.crds.update(updated)
+	pm.crds.delete(cache.DeletedFinalStateUnknown{Key: "m5", Obj: updated})
+	assert.Equal(t, pluginConfig.PowerModel{}, pm.Get(node))
+	assert.Empty(t, pm.crds.crds)
+}
+
+func TestPowerModelsSharedInformer(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	kubeConfig := &rest.Config{Host: "shared"}
+	starts := 0
+	start := func(ctx context.Context, _ *powerModelInformer) error {
+		starts++
+		return nil
+	}
+
+	pm1 := newPowerModels(klog.Background(), nil)
+	pm1.acquireInformer(ctx, kubeConfig, start)
+	pm2 := newPowerModels(klog.Background(), nil)
+	pm2.acquireInformer(ctx, kubeConfig, start)
+	assert.Equal(t, 1, starts, "the informer is started once")
+	assert.Same(t, pm1.crds, pm2.crds)
+
+	pm1.crds.update(makePowerModel("m5", []string{"m5.large"}, nil, "1", "2", "3"))
+	node := &v1.Node{ObjectMeta: metav1.ObjectMeta{
+		Name:   "node",
+		Labels: map[string]string{v1.LabelInstanceTypeStable: "m5.large"},
+	}}
+	assert.Equal(t, pluginConfig.PowerModel{K0: 1, K1: 2, K2: 3}, pm2.Get(node))
+
+	informer := pm1.crds
+	pm1.Release()
+	pm1.Release()
+	assert.Contains(t, powerModelInformers, kubeConfig, "releasing twice keeps the informer of the other plugin")
+	pm2.Release()
+	assert.NotContains(t, powerModelInformers, kubeConfig)
+
+	pm3 := newPowerModels(klog.Background(), nil)
+	pm3.acquireInformer(ctx, kubeConfig, start)
+	defer pm3.Release()
+	assert.Equal(t, 2, starts, "a new informer is started once the previous one is released")
+	assert.NotSame(t, informer, pm3.crds)
+
+	failed := newPowerModels(klog.Background(), nil)
+	failed.acquireInformer(ctx, &rest.Config{Host: "failed"}, func(context.Context, *powerModelInformer) error {
+		return errors.New("unreachable")
+	})
+	failed.Release()
+	assert.Len(t, powerModelInformers, 1, "failed informers are not shared")
+}
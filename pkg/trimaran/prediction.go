@@ -0,0 +1,61 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trimaran
+
+import (
+	"math"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// Predictor predicts the utilization of the containers of a pod from their resources: their limits, or their
+// requests times RequestsMultiplier for burstable containers, or DefaultRequests for best effort containers
+type Predictor struct {
+	// RequestsMultiplier is the multiplier of the requests of the containers without limits
+	RequestsMultiplier float64
+	// DefaultRequests are the requests of the containers without limits nor requests
+	DefaultRequests v1.ResourceList
+}
+
+// PredictUtilisation predicts the utilization of a resource for a container
+// (in millicores for CPU, and in the base unit of the resource otherwise)
+func (p *Predictor) PredictUtilisation(container *v1.Container, resourceName v1.ResourceName) int64 {
+	if limit, ok := container.Resources.Limits[resourceName]; ok {
+		return QuantityValue(limit, resourceName)
+	} else if request, ok := container.Resources.Requests[resourceName]; ok {
+		return int64(math.Round(float64(QuantityValue(request, resourceName)) * p.RequestsMultiplier))
+	}
+	return QuantityValue(p.DefaultRequests[resourceName], resourceName)
+}
+
+// PredictPodUtilisation predicts the utilization of a resource for the containers of a pod, plus its overhead
+func (p *Predictor) PredictPodUtilisation(pod *v1.Pod, resourceName v1.ResourceName) int64 {
+	var utilisation int64
+	for _, container := range pod.Spec.Containers {
+		utilisation += p.PredictUtilisation(&container, resourceName)
+	}
+	return utilisation + QuantityValue(pod.Spec.Overhead[resourceName], resourceName)
+}
+
+// QuantityValue returns the value of a quantity, in millicores for CPU
+func QuantityValue(quantity resource.Quantity, resourceName v1.ResourceName) int64 {
+	if resourceName == v1.ResourceCPU {
+		return quantity.MilliValue()
+	}
+	return quantity.Value()
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trimaran
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/paypal/load-watcher/pkg/watcher"
+	"github.com/prometheus/client_golang/api"
+	promv1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	promconfig "github.com/prometheus/common/config"
+	"github.com/prometheus/common/model"
+	"k8s.io/klog/v2"
+
+	pluginConfig "sigs.k8s.io/scheduler-plugins/apis/config"
+)
+
+const (
+	prometheusQueryTimeout = 10 * time.Second
+)
+
+// prometheusClient : client issuing the configured PromQL queries to Prometheus directly, in place of load watcher
+type prometheusClient struct {
+	logger klog.Logger
+	// Prometheus HTTP API
+	api promv1.API
+	// queries of the metrics of the nodes
+	queries []pluginConfig.MetricQuery
+}
+
+// newPrometheusClient : create a client of the Prometheus metric provider issuing its queries
+func newPrometheusClient(logger klog.Logger, metricProvider *pluginConfig.MetricProviderSpec) (*prometheusClient, error) {
+	roundTripper := api.DefaultRoundTripper
+	if metricProvider.InsecureSkipVerify {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+		roundTripper = transport
+	}
+	if metricProvider.Token != "" {
+		roundTripper = promconfig.NewAuthorizationCredentialsRoundTripper("Bearer",
+			promconfig.NewInlineSecret(metricProvider.Token), roundTripper)
+	}
+	client, err := api.NewClient(api.Config{
+		Address:      metricProvider.Address,
+		RoundTripper: roundTripper,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &prometheusClient{
+		logger:  logger,
+		api:     promv1.NewAPI(client),
+		queries: metricProvider.Queries,
+	}, nil
+}
+
+// GetLatestWatcherMetrics : issue the queries and map their results to the metrics of the nodes
+// (fails only if all queries fail, the metrics of the failed queries are missing otherwise)
+func (c *prometheusClient) GetLatestWatcherMetrics() (*watcher.WatcherMetrics, error) {
+	now := time.Now()
+	metrics := &watcher.WatcherMetrics{
+		Timestamp: now.Unix(),
+		Window:    watcher.Window{Start: now.Unix(), End: now.Unix()},
+		Source:    string(pluginConfig.Prometheus),
+		Data:      watcher.Data{NodeMetricsMap: make(watcher.NodeMetricsMap)},
+	}
+	var errs []error
+	for _, query := range c.queries {
+		vector, err := c.query(query.Query, now)
+		if err != nil {
+			c.logger.Error(err, "Failed to query Prometheus", "query", query.Query)
+			errs = append(errs, err)
+			continue
+		}
+		for _, sample := range vector {
+			nodeName := string(sample.Metric[model.LabelName(query.NodeLabel)])
+			if nodeName == "" {
+				continue
+			}
+			nodeMetrics := metrics.Data.NodeMetricsMap[nodeName]
+			nodeMetrics.Metrics = append(nodeMetrics.Metrics, watcher.Metric{
+				Name:     query.Query,
+				Type:     query.Type,
+				Operator: query.Operator,
+				Value:    float64(sample.Value),
+			})
+			metrics.Data.NodeMetricsMap[nodeName] = nodeMetrics
+		}
+	}
+	if len(c.queries) > 0 && len(errs) == len(c.queries) {
+		return nil, errors.Join(errs...)
+	}
+	return metrics, nil
+}
+
+// query : issue an instant query to Prometheus, expecting a vector
+func (c *prometheusClient) query(query string, ts time.Time) (model.Vector, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), prometheusQueryTimeout)
+	defer cancel()
+	result, warnings, err := c.api.Query(ctx, query, ts)
+	if err != nil {
+		return nil, err
+	}
+	if len(warnings) > 0 {
+		c.logger.V(4).Info("Warnings from Prometheus", "query", query, "warnings", warnings)
+	}
+	vector, ok := result.(model.Vector)
+	if !ok {
+		return nil, fmt.Errorf("unexpected result type %v of query %q, expected %v", result.Type(), query, model.ValVector)
+	}
+	return vector, nil
+}
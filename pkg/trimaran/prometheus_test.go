@@ -0,0 +1,169 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trimaran
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/paypal/load-watcher/pkg/watcher"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/klog/v2"
+
+	pluginConfig "sigs.k8s.io/scheduler-plugins/apis/config"
+)
+
+const (
+	cpuQuery    = "node:cpu_utilisation:avg5m * 100"
+	memoryQuery = "node:memory_utilisation:avg5m * 100"
+	powerQuery  = "node:power_watts:avg5m"
+)
+
+// newFakePrometheus : fake Prometheus server answering instant queries with the given responses
+func newFakePrometheus(t *testing.T, responses map[string]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "/api/v1/query", req.URL.Path)
+		assert.Nil(t, req.ParseForm())
+		body, ok := responses[req.Form.Get("query")]
+		if !ok {
+			resp.WriteHeader(http.StatusBadRequest)
+			resp.Write([]byte(`{"status":"error","errorType":"bad_data","error":"unknown query"}`))
+			return
+		}
+		resp.Header().Set("Content-Type", "application/json")
+		resp.Write([]byte(body))
+	}))
+}
+
+func TestPrometheusClient(t *testing.T) {
+	server := newFakePrometheus(t, map[string]string{
+		cpuQuery: `{"status":"success","data":{"resultType":"vector","result":[
+			{"metric":{"node":"node-1"},"value":[1700000000,"40"]},
+			{"metric":{"node":"node-2"},"value":[1700000000,"75.5"]},
+			{"metric":{},"value":[1700000000,"10"]}]}}`,
+		memoryQuery: `{"status":"success","data":{"resultType":"vector","result":[
+			{"metric":{"node":"node-1"},"value":[1700000000,"20"]}]}}`,
+		powerQuery: `{"status":"success","data":{"resultType":"scalar","result":[1700000000,"1"]}}`,
+	})
+	defer server.Close()
+
+	tests := []struct {
+		name      string
+		queries   []pluginConfig.MetricQuery
+		expected  watcher.NodeMetricsMap
+		expectErr bool
+	}{
+		{
+			name: "results are mapped to the metrics of the nodes",
+			queries: []pluginConfig.MetricQuery{
+				{Type: watcher.CPU, Operator: watcher.Average, Query: cpuQuery, NodeLabel: "node"},
+				{Type: watcher.Memory, Operator: watcher.Std, Query: memoryQuery, NodeLabel: "node"},
+			},
+			expected: watcher.NodeMetricsMap{
+				"node-1": {Metrics: []watcher.Metric{
+					{Name: cpuQuery, Type: watcher.CPU, Operator: watcher.Average, Value: 40},
+					{Name: memoryQuery, Type: watcher.Memory, Operator: watcher.Std, Value: 20},
+				}},
+				"node-2": {Metrics: []watcher.Metric{
+					{Name: cpuQuery, Type: watcher.CPU, Operator: watcher.Average, Value: 75.5},
+				}},
+			},
+		},
+		{
+			name: "failed queries are left out",
+			queries: []pluginConfig.MetricQuery{
+				{Type: watcher.CPU, Operator: watcher.Average, Query: cpuQuery, NodeLabel: "node"},
+				{Type: watcher.Energy, Operator: watcher.Average, Query: powerQuery, NodeLabel: "node"},
+				{Type: watcher.Memory, Operator: watcher.Average, Query: "unknown", NodeLabel: "node"},
+			},
+			expected: watcher.NodeMetricsMap{
+				"node-1": {Metrics: []watcher.Metric{
+					{Name: cpuQuery, Type: watcher.CPU, Operator: watcher.Average, Value: 40},
+				}},
+				"node-2": {Metrics: []watcher.Metric{
+					{Name: cpuQuery, Type: watcher.CPU, Operator: watcher.Average, Value: 75.5},
+				}},
+			},
+		},
+		{
+			name: "all queries failed",
+			queries: []pluginConfig.MetricQuery{
+				{Type: watcher.Memory, Operator: watcher.Average, Query: "unknown", NodeLabel: "node"},
+			},
+			expectErr: true,
+		},
+	}
+	logger := klog.FromContext(context.TODO())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := newPrometheusClient(logger, &pluginConfig.MetricProviderSpec{
+				Type:    pluginConfig.Prometheus,
+				Address: server.URL,
+				Queries: tt.queries,
+			})
+			assert.Nil(t, err)
+			metrics, err := client.GetLatestWatcherMetrics()
+			if tt.expectErr {
+				assert.NotNil(t, err)
+				return
+			}
+			assert.Nil(t, err)
+			assert.EqualValues(t, tt.expected, metrics.Data.NodeMetricsMap)
+			assert.Equal(t, metrics.Timestamp, metrics.Window.End)
+		})
+	}
+}
+
+func TestNewCollectorPrometheusQueries(t *testing.T) {
+	server := newFakePrometheus(t, map[string]string{
+		cpuQuery: `{"status":"success","data":{"resultType":"vector","result":[
+			{"metric":{"instance":"node-1"},"value":[1700000000,"40"]}]}}`,
+	})
+	defer server.Close()
+
+	logger := klog.FromContext(context.TODO())
+	collector, err := NewCollector(logger, &pluginConfig.TrimaranSpec{
+		MetricProvider: pluginConfig.MetricProviderSpec{
+			Type:    pluginConfig.Prometheus,
+			Address: server.URL,
+			Queries: []pluginConfig.MetricQuery{
+				{Type: watcher.CPU, Operator: watcher.Average, Query: cpuQuery, NodeLabel: "instance"},
+			},
+		},
+	})
+	assert.Nil(t, err)
+	metrics, _ := collector.GetNodeMetrics(logger, "node-1")
+	assert.EqualValues(t, []watcher.Metric{{Name: cpuQuery, Type: watcher.CPU, Operator: watcher.Average, Value: 40}}, metrics)
+
+	_, err = NewCollector(logger, &pluginConfig.TrimaranSpec{
+		MetricProvider: pluginConfig.MetricProviderSpec{
+			Type:    pluginConfig.KubernetesMetricsServer,
+			Queries: []pluginConfig.MetricQuery{{Type: watcher.CPU, Query: cpuQuery, NodeLabel: "instance"}},
+		},
+	})
+	assert.EqualError(t, err, "MetricProvider.Queries are only supported by Prometheus, got KubernetesMetricsServer")
+
+	_, err = NewCollector(logger, &pluginConfig.TrimaranSpec{
+		MetricProvider: pluginConfig.MetricProviderSpec{
+			Type:    pluginConfig.Prometheus,
+			Queries: []pluginConfig.MetricQuery{{Type: watcher.CPU, NodeLabel: "instance"}},
+		},
+	})
+	assert.EqualError(t, err, "MetricProvider.Queries[0] must set type, query and nodeLabel")
+}
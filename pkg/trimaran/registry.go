@@ -0,0 +1,139 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trimaran
+
+import (
+	"fmt"
+	"sync"
+
+	"k8s.io/client-go/informers"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	pluginConfig "sigs.k8s.io/scheduler-plugins/apis/config"
+)
+
+var (
+	// collectors are shared by the plugins, of any profile, using the same metric source
+	collectors = newRegistry[string](func(c *Collector) { c.stop() })
+	// handlers are shared by the plugins using the same informer factory
+	handlers = newRegistry[informers.SharedInformerFactory](func(p *PodAssignEventHandler) { p.stop() })
+)
+
+type registryEntry[V any] struct {
+	value V
+	refs  int
+}
+
+// registry holds reference-counted values, created on first use and stopped when the last reference is released
+type registry[K comparable, V any] struct {
+	mu      sync.Mutex
+	entries map[K]*registryEntry[V]
+	stop    func(V)
+}
+
+func newRegistry[K comparable, V any](stop func(V)) *registry[K, V] {
+	return &registry[K, V]{
+		entries: make(map[K]*registryEntry[V]),
+		stop:    stop,
+	}
+}
+
+func (r *registry[K, V]) acquire(key K, create func() (V, error)) (V, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if entry, ok := r.entries[key]; ok {
+		entry.refs++
+		return entry.value, nil
+	}
+	value, err := create()
+	if err != nil {
+		return value, err
+	}
+	r.entries[key] = &registryEntry[V]{value: value, refs: 1}
+	return value, nil
+}
+
+func (r *registry[K, V]) release(key K) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	entry, ok := r.entries[key]
+	if !ok {
+		return
+	}
+	entry.refs--
+	if entry.refs > 0 {
+		return
+	}
+	delete(r.entries, key)
+	r.stop(entry.value)
+}
+
+// AcquireCollector : get the collector of the metric source of the spec, creating it on first use.
+// The collector is shared by all the plugins using the same metric source, and each of them must
+// call Release once done with it.
+func AcquireCollector(logger klog.Logger, trimaranSpec *pluginConfig.TrimaranSpec) (*Collector, error) {
+	if err := checkSpecs(trimaranSpec); err != nil {
+		return nil, err
+	}
+	key := collectorKey(trimaranSpec)
+	return collectors.acquire(key, func() (*Collector, error) {
+		collector, err := NewCollector(logger, trimaranSpec)
+		if err != nil {
+			return nil, err
+		}
+		collector.key = key
+		return collector, nil
+	})
+}
+
+// Release : release a collector obtained from AcquireCollector, stopping it if it is no longer used
+func (collector *Collector) Release() {
+	if collector.key == "" {
+		collector.stop()
+		return
+	}
+	collectors.release(collector.key)
+}
+
+// collectorKey identifies the metric source of the spec, along with the way it is polled
+func collectorKey(trimaranSpec *pluginConfig.TrimaranSpec) string {
+	updateInterval, retention := collectorIntervals(trimaranSpec)
+	return fmt.Sprintf("%+v/%v/%v/%v", trimaranSpec.MetricProvider, trimaranSpec.WatcherAddress, updateInterval, retention)
+}
+
+// AcquirePodAssignEventHandler : get the event handler caching the pods assigned through the informers of
+// the handle, creating it on first use. Each plugin using it must call Release once done with it.
+func AcquirePodAssignEventHandler(handle framework.Handle) *PodAssignEventHandler {
+	factory := handle.SharedInformerFactory()
+	p, _ := handlers.acquire(factory, func() (*PodAssignEventHandler, error) {
+		p := New()
+		p.AddToHandle(handle)
+		p.factory = factory
+		return p, nil
+	})
+	return p
+}
+
+// Release : release an event handler obtained from AcquirePodAssignEventHandler, stopping it if it is no longer used
+func (p *PodAssignEventHandler) Release() {
+	if p.factory == nil {
+		p.stop()
+		return
+	}
+	handlers.release(p.factory)
+}
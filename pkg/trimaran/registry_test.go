@@ -0,0 +1,104 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trimaran
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/klog/v2"
+
+	pluginConfig "sigs.k8s.io/scheduler-plugins/apis/config"
+)
+
+func TestAcquireCollector(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		requests.Add(1)
+		bytes, err := json.Marshal(watcherResponse)
+		assert.Nil(t, err)
+		resp.Write(bytes)
+	}))
+	defer server.Close()
+
+	logger := klog.FromContext(context.TODO())
+	spec := pluginConfig.TrimaranSpec{WatcherAddress: server.URL}
+
+	first, err := AcquireCollector(logger, &spec)
+	assert.Nil(t, err)
+	second, err := AcquireCollector(logger, &spec)
+	assert.Nil(t, err)
+	assert.Same(t, first, second, "collectors of the same metric source are shared")
+	assert.EqualValues(t, 1, requests.Load(), "shared collector polls the metric source once")
+
+	other, err := AcquireCollector(logger, &pluginConfig.TrimaranSpec{WatcherAddress: server.URL, MetricsRefreshIntervalSeconds: 10})
+	assert.Nil(t, err)
+	assert.NotSame(t, first, other, "collectors polling differently are not shared")
+	other.Release()
+
+	first.Release()
+	select {
+	case <-first.stopCh:
+		t.Fatal("collector stopped while still used")
+	default:
+	}
+	second.Release()
+	select {
+	case <-first.stopCh:
+	default:
+		t.Fatal("collector not stopped when no longer used")
+	}
+
+	third, err := AcquireCollector(logger, &spec)
+	assert.Nil(t, err)
+	assert.NotSame(t, first, third, "stopped collector reused")
+	third.Release()
+
+	_, err = AcquireCollector(logger, &pluginConfig.TrimaranSpec{})
+	assert.NotNil(t, err)
+	assert.Empty(t, collectors.entries)
+}
+
+func TestRegistry(t *testing.T) {
+	var stopped []string
+	r := newRegistry[string](func(v string) { stopped = append(stopped, v) })
+	create := func(v string) func() (string, error) {
+		return func() (string, error) { return v, nil }
+	}
+
+	v, _ := r.acquire("a", create("a1"))
+	assert.Equal(t, "a1", v)
+	v, _ = r.acquire("a", create("a2"))
+	assert.Equal(t, "a1", v)
+	v, _ = r.acquire("b", create("b1"))
+	assert.Equal(t, "b1", v)
+
+	r.release("a")
+	assert.Empty(t, stopped)
+	r.release("a")
+	assert.Equal(t, []string{"a1"}, stopped)
+	r.release("a")
+	assert.Equal(t, []string{"a1"}, stopped, "extra release ignored")
+	r.release("b")
+	assert.Equal(t, []string{"a1", "b1"}, stopped)
+	assert.Empty(t, r.entries)
+}
@@ -15,7 +15,8 @@ limitations under the License.
 */
 
 /*
-targetloadpacking package provides K8s scheduler plugin for best-fit variant of bin packing based on CPU utilization around a target load
+targetloadpacking package provides K8s scheduler plugin for best-fit variant of bin packing based on the utilization of
+CPU, memory or other resources around a target load
 It contains plugin for Score extension point.
 */
 
@@ -25,8 +26,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"math"
+	"sort"
 	"strconv"
+	"sync"
 
 	"github.com/paypal/load-watcher/pkg/watcher"
 	fwk "k8s.io/kube-scheduler/framework"
@@ -37,7 +41,7 @@ import (
 	"k8s.io/kubernetes/pkg/scheduler/framework"
 
 	pluginConfig "sigs.k8s.io/scheduler-plugins/apis/config"
-	cfgv1 "sigs.k8s.io/scheduler-plugins/apis/config/v1"
+	"sigs.k8s.io/scheduler-plugins/apis/config/validation"
 	"sigs.k8s.io/scheduler-plugins/pkg/trimaran"
 )
 
@@ -47,21 +51,31 @@ const (
 	metricsAgentReportingIntervalSeconds = 60
 )
 
-var (
-	requestsMilliCores           = cfgv1.DefaultRequestsMilliCores
-	hostTargetUtilizationPercent = cfgv1.DefaultTargetUtilizationPercent
-	requestsMultiplier           float64
-)
-
 type TargetLoadPacking struct {
 	logger       klog.Logger
 	handle       framework.Handle
 	eventHandler *trimaran.PodAssignEventHandler
 	collector    *trimaran.Collector
 	args         *pluginConfig.TargetLoadPackingArgs
+	// predicts the utilization of the pods from their limits, requests, or the default requests
+	predictor trimaran.Predictor
+	// target utilization and score weight of each resource, sorted by resource name
+	targets []resourceTarget
+
+	// mu guards the targets scored with the metrics of scoredTimestamp
+	mu               sync.Mutex
+	scoredTimestamp  int64
+	scoredTargets    []resourceTarget
+	droppedResources string
+}
+
+type resourceTarget struct {
+	name v1.ResourceName
+	pluginConfig.ResourceTarget
 }
 
 var _ framework.ScorePlugin = &TargetLoadPacking{}
+var _ io.Closer = &TargetLoadPacking{}
 
 func New(ctx context.Context, obj runtime.Object, handle framework.Handle) (framework.Plugin, error) {
 	logger := klog.FromContext(ctx).WithValues("plugin", Name)
@@ -71,33 +85,47 @@ func New(ctx context.Context, obj runtime.Object, handle framework.Handle) (fram
 	if !ok {
 		return nil, fmt.Errorf("want args to be of type TargetLoadPackingArgs, got %T", obj)
 	}
-	collector, err := trimaran.NewCollector(logger, &args.TrimaranSpec)
-	if err != nil {
+	if err := validation.ValidateTargetLoadPackingArgs(args, nil); err != nil {
 		return nil, err
 	}
-
-	hostTargetUtilizationPercent = args.TargetUtilization
-	requestsMilliCores = args.DefaultRequests.Cpu().MilliValue()
-	requestsMultiplier, err = strconv.ParseFloat(args.DefaultRequestsMultiplier, 64)
+	requestsMultiplier, err := strconv.ParseFloat(args.DefaultRequestsMultiplier, 64)
 	if err != nil {
 		return nil, errors.New("unable to parse DefaultRequestsMultiplier: " + err.Error())
 	}
 
-	logger.V(4).Info("Using TargetLoadPackingArgs",
-		"requestsMilliCores", requestsMilliCores,
-		"requestsMultiplier", requestsMultiplier,
-		"targetUtilization", hostTargetUtilizationPercent)
+	var targets []resourceTarget
+	for name, target := range args.ResourceTargets {
+		targets = append(targets, resourceTarget{name: name, ResourceTarget: target})
+	}
+	if len(targets) == 0 {
+		targets = []resourceTarget{{
+			name:           v1.ResourceCPU,
+			ResourceTarget: pluginConfig.ResourceTarget{TargetUtilization: args.TargetUtilization, Weight: 1},
+		}}
+	}
+	sort.Slice(targets, func(i, j int) bool {
+		return targets[i].name < targets[j].name
+	})
 
-	podAssignEventHandler := trimaran.New()
-	podAssignEventHandler.AddToHandle(handle)
+	collector, err := trimaran.AcquireCollector(logger, &args.TrimaranSpec)
+	if err != nil {
+		return nil, err
+	}
 
 	pl := &TargetLoadPacking{
 		logger:       logger,
 		handle:       handle,
-		eventHandler: podAssignEventHandler,
+		eventHandler: trimaran.AcquirePodAssignEventHandler(handle),
 		collector:    collector,
 		args:         args,
+		predictor:    trimaran.Predictor{RequestsMultiplier: requestsMultiplier, DefaultRequests: args.DefaultRequests},
+		targets:      targets,
 	}
+
+	logger.V(4).Info("Using TargetLoadPackingArgs",
+		"defaultRequests", pl.predictor.DefaultRequests,
+		"requestsMultiplier", pl.predictor.RequestsMultiplier,
+		"resourceTargets", pl.targets)
 	return pl, nil
 }
 
@@ -105,10 +133,18 @@ func (pl *TargetLoadPacking) Name() string {
 	return Name
 }
 
+// Close releases the collector and the event handler, shared with the other Trimaran plugins
+func (pl *TargetLoadPacking) Close() error {
+	pl.collector.Release()
+	pl.eventHandler.Release()
+	return nil
+}
+
 func (pl *TargetLoadPacking) Score(ctx context.Context, cycleState fwk.CycleState, pod *v1.Pod, nodeInfo fwk.NodeInfo) (int64, *fwk.Status) {
 	logger := klog.FromContext(klog.NewContext(ctx, pl.logger)).WithValues("ExtensionPoint", "Score")
 	score := framework.MinNodeScore
-	nodeName := nodeInfo.Node().Name
+	node := nodeInfo.Node()
+	nodeName := node.Name
 
 	// get node metrics
 	metrics, allMetrics := pl.collector.GetNodeMetrics(logger, nodeName)
@@ -120,37 +156,109 @@ func (pl *TargetLoadPacking) Score(ctx context.Context, cycleState fwk.CycleStat
 
 	}
 
-	var curPodCPUUsage int64
-	for _, container := range pod.Spec.Containers {
-		curPodCPUUsage += PredictUtilisation(&container)
+	// combine the scores of the resources, weighted
+	var weightedScore, totalWeight int64
+	for _, target := range pl.targetsWithMetrics(logger, allMetrics) {
+		resourceScore, ok := pl.scoreResource(logger, pod, node, metrics, allMetrics, target)
+		if !ok {
+			continue
+		}
+		weightedScore += target.Weight * resourceScore
+		totalWeight += target.Weight
 	}
-	logger.V(6).Info("Predicted utilization for pod", "podName", pod.Name, "cpuUsage", curPodCPUUsage)
-	if pod.Spec.Overhead != nil {
-		curPodCPUUsage += pod.Spec.Overhead.Cpu().MilliValue()
+	if totalWeight == 0 {
+		return score, nil
 	}
 
-	var nodeCPUUtilPercent float64
-	var cpuMetricFound bool
-	for _, metric := range metrics {
-		if metric.Type == watcher.CPU {
-			if metric.Operator == watcher.Average || metric.Operator == watcher.Latest {
-				nodeCPUUtilPercent = metric.Value
-				cpuMetricFound = true
+	score = int64(math.Round(float64(weightedScore) / float64(totalWeight)))
+	logger.V(6).Info("Score for host", "nodeName", nodeName, "score", score)
+	return score, fwk.NewStatus(fwk.Success, "")
+}
+
+// targetsWithMetrics returns the targets whose metric is reported for every node of the latest metrics, so that
+// all the nodes are scored on the same resources. The targets are computed once per refresh of the metrics,
+// and the resources dropped for lack of metrics are logged when they change.
+func (pl *TargetLoadPacking) targetsWithMetrics(logger klog.Logger, allMetrics *watcher.WatcherMetrics) []resourceTarget {
+	if allMetrics == nil {
+		return pl.targets
+	}
+	pl.mu.Lock()
+	defer pl.mu.Unlock()
+	if pl.scoredTargets != nil && pl.scoredTimestamp == allMetrics.Timestamp {
+		return pl.scoredTargets
+	}
+
+	scored := []resourceTarget{}
+	var dropped []string
+	for _, target := range pl.targets {
+		found := true
+		for _, nodeMetrics := range allMetrics.Data.NodeMetricsMap {
+			if _, found = nodeUtilisation(nodeMetrics.Metrics, target.name); !found {
+				break
 			}
 		}
+		if found {
+			scored = append(scored, target)
+		} else {
+			dropped = append(dropped, string(target.name))
+		}
+	}
+	if droppedResources := fmt.Sprint(dropped); droppedResources != pl.droppedResources {
+		if len(dropped) > 0 {
+			logger.V(4).Info("Resources not scored, their metric is missing for some nodes", "resources", dropped)
+		}
+		pl.droppedResources = droppedResources
 	}
+	pl.scoredTimestamp, pl.scoredTargets = allMetrics.Timestamp, scored
+	return scored
+}
 
-	if !cpuMetricFound {
-		logger.Error(nil, "Cpu metric not found in node metrics", "nodeName", nodeName, "nodeMetrics", metrics)
-		return score, nil
+// scoreResource scores the predicted utilization of a resource on the node against its target utilization
+func (pl *TargetLoadPacking) scoreResource(logger klog.Logger, pod *v1.Pod, node *v1.Node, metrics []watcher.Metric,
+	allMetrics *watcher.WatcherMetrics, target resourceTarget) (int64, bool) {
+	nodeName := node.Name
+	nodeUtilPercent, metricFound := nodeUtilisation(metrics, target.name)
+	if !metricFound {
+		// only possible with the retained metrics of a node missing from the latest ones
+		logger.V(6).Info("Resource metric not found in node metrics", "nodeName", nodeName, "resource", target.name)
+		return 0, false
+	}
+
+	curPodUsage := pl.predictor.PredictPodUtilisation(pod, target.name)
+	logger.V(6).Info("Predicted utilization for pod", "podName", pod.Name, "resource", target.name, "usage", curPodUsage)
+
+	nodeCap := float64(trimaran.QuantityValue(node.Status.Capacity[target.name], target.name))
+	nodeUtil := (nodeUtilPercent / 100) * nodeCap
+	logger.V(6).Info("Calculating utilization and capacity", "nodeName", nodeName, "resource", target.name, "util", nodeUtil, "cap", nodeCap)
+
+	missingUtil := pl.missingUtilisation(logger, nodeName, allMetrics, target.name)
+	logger.V(6).Info("Missing utilization for node", "nodeName", nodeName, "resource", target.name, "missingUtil", missingUtil)
+
+	var predictedUsage float64
+	if nodeCap != 0 {
+		predictedUsage = 100 * (nodeUtil + float64(curPodUsage) + float64(missingUtil)) / nodeCap
+	}
+	hostTargetUtilizationPercent := target.TargetUtilization
+	if predictedUsage > float64(hostTargetUtilizationPercent) {
+		if predictedUsage > 100 {
+			return framework.MinNodeScore, true
+		}
+		penalisedScore := int64(math.Round(float64(hostTargetUtilizationPercent) * (100 - predictedUsage) / (100 - float64(hostTargetUtilizationPercent))))
+		logger.V(6).Info("Penalised score for host", "nodeName", nodeName, "resource", target.name, "penalisedScore", penalisedScore)
+		return penalisedScore, true
 	}
-	nodeCPUCapMillis := float64(nodeInfo.Node().Status.Capacity.Cpu().MilliValue())
-	nodeCPUUtilMillis := (nodeCPUUtilPercent / 100) * nodeCPUCapMillis
 
-	logger.V(6).Info("Calculating CPU utilization and capacity", "nodeName", nodeName, "cpuUtilMillis", nodeCPUUtilMillis, "cpuCapMillis", nodeCPUCapMillis)
+	score := int64(math.Round((100-float64(hostTargetUtilizationPercent))*
+		predictedUsage/float64(hostTargetUtilizationPercent) + float64(hostTargetUtilizationPercent)))
+	logger.V(6).Info("Score for resource", "nodeName", nodeName, "resource", target.name, "score", score)
+	return score, true
+}
 
-	var missingCPUUtilMillis int64 = 0
+// missingUtilisation predicts the utilization of the pods recently scheduled on the node, not reported in the metrics yet
+func (pl *TargetLoadPacking) missingUtilisation(logger klog.Logger, nodeName string, allMetrics *watcher.WatcherMetrics, resourceName v1.ResourceName) int64 {
+	var missingUtil int64
 	pl.eventHandler.RLock()
+	defer pl.eventHandler.RUnlock()
 	for _, info := range pl.eventHandler.ScheduledPodsCache[nodeName] {
 		// If the time stamp of the scheduled pod is outside fetched metrics window, or it is within metrics reporting interval seconds, we predict util.
 		// Note that the second condition doesn't guarantee metrics for that pod are not reported yet as the 0 <= t <= 2*metricsAgentReportingIntervalSeconds
@@ -158,33 +266,11 @@ func (pl *TargetLoadPacking) Score(ctx context.Context, cycleState fwk.CycleStat
 		// counting metrics twice in case actual t is less than metricsAgentReportingIntervalSeconds
 		if info.Timestamp.Unix() > allMetrics.Window.End || info.Timestamp.Unix() <= allMetrics.Window.End &&
 			(allMetrics.Window.End-info.Timestamp.Unix()) < metricsAgentReportingIntervalSeconds {
-			for _, container := range info.Pod.Spec.Containers {
-				missingCPUUtilMillis += PredictUtilisation(&container)
-			}
-			missingCPUUtilMillis += info.Pod.Spec.Overhead.Cpu().MilliValue()
-			logger.V(6).Info("Missing utilization for pod", "podName", info.Pod.Name, "missingCPUUtilMillis", missingCPUUtilMillis)
+			missingUtil += pl.predictor.PredictPodUtilisation(info.Pod, resourceName)
+			logger.V(6).Info("Missing utilization for pod", "podName", info.Pod.Name, "resource", resourceName, "missingUtil", missingUtil)
 		}
 	}
-	pl.eventHandler.RUnlock()
-	logger.V(6).Info("Missing utilization for node", "nodeName", nodeName, "missingCPUUtilMillis", missingCPUUtilMillis)
-
-	var predictedCPUUsage float64
-	if nodeCPUCapMillis != 0 {
-		predictedCPUUsage = 100 * (nodeCPUUtilMillis + float64(curPodCPUUsage) + float64(missingCPUUtilMillis)) / nodeCPUCapMillis
-	}
-	if predictedCPUUsage > float64(hostTargetUtilizationPercent) {
-		if predictedCPUUsage > 100 {
-			return score, fwk.NewStatus(fwk.Success, "")
-		}
-		penalisedScore := int64(math.Round(float64(hostTargetUtilizationPercent) * (100 - predictedCPUUsage) / (100 - float64(hostTargetUtilizationPercent))))
-		logger.V(6).Info("Penalised score for host", "nodeName", nodeName, "penalisedScore", penalisedScore)
-		return penalisedScore, fwk.NewStatus(fwk.Success, "")
-	}
-
-	score = int64(math.Round((100-float64(hostTargetUtilizationPercent))*
-		predictedCPUUsage/float64(hostTargetUtilizationPercent) + float64(hostTargetUtilizationPercent)))
-	logger.V(6).Info("Score for host", "nodeName", nodeName, "score", score)
-	return score, fwk.NewStatus(fwk.Success, "")
+	return missingUtil
 }
 
 func (pl *TargetLoadPacking) ScoreExtensions() framework.ScoreExtensions {
@@ -195,12 +281,25 @@ func (pl *TargetLoadPacking) NormalizeScore(context.Context, fwk.CycleState, *v1
 	return nil
 }
 
-// PredictUtilisation predict utilization for a container based on its requests/limits
-func PredictUtilisation(container *v1.Container) int64 {
-	if _, ok := container.Resources.Limits[v1.ResourceCPU]; ok {
-		return container.Resources.Limits.Cpu().MilliValue()
-	} else if _, ok := container.Resources.Requests[v1.ResourceCPU]; ok {
-		return int64(math.Round(float64(container.Resources.Requests.Cpu().MilliValue()) * requestsMultiplier))
+// nodeUtilisation returns the utilization percent of a resource from the node metrics
+func nodeUtilisation(metrics []watcher.Metric, resourceName v1.ResourceName) (float64, bool) {
+	metricType := string(resourceName)
+	switch resourceName {
+	case v1.ResourceCPU:
+		metricType = watcher.CPU
+	case v1.ResourceMemory:
+		metricType = watcher.Memory
+	}
+
+	var utilPercent float64
+	var metricFound bool
+	for _, metric := range metrics {
+		if metric.Type == metricType {
+			if metric.Operator == watcher.Average || metric.Operator == watcher.Latest {
+				utilPercent = metric.Value
+				metricFound = true
+			}
+		}
 	}
-	return requestsMilliCores
+	return utilPercent, metricFound
 }
@@ -280,6 +280,200 @@ func TestTargetLoadPackingScoring(t *testing.T) {
 	}
 }
 
+func TestTargetLoadPackingResourceTargets(t *testing.T) {
+	node := st.MakeNode().Name("node-1").Capacity(map[v1.ResourceName]string{
+		v1.ResourceCPU:    "1000m",
+		v1.ResourceMemory: "1000",
+	}).Obj()
+	pod := st.MakePod().Name("p").Obj()
+	pod.Spec.Containers = []v1.Container{{
+		Resources: v1.ResourceRequirements{
+			Limits: v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("100m"),
+				v1.ResourceMemory: resource.MustParse("100"),
+			},
+		},
+	}}
+	cpuMetric := watcher.Metric{Type: watcher.CPU, Operator: watcher.Latest, Value: 20}
+	memoryMetric := watcher.Metric{Type: watcher.Memory, Operator: watcher.Latest, Value: 90}
+
+	tests := []struct {
+		name            string
+		resourceTargets map[v1.ResourceName]pluginConfig.ResourceTarget
+		metrics         []watcher.Metric
+		// metrics of another node, if any
+		otherMetrics []watcher.Metric
+		expected     int64
+	}{
+		{
+			// 30% CPU predicted, below the target
+			name:     "CPU only by default",
+			metrics:  []watcher.Metric{cpuMetric, memoryMetric},
+			expected: 85,
+		},
+		{
+			// 100% memory predicted, above the target
+			name: "memory only",
+			resourceTargets: map[v1.ResourceName]pluginConfig.ResourceTarget{
+				v1.ResourceMemory: {TargetUtilization: 60, Weight: 1},
+			},
+			metrics:  []watcher.Metric{cpuMetric, memoryMetric},
+			expected: 0,
+		},
+		{
+			name: "CPU and memory",
+			resourceTargets: map[v1.ResourceName]pluginConfig.ResourceTarget{
+				v1.ResourceCPU:    {TargetUtilization: 40, Weight: 1},
+				v1.ResourceMemory: {TargetUtilization: 60, Weight: 1},
+			},
+			metrics:  []watcher.Metric{cpuMetric, memoryMetric},
+			expected: 43,
+		},
+		{
+			name: "weighted CPU and memory",
+			resourceTargets: map[v1.ResourceName]pluginConfig.ResourceTarget{
+				v1.ResourceCPU:    {TargetUtilization: 40, Weight: 1},
+				v1.ResourceMemory: {TargetUtilization: 60, Weight: 3},
+			},
+			metrics:  []watcher.Metric{cpuMetric, memoryMetric},
+			expected: 21,
+		},
+		{
+			name: "resource without metrics ignored",
+			resourceTargets: map[v1.ResourceName]pluginConfig.ResourceTarget{
+				v1.ResourceCPU:    {TargetUtilization: 40, Weight: 1},
+				v1.ResourceMemory: {TargetUtilization: 60, Weight: 1},
+			},
+			metrics:  []watcher.Metric{cpuMetric},
+			expected: 85,
+		},
+		{
+			name: "resource without metrics on another node ignored",
+			resourceTargets: map[v1.ResourceName]pluginConfig.ResourceTarget{
+				v1.ResourceCPU:    {TargetUtilization: 40, Weight: 1},
+				v1.ResourceMemory: {TargetUtilization: 60, Weight: 1},
+			},
+			metrics:      []watcher.Metric{cpuMetric, memoryMetric},
+			otherMetrics: []watcher.Metric{cpuMetric},
+			expected:     85,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+				nodeMetrics := map[string]watcher.NodeMetrics{
+					"node-1": {Metrics: tt.metrics},
+				}
+				if tt.otherMetrics != nil {
+					nodeMetrics["node-2"] = watcher.NodeMetrics{Metrics: tt.otherMetrics}
+				}
+				bytes, err := json.Marshal(watcher.WatcherMetrics{
+					Data: watcher.Data{NodeMetricsMap: nodeMetrics},
+				})
+				assert.Nil(t, err)
+				resp.Write(bytes)
+			}))
+			defer server.Close()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			registeredPlugins := []tf.RegisterPluginFunc{
+				tf.RegisterBindPlugin(defaultbinder.Name, defaultbinder.New),
+				tf.RegisterQueueSortPlugin(queuesort.Name, queuesort.New),
+			}
+			cs := testClientSet.NewSimpleClientset()
+			informerFactory := informers.NewSharedInformerFactory(cs, 0)
+			fh, err := testutil.NewFramework(ctx, registeredPlugins, nil,
+				"default-scheduler", runtime.WithClientSet(cs),
+				runtime.WithInformerFactory(informerFactory), runtime.WithSnapshotSharedLister(newTestSharedLister(nil, nil)))
+			assert.Nil(t, err)
+
+			p, err := New(ctx, &pluginConfig.TargetLoadPackingArgs{
+				TrimaranSpec:              pluginConfig.TrimaranSpec{WatcherAddress: server.URL},
+				TargetUtilization:         cfgv1.DefaultTargetUtilizationPercent,
+				DefaultRequestsMultiplier: cfgv1.DefaultRequestsMultiplier,
+				ResourceTargets:           tt.resourceTargets,
+			}, fh)
+			assert.Nil(t, err)
+			pl := p.(*TargetLoadPacking)
+			defer pl.Close()
+
+			nodeInfo := framework.NewNodeInfo()
+			nodeInfo.SetNode(node)
+			score, _ := pl.Score(ctx, framework.NewCycleState(), pod, nodeInfo)
+			assert.Equal(t, tt.expected, score)
+		})
+	}
+}
+
+func TestTargetLoadPackingProfiles(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		bytes, err := json.Marshal(watcher.WatcherMetrics{
+			Data: watcher.Data{
+				NodeMetricsMap: map[string]watcher.NodeMetrics{
+					"node-1": {
+						Metrics: []watcher.Metric{
+							{
+								Type:     watcher.CPU,
+								Value:    0,
+								Operator: watcher.Latest,
+							},
+						},
+					},
+				},
+			},
+		})
+		assert.Nil(t, err)
+		resp.Write(bytes)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	registeredPlugins := []tf.RegisterPluginFunc{
+		tf.RegisterBindPlugin(defaultbinder.Name, defaultbinder.New),
+		tf.RegisterQueueSortPlugin(queuesort.Name, queuesort.New),
+	}
+	cs := testClientSet.NewSimpleClientset()
+	informerFactory := informers.NewSharedInformerFactory(cs, 0)
+	fh, err := testutil.NewFramework(ctx, registeredPlugins, nil,
+		"default-scheduler", runtime.WithClientSet(cs),
+		runtime.WithInformerFactory(informerFactory), runtime.WithSnapshotSharedLister(newTestSharedLister(nil, nil)))
+	assert.Nil(t, err)
+
+	newPlugin := func(targetUtilization int64) *TargetLoadPacking {
+		p, err := New(ctx, &pluginConfig.TargetLoadPackingArgs{
+			TrimaranSpec:              pluginConfig.TrimaranSpec{WatcherAddress: server.URL},
+			TargetUtilization:         targetUtilization,
+			DefaultRequestsMultiplier: cfgv1.DefaultRequestsMultiplier,
+		}, fh)
+		assert.Nil(t, err)
+		return p.(*TargetLoadPacking)
+	}
+	score := func(pl *TargetLoadPacking) int64 {
+		nodeInfo := framework.NewNodeInfo()
+		nodeInfo.SetNode(st.MakeNode().Name("node-1").Capacity(map[v1.ResourceName]string{v1.ResourceCPU: "1000m"}).Obj())
+		score, status := pl.Score(ctx, framework.NewCycleState(), st.MakePod().Name("p").Obj(), nodeInfo)
+		assert.True(t, status.IsSuccess())
+		return score
+	}
+
+	low := newPlugin(40)
+	high := newPlugin(80)
+	defer high.Close()
+	assert.Same(t, low.collector, high.collector)
+	assert.Same(t, low.eventHandler, high.eventHandler)
+	// an empty node scores the target utilization
+	assert.EqualValues(t, 40, score(low))
+	assert.EqualValues(t, 80, score(high))
+
+	low.Close()
+	assert.EqualValues(t, 80, score(high))
+}
+
 func BenchmarkTargetLoadPackingPlugin(b *testing.B) {
 	tests := []struct {
 		name     string
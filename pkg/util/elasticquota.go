@@ -0,0 +1,65 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"sigs.k8s.io/scheduler-plugins/apis/scheduling/v1alpha1"
+)
+
+// GetElasticQuotaForNamespace returns the ElasticQuota that the pods of the given namespace are subject to,
+// or nil if there is none. An ElasticQuota created in the namespace itself always takes precedence. Otherwise,
+// among the ElasticQuotas whose NamespaceSelector matches the namespace labels, the first one ordered by
+// namespace and name is returned. Both the scheduler and the controller use it to agree on membership.
+func GetElasticQuotaForNamespace(namespace string, nsLabels map[string]string, eqs []v1alpha1.ElasticQuota) *v1alpha1.ElasticQuota {
+	var own, selected *v1alpha1.ElasticQuota
+	for i := range eqs {
+		eq := &eqs[i]
+		if eq.Namespace == namespace {
+			if own == nil || eq.Name < own.Name {
+				own = eq
+			}
+			continue
+		}
+		if own != nil || !ElasticQuotaSelectsNamespace(eq, nsLabels) {
+			continue
+		}
+		if selected == nil || eq.Namespace < selected.Namespace ||
+			(eq.Namespace == selected.Namespace && eq.Name < selected.Name) {
+			selected = eq
+		}
+	}
+	if own != nil {
+		return own
+	}
+	return selected
+}
+
+// ElasticQuotaSelectsNamespace checks whether the NamespaceSelector of the ElasticQuota matches the given
+// namespace labels. An ElasticQuota without a NamespaceSelector selects no other namespace.
+func ElasticQuotaSelectsNamespace(eq *v1alpha1.ElasticQuota, nsLabels map[string]string) bool {
+	if eq.Spec.NamespaceSelector == nil {
+		return false
+	}
+	selector, err := metav1.LabelSelectorAsSelector(eq.Spec.NamespaceSelector)
+	if err != nil {
+		return false
+	}
+	return selector.Matches(labels.Set(nsLabels))
+}
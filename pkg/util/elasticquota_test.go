@@ -0,0 +1,99 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/scheduler-plugins/apis/scheduling/v1alpha1"
+)
+
+func TestGetElasticQuotaForNamespace(t *testing.T) {
+	makeEQ := func(namespace, name string, selector map[string]string) v1alpha1.ElasticQuota {
+		eq := v1alpha1.ElasticQuota{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name}}
+		if selector != nil {
+			eq.Spec.NamespaceSelector = &metav1.LabelSelector{MatchLabels: selector}
+		}
+		return eq
+	}
+	tenant := map[string]string{"tenant": "a"}
+
+	tests := []struct {
+		name      string
+		namespace string
+		nsLabels  map[string]string
+		eqs       []v1alpha1.ElasticQuota
+		expected  string
+	}{
+		{
+			name:      "no elasticquota",
+			namespace: "ns1",
+			eqs:       []v1alpha1.ElasticQuota{makeEQ("ns2", "eq", nil)},
+		},
+		{
+			name:      "own elasticquota",
+			namespace: "ns1",
+			eqs:       []v1alpha1.ElasticQuota{makeEQ("ns2", "eq", nil), makeEQ("ns1", "eq", nil)},
+			expected:  "ns1/eq",
+		},
+		{
+			name:      "own elasticquota takes precedence over selectors",
+			namespace: "ns1",
+			nsLabels:  tenant,
+			eqs:       []v1alpha1.ElasticQuota{makeEQ("admin", "eq", tenant), makeEQ("ns1", "eq", nil)},
+			expected:  "ns1/eq",
+		},
+		{
+			name:      "selected by namespace labels",
+			namespace: "ns1",
+			nsLabels:  tenant,
+			eqs:       []v1alpha1.ElasticQuota{makeEQ("admin", "eq", tenant)},
+			expected:  "admin/eq",
+		},
+		{
+			name:      "selector doesn't match",
+			namespace: "ns1",
+			nsLabels:  map[string]string{"tenant": "b"},
+			eqs:       []v1alpha1.ElasticQuota{makeEQ("admin", "eq", tenant)},
+		},
+		{
+			name:      "first matching elasticquota by namespace and name",
+			namespace: "ns1",
+			nsLabels:  tenant,
+			eqs: []v1alpha1.ElasticQuota{
+				makeEQ("admin-b", "eq", tenant),
+				makeEQ("admin-a", "eq2", tenant),
+				makeEQ("admin-a", "eq1", tenant),
+			},
+			expected: "admin-a/eq1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := GetElasticQuotaForNamespace(tt.namespace, tt.nsLabels, tt.eqs)
+			var gotKey string
+			if got != nil {
+				gotKey = got.Namespace + "/" + got.Name
+			}
+			if gotKey != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, gotKey)
+			}
+		})
+	}
+}
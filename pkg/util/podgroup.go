@@ -19,6 +19,7 @@ package util
 import (
 	"encoding/json"
 	"fmt"
+	"slices"
 	"time"
 
 	v1 "k8s.io/api/core/v1"
@@ -61,6 +62,35 @@ func GetPodGroupFullName(pod *v1.Pod) string {
 	return fmt.Sprintf("%v/%v", pod.Namespace, pgName)
 }
 
+// GetPodGroupRole get the role of the pod in its pod group from pod labels
+func GetPodGroupRole(pod *v1.Pod) string {
+	return pod.Labels[v1alpha1.PodGroupRoleLabel]
+}
+
+// CountPodsByRole counts the given pods by their role in the pod group. If phases are given,
+// only the pods in one of these phases are counted.
+func CountPodsByRole(pods []*v1.Pod, phases ...v1.PodPhase) map[string]int32 {
+	counts := make(map[string]int32)
+	for _, pod := range pods {
+		if len(phases) != 0 && !slices.Contains(phases, pod.Status.Phase) {
+			continue
+		}
+		counts[GetPodGroupRole(pod)]++
+	}
+	return counts
+}
+
+// GetUnsatisfiedRole returns the first role of the pod group whose number of pods, given by role
+// in podsByRole, is less than its MinMember. It returns nil if every role is satisfied.
+func GetUnsatisfiedRole(pg *v1alpha1.PodGroup, podsByRole map[string]int32) *v1alpha1.PodGroupRole {
+	for i, role := range pg.Spec.Roles {
+		if podsByRole[role.Name] < role.MinMember {
+			return &pg.Spec.Roles[i]
+		}
+	}
+	return nil
+}
+
 // GetWaitTimeDuration returns a wait timeout based on the following precedences:
 // 1. spec.scheduleTimeoutSeconds of the given pg, if specified
 // 2. given scheduleTimeout, if not nil
@@ -17,9 +17,14 @@ limitations under the License.
 package util
 
 import (
+	"reflect"
 	"testing"
 
+	v1 "k8s.io/api/core/v1"
 	"k8s.io/kubernetes/pkg/apis/core"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+
+	"sigs.k8s.io/scheduler-plugins/apis/scheduling/v1alpha1"
 )
 
 func TestCreateMergePatch(t *testing.T) {
@@ -70,3 +75,43 @@ func TestCreateMergePatch(t *testing.T) {
 		}
 	}
 }
+
+func TestCountPodsByRole(t *testing.T) {
+	makePod := func(name, role string, phase v1.PodPhase) *v1.Pod {
+		pod := st.MakePod().Name(name).Label(v1alpha1.PodGroupLabel, "pg").Obj()
+		if role != "" {
+			pod.Labels[v1alpha1.PodGroupRoleLabel] = role
+		}
+		pod.Status.Phase = phase
+		return pod
+	}
+	pods := []*v1.Pod{
+		makePod("p1", "driver", v1.PodRunning),
+		makePod("p2", "worker", v1.PodRunning),
+		makePod("p3", "worker", v1.PodPending),
+		makePod("p4", "worker", v1.PodSucceeded),
+		makePod("p5", "", v1.PodFailed),
+	}
+	tests := []struct {
+		name     string
+		phases   []v1.PodPhase
+		expected map[string]int32
+	}{
+		{
+			name:     "all phases",
+			expected: map[string]int32{"driver": 1, "worker": 3, "": 1},
+		},
+		{
+			name:     "running and succeeded pods",
+			phases:   []v1.PodPhase{v1.PodRunning, v1.PodSucceeded},
+			expected: map[string]int32{"driver": 1, "worker": 2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CountPodsByRole(pods, tt.phases...); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
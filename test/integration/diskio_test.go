@@ -0,0 +1,155 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package integration
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/kubernetes/pkg/scheduler"
+	schedapi "k8s.io/kubernetes/pkg/scheduler/apis/config"
+	fwkruntime "k8s.io/kubernetes/pkg/scheduler/framework/runtime"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+	imageutils "k8s.io/kubernetes/test/utils/image"
+
+	schedconfig "sigs.k8s.io/scheduler-plugins/apis/config"
+	"sigs.k8s.io/scheduler-plugins/pkg/diskio"
+	"sigs.k8s.io/scheduler-plugins/test/util"
+)
+
+func TestDiskIOPlugin(t *testing.T) {
+	testCtx := &testContext{}
+	testCtx.Ctx, testCtx.CancelFn = context.WithCancel(context.Background())
+
+	cs := kubernetes.NewForConfigOrDie(globalKubeConfig)
+	testCtx.ClientSet = cs
+	testCtx.KubeConfig = globalKubeConfig
+
+	cfg, err := util.NewDefaultSchedulerComponentConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.Profiles[0].Plugins.PreFilter.Enabled = append(cfg.Profiles[0].Plugins.PreFilter.Enabled, schedapi.Plugin{Name: diskio.Name})
+	cfg.Profiles[0].Plugins.Filter.Enabled = append(cfg.Profiles[0].Plugins.Filter.Enabled, schedapi.Plugin{Name: diskio.Name})
+	cfg.Profiles[0].Plugins.Reserve.Enabled = append(cfg.Profiles[0].Plugins.Reserve.Enabled, schedapi.Plugin{Name: diskio.Name})
+	cfg.Profiles[0].Plugins.PreScore = schedapi.PluginSet{
+		Enabled:  []schedapi.Plugin{{Name: diskio.Name}},
+		Disabled: []schedapi.Plugin{{Name: "*"}},
+	}
+	cfg.Profiles[0].Plugins.Score = schedapi.PluginSet{
+		Enabled:  []schedapi.Plugin{{Name: diskio.Name, Weight: 10}},
+		Disabled: []schedapi.Plugin{{Name: "*"}},
+	}
+	cfg.Profiles[0].PluginConfig = append(cfg.Profiles[0].PluginConfig, schedapi.PluginConfig{
+		Name: diskio.Name,
+		Args: &schedconfig.DiskIOArgs{
+			ScoringStrategy: schedconfig.DiskIOLeastAllocated,
+		},
+	})
+
+	ns := fmt.Sprintf("integration-test-%v", string(uuid.NewUUID()))
+	createNamespace(t, testCtx, ns)
+
+	testCtx = initTestSchedulerWithOptions(
+		t,
+		testCtx,
+		scheduler.WithProfiles(cfg.Profiles...),
+		scheduler.WithFrameworkOutOfTreeRegistry(fwkruntime.Registry{diskio.Name: diskio.New}),
+	)
+	syncInformerFactory(testCtx)
+	go testCtx.Scheduler.Run(testCtx.Ctx)
+	defer cleanupTest(t, testCtx)
+
+	capacity := map[v1.ResourceName]string{
+		v1.ResourceCPU:    "32",
+		v1.ResourceMemory: "64Gi",
+		v1.ResourcePods:   "32",
+	}
+	nodes := []*v1.Node{
+		st.MakeNode().Name("node-fast").Capacity(capacity).
+			Annotation(diskio.AllocatableAnnotation, `{"read": "300M", "write": "300M"}`).Obj(),
+		st.MakeNode().Name("node-slow").Capacity(capacity).
+			Annotation(diskio.AllocatableAnnotation, `{"read": "100M", "write": "100M"}`).Obj(),
+		// A node whose IO driver doesn't report any bandwidth only runs pods without disk IO requests.
+		st.MakeNode().Name("node-unknown").Capacity(capacity).Obj(),
+	}
+	for _, node := range nodes {
+		if _, err := cs.CoreV1().Nodes().Create(testCtx.Ctx, node, metav1.CreateOptions{}); err != nil {
+			t.Fatalf("failed to create node %q: %v", node.Name, err)
+		}
+	}
+
+	makePod := func(name, throughput string) *v1.Pod {
+		return st.MakePod().Namespace(ns).Name(name).Container(imageutils.GetPauseImageName()).
+			Annotation(diskio.ThroughputAnnotation, throughput).Obj()
+	}
+
+	var createdPods []*v1.Pod
+	defer func() { cleanupPods(t, testCtx, createdPods) }()
+	createPod := func(pod *v1.Pod) {
+		p, err := cs.CoreV1().Pods(ns).Create(testCtx.Ctx, pod, metav1.CreateOptions{})
+		if err != nil {
+			t.Fatalf("failed to create pod %q: %v", pod.Name, err)
+		}
+		createdPods = append(createdPods, p)
+	}
+	expectScheduledOn := func(podName, nodeName string) {
+		t.Helper()
+		if err := wait.PollUntilContextTimeout(testCtx.Ctx, 100*time.Millisecond, 10*time.Second, false, func(ctx context.Context) (bool, error) {
+			return podScheduled(t, cs, ns, podName), nil
+		}); err != nil {
+			t.Fatalf("pod %q failed to be scheduled: %v", podName, err)
+		}
+		p, err := cs.CoreV1().Pods(ns).Get(testCtx.Ctx, podName, metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("failed to get pod %q: %v", podName, err)
+		}
+		if p.Spec.NodeName != nodeName {
+			t.Errorf("pod %q scheduled on node %q, expected %q", podName, p.Spec.NodeName, nodeName)
+		}
+	}
+
+	// The least utilized node is preferred, so the first pods go to the fast node until its
+	// utilization exceeds the one of the slow node.
+	createPod(makePod("pod-1", `{"rbps": "100M", "wbps": "100M"}`))
+	expectScheduledOn("pod-1", "node-fast")
+	createPod(makePod("pod-2", `{"rbps": "40M", "wbps": "40M"}`))
+	expectScheduledOn("pod-2", "node-slow")
+
+	// Only the fast node has enough bandwidth left for the third pod.
+	createPod(makePod("pod-3", `{"rbps": "100M", "wbps": "100M"}`))
+	expectScheduledOn("pod-3", "node-fast")
+
+	// No node has enough bandwidth left.
+	createPod(makePod("pod-4", `{"rbps": "150M"}`))
+	if _, err := podIsPending(t, 100*time.Millisecond, 20, cs, ns, "pod-4"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Deleting a pod releases its bandwidth, so the pending pod fits on the fast node again.
+	if err := cs.CoreV1().Pods(ns).Delete(testCtx.Ctx, "pod-1", *metav1.NewDeleteOptions(0)); err != nil {
+		t.Fatalf("failed to delete pod %q: %v", "pod-1", err)
+	}
+	expectScheduledOn("pod-4", "node-fast")
+}
@@ -153,6 +153,16 @@ func (e *eqWrapper) Max(max v1.ResourceList) *eqWrapper {
 	return e
 }
 
+func (e *eqWrapper) Parent(parent string) *eqWrapper {
+	e.ElasticQuota.Spec.Parent = parent
+	return e
+}
+
+func (e *eqWrapper) NamespaceSelector(matchLabels map[string]string) *eqWrapper {
+	e.ElasticQuota.Spec.NamespaceSelector = &metav1.LabelSelector{MatchLabels: matchLabels}
+	return e
+}
+
 func (e *eqWrapper) Used(used v1.ResourceList) *eqWrapper {
 	e.ElasticQuota.Status.Used = used
 	return e
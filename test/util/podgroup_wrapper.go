@@ -50,6 +50,11 @@ func (p *PodGroupWrapper) MinMember(i int32) *PodGroupWrapper {
 	return p
 }
 
+func (p *PodGroupWrapper) MaxMember(i int32) *PodGroupWrapper {
+	p.PodGroup.Spec.MaxMember = &i
+	return p
+}
+
 func (p *PodGroupWrapper) Time(t time.Time) *PodGroupWrapper {
 	p.CreationTimestamp.Time = t
 	return p
@@ -68,3 +73,20 @@ func (p *PodGroupWrapper) Phase(phase v1alpha1.PodGroupPhase) *PodGroupWrapper {
 	p.Status.Phase = phase
 	return p
 }
+
+func (p *PodGroupWrapper) TopologyPolicy(topologyKey string, mode v1alpha1.TopologyMode) *PodGroupWrapper {
+	p.Spec.TopologyPolicy = &v1alpha1.TopologyPolicy{TopologyKey: topologyKey, Mode: mode}
+	return p
+}
+
+func (p *PodGroupWrapper) Role(name string, minMember int32, minResources map[v1.ResourceName]string) *PodGroupWrapper {
+	role := v1alpha1.PodGroupRole{Name: name, MinMember: minMember}
+	if minResources != nil {
+		role.MinResources = make(v1.ResourceList)
+		for resName, value := range minResources {
+			role.MinResources[resName] = resource.MustParse(value)
+		}
+	}
+	p.Spec.Roles = append(p.Spec.Roles, role)
+	return p
+}